@@ -0,0 +1,76 @@
+// docgen-gen 根据服务端模板生成类型化的 Go 渲染代码
+//
+// 用法（通常写在 go:generate 指令中）:
+//
+//	//go:generate go run github.com/Mars-Sea/doc-gen-service/sdk/go/cmd/docgen-gen -base-url http://localhost:8081 -out ./templates
+//
+// 参数:
+//
+//	-base-url  服务地址（默认读取环境变量 DOCGEN_BASE_URL）
+//	-api-key   API 密钥（默认读取环境变量 DOCGEN_API_KEY）
+//	-out       输出目录，每个模板生成一个子包（默认当前目录）
+//	-template  仅生成指定模板，可重复指定或以逗号分隔
+//	-timeout   请求超时（默认 30s）
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/codegen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// templateFlags 可重复指定的模板参数
+type templateFlags []string
+
+func (t *templateFlags) String() string { return strings.Join(*t, ",") }
+
+func (t *templateFlags) Set(v string) error {
+	for _, name := range strings.Split(v, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			*t = append(*t, name)
+		}
+	}
+	return nil
+}
+
+func main() {
+	baseURL := flag.String("base-url", envOr("DOCGEN_BASE_URL", "http://localhost:8081"), "服务地址")
+	apiKey := flag.String("api-key", os.Getenv("DOCGEN_API_KEY"), "API 密钥")
+	out := flag.String("out", ".", "输出目录")
+	timeout := flag.Duration("timeout", 30*time.Second, "请求超时")
+	var templates templateFlags
+	flag.Var(&templates, "template", "仅生成指定模板（可重复或以逗号分隔）")
+	flag.Parse()
+
+	var opts []docgen.Option
+	if *apiKey != "" {
+		opts = append(opts, docgen.WithAPIKey(*apiKey))
+	}
+	client := docgen.NewClientWithTimeout(*baseURL, *timeout, opts...)
+
+	files, err := codegen.Generate(client, codegen.Options{Templates: templates})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "docgen-gen:", err)
+		os.Exit(1)
+	}
+	if err := codegen.WriteFiles(*out, files); err != nil {
+		fmt.Fprintln(os.Stderr, "docgen-gen:", err)
+		os.Exit(1)
+	}
+	for _, f := range files {
+		fmt.Printf("%s -> %s\n", f.TemplateName, f.Path)
+	}
+}
+
+// envOr 读取环境变量，未设置时返回默认值
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
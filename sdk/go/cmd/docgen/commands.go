@@ -0,0 +1,457 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// cmdEnv 命令执行环境
+type cmdEnv struct {
+	cfg    *globalConfig
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// newFlagSet 创建子命令参数集（包含全局参数）
+func (env *cmdEnv) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	addGlobalFlags(fs, env.cfg)
+	return fs
+}
+
+// dispatch 根据命令名称分发执行
+func dispatch(env *cmdEnv, args []string) error {
+	if len(args) == 0 {
+		printUsage(env.stderr)
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+
+	cmd, rest := args[0], args[1:]
+	sub := ""
+	if len(rest) > 0 {
+		sub = rest[0]
+	}
+
+	switch {
+	case cmd == "generate" && sub == "word":
+		return generateWord(env, rest[1:])
+	case cmd == "generate" && sub == "excel":
+		return generateExcel(env, rest[1:])
+	case cmd == "fill" && sub == "excel":
+		return fillExcel(env, rest[1:])
+	case cmd == "template" && sub == "upload":
+		return templateUpload(env, rest[1:])
+	case cmd == "template" && sub == "list":
+		return templateList(env, rest[1:])
+	case cmd == "template" && sub == "download":
+		return templateDownload(env, rest[1:])
+	case cmd == "template" && sub == "delete":
+		return templateDelete(env, rest[1:])
+	case cmd == "batch" && sub == "jsonl":
+		return batchJSONL(env, rest[1:])
+	case cmd == "run":
+		return runManifest(env, rest)
+	case cmd == "health":
+		return health(env, rest)
+	case cmd == "help":
+		printUsage(env.stdout)
+		return nil
+	default:
+		printUsage(env.stderr)
+		return fmt.Errorf("%w: unknown command %q", errUsage, joinArgs(cmd, sub))
+	}
+}
+
+// generateWord generate word 命令
+func generateWord(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("generate word")
+	template := fs.String("t", "", "模板文件名（必填）")
+	dataPath := fs.String("d", "-", "数据文件（JSON），- 表示标准输入")
+	output := fs.String("o", "", "输出文件路径（必填）")
+	fileName := fs.String("name", "", "服务端输出文件名（不含扩展名，可选）")
+	batch := fs.Bool("batch", false, "批量生成：数据文件为 JSON 数组，每个元素生成一页")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "t", *template, "o", *output); err != nil {
+		return err
+	}
+
+	client := env.cfg.newClient()
+	var doc []byte
+	if *batch {
+		var dataList []map[string]any
+		if err := env.readJSON(*dataPath, &dataList); err != nil {
+			return err
+		}
+		var err error
+		if doc, err = client.BatchGenerateWord(*template, dataList, *fileName); err != nil {
+			return err
+		}
+	} else {
+		var data map[string]any
+		if err := env.readJSON(*dataPath, &data); err != nil {
+			return err
+		}
+		var err error
+		if doc, err = client.GenerateWord(*template, data, *fileName); err != nil {
+			return err
+		}
+	}
+
+	return env.writeOutput(*output, doc)
+}
+
+// generateExcel generate excel 命令，数据文件格式与 docgen.ExcelGenRequest 一致
+func generateExcel(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("generate excel")
+	dataPath := fs.String("d", "-", `数据文件（JSON，{"sheetName":..,"headers":[..],"data":[[..]]}），- 表示标准输入`)
+	output := fs.String("o", "", "输出文件路径（必填）")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "o", *output); err != nil {
+		return err
+	}
+
+	var req docgen.ExcelGenRequest
+	if err := env.readJSON(*dataPath, &req); err != nil {
+		return err
+	}
+
+	doc, err := env.cfg.newClient().GenerateExcelWithRequest(req)
+	if err != nil {
+		return err
+	}
+	return env.writeOutput(*output, doc)
+}
+
+// fillExcel fill excel 命令，数据文件格式为 {"data":{..},"listData":{..}}
+func fillExcel(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("fill excel")
+	template := fs.String("t", "", "模板文件名（必填）")
+	dataPath := fs.String("d", "-", `数据文件（JSON，{"data":{..},"listData":{..}}），- 表示标准输入`)
+	output := fs.String("o", "", "输出文件路径（必填）")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "t", *template, "o", *output); err != nil {
+		return err
+	}
+
+	var req docgen.ExcelFillRequest
+	if err := env.readJSON(*dataPath, &req); err != nil {
+		return err
+	}
+	req.TemplateName = *template
+
+	doc, err := env.cfg.newClient().FillExcelTemplateWithRequest(req)
+	if err != nil {
+		return err
+	}
+	return env.writeOutput(*output, doc)
+}
+
+// templateUpload template upload 命令
+func templateUpload(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("template upload")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: template upload requires exactly one file", errUsage)
+	}
+
+	resp, err := env.cfg.newClient().UploadTemplate(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "uploaded: %s\n", resp.FileName)
+	return nil
+}
+
+// templateList template list 命令
+func templateList(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("template list")
+	output := fs.String("output", "table", "输出格式：json 或 table")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkOutputFormat(*output); err != nil {
+		return err
+	}
+
+	templates, err := env.cfg.newClient().ListTemplates()
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return env.printJSON(templates)
+	}
+	tw := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME")
+	for _, name := range templates {
+		fmt.Fprintln(tw, name)
+	}
+	return tw.Flush()
+}
+
+// templateDownload template download 命令
+func templateDownload(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("template download")
+	output := fs.String("o", "", "输出文件路径（默认使用模板文件名，- 表示标准输出）")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: template download requires exactly one template name", errUsage)
+	}
+
+	name := fs.Arg(0)
+	content, err := env.cfg.newClient().DownloadTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if path == "" {
+		path = filepath.Base(name)
+	}
+	return env.writeOutput(path, content)
+}
+
+// templateDelete template delete 命令
+func templateDelete(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("template delete")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: template delete requires exactly one template name", errUsage)
+	}
+
+	resp, err := env.cfg.newClient().DeleteTemplate(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("delete %s: %s", resp.FileName, resp.Message)
+	}
+	fmt.Fprintf(env.stdout, "deleted: %s\n", resp.FileName)
+	return nil
+}
+
+// health health 命令，服务不健康时以非零退出码结束
+func health(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("health")
+	output := fs.String("output", "table", "输出格式：json 或 table")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkOutputFormat(*output); err != nil {
+		return err
+	}
+
+	resp, err := env.cfg.newClient().Health()
+	if resp != nil {
+		if *output == "json" {
+			if perr := env.printJSON(resp); perr != nil {
+				return perr
+			}
+		} else {
+			fmt.Fprintln(env.stdout, resp.Status)
+		}
+	}
+	return err
+}
+
+// batchJSONL batch jsonl 命令：JSONL 每行渲染一个文档
+func batchJSONL(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("batch jsonl")
+	template := fs.String("t", "", "模板文件名（必填）")
+	input := fs.String("i", "-", "JSONL 输入文件，- 表示标准输入")
+	outDir := fs.String("out", "", "输出目录（必填）")
+	nameField := fs.String("name-field", "", "用于命名输出文件的记录字段")
+	concurrency := fs.Int("concurrency", 4, "并发渲染数")
+	noResume := fs.Bool("no-resume", false, "忽略已有输出，全部重新渲染")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "t", *template, "out", *outDir); err != nil {
+		return err
+	}
+
+	var r io.Reader = env.stdin
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := docgen.RunBatchFromJSONL(ctx, env.cfg.newClient(), *template, r, *outDir, docgen.BatchRunOptions{
+		Concurrency:   *concurrency,
+		FileNameField: *nameField,
+		NoResume:      *noResume,
+	})
+	if report != nil {
+		if perr := env.printJSON(report); perr != nil {
+			return perr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d records failed, see %s", report.Failed, report.Total, report.FailuresPath)
+	}
+	return nil
+}
+
+// runManifest run 命令：执行声明式生成清单，报告以 JSON 输出到标准输出或 -report 文件
+func runManifest(env *cmdEnv, args []string) error {
+	fs := env.newFlagSet("run")
+	path := fs.String("f", "", "清单文件（JSON，必填）")
+	concurrency := fs.Int("concurrency", 0, "并发生成数，覆盖清单中的设置")
+	reportPath := fs.String("report", "", "报告输出文件，默认输出到标准输出")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "f", *path); err != nil {
+		return err
+	}
+
+	manifest, err := docgen.LoadManifest(*path)
+	if err != nil {
+		return err
+	}
+	if *concurrency > 0 {
+		manifest.Concurrency = *concurrency
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := docgen.RunManifest(ctx, env.cfg.newClient(), *manifest)
+	if report != nil {
+		if *reportPath != "" {
+			data, merr := json.MarshalIndent(report, "", "  ")
+			if merr != nil {
+				return merr
+			}
+			if werr := env.writeOutput(*reportPath, append(data, '\n')); werr != nil {
+				return werr
+			}
+		} else if perr := env.printJSON(report); perr != nil {
+			return perr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d entries failed", report.Failed, len(report.Entries))
+	}
+	return nil
+}
+
+// parseFlags 解析子命令参数
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
+
+// requireFlags 检查必填参数，参数以 (名称, 值) 成对传入
+func requireFlags(fs *flag.FlagSet, pairs ...string) error {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			return fmt.Errorf("%w: %s: -%s is required", errUsage, fs.Name(), pairs[i])
+		}
+	}
+	return nil
+}
+
+// checkOutputFormat 校验输出格式参数
+func checkOutputFormat(format string) error {
+	if format != "json" && format != "table" {
+		return fmt.Errorf("%w: unknown output format %q (want json or table)", errUsage, format)
+	}
+	return nil
+}
+
+// readJSON 读取 JSON 数据文件（"-" 表示标准输入），数值保留为 json.Number 以避免精度丢失
+func (env *cmdEnv) readJSON(path string, v any) error {
+	var r io.Reader = env.stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open data file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("failed to parse data file %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeOutput 写入输出文件（"-" 表示标准输出）
+func (env *cmdEnv) writeOutput(path string, data []byte) error {
+	if path == "-" {
+		_, err := env.stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	fmt.Fprintf(env.stderr, "saved: %s (%d bytes)\n", path, len(data))
+	return nil
+}
+
+// printJSON 以缩进 JSON 格式输出
+func (env *cmdEnv) printJSON(v any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := env.stdout.Write(buf.Bytes())
+	return err
+}
+
+// joinArgs 拼接命令名称用于错误提示
+func joinArgs(cmd, sub string) string {
+	if sub == "" {
+		return cmd
+	}
+	return cmd + " " + sub
+}
@@ -0,0 +1,174 @@
+// docgen 文档生成服务命令行工具
+//
+// 所有命令均通过 SDK 公开的 docgen.Client 实现，可兼作 SDK 的集成测试。
+//
+// 用法:
+//
+//	docgen [全局参数] <命令> [子命令] [参数]
+//
+//	docgen generate word -t template.docx -d data.json -o out.docx
+//	docgen generate word -t cert.docx -d list.json -o certs.docx -batch
+//	docgen generate excel -d excel.json -o out.xlsx
+//	docgen fill excel -t template.xlsx -d fill.json -o out.xlsx
+//	docgen template upload template.docx
+//	docgen template list -output table
+//	docgen template download -o local.docx template.docx
+//	docgen template delete template.docx
+//	docgen batch jsonl -t template.docx -i records.jsonl -out ./out -name-field id
+//	docgen run -f manifest.json
+//	docgen health
+//
+// 全局参数（也可通过环境变量设置）:
+//
+//	-base-url  服务地址（DOCGEN_BASE_URL，默认 http://localhost:8081）
+//	-timeout   请求超时（DOCGEN_TIMEOUT，默认 30s）
+//	-api-key   API 密钥（DOCGEN_API_KEY）
+//
+// -d 省略或为 "-" 时从标准输入读取数据。
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// 退出码，按 SDK 的错误类型映射
+const (
+	exitOK          = 0
+	exitError       = 1 // 其他错误（文件读写、参数解析等）
+	exitUsage       = 2 // 命令行用法错误
+	exitUnreachable = 3 // 服务无法连接
+	exitTimeout     = 4 // 请求超时
+	exitAPIError    = 5 // 服务端返回错误响应
+	exitServiceDown = 6 // 服务可连接但不可用
+	exitTooLarge    = 7 // 请求体超过大小限制
+)
+
+// errUsage 命令行用法错误
+var errUsage = errors.New("usage error")
+
+// globalConfig 全局参数
+type globalConfig struct {
+	baseURL string
+	timeout time.Duration
+	apiKey  string
+}
+
+// addGlobalFlags 注册全局参数，使其既可出现在命令前也可出现在子命令参数中
+func addGlobalFlags(fs *flag.FlagSet, cfg *globalConfig) {
+	fs.StringVar(&cfg.baseURL, "base-url", cfg.baseURL, "服务地址（环境变量 DOCGEN_BASE_URL）")
+	fs.DurationVar(&cfg.timeout, "timeout", cfg.timeout, "请求超时（环境变量 DOCGEN_TIMEOUT）")
+	fs.StringVar(&cfg.apiKey, "api-key", cfg.apiKey, "API 密钥（环境变量 DOCGEN_API_KEY）")
+}
+
+// defaultConfig 从环境变量加载全局参数默认值
+func defaultConfig() (*globalConfig, error) {
+	cfg := &globalConfig{
+		baseURL: "http://localhost:8081",
+		timeout: 30 * time.Second,
+		apiKey:  os.Getenv("DOCGEN_API_KEY"),
+	}
+	if v := os.Getenv("DOCGEN_BASE_URL"); v != "" {
+		cfg.baseURL = v
+	}
+	if v := os.Getenv("DOCGEN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DOCGEN_TIMEOUT %q: %w", v, err)
+		}
+		cfg.timeout = d
+	}
+	return cfg, nil
+}
+
+// newClient 根据全局参数创建客户端
+func (cfg *globalConfig) newClient() *docgen.Client {
+	var opts []docgen.Option
+	if cfg.apiKey != "" {
+		opts = append(opts, docgen.WithAPIKey(cfg.apiKey))
+	}
+	return docgen.NewClientWithTimeout(cfg.baseURL, cfg.timeout, opts...)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run 执行命令并返回退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, err := defaultConfig()
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("docgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { printUsage(stderr) }
+	addGlobalFlags(fs, cfg)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	env := &cmdEnv{cfg: cfg, stdin: stdin, stdout: stdout, stderr: stderr}
+	if err := dispatch(env, fs.Args()); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		fmt.Fprintln(stderr, "error:", err)
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// exitCode 将错误映射为退出码
+func exitCode(err error) int {
+	var apiErr *docgen.ErrorResponse
+	switch {
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, docgen.ErrTimeout):
+		return exitTimeout
+	case errors.Is(err, docgen.ErrUnreachable):
+		return exitUnreachable
+	case errors.Is(err, docgen.ErrServiceDown):
+		return exitServiceDown
+	case errors.Is(err, docgen.ErrRequestTooLarge):
+		return exitTooLarge
+	case errors.As(err, &apiErr):
+		return exitAPIError
+	default:
+		return exitError
+	}
+}
+
+// printUsage 输出帮助信息
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage: docgen [global flags] <command> [subcommand] [flags]
+
+Commands:
+  generate word   -t template.docx -d data.json -o out.docx [-name fileName] [-batch]
+  generate excel  -d excel.json -o out.xlsx
+  fill excel      -t template.xlsx -d fill.json -o out.xlsx
+  template upload <file>
+  template list   [-output json|table]
+  template download [-o path] <name>
+  template delete <name>
+  batch jsonl     -t template.docx -i records.jsonl -out dir [-name-field id] [-concurrency 4] [-no-resume]
+  run             -f manifest.json [-concurrency N] [-report report.json]
+  health          [-output json|table]
+
+Global flags (also accepted after the subcommand):
+  -base-url  service URL (env DOCGEN_BASE_URL, default http://localhost:8081)
+  -timeout   request timeout (env DOCGEN_TIMEOUT, default 30s)
+  -api-key   API key (env DOCGEN_API_KEY)
+
+Data files are read from stdin when -d is omitted or "-".
+`)
+}
@@ -0,0 +1,358 @@
+// Package codegen 根据服务端模板的占位符结构生成类型化的 Go 渲染代码
+//
+// 每个模板生成一个独立的包（一个 .go 文件），包含:
+//   - TemplateName 常量
+//   - Params 参数结构体（列表区域生成对应的行结构体）
+//   - Render(c *docgen.Client, p Params) ([]byte, error) 渲染函数
+//
+// 生成结果只依赖 SDK 公开 API，且按名称排序输出，重复生成的内容保持一致。
+// 通常配合 cmd/docgen-gen 在 go:generate 中使用:
+//
+//	//go:generate go run github.com/Mars-Sea/doc-gen-service/sdk/go/cmd/docgen-gen -base-url http://localhost:8081 -out ./templates
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// DefaultImportPath SDK 包的默认导入路径
+const DefaultImportPath = "github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+
+// Options 生成配置
+type Options struct {
+	// Templates 仅生成指定模板，为空时生成服务端全部 .docx / .xlsx 模板
+	Templates []string
+	// ImportPath SDK 导入路径，为空时使用 DefaultImportPath
+	ImportPath string
+}
+
+// File 生成的源文件
+type File struct {
+	// Path 相对输出目录的路径，如 "invoice/invoice.go"
+	Path string
+	// Package 包名
+	Package string
+	// TemplateName 对应的模板文件名
+	TemplateName string
+	// Content 已格式化的源码
+	Content []byte
+}
+
+// Generate 连接服务端，枚举模板并获取占位符结构后生成代码
+func Generate(c *docgen.Client, opts Options) ([]File, error) {
+	names := opts.Templates
+	if len(names) == 0 {
+		all, err := c.ListTemplates()
+		if err != nil {
+			return nil, fmt.Errorf("list templates: %w", err)
+		}
+		for _, name := range all {
+			if templateKind(name) != kindUnknown {
+				names = append(names, name)
+			}
+		}
+	}
+
+	schemas := make([]docgen.TemplateSchema, 0, len(names))
+	for _, name := range names {
+		schema, err := c.GetTemplateVariables(name)
+		if err != nil {
+			return nil, fmt.Errorf("get variables of %s: %w", name, err)
+		}
+		schemas = append(schemas, *schema)
+	}
+
+	return GenerateFromSchemas(schemas, opts)
+}
+
+// GenerateFromSchemas 根据已获取的占位符结构生成代码（不访问网络）
+func GenerateFromSchemas(schemas []docgen.TemplateSchema, opts Options) ([]File, error) {
+	importPath := opts.ImportPath
+	if importPath == "" {
+		importPath = DefaultImportPath
+	}
+
+	sorted := append([]docgen.TemplateSchema(nil), schemas...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].TemplateName < sorted[j].TemplateName })
+
+	usedPkgs := make(map[string]bool)
+	files := make([]File, 0, len(sorted))
+	for _, schema := range sorted {
+		kind := templateKind(schema.TemplateName)
+		if kind == kindUnknown {
+			return nil, fmt.Errorf("template %s: unsupported extension (want .docx or .xlsx)", schema.TemplateName)
+		}
+
+		pkg := uniqueName(packageName(schema.TemplateName), usedPkgs)
+		src, err := renderFile(pkg, importPath, kind, schema)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", schema.TemplateName, err)
+		}
+		files = append(files, File{
+			Path:         path.Join(pkg, pkg+".go"),
+			Package:      pkg,
+			TemplateName: schema.TemplateName,
+			Content:      src,
+		})
+	}
+	return files, nil
+}
+
+// WriteFiles 将生成的文件写入输出目录
+func WriteFiles(dir string, files []File) error {
+	for _, f := range files {
+		target := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, f.Content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// kind 模板类型
+type kind int
+
+const (
+	kindUnknown kind = iota
+	kindWord
+	kindExcel
+)
+
+// templateKind 根据扩展名判断模板类型
+func templateKind(name string) kind {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".docx", ".docm":
+		return kindWord
+	case ".xlsx", ".xlsm":
+		return kindExcel
+	default:
+		return kindUnknown
+	}
+}
+
+// field 生成的结构体字段
+type field struct {
+	GoName  string
+	JSON    string
+	Type    string
+	Comment string
+}
+
+// list 生成的列表区域
+type list struct {
+	field
+	RowType string
+	Fields  []field
+}
+
+// renderFile 生成单个模板的源码并格式化
+func renderFile(pkg, importPath string, k kind, schema docgen.TemplateSchema) ([]byte, error) {
+	usedFields := reservedNames()
+	usedTypes := map[string]bool{"Params": true}
+
+	var scalars []field
+	for _, v := range sortedVariables(schema.Variables) {
+		scalars = append(scalars, newField(v, usedFields))
+	}
+
+	lists := append([]docgen.TemplateList(nil), schema.Lists...)
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Name < lists[j].Name })
+	var regions []list
+	for _, l := range lists {
+		f := field{GoName: uniqueName(goName(l.Name), usedFields), JSON: l.Name}
+		rowType := uniqueName(f.GoName+"Row", usedTypes)
+		f.Type = "[]" + rowType
+		f.Comment = fmt.Sprintf("列表 %q", l.Name)
+
+		rowFields := reservedNames()
+		var fields []field
+		for _, v := range sortedVariables(l.Fields) {
+			fields = append(fields, newField(v, rowFields))
+		}
+		regions = append(regions, list{field: f, RowType: rowType, Fields: fields})
+	}
+
+	var b bytes.Buffer
+	p := func(format string, args ...any) { fmt.Fprintf(&b, format, args...) }
+
+	p("// Code generated by docgen-gen. DO NOT EDIT.\n\n")
+	p("// Package %s 模板 %q 的类型化渲染参数\n", pkg, schema.TemplateName)
+	p("package %s\n\n", pkg)
+	p("import docgen %q\n\n", importPath)
+	p("// TemplateName 模板文件名\n")
+	p("const TemplateName = %q\n\n", schema.TemplateName)
+
+	p("// Params 模板渲染参数\ntype Params struct {\n")
+	for _, f := range scalars {
+		p("\t// %s %s\n\t%s %s `json:%q`\n", f.GoName, f.Comment, f.GoName, f.Type, f.JSON)
+	}
+	for _, l := range regions {
+		p("\t// %s %s\n\t%s %s `json:%q`\n", l.GoName, l.Comment, l.GoName, l.Type, l.JSON)
+	}
+	p("}\n\n")
+
+	for _, l := range regions {
+		p("// %s 列表 %q 的行数据\ntype %s struct {\n", l.RowType, l.JSON, l.RowType)
+		for _, f := range l.Fields {
+			p("\t// %s %s\n\t%s %s `json:%q`\n", f.GoName, f.Comment, f.GoName, f.Type, f.JSON)
+		}
+		p("}\n\n")
+		p("// Data 转换为行数据\nfunc (r %s) Data() map[string]any {\n\treturn map[string]any{\n", l.RowType)
+		for _, f := range l.Fields {
+			p("\t\t%q: r.%s,\n", f.JSON, f.GoName)
+		}
+		p("\t}\n}\n\n")
+	}
+
+	if k == kindWord {
+		// Word 的循环表格数据与单值数据位于同一个 data 中
+		p("// Data 转换为模板渲染数据\nfunc (p Params) Data() map[string]any {\n\tdata := map[string]any{\n")
+		for _, f := range scalars {
+			p("\t\t%q: p.%s,\n", f.JSON, f.GoName)
+		}
+		p("\t}\n")
+		for _, l := range regions {
+			p("\tdata[%q] = rows%s(p.%s)\n", l.JSON, l.RowType, l.GoName)
+		}
+		p("\treturn data\n}\n\n")
+		p("// Render 使用参数渲染模板，返回 Word 文档字节数组\n")
+		p("func Render(c *docgen.Client, p Params) ([]byte, error) {\n\treturn c.GenerateWord(TemplateName, p.Data(), \"\")\n}\n\n")
+	} else {
+		p("// Data 转换为单值变量数据\nfunc (p Params) Data() map[string]any {\n\treturn map[string]any{\n")
+		for _, f := range scalars {
+			p("\t\t%q: p.%s,\n", f.JSON, f.GoName)
+		}
+		p("\t}\n}\n\n")
+		p("// ListData 转换为列表数据\nfunc (p Params) ListData() map[string][]map[string]any {\n\treturn map[string][]map[string]any{\n")
+		for _, l := range regions {
+			p("\t\t%q: rows%s(p.%s),\n", l.JSON, l.RowType, l.GoName)
+		}
+		p("\t}\n}\n\n")
+		p("// Render 使用参数填充模板，返回 Excel 文档字节数组\n")
+		p("func Render(c *docgen.Client, p Params) ([]byte, error) {\n\treturn c.FillExcelTemplate(TemplateName, p.Data(), p.ListData(), \"\")\n}\n\n")
+	}
+
+	for _, l := range regions {
+		p("func rows%s(rows []%s) []map[string]any {\n", l.RowType, l.RowType)
+		p("\tresult := make([]map[string]any, 0, len(rows))\n\tfor _, r := range rows {\n\t\tresult = append(result, r.Data())\n\t}\n\treturn result\n}\n\n")
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// reservedNames 生成的 Params 与行结构体的方法名，字段不能与之同名
+func reservedNames() map[string]bool {
+	return map[string]bool{"Data": true, "ListData": true}
+}
+
+// sortedVariables 按名称排序并去重
+func sortedVariables(vars []docgen.TemplateVariable) []docgen.TemplateVariable {
+	sorted := append([]docgen.TemplateVariable(nil), vars...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	result := sorted[:0]
+	for i, v := range sorted {
+		if i > 0 && v.Name == sorted[i-1].Name {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// newField 根据占位符创建字段
+func newField(v docgen.TemplateVariable, used map[string]bool) field {
+	comment := fmt.Sprintf("占位符 %q", v.Name)
+	if v.Required {
+		comment += "（必填）"
+	}
+	return field{
+		GoName:  uniqueName(goName(v.Name), used),
+		JSON:    v.Name,
+		Type:    goType(v.Type),
+		Comment: comment,
+	}
+}
+
+// goType 将占位符类型映射为 Go 类型
+func goType(t string) string {
+	switch strings.ToLower(t) {
+	case "", "text", "string", "date", "image":
+		return "string"
+	case "number":
+		return "float64"
+	case "integer", "int":
+		return "int64"
+	case "boolean", "bool":
+		return "bool"
+	default:
+		return "any"
+	}
+}
+
+// goName 将占位符名称转换为导出的 Go 标识符（如 "order_no" -> "OrderNo"）
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	id := b.String()
+	// 首字符不是大写字母（数字、中文等）时无法导出，添加前缀
+	if first := []rune(id); len(first) == 0 || !unicode.IsUpper(first[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// packageName 根据模板文件名生成包名（如 "tenantA/Invoice-2025.docx" -> "invoice2025"）
+func packageName(templateName string) string {
+	base := strings.TrimSuffix(path.Base(strings.ReplaceAll(templateName, "\\", "/")), path.Ext(templateName))
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || token.IsKeyword(name) {
+		name = "tmpl" + name
+	}
+	return name
+}
+
+// uniqueName 在 used 中登记名称，重复时追加数字后缀
+func uniqueName(name string, used map[string]bool) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	used[candidate] = true
+	return candidate
+}
@@ -0,0 +1,89 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// testSchemas 含有与生成的方法同名的占位符
+var testSchemas = []docgen.TemplateSchema{
+	{
+		TemplateName: "contract.docx",
+		Variables: []docgen.TemplateVariable{
+			{Name: "data"}, {Name: "listData"}, {Name: "Data", Type: "number"}, {Name: "客户名称", Required: true},
+		},
+		Lists: []docgen.TemplateList{
+			{Name: "items", Fields: []docgen.TemplateVariable{{Name: "data"}, {Name: "amount", Type: "integer"}}},
+		},
+	},
+	{
+		TemplateName: "report.xlsx",
+		Variables:    []docgen.TemplateVariable{{Name: "data"}, {Name: "list_data", Type: "boolean"}},
+		Lists: []docgen.TemplateList{
+			{Name: "listData", Fields: []docgen.TemplateVariable{{Name: "data"}, {Name: "ListData"}}},
+		},
+	},
+}
+
+func TestGeneratedCodeCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a temporary module")
+	}
+	goTool := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goTool); err != nil {
+		t.Skipf("go tool not available: %v", err)
+	}
+	files, err := GenerateFromSchemas(testSchemas, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sdk, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	mod := "module example.com/generated\n\ngo 1.20\n\nrequire github.com/Mars-Sea/doc-gen-service/sdk/go v0.0.0\n\nreplace github.com/Mars-Sea/doc-gen-service/sdk/go => " + sdk + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goTool, "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		for _, f := range files {
+			t.Logf("%s:\n%s", f.Path, f.Content)
+		}
+		t.Fatalf("generated code does not compile: %v\n%s", err, out)
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	first, err := GenerateFromSchemas(testSchemas, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reversed := []docgen.TemplateSchema{testSchemas[1], testSchemas[0]}
+	second, err := GenerateFromSchemas(reversed, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != len(second) {
+		t.Fatalf("got %d and %d files", len(first), len(second))
+	}
+	for i := range first {
+		if first[i].Path != second[i].Path || !bytes.Equal(first[i].Content, second[i].Content) {
+			t.Errorf("file %s differs between runs", first[i].Path)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package docgen
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrUnsafeEntryName 压缩包条目名称为绝对路径或包含 ".."，解压到目录时可能写到目录之外（zip slip）
+var ErrUnsafeEntryName = errors.New("docgen: unsafe archive entry name")
+
+// ErrCorruptEntry 压缩包条目无法读取或校验和不一致
+var ErrCorruptEntry = errors.New("docgen: corrupt archive entry")
+
+// ArchiveOptions Documents 的选项
+type ArchiveOptions struct {
+	// SkipCorrupt 跳过无法读取、校验和不一致或名称不安全的条目并记录在 ArchiveReport.Corrupt 中，
+	// 默认遇到第一个这样的条目即返回错误
+	SkipCorrupt bool
+	// RawNames 只拒绝不安全的路径（zip slip），不以 SanitizeFileName 清理各级名称
+	RawNames bool
+}
+
+// CorruptEntry 被跳过的压缩包条目
+type CorruptEntry struct {
+	// Name 条目在压缩包中的原始名称
+	Name string
+	// Err 跳过的原因，errors.Is 匹配 ErrCorruptEntry 或 ErrUnsafeEntryName
+	Err error
+}
+
+// ArchiveReport Documents 的结果
+type ArchiveReport struct {
+	// Documents 交给回调处理的条目数
+	Documents int
+	// Corrupt 按 ArchiveOptions.SkipCorrupt 跳过的条目，按压缩包中的顺序
+	Corrupt []CorruptEntry
+}
+
+// DocumentFunc 处理压缩包中的一个文档
+//
+// name 为清理后的相对路径（使用 "/" 分隔，不含 ".."，各级名称经 SanitizeFileName 清理）；r 只在回调返回前有效，
+// 之后的读取返回错误，size 为解压后的大小。
+// 返回错误时停止遍历，Documents 原样返回该错误
+type DocumentFunc func(name string, r io.Reader, size int64) error
+
+// Documents 按顺序遍历 ZIP 压缩包结果（WordBatchRequest.Archive）中的文档
+//
+// 每次只解压一个条目并以流的方式交给 fn，不在内存中保存完整的条目；目录条目被忽略
+func (r *DocumentResult) Documents(opts ArchiveOptions, fn DocumentFunc) (*ArchiveReport, error) {
+	return readArchive(bytes.NewReader(r.Data), int64(len(r.Data)), opts, fn)
+}
+
+// Documents 按顺序遍历 ZIP 压缩包结果中的文档，行为与 DocumentResult.Documents 一致
+//
+// 暂存在磁盘上的结果直接从暂存文件读取，内存占用与压缩包大小无关
+func (r *SpooledResult) Documents(opts ArchiveOptions, fn DocumentFunc) (*ArchiveReport, error) {
+	f, err := r.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readArchive(f.(io.ReaderAt), r.size, opts, fn)
+}
+
+// BatchGenerateWordArchive 以压缩包模式批量生成 Word 文档，并逐个将文档交给 fn
+//
+// 设置 req.Archive 后将响应流式写入暂存文件（ZIP 的目录位于末尾，需先完整接收），再以 Documents 遍历；
+// 无论压缩包大小与是否启用 WithResultSpooling 都暂存到磁盘（目录取 WithResultSpooling 的 dir，否则为系统临时目录），
+// 不在内存中保存完整的压缩包，暂存文件在返回前删除。需服务端支持 FeatureBatchArchive，
+// 服务端返回合并后的 Word 文档时返回 *UnsupportedFeatureError
+func (c *Client) BatchGenerateWordArchive(ctx context.Context, req WordBatchRequest, opts ArchiveOptions, fn DocumentFunc) (*ArchiveReport, error) {
+	req.Archive = true
+	cfg := &spoolConfig{}
+	if c.spool != nil {
+		cfg.dir = c.spool.dir
+	}
+	result, err := spoolTo(cfg, func(w io.Writer) (*DocumentMeta, error) {
+		return c.BatchGenerateWordTo(ctx, req, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+	if strings.HasPrefix(result.Meta.ContentType, "application/vnd.openxmlformats") {
+		// 不识别 archive 字段的旧版服务返回合并后的文档
+		return nil, &UnsupportedFeatureError{Feature: FeatureBatchArchive}
+	}
+	return result.Documents(opts, fn)
+}
+
+// readArchive 遍历压缩包条目
+func readArchive(ra io.ReaderAt, size int64, opts ArchiveOptions, fn DocumentFunc) (*ArchiveReport, error) {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid zip archive: %w", ErrCorruptEntry, err)
+	}
+	report := &ArchiveReport{}
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		err := readEntry(f, opts, fn)
+		var entryErr *entryError
+		if !errors.As(err, &entryErr) {
+			if err != nil {
+				return report, err
+			}
+			report.Documents++
+			continue
+		}
+		if !opts.SkipCorrupt {
+			return report, entryErr.err
+		}
+		report.Corrupt = append(report.Corrupt, CorruptEntry{Name: f.Name, Err: entryErr.err})
+	}
+	return report, nil
+}
+
+// entryError 条目本身的问题（名称不安全或内容损坏），与回调返回的错误区分
+type entryError struct {
+	err error
+}
+
+// Error 实现 error 接口
+func (e *entryError) Error() string {
+	return e.err.Error()
+}
+
+// readEntry 打开条目并交给 fn，之后读完剩余内容以校验 CRC
+func readEntry(f *zip.File, opts ArchiveOptions, fn DocumentFunc) error {
+	name, ok := safeEntryName(f.Name, !opts.RawNames)
+	if !ok {
+		return &entryError{fmt.Errorf("%w: %q", ErrUnsafeEntryName, f.Name)}
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return &entryError{fmt.Errorf("%w: %s: %w", ErrCorruptEntry, name, err)}
+	}
+	defer rc.Close()
+
+	r := &entryReader{r: rc}
+	defer r.invalidate()
+	if err := fn(name, r, int64(f.UncompressedSize64)); err != nil {
+		if r.err != nil {
+			return &entryError{fmt.Errorf("%w: %s: %w", ErrCorruptEntry, name, r.err)}
+		}
+		return err
+	}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return &entryError{fmt.Errorf("%w: %s: %w", ErrCorruptEntry, name, err)}
+	}
+	return nil
+}
+
+// errEntryReaderExpired 回调返回后读取条目
+var errEntryReaderExpired = errors.New("docgen: archive entry read after the DocumentFunc returned")
+
+// entryReader 记录读取条目时的错误，以便区分损坏的条目与回调自身的错误；回调返回后失效
+type entryReader struct {
+	r       io.Reader
+	err     error
+	expired bool
+}
+
+// invalidate 使之后的读取返回 errEntryReaderExpired，回调保留的 reader 不会读到下一个条目或已关闭的条目
+func (r *entryReader) invalidate() {
+	r.expired = true
+}
+
+// Read 实现 io.Reader 接口
+func (r *entryReader) Read(p []byte) (int, error) {
+	if r.expired {
+		return 0, errEntryReaderExpired
+	}
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// safeEntryName 清理条目名称，拒绝绝对路径、盘符与指向上级目录的名称；sanitize 时各级名称再经 SanitizeFileName 清理
+func safeEntryName(name string, sanitize bool) (string, bool) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if name == "" || strings.HasPrefix(name, "/") || (len(name) >= 2 && name[1] == ':') {
+		return "", false
+	}
+	cleaned := path.Clean(name)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false
+	}
+	if !sanitize {
+		return cleaned, true
+	}
+	segments := strings.Split(cleaned, "/")
+	for i, s := range segments {
+		if segments[i] = SanitizeFileName(s, SanitizeOptions{}); segments[i] == "" {
+			return "", false
+		}
+	}
+	return strings.Join(segments, "/"), true
+}
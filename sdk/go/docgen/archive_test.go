@@ -0,0 +1,224 @@
+package docgen_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+func TestBatchGenerateWordArchiveSpoolsToDisk(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	req := docgen.WordBatchRequest{TemplateName: "t.docx", DataList: []map[string]any{{"a": 1}, {"a": 2}}}
+
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	spoolDir := t.TempDir()
+	clients := map[string]struct {
+		client *docgen.Client
+		dir    string
+	}{
+		"without spooling": {docgen.NewClient(srv.URL), tmp},
+		// 阈值大于压缩包时同样暂存到磁盘
+		"large threshold": {docgen.NewClient(srv.URL, docgen.WithResultSpooling(spoolDir, 1<<30)), spoolDir},
+	}
+	for name, tc := range clients {
+		t.Run(name, func(t *testing.T) {
+			var names []string
+			report, err := tc.client.BatchGenerateWordArchive(context.Background(), req, docgen.ArchiveOptions{}, func(name string, r io.Reader, size int64) error {
+				if spooled := spoolFiles(t, tc.dir); len(spooled) != 1 {
+					t.Errorf("spool files during iteration: %v", spooled)
+				}
+				names = append(names, name)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Documents != 2 || len(names) != 2 {
+				t.Errorf("report = %+v, names = %v", report, names)
+			}
+			if left := spoolFiles(t, tc.dir); len(left) != 0 {
+				t.Errorf("spool files left behind: %v", left)
+			}
+		})
+	}
+}
+
+func spoolFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "docgen-spool-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// TestBatchGenerateWordArchiveLarge 500 个条目的压缩包：按顺序逐个交给回调，各条目（数 MiB）不同时保留在内存中；
+// 回调保留的 reader 在回调返回后失效
+func TestBatchGenerateWordArchiveLarge(t *testing.T) {
+	const entries, blobSize = 500, 8 << 10
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{blob}}"))
+	// 随机内容使每个条目（本身是压缩过的 .docx）都有数 KiB
+	raw := make([]byte, blobSize)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatal(err)
+	}
+	blob := base64.StdEncoding.EncodeToString(raw)
+	req := docgen.WordBatchRequest{TemplateName: "t.docx", FileName: "letter", DataList: make([]map[string]any, entries)}
+	for i := range req.DataList {
+		req.DataList[i] = map[string]any{"n": i, "blob": blob}
+	}
+	client := docgen.NewClient(srv.URL)
+
+	var (
+		names    []string
+		total    int64
+		baseline uint64
+		peak     uint64
+		previous io.Reader
+	)
+	heap := func() uint64 {
+		runtime.GC()
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		return m.HeapAlloc
+	}
+	report, err := client.BatchGenerateWordArchive(context.Background(), req, docgen.ArchiveOptions{}, func(name string, r io.Reader, size int64) error {
+		if previous != nil {
+			if n, err := previous.Read(make([]byte, 16)); n != 0 || err == nil || err == io.EOF {
+				t.Fatalf("%s: previous entry's reader returned %d, %v after its callback, want an error", name, n, err)
+			}
+		}
+		previous = r
+		n, err := io.Copy(io.Discard, r)
+		if err != nil {
+			return err
+		}
+		if n != size {
+			t.Errorf("%s: read %d bytes, size %d", name, n, size)
+		}
+		total += n
+		names = append(names, name)
+		switch i := len(names) - 1; {
+		case i == 0:
+			baseline = heap()
+		case i%50 == 0:
+			if h := heap(); h > peak {
+				peak = h
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Documents != entries || len(names) != entries {
+		t.Fatalf("report = %+v, %d callbacks, want %d documents", report, len(names), entries)
+	}
+	for i, name := range names {
+		if want := fmt.Sprintf("letter_%d.docx", i+1); name != want {
+			t.Fatalf("entry %d = %q, want %q", i, name, want)
+		}
+	}
+	if total < entries*blobSize/2 {
+		t.Fatalf("decompressed %d bytes, want at least %d", total, entries*blobSize/2)
+	}
+	if peak > baseline && peak-baseline > uint64(total)/4 {
+		t.Errorf("heap grew by %d bytes while iterating %d bytes of entries", peak-baseline, total)
+	}
+}
+
+// corruptArchive 三个条目的压缩包，第二个条目的内容被改写（CRC 校验失败），另有一个名称不安全的条目
+func corruptArchive(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range []struct{ name, content string }{
+		{"a.docx", "first document"},
+		{"b.docx", "second document"},
+		{"../evil.docx", "outside"},
+		{"c.docx", "third document"},
+	} {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, e.content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	i := bytes.Index(data, []byte("second document"))
+	if i < 0 {
+		t.Fatal("entry content not found")
+	}
+	data[i] ^= 0xff
+	return data
+}
+
+func TestArchiveCorruptEntries(t *testing.T) {
+	result := &docgen.DocumentResult{Data: corruptArchive(t)}
+	read := func(opts docgen.ArchiveOptions) ([]string, *docgen.ArchiveReport, error) {
+		var names []string
+		report, err := result.Documents(opts, func(name string, r io.Reader, size int64) error {
+			if _, err := io.Copy(io.Discard, r); err != nil {
+				return err
+			}
+			names = append(names, name)
+			return nil
+		})
+		return names, report, err
+	}
+
+	// 默认遇到损坏的条目即停止
+	names, report, err := read(docgen.ArchiveOptions{})
+	if !errors.Is(err, docgen.ErrCorruptEntry) {
+		t.Fatalf("err = %v, want ErrCorruptEntry", err)
+	}
+	if fmt.Sprint(names) != "[a.docx]" || report.Documents != 1 {
+		t.Errorf("names = %v, report = %+v, want only the entry before the corrupt one", names, report)
+	}
+
+	// SkipCorrupt 跳过损坏与名称不安全的条目，其余条目照常处理
+	names, report, err = read(docgen.ArchiveOptions{SkipCorrupt: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(names) != "[a.docx c.docx]" || report.Documents != 2 {
+		t.Errorf("names = %v, report = %+v", names, report)
+	}
+	if len(report.Corrupt) != 2 {
+		t.Fatalf("Corrupt = %+v, want two skipped entries", report.Corrupt)
+	}
+	if c := report.Corrupt[0]; c.Name != "b.docx" || !errors.Is(c.Err, docgen.ErrCorruptEntry) {
+		t.Errorf("Corrupt[0] = %+v, want b.docx with ErrCorruptEntry", c)
+	}
+	if c := report.Corrupt[1]; c.Name != "../evil.docx" || !errors.Is(c.Err, docgen.ErrUnsafeEntryName) {
+		t.Errorf("Corrupt[1] = %+v, want ../evil.docx with ErrUnsafeEntryName", c)
+	}
+
+	// 回调的错误原样返回，不记为损坏的条目
+	stop := errors.New("stop")
+	_, err = result.Documents(docgen.ArchiveOptions{SkipCorrupt: true}, func(name string, r io.Reader, size int64) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("err = %v, want the callback's error", err)
+	}
+}
@@ -0,0 +1,145 @@
+package docgen
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// AssemblySpec 片段组装请求：按顺序渲染多个片段模板并拼接为一个 Word 文档
+type AssemblySpec struct {
+	// Fragments 按顺序拼接的片段
+	Fragments []Fragment `json:"fragments"`
+	// Data 所有片段共享的渲染数据
+	Data map[string]any `json:"data,omitempty"`
+	// Join 片段之间的拼接方式
+	Join JoinOptions `json:"join"`
+	// FileName 输出文件名（不含扩展名，可选）
+	FileName string `json:"fileName,omitempty"`
+	// Deterministic 相同输入生成逐字节相同的文档，规则与 WordGenRequest.Deterministic 相同
+	Deterministic bool `json:"deterministic,omitempty"`
+	// SimpleRender 简化渲染，规则与 WordGenRequest.SimpleRender 相同
+	SimpleRender bool `json:"simpleRender,omitempty"`
+}
+
+// Fragment 组装文档中的一个片段
+type Fragment struct {
+	// TemplateName 片段模板文件名（需包含扩展名）
+	TemplateName string `json:"templateName"`
+	// Data 仅用于此片段的数据（可选），与 AssemblySpec.Data 同名的键以此为准
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// JoinOptions 片段的拼接方式
+type JoinOptions struct {
+	// PageBreaks 片段之间插入分页符，默认直接衔接
+	PageBreaks bool `json:"pageBreaks,omitempty"`
+	// ContinueNumbering 编号列表跨片段连续编号，默认每个片段重新开始
+	ContinueNumbering bool `json:"continueNumbering,omitempty"`
+}
+
+// FragmentNotFoundError 组装请求引用的片段模板不存在
+//
+// errors.Is(err, ErrTemplateNotFound) 返回 true
+type FragmentNotFoundError struct {
+	// Names 不存在的片段模板，按在 Fragments 中首次出现的顺序
+	Names []string
+}
+
+// Error 实现 error 接口
+func (e *FragmentNotFoundError) Error() string {
+	return fmt.Sprintf("%v: fragments %s", ErrTemplateNotFound, strings.Join(e.Names, ", "))
+}
+
+// Is 使 errors.Is(err, ErrTemplateNotFound) 成立
+func (e *FragmentNotFoundError) Is(target error) bool {
+	return target == ErrTemplateNotFound
+}
+
+// AssembleDocument 由片段模板组装 Word 文档
+//
+// 发送前以一次模板列表查询校验所有片段均已存在，缺失时返回 *FragmentNotFoundError；需服务端支持 FeatureAssembly
+func (c *Client) AssembleDocument(spec AssemblySpec) ([]byte, error) {
+	return c.AssembleDocumentContext(context.Background(), spec)
+}
+
+// AssembleDocumentContext 支持 context 的 AssembleDocument
+func (c *Client) AssembleDocumentContext(ctx context.Context, spec AssemblySpec) ([]byte, error) {
+	return documentData(c.AssembleDocumentWithMeta(ctx, spec))
+}
+
+// AssembleDocumentWithMeta 由片段模板组装 Word 文档，同时返回元数据；各片段的渲染警告合并在 Meta.Warnings 中
+func (c *Client) AssembleDocumentWithMeta(ctx context.Context, spec AssemblySpec) (*DocumentResult, error) {
+	ctx = withCallCorrelationID(ctx)
+	if err := c.prepareAssembly(ctx, &spec); err != nil {
+		return nil, err
+	}
+	return c.postDocument(ctx, "/api/v1/doc/assemble", spec)
+}
+
+// prepareAssembly 校验片段，转换数据并补全租户模板名称；spec 的切片与 map 会被替换而非修改
+func (c *Client) prepareAssembly(ctx context.Context, spec *AssemblySpec) error {
+	if len(spec.Fragments) == 0 {
+		return fmt.Errorf("docgen: assembly needs at least one fragment")
+	}
+	for i, f := range spec.Fragments {
+		if f.TemplateName == "" {
+			return fmt.Errorf("docgen: fragments[%d]: empty templateName", i)
+		}
+		if err := c.checkTemplateName(f.TemplateName); err != nil {
+			return err
+		}
+	}
+	if err := c.requireFeature(ctx, FeatureAssembly); err != nil {
+		return err
+	}
+	if err := c.checkFragmentsExist(ctx, spec.Fragments); err != nil {
+		return err
+	}
+
+	data, err := c.transformData("data", spec.Data)
+	if err != nil {
+		return err
+	}
+	spec.Data = data
+	if err := c.checkSections(ctx, "data", spec.Data); err != nil {
+		return err
+	}
+	fragments := make([]Fragment, len(spec.Fragments))
+	for i, f := range spec.Fragments {
+		path := fmt.Sprintf("fragments[%d].data", i)
+		if f.Data, err = c.transformData(path, f.Data); err != nil {
+			return err
+		}
+		if err := c.checkSections(ctx, path, f.Data); err != nil {
+			return err
+		}
+		f.TemplateName = c.qualifyTemplate(ctx, f.TemplateName)
+		fragments[i] = f
+	}
+	spec.Fragments = fragments
+	return nil
+}
+
+// checkFragmentsExist 以一次模板列表查询校验片段模板均已存在，一并报告所有缺失的片段
+func (c *Client) checkFragmentsExist(ctx context.Context, fragments []Fragment) error {
+	infos, err := c.ListTemplateInfosContext(ctx)
+	if err != nil {
+		return fmt.Errorf("docgen: list templates: %w", err)
+	}
+	known := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		known[info.Name] = true
+	}
+	var missing []string
+	for _, f := range fragments {
+		if !known[f.TemplateName] {
+			missing = append(missing, f.TemplateName)
+			known[f.TemplateName] = true
+		}
+	}
+	if len(missing) > 0 {
+		return &FragmentNotFoundError{Names: missing}
+	}
+	return nil
+}
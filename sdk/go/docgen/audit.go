@@ -0,0 +1,97 @@
+package docgen
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// 审计信息请求头，值经过百分号编码（与 url.PathEscape 一致），服务端需解码
+const (
+	AuditUserIDHeader    = "X-Audit-User-Id"
+	AuditOperationHeader = "X-Audit-Operation"
+	AuditReasonHeader    = "X-Audit-Reason"
+)
+
+// AuditInfo 请求的审计信息，用于将每次生成归属到具体用户与业务操作
+type AuditInfo struct {
+	// UserID 发起操作的终端用户
+	UserID string
+	// Operation 业务操作，如 "contract.export"
+	Operation string
+	// Reason 操作原因（可选）
+	Reason string
+}
+
+// auditContextKey AuditInfo 在 context 中的键
+type auditContextKey struct{}
+
+// WithAudit 返回携带审计信息的 context，用于覆盖 WithAuditContext 提供的值
+//
+// 只覆盖 info 中的非空字段，如：
+//
+//	ctx = docgen.WithAudit(ctx, docgen.AuditInfo{Reason: "customer request #123"})
+//	doc, err := client.GenerateWordContext(ctx, req)
+func WithAudit(ctx context.Context, info AuditInfo) context.Context {
+	if prev, ok := ctx.Value(auditContextKey{}).(AuditInfo); ok {
+		info = prev.merge(info)
+	}
+	return context.WithValue(ctx, auditContextKey{}, info)
+}
+
+// AuditFromContext 读取 WithAudit 放入 context 的审计信息
+func AuditFromContext(ctx context.Context) (AuditInfo, bool) {
+	info, ok := ctx.Value(auditContextKey{}).(AuditInfo)
+	return info, ok
+}
+
+// ParseAuditHeaders 从请求头解析审计信息，供服务端实现与测试替身使用
+func ParseAuditHeaders(h http.Header) AuditInfo {
+	return AuditInfo{
+		UserID:    unescapeAuditValue(h.Get(AuditUserIDHeader)),
+		Operation: unescapeAuditValue(h.Get(AuditOperationHeader)),
+		Reason:    unescapeAuditValue(h.Get(AuditReasonHeader)),
+	}
+}
+
+// merge 用 override 中的非空字段覆盖 a
+func (a AuditInfo) merge(override AuditInfo) AuditInfo {
+	if override.UserID != "" {
+		a.UserID = override.UserID
+	}
+	if override.Operation != "" {
+		a.Operation = override.Operation
+	}
+	if override.Reason != "" {
+		a.Reason = override.Reason
+	}
+	return a
+}
+
+// setAuditHeaders 合并 WithAuditContext 与 context 中的审计信息并写入请求头
+func (c *Client) setAuditHeaders(req *http.Request) {
+	var info AuditInfo
+	if c.auditFunc != nil {
+		info = c.auditFunc(req.Context())
+	}
+	if override, ok := AuditFromContext(req.Context()); ok {
+		info = info.merge(override)
+	}
+	for header, value := range map[string]string{
+		AuditUserIDHeader:    info.UserID,
+		AuditOperationHeader: info.Operation,
+		AuditReasonHeader:    info.Reason,
+	} {
+		if value != "" {
+			req.Header.Set(header, url.PathEscape(value))
+		}
+	}
+}
+
+// unescapeAuditValue 解码审计请求头，无法解码时返回原值
+func unescapeAuditValue(v string) string {
+	if decoded, err := url.PathUnescape(v); err == nil {
+		return decoded
+	}
+	return v
+}
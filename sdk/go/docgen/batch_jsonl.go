@@ -0,0 +1,419 @@
+package docgen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// BatchRunOptions RunBatchFromJSONL 的配置
+type BatchRunOptions struct {
+	// Concurrency 并发渲染数，默认 4
+	Concurrency int
+	// FileNameField 用于命名输出文件的记录字段（如 "id"），字段值经 SanitizeFileName 清理（WithRawFileNames 时不清理），
+	// 为空、记录中缺少该字段或清理后为空时使用 "record-<行号>"
+	FileNameField string
+	// Validate 自定义记录校验，返回错误的记录不会提交渲染，并写入 failures.jsonl
+	Validate func(record map[string]any) error
+	// NoResume 为 true 时忽略已有输出，全部重新渲染
+	NoResume bool
+}
+
+// BatchReport 批量渲染结果报告
+type BatchReport struct {
+	// Total 读取的记录数（不含空行）
+	Total int64 `json:"total"`
+	// Succeeded 渲染成功数
+	Succeeded int64 `json:"succeeded"`
+	// Skipped 因输出已存在且校验和一致而跳过的数量
+	Skipped int64 `json:"skipped"`
+	// Failed 失败数（校验失败或渲染失败）
+	Failed int64 `json:"failed"`
+	// FailuresPath 失败记录文件路径（无失败时为空）
+	FailuresPath string `json:"failuresPath,omitempty"`
+	// Duration 总耗时
+	Duration time.Duration `json:"duration"`
+}
+
+// batchManifestName 断点续跑清单文件名，记录每个输出的校验和
+const batchManifestName = ".docgen-batch.jsonl"
+
+// batchFailuresName 失败记录文件名
+const batchFailuresName = "failures.jsonl"
+
+// batchManifestEntry 清单条目
+type batchManifestEntry struct {
+	File        string `json:"file"`
+	SHA256      string `json:"sha256"`
+	RequestHash string `json:"requestHash"`
+}
+
+// batchFailure 失败记录
+type batchFailure struct {
+	Line   int64           `json:"line"`
+	Record json.RawMessage `json:"record,omitempty"`
+	Raw    string          `json:"raw,omitempty"`
+	Error  string          `json:"error"`
+}
+
+// batchJob 待渲染记录
+type batchJob struct {
+	line   int64
+	raw    []byte
+	record map[string]any
+	file   string
+	// hash 记录对应生成请求的 RequestHash
+	hash string
+}
+
+// RunBatchFromJSONL 逐行读取 JSONL 文件，每条记录渲染一个文档
+//
+// templateName: 模板文件名（.docx / .docm 使用 GenerateWord，.xlsx / .xlsm 使用 FillExcelTemplate 并将记录作为单值数据；
+// 启用宏的模板输出文件的扩展名按 WithMacroOutput 决定）
+// r: JSONL 输入，每行一个 JSON 对象，空行忽略
+// outDir: 输出目录，失败记录写入 outDir/failures.jsonl
+//
+// 输入以流式方式读取，渲染并发数受 Concurrency 限制。默认支持断点续跑：
+// 输出文件已存在、且与清单中记录的文件校验和及生成请求的 RequestHash 一致时跳过（只改动键顺序或数值写法的记录不会重新渲染）。
+// 单条记录失败不会中止整体运行；ctx 取消时停止读取并返回已完成部分的报告和 ctx.Err()
+func RunBatchFromJSONL(ctx context.Context, c *Client, templateName string, r io.Reader, outDir string, opts BatchRunOptions) (*BatchReport, error) {
+	start := time.Now()
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if err := legacyFormat(templateName); err != nil {
+		return nil, err
+	}
+	format := templateFormat(templateName)
+	if format == "" {
+		return nil, fmt.Errorf("unsupported template extension %q (want .docx, .docm, .xlsx or .xlsm)", filepath.Ext(templateName))
+	}
+	ext := "." + string(c.macroOutputFormat(format))
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	run := &batchRun{
+		ctx:          ctx,
+		client:       c,
+		templateName: templateName,
+		ext:          ext,
+		outDir:       outDir,
+		opts:         opts,
+		report:       &BatchReport{},
+		usedNames:    make(map[string]int64),
+	}
+	if !opts.NoResume {
+		if err := run.loadManifest(); err != nil {
+			return nil, err
+		}
+	}
+	if err := run.openOutputs(); err != nil {
+		return nil, err
+	}
+	defer run.closeOutputs()
+
+	jobs := make(chan batchJob)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				run.render(job)
+			}
+		}()
+	}
+
+	readErr := run.read(r, jobs)
+	close(jobs)
+	wg.Wait()
+
+	run.report.Duration = time.Since(start)
+	if run.report.Failed > 0 {
+		run.report.FailuresPath = filepath.Join(outDir, batchFailuresName)
+	}
+	if readErr != nil {
+		return run.report, readErr
+	}
+	if err := run.writeErr; err != nil {
+		return run.report, err
+	}
+	return run.report, ctx.Err()
+}
+
+// batchRun 单次批量渲染的运行状态
+type batchRun struct {
+	ctx          context.Context
+	client       *Client
+	templateName string
+	ext          string
+	outDir       string
+	opts         BatchRunOptions
+
+	mu        sync.Mutex
+	report    *BatchReport
+	manifest  map[string]batchManifestEntry
+	usedNames map[string]int64
+	failures  *os.File
+	manifestW *os.File
+	writeErr  error
+}
+
+// read 逐行读取输入并投递任务，读取阶段即可判定的失败（JSON 非法、校验失败）直接记录
+func (b *batchRun) read(r io.Reader, jobs chan<- batchJob) error {
+	br := bufio.NewReader(r)
+	var line int64
+	for {
+		raw, err := br.ReadBytes('\n')
+		if len(raw) > 0 {
+			line++
+			if job, ok := b.prepare(line, raw); ok {
+				select {
+				case jobs <- job:
+				case <-b.ctx.Done():
+					return nil
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input at line %d: %w", line+1, err)
+		}
+		if b.ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// prepare 解析并校验记录，确定输出文件名，返回是否需要渲染
+func (b *batchRun) prepare(line int64, raw []byte) (batchJob, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return batchJob{}, false
+	}
+
+	b.mu.Lock()
+	b.report.Total++
+	b.mu.Unlock()
+
+	var record map[string]any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&record); err != nil || record == nil {
+		if err == nil {
+			err = errors.New("record must be a JSON object")
+		}
+		b.fail(line, raw, fmt.Errorf("invalid JSON: %w", err))
+		return batchJob{}, false
+	}
+	if b.opts.Validate != nil {
+		if err := b.opts.Validate(record); err != nil {
+			b.fail(line, raw, fmt.Errorf("validation failed: %w", err))
+			return batchJob{}, false
+		}
+	}
+
+	job := batchJob{line: line, raw: raw, record: record, file: b.outputName(line, record)}
+	hash, err := RequestHash(b.request(job))
+	if err != nil {
+		b.fail(line, raw, err)
+		return batchJob{}, false
+	}
+	job.hash = hash
+	if b.alreadyDone(job) {
+		b.mu.Lock()
+		b.report.Skipped++
+		b.mu.Unlock()
+		return batchJob{}, false
+	}
+	return job, true
+}
+
+// outputName 根据 FileNameField 生成输出文件名，重名时追加行号
+func (b *batchRun) outputName(line int64, record map[string]any) string {
+	base := ""
+	if b.opts.FileNameField != "" {
+		if v, ok := record[b.opts.FileNameField]; ok && v != nil {
+			base = b.client.derivedFileName(fmt.Sprint(v), nil)
+		}
+	}
+	if base == "" {
+		base = fmt.Sprintf("record-%d", line)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if first, dup := b.usedNames[base]; dup && first != line {
+		base = fmt.Sprintf("%s-%d", base, line)
+	}
+	b.usedNames[base] = line
+	return base + b.ext
+}
+
+// request 记录对应的生成请求
+func (b *batchRun) request(job batchJob) GenerationRequest {
+	if !isSpreadsheet(templateFormat(b.templateName)) {
+		return GenerationRequest{Word: &WordGenRequest{TemplateName: b.templateName, Data: job.record}}
+	}
+	return GenerationRequest{ExcelFill: &ExcelFillRequest{TemplateName: b.templateName, Data: job.record}}
+}
+
+// alreadyDone 判断输出是否已存在且与清单记录一致（输出文件校验和相同、生成请求的 RequestHash 相同）
+func (b *batchRun) alreadyDone(job batchJob) bool {
+	entry, ok := b.manifest[job.file]
+	if !ok || entry.RequestHash != job.hash {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(b.outDir, job.file))
+	if err != nil {
+		return false
+	}
+	return sha256Hex(data) == entry.SHA256
+}
+
+// render 渲染单条记录并原子写入输出文件
+func (b *batchRun) render(job batchJob) {
+	if b.ctx.Err() != nil {
+		return
+	}
+
+	var (
+		doc []byte
+		err error
+	)
+	req := b.request(job)
+	if req.Word != nil {
+		doc, err = b.client.GenerateWordContext(b.ctx, *req.Word)
+	} else {
+		doc, err = b.client.FillExcelTemplateContext(b.ctx, *req.ExcelFill)
+	}
+	if err != nil {
+		if b.ctx.Err() != nil {
+			// 取消导致的失败不计入失败记录，下次运行会重新渲染
+			return
+		}
+		b.fail(job.line, job.raw, err)
+		return
+	}
+
+	if err := writeFileAtomic(filepath.Join(b.outDir, job.file), doc); err != nil {
+		b.fail(job.line, job.raw, err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.report.Succeeded++
+	b.appendLocked(b.manifestW, batchManifestEntry{File: job.file, SHA256: sha256Hex(doc), RequestHash: job.hash})
+}
+
+// fail 记录失败条目
+func (b *batchRun) fail(line int64, raw []byte, err error) {
+	f := batchFailure{Line: line, Error: err.Error()}
+	if json.Valid(raw) {
+		f.Record = json.RawMessage(raw)
+	} else {
+		f.Raw = string(raw)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.report.Failed++
+	b.appendLocked(b.failures, f)
+}
+
+// appendLocked 向 JSONL 文件追加一行，调用方需持有锁
+func (b *batchRun) appendLocked(f *os.File, v any) {
+	line, err := json.Marshal(v)
+	if err == nil {
+		_, err = f.Write(append(line, '\n'))
+	}
+	if err != nil && b.writeErr == nil {
+		b.writeErr = fmt.Errorf("failed to write %s: %w", f.Name(), err)
+	}
+}
+
+// loadManifest 读取断点续跑清单
+func (b *batchRun) loadManifest() error {
+	b.manifest = make(map[string]batchManifestEntry)
+	data, err := os.ReadFile(filepath.Join(b.outDir, batchManifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read batch manifest: %w", err)
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var entry batchManifestEntry
+		if json.Unmarshal(line, &entry) == nil && entry.File != "" {
+			// 同一文件可能被多次记录，以最后一次为准
+			b.manifest[entry.File] = entry
+		}
+	}
+	return nil
+}
+
+// openOutputs 打开清单（追加）与失败记录（覆盖）文件
+func (b *batchRun) openOutputs() error {
+	var err error
+	b.manifestW, err = os.OpenFile(filepath.Join(b.outDir, batchManifestName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open batch manifest: %w", err)
+	}
+	b.failures, err = os.Create(filepath.Join(b.outDir, batchFailuresName))
+	if err != nil {
+		b.manifestW.Close()
+		return fmt.Errorf("failed to create failures file: %w", err)
+	}
+	return nil
+}
+
+// closeOutputs 关闭输出文件，无失败时删除空的失败记录文件
+func (b *batchRun) closeOutputs() {
+	b.manifestW.Close()
+	b.failures.Close()
+	if b.report.Failed == 0 {
+		os.Remove(b.failures.Name())
+	}
+}
+
+// writeFileAtomic 先写入临时文件再重命名，避免中断时留下不完整的输出
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to rename output: %w", err)
+	}
+	return nil
+}
+
+// sha256Hex 计算 SHA-256 十六进制摘要
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
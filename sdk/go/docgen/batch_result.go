@@ -0,0 +1,67 @@
+package docgen
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// BatchReportHeader 服务端以独立报告返回批量生成失败条目时，在此响应头中给出报告路径
+const BatchReportHeader = "X-Batch-Report"
+
+// ItemFailure 批量生成中未能渲染的条目
+type ItemFailure struct {
+	// Index 条目在 WordBatchRequest.DataList 中的下标
+	Index int `json:"index"`
+	// Error 该条目的错误
+	Error ErrorResponse `json:"error"`
+}
+
+// BatchResult BatchGenerateWordWithResult 的结果
+type BatchResult struct {
+	// Document 由渲染成功的条目生成的文档，条目顺序与 DataList 一致
+	Document []byte
+	// Failures 渲染失败的条目，按 Index 升序；全部成功时为空
+	Failures []ItemFailure
+	// Meta 文档元数据
+	Meta DocumentMeta
+}
+
+// batchReport 失败条目报告（multipart 响应的元数据部分或 BatchReportHeader 指向的报告）
+type batchReport struct {
+	Failures []ItemFailure `json:"failures"`
+}
+
+// BatchGenerateWordWithResult 批量生成 Word 文档，单个条目渲染失败时不中止整个批次
+//
+// 请求以 ContinueOnError 发送，失败的条目不出现在文档中，并在 Failures 中按 DataList 下标报告；
+// 需服务端支持 FeatureBatchPartialFailure，能力接口明确不支持时返回 *UnsupportedFeatureError。
+// 其他批量方法保持全部成功或整体失败的行为
+func (c *Client) BatchGenerateWordWithResult(ctx context.Context, req WordBatchRequest) (*BatchResult, error) {
+	if err := c.rejectUnsupported(ctx, FeatureBatchPartialFailure); err != nil {
+		return nil, err
+	}
+	req.ContinueOnError = true
+	result, err := c.BatchGenerateWordWithMeta(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchResult{Document: result.Data, Failures: result.failures, Meta: result.Meta}, nil
+}
+
+// fetchBatchReport 读取 BatchReportHeader 指向的失败条目报告，响应未携带该头时返回 nil
+func (c *Client) fetchBatchReport(ctx context.Context, resp *http.Response) ([]ItemFailure, error) {
+	path := resp.Header.Get(BatchReportHeader)
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		// 只接受服务端的相对路径，不向其他主机发送认证信息
+		return nil, &ErrorResponse{Status: resp.StatusCode, Message: "invalid " + BatchReportHeader + ": " + path}
+	}
+	var report batchReport
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &report); err != nil {
+		return nil, err
+	}
+	return report.Failures, nil
+}
@@ -0,0 +1,97 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// TestBatchResultPartialFailure 条目 0 与 2 渲染失败时，Failures 按 DataList 下标报告这两个条目，
+// 文档保留条目 1 与 3 的内容，且请求以 continueOnError 发送
+func TestBatchResultPartialFailure(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	client := docgen.NewClient(srv.URL)
+
+	req := docgen.WordBatchRequest{TemplateName: "t.docx", DataList: []map[string]any{
+		{"name": "item-0", "total": docgentest.RenderErrorValue},
+		{"name": "item-1"},
+		{"name": docgentest.RenderErrorValue},
+		{"name": "item-3"},
+	}}
+	result, err := client.BatchGenerateWordWithResult(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docgentest.AssertJSONPath(t, srv.LastRequest(), "continueOnError", true)
+
+	if len(result.Failures) != 2 || result.Failures[0].Index != 0 || result.Failures[1].Index != 2 {
+		t.Fatalf("Failures = %+v, want items 0 and 2", result.Failures)
+	}
+	for i, key := range []string{"total", "name"} {
+		f := result.Failures[i]
+		if f.Error.Code != docgen.CodeRenderError || !errors.Is(&f.Error, docgen.ErrRenderFailed) {
+			t.Errorf("item %d: error %+v, want RENDER_ERROR", f.Index, f.Error)
+		}
+		if want := fmt.Sprintf("dataList[%d].%s", f.Index, key); !strings.Contains(f.Error.Message, want) {
+			t.Errorf("item %d: message %q, want it to name %s", f.Index, f.Error.Message, want)
+		}
+	}
+
+	text, err := docgentest.ExtractDocxText(result.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "item-1") || !strings.Contains(text, "item-3") {
+		t.Errorf("document text %q, want the successful items 1 and 3", text)
+	}
+	if strings.Contains(text, "item-0") || strings.Contains(text, docgentest.RenderErrorValue) {
+		t.Errorf("document text %q contains a failed item", text)
+	}
+	if strings.Index(text, "item-1") > strings.Index(text, "item-3") {
+		t.Errorf("document text %q, want the items in DataList order", text)
+	}
+	if result.Meta.Size != int64(len(result.Document)) || result.Meta.SHA256 == "" {
+		t.Errorf("Meta = %+v, want the size and digest of the document part", result.Meta)
+	}
+}
+
+// TestBatchResultAllFailed 全部条目失败时返回错误，不返回空文档
+func TestBatchResultAllFailed(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	client := docgen.NewClient(srv.URL)
+
+	req := docgen.WordBatchRequest{TemplateName: "t.docx", DataList: []map[string]any{
+		{"name": docgentest.RenderErrorValue},
+		{"name": docgentest.RenderErrorValue},
+	}}
+	result, err := client.BatchGenerateWordWithResult(context.Background(), req)
+	if !errors.Is(err, docgen.ErrRenderFailed) || result != nil {
+		t.Errorf("result %v, err %v; want ErrRenderFailed", result, err)
+	}
+}
+
+// TestBatchResultUnsupported 服务端明确不支持部分失败时返回 *UnsupportedFeatureError，不发送批量请求
+func TestBatchResultUnsupported(t *testing.T) {
+	srv := docgentest.NewServer(docgentest.WithDisabledFeatures(docgen.FeatureBatchPartialFailure))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	client := docgen.NewClient(srv.URL)
+
+	_, err := client.BatchGenerateWordWithResult(context.Background(), docgen.WordBatchRequest{TemplateName: "t.docx", DataList: []map[string]any{{"name": "a"}}})
+	var unsupported *docgen.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Feature != docgen.FeatureBatchPartialFailure {
+		t.Errorf("err = %v, want *UnsupportedFeatureError for %s", err, docgen.FeatureBatchPartialFailure)
+	}
+	if n := len(srv.RequestsTo(docgentest.EndpointWordBatch)); n != 0 {
+		t.Errorf("%d batch requests sent", n)
+	}
+}
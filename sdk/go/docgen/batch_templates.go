@@ -0,0 +1,168 @@
+package docgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// templateRun 使用同一模板的连续批量条目
+type templateRun struct {
+	template string
+	dataList []map[string]any
+}
+
+// generateMixedBatch 生成按条目指定模板的批量文档
+//
+// 服务端支持 FeatureBatchTemplates 时一次请求完成；否则按连续的相同模板拆分为多次批量请求，
+// 再通过 MergeWordDocuments 按输入顺序合并
+func (c *Client) generateMixedBatch(ctx context.Context, req WordBatchRequest) (*DocumentResult, error) {
+	runs, err := c.prepareMixedBatch(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	if runs == nil {
+		return c.postDocument(ctx, "/api/v1/doc/word/batch", req)
+	}
+	return c.mergeBatchRuns(ctx, req, runs)
+}
+
+// generateMixedBatchTo 与 generateMixedBatch 相同，结果写入 w；客户端合并时需先在内存中生成完整文档
+func (c *Client) generateMixedBatchTo(ctx context.Context, req WordBatchRequest, w io.Writer) (*DocumentMeta, error) {
+	runs, err := c.prepareMixedBatch(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	if runs == nil {
+		return c.postDocumentTo(ctx, "/api/v1/doc/word/batch", req, w)
+	}
+
+	result, err := c.mergeBatchRuns(ctx, req, runs)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(result.Data); err != nil {
+		return nil, fmt.Errorf("failed to write document: %w", err)
+	}
+	return &result.Meta, nil
+}
+
+// mergeBatchRuns 逐组生成并按顺序合并为一个文档
+func (c *Client) mergeBatchRuns(ctx context.Context, req WordBatchRequest, runs []templateRun) (*DocumentResult, error) {
+	docs := make([][]byte, 0, len(runs))
+	var failures []ItemFailure
+	var warnings []Warning
+	degraded := false
+	offset := 0
+	for _, run := range runs {
+		part := req
+		part.TemplateName, part.Templates, part.DataList = run.template, nil, run.dataList
+		result, err := c.postDocument(ctx, "/api/v1/doc/word/batch", part)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, result.Data)
+		warnings = append(warnings, result.Meta.Warnings...)
+		degraded = degraded || result.Meta.Degraded
+		// 失败条目的下标换算为完整 DataList 中的下标
+		for _, f := range result.failures {
+			f.Index += offset
+			failures = append(failures, f)
+		}
+		offset += len(run.dataList)
+	}
+	merged, err := MergeWordDocuments(docs...)
+	if err != nil {
+		return nil, err
+	}
+
+	format := FormatDocx
+	if req.OutputFormat == FormatDocm {
+		format = FormatDocm
+	}
+	sum := sha256.Sum256(merged)
+	meta := DocumentMeta{
+		ContentType: format.ContentType(),
+		Size:        int64(len(merged)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		Warnings:    warnings,
+		// 各组请求共用调用的关联 ID
+		CorrelationID: CorrelationID(ctx),
+		Degraded:      degraded,
+	}
+	if req.FileName != "" {
+		meta.FileName = req.FileName + "." + string(format)
+	}
+	return &DocumentResult{Data: merged, Meta: meta, failures: failures}, nil
+}
+
+// prepareMixedBatch 校验并补全请求；服务端支持按条目指定模板或所有条目使用同一模板时返回 nil，
+// 否则返回需要分别生成的条目分组（模板名称已补全租户前缀）
+func (c *Client) prepareMixedBatch(ctx context.Context, req *WordBatchRequest) ([]templateRun, error) {
+	if err := c.validateBatchTemplates(*req); err != nil {
+		return nil, err
+	}
+	if err := c.prepareWordBatch(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var runs []templateRun
+	for i, data := range req.DataList {
+		name := req.TemplateName
+		if req.Templates[i] != "" {
+			name = req.Templates[i]
+		}
+		if n := len(runs); n > 0 && runs[n-1].template == name {
+			runs[n-1].dataList = append(runs[n-1].dataList, data)
+		} else {
+			runs = append(runs, templateRun{template: name, dataList: []map[string]any{data}})
+		}
+	}
+	if len(runs) == 1 {
+		req.TemplateName, req.Templates = runs[0].template, nil
+		return nil, nil
+	}
+
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if caps.Supports(FeatureBatchTemplates) {
+		return nil, nil
+	}
+	if req.Archive {
+		// 压缩包无法通过 MergeWordDocuments 合并
+		return nil, &UnsupportedFeatureError{Feature: FeatureBatchTemplates}
+	}
+	return runs, nil
+}
+
+// validateBatchTemplates 校验 Templates 与 DataList 一一对应，且引用的模板均已存在（ListTemplates）
+func (c *Client) validateBatchTemplates(req WordBatchRequest) error {
+	if len(req.Templates) != len(req.DataList) {
+		return fmt.Errorf("docgen: templates has %d entries, dataList has %d", len(req.Templates), len(req.DataList))
+	}
+
+	names, err := c.ListTemplates()
+	if err != nil {
+		return fmt.Errorf("docgen: list templates: %w", err)
+	}
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+	for i, name := range req.Templates {
+		if name == "" {
+			if req.TemplateName == "" {
+				return fmt.Errorf("docgen: templates[%d]: empty entry requires templateName", i)
+			}
+			name = req.TemplateName
+		}
+		if !known[name] {
+			return fmt.Errorf("docgen: templates[%d]: template %q not found", i, name)
+		}
+	}
+	return nil
+}
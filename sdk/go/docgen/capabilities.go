@@ -0,0 +1,212 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ErrUnsupportedFeature 服务端不支持请求的功能
+//
+// 具体错误类型为 *UnsupportedFeatureError，可通过 errors.As 获取功能名称
+var ErrUnsupportedFeature = errors.New("docgen: feature not supported by server")
+
+// Feature 服务端可选功能
+type Feature string
+
+const (
+	// FeatureAsyncJobs 异步生成任务（SubmitWordJob、SubmitExcelJob）
+	FeatureAsyncJobs Feature = "async-jobs"
+	// FeatureChunkedUpload 可续传的分片上传（UploadTemplateLarge）
+	FeatureChunkedUpload Feature = "chunked-upload"
+	// FeatureSignedLinks 签名下载链接（CreateDownloadLink、CreateTemplateDownloadLink）
+	FeatureSignedLinks Feature = "signed-links"
+	// FeaturePDF PDF 输出
+	FeaturePDF Feature = "pdf"
+	// FeatureBatchTemplates 批量生成按条目指定模板（WordBatchRequest.Templates），无法通过探测发现
+	FeatureBatchTemplates Feature = "batch-templates"
+	// FeatureFontEmbedding 生成文档时嵌入字体（FontOptions.EmbedFonts），无法通过探测发现
+	FeatureFontEmbedding Feature = "font-embedding"
+	// FeatureSheetSplit 超出单个工作表行数上限时拆分到多个工作表（ExcelGenRequest.SplitRows），无法通过探测发现
+	FeatureSheetSplit Feature = "sheet-split"
+	// FeatureTemplateChecksum 生成前校验模板校验和（ExpectedTemplateChecksum），无法通过探测发现
+	FeatureTemplateChecksum Feature = "template-checksum"
+	// FeatureBatchPartialFailure 批量生成跳过渲染失败的条目并报告（WordBatchRequest.ContinueOnError），无法通过探测发现
+	FeatureBatchPartialFailure Feature = "batch-partial-failure"
+	// FeatureImageFetch 服务端下载并嵌入 ImageURL 图片，无法通过探测发现
+	FeatureImageFetch Feature = "image-fetch"
+	// FeatureUsage 用量与配额查询（GetUsage、GetQuota）
+	FeatureUsage Feature = "usage"
+	// FeatureTemplateDependencies 模板引用关系查询（GetTemplateDependencies）
+	FeatureTemplateDependencies Feature = "template-dependencies"
+	// FeatureBatchArchive 批量生成以 ZIP 压缩包返回每个条目的独立文档（WordBatchRequest.Archive），无法通过探测发现
+	FeatureBatchArchive Feature = "batch-archive"
+	// FeaturePagedPreview 异步任务结果的分页预览（GetDocumentPage、GetDocumentPageCount），无法通过探测发现
+	FeaturePagedPreview Feature = "paged-preview"
+	// FeatureMacroTemplates 启用宏的模板（.docm、.xlsm）及输出格式选择（OutputFormat），无法通过探测发现
+	FeatureMacroTemplates Feature = "macro-templates"
+	// FeatureSections Word 数据中的重复区块（Section），包括区块内嵌套的区块与表格，无法通过探测发现
+	FeatureSections Feature = "sections"
+	// FeatureTemporaryTemplates 临时模板（UploadTemporaryTemplate、UploadOptions.TTL），无法通过探测发现
+	FeatureTemporaryTemplates Feature = "temporary-templates"
+	// FeatureAssembly 由片段模板组装文档（AssembleDocument）
+	FeatureAssembly Feature = "assembly"
+	// FeatureDeterministicOutput 服务端生成可复现的文档（Deterministic），无法通过探测发现
+	FeatureDeterministicOutput Feature = "deterministic-output"
+	// FeatureResultStore 异步任务结果查询与保留期限管理（ListResults、ExtendResultRetention、DeleteResult）
+	FeatureResultStore Feature = "result-store"
+	// FeatureSimpleRender 简化渲染（SimpleRender、WithDegradedMode），无法通过探测发现
+	FeatureSimpleRender Feature = "simple-render"
+	// FeatureContentControls 按标记填充 Word 内容控件（WordGenRequest.ContentControls），无法通过探测发现
+	FeatureContentControls Feature = "content-controls"
+)
+
+// featureProbes 服务端不提供能力接口时，用于探测各功能的接口路径
+var featureProbes = []struct {
+	feature Feature
+	path    string
+}{
+	{FeatureAsyncJobs, "/api/v1/jobs"},
+	{FeatureChunkedUpload, "/api/v1/template/uploads"},
+	{FeatureSignedLinks, "/api/v1/links"},
+	{FeaturePDF, "/api/v1/doc/pdf"},
+	{FeatureUsage, "/api/v1/usage"},
+	{FeatureTemplateDependencies, "/api/v1/template/dependencies"},
+	{FeatureAssembly, "/api/v1/doc/assemble"},
+	{FeatureResultStore, "/api/v1/results"},
+}
+
+// UnsupportedFeatureError 服务端不支持请求的功能
+//
+// errors.Is(err, ErrUnsupportedFeature) 返回 true
+type UnsupportedFeatureError struct {
+	// Feature 功能名称
+	Feature Feature
+}
+
+// Error 实现 error 接口
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrUnsupportedFeature, e.Feature)
+}
+
+// Is 使 errors.Is(err, ErrUnsupportedFeature) 成立
+func (e *UnsupportedFeatureError) Is(target error) bool {
+	return target == ErrUnsupportedFeature
+}
+
+// Capabilities 服务端支持的功能
+type Capabilities struct {
+	// Version 服务端版本，探测得到时为空
+	Version string `json:"version,omitempty"`
+	// Features 支持的功能列表
+	Features []Feature `json:"features"`
+	// Probed 服务端不提供能力接口，功能列表由逐个探测已知接口得到
+	Probed bool `json:"-"`
+}
+
+// Supports 判断是否支持指定功能
+func (caps *Capabilities) Supports(feature Feature) bool {
+	for _, f := range caps.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// capabilityCache 能力查询结果缓存，首次成功查询后一直有效，直到 RefreshCapabilities
+type capabilityCache struct {
+	mu   sync.Mutex
+	caps *Capabilities
+}
+
+// Capabilities 查询服务端支持的功能
+//
+// 优先使用能力接口（GET /api/v1/capabilities）；服务端不提供该接口时，以 OPTIONS 请求逐个探测已知接口。
+// 首次成功查询的结果会被缓存，服务端升级后可调用 RefreshCapabilities 重新查询。
+// 异步任务、分片上传与签名链接等可选功能在调用前会查询此结果，服务端不支持时直接返回 *UnsupportedFeatureError
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	return c.capabilities(ctx, false)
+}
+
+// RefreshCapabilities 丢弃缓存并重新查询服务端支持的功能
+func (c *Client) RefreshCapabilities(ctx context.Context) (*Capabilities, error) {
+	return c.capabilities(ctx, true)
+}
+
+// capabilities 返回缓存的能力查询结果，未缓存或 force 为 true 时查询；并发调用方共享同一次查询
+func (c *Client) capabilities(ctx context.Context, force bool) (*Capabilities, error) {
+	c.capabilityCache.mu.Lock()
+	defer c.capabilityCache.mu.Unlock()
+	if c.capabilityCache.caps != nil && !force {
+		return c.capabilityCache.caps, nil
+	}
+
+	caps, err := c.fetchCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.capabilityCache.caps = caps
+	return caps, nil
+}
+
+// fetchCapabilities 请求能力接口，接口不存在时探测已知接口
+func (c *Client) fetchCapabilities(ctx context.Context) (*Capabilities, error) {
+	var caps Capabilities
+	err := c.doJSON(ctx, http.MethodGet, "/api/v1/capabilities", nil, &caps)
+	if err == nil {
+		return &caps, nil
+	}
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Status != http.StatusNotFound && errResp.Status != http.StatusMethodNotAllowed {
+		return nil, err
+	}
+
+	caps = Capabilities{Features: []Feature{}, Probed: true}
+	for _, probe := range featureProbes {
+		ok, err := c.probeEndpoint(ctx, probe.path)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			caps.Features = append(caps.Features, probe.feature)
+		}
+	}
+	return &caps, nil
+}
+
+// probeEndpoint 以 OPTIONS 请求探测接口是否存在：404 表示不存在，其他响应均视为存在
+func (c *Client) probeEndpoint(ctx context.Context, path string) (bool, error) {
+	req, err := c.newRequest(ctx, http.MethodOptions, path, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, _, err := c.roundTrip(req)
+	if err != nil {
+		return false, err
+	}
+	return resp.StatusCode != http.StatusNotFound, nil
+}
+
+// requireFeature 服务端明确不支持 feature 时返回 *UnsupportedFeatureError
+//
+// 能力查询本身失败（如网络错误）时返回 nil，由实际请求报告更准确的错误
+func (c *Client) requireFeature(ctx context.Context, feature Feature) error {
+	caps, err := c.Capabilities(ctx)
+	if err != nil || caps.Supports(feature) {
+		return nil
+	}
+	return &UnsupportedFeatureError{Feature: feature}
+}
+
+// rejectUnsupported 能力接口明确未列出 feature 时返回 *UnsupportedFeatureError
+//
+// 用于无法通过探测发现的功能：能力由探测得到或查询失败时无法判断，返回 nil
+func (c *Client) rejectUnsupported(ctx context.Context, feature Feature) error {
+	if caps, err := c.Capabilities(ctx); err == nil && !caps.Probed && !caps.Supports(feature) {
+		return &UnsupportedFeatureError{Feature: feature}
+	}
+	return nil
+}
@@ -15,8 +15,8 @@
 package docgen
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,6 +29,96 @@ type Client struct {
 	BaseURL string
 	// HTTPClient HTTP 客户端，可自定义超时等配置
 	HTTPClient *http.Client
+	// MaxRequestBytes 请求体大小上限（字节），超过时在序列化阶段提前中止并返回 ErrRequestTooLarge。
+	// 默认 0 表示不限制
+	MaxRequestBytes int64
+	// UploadChunkSize UploadTemplateLarge 的分片大小（字节），默认 0 表示 8 MiB
+	UploadChunkSize int64
+
+	// queryTemplateNames 始终以查询参数传递模板名称
+	queryTemplateNames bool
+	// apiKey API 密钥，非空时随请求发送 Authorization 头
+	apiKey string
+	// validateOutput 生成成功后校验文档格式
+	validateOutput bool
+	// defaultPriority 请求未指定优先级时使用的默认值
+	defaultPriority Priority
+	// fieldKeys EncryptedField 的加密密钥来源
+	fieldKeys KeyProvider
+	// redactor 日志、调试输出与错误信息中请求/响应体的脱敏规则
+	redactor *Redactor
+	// auditFunc 为每个请求提供默认审计信息
+	auditFunc func(ctx context.Context) AuditInfo
+	// tenant 租户 ID，非空时随请求发送 X-Tenant-ID 头
+	tenant string
+	// tenantNamespace 模板名称自动添加租户前缀
+	tenantNamespace bool
+	// healthCache 健康状态缓存，未启用 WithHealthCache 时为 nil
+	healthCache *healthCache
+	// locale 请求的 Accept-Language，决定服务端错误信息的语言
+	locale string
+	// apiPathPrefix 替换默认 "/api" 的路径前缀，customAPIPrefix 为 true 时生效
+	apiPathPrefix   string
+	customAPIPrefix bool
+	// apiVersion API 版本，默认 APIv1
+	apiVersion APIVersion
+	// endpointVersions 按接口覆盖的 API 版本
+	endpointVersions []endpointVersion
+	// fontOptions 请求未设置 FontOptions 时使用的默认值
+	fontOptions *FontOptions
+	// transformers 发送前依次应用于模板数据的转换函数
+	transformers []DataTransformer
+	// flattenSep 非空时 Excel 填充数据中的嵌套值按此分隔符展开
+	flattenSep string
+	// strictTemplateChecksums 服务端不支持模板校验和时由 SDK 预先检查 ExpectedTemplateChecksum
+	strictTemplateChecksums bool
+	// debugDump 非 nil 时将每次请求的请求与响应写入调试文件
+	debugDump *debugDumper
+	// debugDumpLimit 调试文件的保留数量，<= 0 时使用 DefaultDebugDumpFiles
+	debugDumpLimit int
+	// warningHandler 非 nil 时接收每个生成结果的警告
+	warningHandler func(op string, w Warning)
+	// strictWarnings 视为错误的警告码，strictAllWarnings 为 true 时所有警告均视为错误
+	strictWarnings    map[string]bool
+	strictAllWarnings bool
+	// spool 非 nil 时超过阈值的生成结果暂存到磁盘（Spooled 系列方法与 Save* 系列方法）
+	spool *spoolConfig
+	// hedging 非 nil 时对生成请求发出对冲请求
+	hedging *hedgeConfig
+	// metricsHook 非 nil 时接收每次调用的指标
+	metricsHook func(RequestMetrics)
+	// retryBudget 非 nil 时所有调用的重试共享此预算，见 WithRetryBudget
+	retryBudget *RetryBudget
+	// timeouts 分阶段的超时设置，见 WithTimeoutPolicy
+	timeouts TimeoutPolicy
+	// rateLimit 最近一次响应报告的限流状态
+	rateLimit rateLimitTracker
+	// stats 请求统计，见 Stats
+	stats clientStats
+	// throttleThreshold 剩余配额低于此值时发送前主动等待，<= 0 表示不启用
+	throttleThreshold int
+	// rawFileNames 不清理 SDK 自行决定的文件名，见 WithRawFileNames
+	rawFileNames bool
+	// macroTemplates 允许上传与使用启用宏的模板，见 WithMacroTemplates
+	macroTemplates bool
+	// macroOutput 启用宏的模板生成文档时对宏的处理方式，见 WithMacroOutput
+	macroOutput MacroOutput
+	// autoCleanup 内联生成后立即删除临时模板，见 WithAutoCleanup
+	autoCleanup bool
+	// degradedMode 容量不足时以简化渲染重试一次，见 WithDegradedMode
+	degradedMode bool
+	// noRedirectFollow 不跟随 303 See Other，以 *ResultLocation 返回结果地址，见 WithNoRedirectFollow
+	noRedirectFollow bool
+	// fillValidation Excel 填充请求发送前按模板结构检查的方式
+	fillValidation FillValidationMode
+	// schemaCache ValidateFillRequest 使用的模板结构缓存
+	schemaCache schemaCache
+	// outbox 非 nil 时 EnqueueGeneration 写入发件箱，由后台调度器生成并投递
+	outbox *outbox
+	// capabilityCache 服务端能力查询结果
+	capabilityCache capabilityCache
+	// life 生命周期状态（后台 goroutine、上传会话、自建连接池），由 Close 释放
+	life *lifecycle
 }
 
 // WordGenRequest Word 文档生成请求参数
@@ -37,8 +127,25 @@ type WordGenRequest struct {
 	TemplateName string `json:"templateName"`
 	// Data 模板渲染数据
 	Data map[string]any `json:"data"`
+	// ContentControls 按标记（Tag）填充的内容控件（可选），下拉列表、日期与复选框的值分别由
+	// DropdownValue、DateControlValue、CheckboxValue 创建，文本控件直接使用字符串或数字；需服务端支持 FeatureContentControls
+	ContentControls map[string]any `json:"contentControls,omitempty"`
 	// FileName 自定义输出文件名（不含扩展名，可选）
 	FileName string `json:"fileName,omitempty"`
+	// FontOptions 字体嵌入与替换（可选，默认使用 WithFontOptions 设置的值）
+	FontOptions *FontOptions `json:"fontOptions,omitempty"`
+	// ExpectedTemplateChecksum 期望的模板 SHA-256（可选）。模板已被替换时服务端拒绝生成并返回 *TemplateChangedError；
+	// 服务端不支持（见 FeatureTemplateChecksum）时仅在启用 WithStrictTemplateChecksums 后由 SDK 预先检查
+	ExpectedTemplateChecksum string `json:"expectedTemplateChecksum,omitempty"`
+	// OutputFormat 输出格式（可选）。模板启用宏（.docm、.xlsm）时由 SDK 按 WithMacroOutput 设置：
+	// 保留宏时与模板格式相同，去掉宏时为对应的 docx / xlsx
+	OutputFormat Format `json:"outputFormat,omitempty"`
+	// Deterministic 相同输入生成逐字节相同的文档（压缩包时间戳归零、去除 rsid 等随机属性），用于按内容寻址的归档去重；
+	// 服务端不支持（见 FeatureDeterministicOutput）时由 SDK 以 NormalizeDocument 处理收到的文档
+	Deterministic bool `json:"deterministic,omitempty"`
+	// SimpleRender 简化渲染：跳过图片与图表、只保留基本样式，以更低的服务端开销生成可读的文档；
+	// 通常由 WithDegradedMode 在服务端容量不足时自动设置，需服务端支持 FeatureSimpleRender
+	SimpleRender bool `json:"simpleRender,omitempty"`
 }
 
 // ExcelGenRequest Excel 生成请求参数
@@ -51,6 +158,18 @@ type ExcelGenRequest struct {
 	Data [][]any `json:"data"`
 	// FileName 自定义输出文件名（不含扩展名，可选）
 	FileName string `json:"fileName,omitempty"`
+	// Priority 服务端队列优先级（可选，默认使用 WithDefaultPriority 设置的值）
+	Priority Priority `json:"priority,omitempty"`
+	// FontOptions 字体嵌入与替换（可选，默认使用 WithFontOptions 设置的值）
+	FontOptions *FontOptions `json:"fontOptions,omitempty"`
+	// SplitRows 每个工作表的最大数据行数（可选，不超过 DefaultSplitRows，默认 DefaultSplitRows）。
+	// 数据行超过该值时拆分为 "<SheetName>_1"、"<SheetName>_2"……（SheetName 为空时为 "Data_1"……），
+	// 每个工作表重复表头；需服务端支持 FeatureSheetSplit
+	SplitRows int `json:"splitRows,omitempty"`
+	// Deterministic 相同输入生成逐字节相同的文档，规则与 WordGenRequest.Deterministic 相同
+	Deterministic bool `json:"deterministic,omitempty"`
+	// SimpleRender 简化渲染，规则与 WordGenRequest.SimpleRender 相同
+	SimpleRender bool `json:"simpleRender,omitempty"`
 }
 
 // ExcelFillRequest Excel 模板填充请求参数
@@ -63,6 +182,18 @@ type ExcelFillRequest struct {
 	ListData map[string][]map[string]any `json:"listData,omitempty"`
 	// FileName 自定义输出文件名（不含扩展名，可选）
 	FileName string `json:"fileName,omitempty"`
+	// FontOptions 字体嵌入与替换（可选，默认使用 WithFontOptions 设置的值）
+	FontOptions *FontOptions `json:"fontOptions,omitempty"`
+	// ExpectedTemplateChecksum 期望的模板 SHA-256（可选）。模板已被替换时服务端拒绝生成并返回 *TemplateChangedError；
+	// 服务端不支持（见 FeatureTemplateChecksum）时仅在启用 WithStrictTemplateChecksums 后由 SDK 预先检查
+	ExpectedTemplateChecksum string `json:"expectedTemplateChecksum,omitempty"`
+	// OutputFormat 输出格式（可选）。模板启用宏（.docm、.xlsm）时由 SDK 按 WithMacroOutput 设置：
+	// 保留宏时与模板格式相同，去掉宏时为对应的 docx / xlsx
+	OutputFormat Format `json:"outputFormat,omitempty"`
+	// Deterministic 相同输入生成逐字节相同的文档，规则与 WordGenRequest.Deterministic 相同
+	Deterministic bool `json:"deterministic,omitempty"`
+	// SimpleRender 简化渲染，规则与 WordGenRequest.SimpleRender 相同
+	SimpleRender bool `json:"simpleRender,omitempty"`
 }
 
 // WordBatchRequest 批量 Word 生成请求参数
@@ -71,8 +202,30 @@ type WordBatchRequest struct {
 	TemplateName string `json:"templateName"`
 	// DataList 数据列表，每条数据生成一页
 	DataList []map[string]any `json:"dataList"`
+	// Templates 按条目指定模板（可选），与 DataList 一一对应，空字符串表示使用 TemplateName。
+	// 发送前校验引用的模板均已存在；服务端不支持（见 FeatureBatchTemplates）时，
+	// SDK 按连续的相同模板拆分请求，再以 MergeWordDocuments 按输入顺序合并为一个文档
+	Templates []string `json:"templates,omitempty"`
+	// FontOptions 字体嵌入与替换（可选，默认使用 WithFontOptions 设置的值）
+	FontOptions *FontOptions `json:"fontOptions,omitempty"`
+	// ExpectedTemplateChecksum 期望的模板 SHA-256（可选）。模板已被替换时服务端拒绝生成并返回 *TemplateChangedError；
+	// 服务端不支持（见 FeatureTemplateChecksum）时仅在启用 WithStrictTemplateChecksums 后由 SDK 预先检查
+	ExpectedTemplateChecksum string `json:"expectedTemplateChecksum,omitempty"`
 	// FileName 自定义输出文件名（不含扩展名，可选）
 	FileName string `json:"fileName,omitempty"`
+	// Priority 服务端队列优先级（可选，默认使用 WithDefaultPriority 设置的值）
+	Priority Priority `json:"priority,omitempty"`
+	// ContinueOnError 单个条目渲染失败时跳过该条目继续生成，由 BatchGenerateWordWithResult 设置并报告失败条目
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+	// OutputFormat 输出格式（可选），TemplateName 启用宏时由 SDK 设置，规则与 WordGenRequest.OutputFormat 相同
+	OutputFormat Format `json:"outputFormat,omitempty"`
+	// Archive 每个条目生成独立的文档，以 ZIP 压缩包返回而不是合并为一个文档（需服务端支持 FeatureBatchArchive），
+	// 可通过 DocumentResult.Documents、SpooledResult.Documents 或 BatchGenerateWordArchive 逐个读取
+	Archive bool `json:"archive,omitempty"`
+	// Deterministic 相同输入生成逐字节相同的文档，规则与 WordGenRequest.Deterministic 相同；压缩包中的每个文档同样处理
+	Deterministic bool `json:"deterministic,omitempty"`
+	// SimpleRender 简化渲染，规则与 WordGenRequest.SimpleRender 相同
+	SimpleRender bool `json:"simpleRender,omitempty"`
 }
 
 // ErrorResponse 错误响应结构
@@ -80,6 +233,8 @@ type ErrorResponse struct {
 	Status  int    `json:"status"`
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	// Language 错误信息的语言（响应的 Content-Language，如 "zh-CN"），服务端未返回时为空
+	Language string `json:"-"`
 }
 
 // Error 实现 error 接口
@@ -87,75 +242,123 @@ func (e *ErrorResponse) Error() string {
 	return fmt.Sprintf("[%s] %s (status: %d)", e.Code, e.Message, e.Status)
 }
 
+// Is 按错误码分类匹配，如错误码为 CodeTemplateNotFound 时 errors.Is(err, ErrTemplateNotFound) 返回 true（见 RegisterErrorCode）
+func (e *ErrorResponse) Is(target error) bool {
+	kind := errorKind(e.Code)
+	return kind != nil && kind == target
+}
+
 // NewClient 创建文档生成服务客户端
 //
 // baseURL: 服务地址，如 http://localhost:8081
-func NewClient(baseURL string) *Client {
-	return &Client{
-		BaseURL: baseURL,
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
+// opts: 可选配置项，如 WithQueryTemplateNames()
+func NewClient(baseURL string, opts ...Option) *Client {
+	return NewClientWithTimeout(baseURL, 30*time.Second, opts...)
 }
 
 // NewClientWithTimeout 创建带自定义超时的客户端
-func NewClientWithTimeout(baseURL string, timeout time.Duration) *Client {
-	return &Client{
-		BaseURL: baseURL,
-		HTTPClient: &http.Client{
-			Timeout: timeout,
-		},
+func NewClientWithTimeout(baseURL string, timeout time.Duration, opts ...Option) *Client {
+	// 使用独立的连接池，Close 时关闭空闲连接不会影响进程中的其他 HTTP 客户端
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: http.DefaultTransport.(*http.Transport).Clone(),
+	}
+	c := &Client{
+		BaseURL:    baseURL,
+		HTTPClient: httpClient,
+		life:       newLifecycle(httpClient),
 	}
+	c.stats.state.Store(newStatsState())
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.outbox != nil {
+		c.startOutbox()
+	}
+	return c
 }
 
 // HealthResponse 健康检查响应
 type HealthResponse struct {
 	Status string `json:"status"`
+	// Components 各组件健康状态（需服务端开启 show-components）
+	Components map[string]HealthComponent `json:"components,omitempty"`
 }
 
-// Health 检查服务健康状态
-//
-// 返回服务状态，正常时 Status 为 "UP"
-func (c *Client) Health() (*HealthResponse, error) {
-	url := fmt.Sprintf("%s/actuator/health", c.BaseURL)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
+// HealthComponent 组件健康状态
+type HealthComponent struct {
+	Status string `json:"status"`
+	// Details 组件详情（如磁盘空间），数值以 json.Number 保存
+	Details Extra `json:"details,omitempty"`
+}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+// HealthState 服务健康状态
+type HealthState int
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("health check failed with status %d: %s", resp.StatusCode, string(respBody))
-	}
+const (
+	// HealthUp 服务正常
+	HealthUp HealthState = iota
+	// HealthDown 服务可连接，但报告不可用
+	HealthDown
+	// HealthUnreachable 服务无法连接
+	HealthUnreachable
+)
 
-	var result HealthResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// String 返回健康状态名称
+func (s HealthState) String() string {
+	switch s {
+	case HealthUp:
+		return "UP"
+	case HealthDown:
+		return "DOWN"
+	case HealthUnreachable:
+		return "UNREACHABLE"
+	default:
+		return fmt.Sprintf("HealthState(%d)", int(s))
 	}
+}
 
-	return &result, nil
+// Health 检查服务健康状态
+//
+// 返回服务状态，正常时 Status 为 "UP"。
+// 服务返回 503 且响应体可解析时（如 {"status":"DOWN"}），同时返回解析结果和 ErrServiceDown；
+// 网络层错误（连接被拒绝等）返回包装了原始错误的 ErrUnreachable，超时返回 *TimeoutError
+func (c *Client) Health() (*HealthResponse, error) {
+	health, _, err := c.getHealth(context.Background(), healthPath)
+	return health, err
 }
 
 // IsHealthy 检查服务是否健康
 //
-// 返回 true 表示服务正常，false 表示服务不可用
+// 返回 true 表示服务正常，false 表示服务不可用。启用 WithHealthCache 时返回缓存结果
 func (c *Client) IsHealthy() bool {
+	return c.HealthState() == HealthUp
+}
+
+// HealthState 获取服务健康状态
+//
+// 与 IsHealthy 不同，可区分"服务可连接但不可用"（HealthDown）与"服务无法连接"（HealthUnreachable）。
+// 健康检查超时视为无法连接。启用 WithHealthCache 时返回缓存结果
+func (c *Client) HealthState() HealthState {
+	if c.healthCache != nil {
+		return c.healthCache.get(false, c.probeHealthState)
+	}
+	return c.probeHealthState()
+}
+
+// probeHealthState 请求健康检查接口并转换为 HealthState
+func (c *Client) probeHealthState() HealthState {
 	health, err := c.Health()
 	if err != nil {
-		return false
+		if errors.Is(err, ErrUnreachable) || errors.Is(err, ErrTimeout) {
+			return HealthUnreachable
+		}
+		return HealthDown
 	}
-	return health.Status == "UP"
+	if health.Status != "UP" {
+		return HealthDown
+	}
+	return HealthUp
 }
 
 // GenerateWord 生成 Word 文档
@@ -171,12 +374,17 @@ func (c *Client) GenerateWord(templateName string, data map[string]any, fileName
 		Data:         data,
 		FileName:     fileName,
 	}
-	return c.doPostRequest("/api/v1/doc/word", req)
+	return c.GenerateWordWithRequest(req)
 }
 
 // GenerateWordWithRequest 使用完整请求结构生成 Word 文档
 func (c *Client) GenerateWordWithRequest(req WordGenRequest) ([]byte, error) {
-	return c.doPostRequest("/api/v1/doc/word", req)
+	return c.GenerateWordContext(context.Background(), req)
+}
+
+// GenerateWordContext 支持 context 的 Word 生成，context 可携带取消、截止时间与 WithAudit 审计信息
+func (c *Client) GenerateWordContext(ctx context.Context, req WordGenRequest) ([]byte, error) {
+	return documentData(c.GenerateWordWithMeta(ctx, req))
 }
 
 // doPostRequest 通用 POST 请求方法
@@ -186,45 +394,16 @@ func (c *Client) GenerateWordWithRequest(req WordGenRequest) ([]byte, error) {
 //
 // 返回响应体字节数组
 func (c *Client) doPostRequest(path string, reqBody any) ([]byte, error) {
-	// 序列化请求体
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// 构建 HTTP 请求
-	url := fmt.Sprintf("%s%s", c.BaseURL, path)
-	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/octet-stream")
-
-	// 发送请求
-	resp, err := c.HTTPClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
+	return c.doPostRequestContext(context.Background(), path, reqBody)
+}
 
-	// 读取响应体
-	respBody, err := io.ReadAll(resp.Body)
+// doPostRequestContext 支持 context 取消与截止时间的通用 POST 请求方法
+func (c *Client) doPostRequestContext(ctx context.Context, path string, reqBody any) ([]byte, error) {
+	result, err := c.postDocument(ctx, path, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
-
-	// 处理错误响应
-	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, &errResp
-	}
-
-	return respBody, nil
+	return result.Data, nil
 }
 
 // SaveWord 生成 Word 文档并保存到文件
@@ -233,6 +412,10 @@ func (c *Client) doPostRequest(path string, reqBody any) ([]byte, error) {
 // data: 模板渲染数据
 // outputPath: 输出文件路径（需包含 .docx 扩展名）
 func (c *Client) SaveWord(templateName string, data map[string]any, outputPath string) error {
+	if c.spool != nil {
+		result, err := c.GenerateWordSpooled(context.Background(), WordGenRequest{TemplateName: templateName, Data: data})
+		return saveSpooled(result, err, outputPath)
+	}
 	doc, err := c.GenerateWord(templateName, data, "")
 	if err != nil {
 		return err
@@ -254,12 +437,17 @@ func (c *Client) BatchGenerateWord(templateName string, dataList []map[string]an
 		DataList:     dataList,
 		FileName:     fileName,
 	}
-	return c.doPostRequest("/api/v1/doc/word/batch", req)
+	return c.BatchGenerateWordWithRequest(req)
 }
 
 // BatchGenerateWordWithRequest 使用完整请求结构批量生成 Word 文档
 func (c *Client) BatchGenerateWordWithRequest(req WordBatchRequest) ([]byte, error) {
-	return c.doPostRequest("/api/v1/doc/word/batch", req)
+	return c.BatchGenerateWordContext(context.Background(), req)
+}
+
+// BatchGenerateWordContext 支持 context 的批量 Word 生成
+func (c *Client) BatchGenerateWordContext(ctx context.Context, req WordBatchRequest) ([]byte, error) {
+	return documentData(c.BatchGenerateWordWithMeta(ctx, req))
 }
 
 // SaveBatchWord 批量生成 Word 文档并保存到文件
@@ -268,6 +456,10 @@ func (c *Client) BatchGenerateWordWithRequest(req WordBatchRequest) ([]byte, err
 // dataList: 数据列表
 // outputPath: 输出文件路径（需包含 .docx 扩展名）
 func (c *Client) SaveBatchWord(templateName string, dataList []map[string]any, outputPath string) error {
+	if c.spool != nil {
+		result, err := c.BatchGenerateWordSpooled(context.Background(), WordBatchRequest{TemplateName: templateName, DataList: dataList})
+		return saveSpooled(result, err, outputPath)
+	}
 	doc, err := c.BatchGenerateWord(templateName, dataList, "")
 	if err != nil {
 		return err
@@ -291,12 +483,17 @@ func (c *Client) GenerateExcel(sheetName string, headers []string, data [][]any,
 		Data:      data,
 		FileName:  fileName,
 	}
-	return c.doPostRequest("/api/v1/doc/excel", req)
+	return c.GenerateExcelWithRequest(req)
 }
 
 // GenerateExcelWithRequest 使用完整请求结构生成 Excel 文档
 func (c *Client) GenerateExcelWithRequest(req ExcelGenRequest) ([]byte, error) {
-	return c.doPostRequest("/api/v1/doc/excel", req)
+	return c.GenerateExcelContext(context.Background(), req)
+}
+
+// GenerateExcelContext 支持 context 的 Excel 生成
+func (c *Client) GenerateExcelContext(ctx context.Context, req ExcelGenRequest) ([]byte, error) {
+	return documentData(c.GenerateExcelWithMeta(ctx, req))
 }
 
 // SaveExcel 生成 Excel 文档并保存到文件
@@ -306,6 +503,10 @@ func (c *Client) GenerateExcelWithRequest(req ExcelGenRequest) ([]byte, error) {
 // data: 二维数据数组
 // outputPath: 输出文件路径（需包含 .xlsx 扩展名）
 func (c *Client) SaveExcel(sheetName string, headers []string, data [][]any, outputPath string) error {
+	if c.spool != nil {
+		result, err := c.GenerateExcelSpooled(context.Background(), ExcelGenRequest{SheetName: sheetName, Headers: headers, Data: data})
+		return saveSpooled(result, err, outputPath)
+	}
 	doc, err := c.GenerateExcel(sheetName, headers, data, "")
 	if err != nil {
 		return err
@@ -329,12 +530,17 @@ func (c *Client) FillExcelTemplate(templateName string, data map[string]any, lis
 		ListData:     listData,
 		FileName:     fileName,
 	}
-	return c.doPostRequest("/api/v1/doc/excel/fill", req)
+	return c.FillExcelTemplateWithRequest(req)
 }
 
 // FillExcelTemplateWithRequest 使用完整请求结构填充 Excel 模板
 func (c *Client) FillExcelTemplateWithRequest(req ExcelFillRequest) ([]byte, error) {
-	return c.doPostRequest("/api/v1/doc/excel/fill", req)
+	return c.FillExcelTemplateContext(context.Background(), req)
+}
+
+// FillExcelTemplateContext 支持 context 的 Excel 模板填充
+func (c *Client) FillExcelTemplateContext(ctx context.Context, req ExcelFillRequest) ([]byte, error) {
+	return documentData(c.FillExcelTemplateWithMeta(ctx, req))
 }
 
 // SaveFilledExcel 填充 Excel 模板并保存到文件
@@ -344,6 +550,10 @@ func (c *Client) FillExcelTemplateWithRequest(req ExcelFillRequest) ([]byte, err
 // listData: 列表数据
 // outputPath: 输出文件路径（需包含 .xlsx 扩展名）
 func (c *Client) SaveFilledExcel(templateName string, data map[string]any, listData map[string][]map[string]any, outputPath string) error {
+	if c.spool != nil {
+		result, err := c.FillExcelTemplateSpooled(context.Background(), ExcelFillRequest{TemplateName: templateName, Data: data, ListData: listData})
+		return saveSpooled(result, err, outputPath)
+	}
 	doc, err := c.FillExcelTemplate(templateName, data, listData, "")
 	if err != nil {
 		return err
@@ -0,0 +1,123 @@
+package docgen
+
+import (
+	"errors"
+	"sync"
+)
+
+// 服务端错误码（ErrorResponse.Code），可配合 switch 使用，避免手写字符串
+const (
+	// CodeTemplateNotFound 模板不存在
+	CodeTemplateNotFound = "TEMPLATE_NOT_FOUND"
+	// CodeTemplateChanged 模板与请求期望的校验和不一致（ExpectedTemplateChecksum）
+	CodeTemplateChanged = "TEMPLATE_CHANGED"
+	// CodeTemplateInUse 模板仍被其他模板引用，服务端拒绝删除
+	CodeTemplateInUse = "TEMPLATE_IN_USE"
+	// CodeRenderError 模板渲染失败（模板语法错误或数据与模板不匹配）
+	CodeRenderError = "RENDER_ERROR"
+	// CodeValidationError 请求参数校验失败（如必填字段为空）
+	CodeValidationError = "VALIDATION_ERROR"
+	// CodeInvalidArgument 请求参数不合法
+	CodeInvalidArgument = "INVALID_ARGUMENT"
+	// CodePayloadTooLarge 请求体超过服务端限制
+	CodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+	// CodeUnsupportedFormat 不支持的模板或输出格式
+	CodeUnsupportedFormat = "UNSUPPORTED_FORMAT"
+	// CodeIOError 服务端读写文件失败
+	CodeIOError = "IO_ERROR"
+	// CodeInternalError 服务端内部错误
+	CodeInternalError = "INTERNAL_ERROR"
+	// CodeForbidden 无权访问（多租户部署中访问其他租户的资源）
+	CodeForbidden = "FORBIDDEN"
+	// CodeNotFound 接口或资源不存在
+	CodeNotFound = "NOT_FOUND"
+	// CodeRateLimited 请求过于频繁
+	CodeRateLimited = "RATE_LIMITED"
+	// CodeQuotaExceeded 调用方应用的生成配额已耗尽
+	CodeQuotaExceeded = "QUOTA_EXCEEDED"
+	// CodeServiceUnavailable 服务暂时不可用
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	// CodeOverloaded 服务端负载过高，主动拒绝请求（负载削减）
+	CodeOverloaded = "OVERLOADED"
+	// CodeChecksumMismatch 上传内容与声明的 SHA-256 不一致
+	CodeChecksumMismatch = "CHECKSUM_MISMATCH"
+	// CodeFieldDecryptionFailed 加密字段无法解密
+	CodeFieldDecryptionFailed = "FIELD_DECRYPTION_FAILED"
+	// CodeJobNotFound 异步任务不存在
+	CodeJobNotFound = "JOB_NOT_FOUND"
+	// CodeJobNotFinished 异步任务尚未完成，结果不可下载
+	CodeJobNotFinished = "JOB_NOT_FINISHED"
+	// CodeJobAlreadyFinished 异步任务已结束，无法取消
+	CodeJobAlreadyFinished = "JOB_ALREADY_FINISHED"
+	// CodeResultExpired 异步任务结果已超过保留期限
+	CodeResultExpired = "RESULT_EXPIRED"
+	// CodePageOutOfRange 预览页码超出文档页数
+	CodePageOutOfRange = "PAGE_OUT_OF_RANGE"
+	// CodeUploadNotFound 分片上传会话不存在
+	CodeUploadNotFound = "UPLOAD_NOT_FOUND"
+	// CodeUploadIncomplete 分片上传未完成
+	CodeUploadIncomplete = "UPLOAD_INCOMPLETE"
+	// CodeOffsetMismatch 分片偏移与服务端已接收的字节数不一致
+	CodeOffsetMismatch = "OFFSET_MISMATCH"
+	// CodeLinkNotFound 下载链接不存在
+	CodeLinkNotFound = "LINK_NOT_FOUND"
+	// CodeLinkExpired 下载链接已过期
+	CodeLinkExpired = "LINK_EXPIRED"
+	// CodeImageFetchFailed 服务端下载 ImageURL 图片失败（OnError 为 ImageFailError）
+	CodeImageFetchFailed = "IMAGE_FETCH_FAILED"
+)
+
+// 按错误码分类的错误，ErrorResponse 的错误码属于对应分类时 errors.Is 返回 true
+var (
+	// ErrRenderFailed 模板渲染失败（CodeRenderError）
+	ErrRenderFailed = errors.New("docgen: render failed")
+	// ErrInvalidRequest 请求参数不合法（CodeValidationError、CodeInvalidArgument）
+	ErrInvalidRequest = errors.New("docgen: invalid request")
+	// ErrUnsupportedFormat 不支持的模板或输出格式（CodeUnsupportedFormat）
+	ErrUnsupportedFormat = errors.New("docgen: unsupported format")
+	// ErrImageFetchFailed 服务端下载图片失败（CodeImageFetchFailed）
+	ErrImageFetchFailed = errors.New("docgen: image fetch failed")
+)
+
+// errorCodes 错误码到错误分类的映射，由 RegisterErrorCode 扩展
+var errorCodes = struct {
+	mu    sync.RWMutex
+	kinds map[string]error
+}{kinds: map[string]error{
+	CodeTemplateNotFound:      ErrTemplateNotFound,
+	CodeTemplateChanged:       ErrTemplateChanged,
+	CodeTemplateInUse:         ErrTemplateInUse,
+	CodeRenderError:           ErrRenderFailed,
+	CodeValidationError:       ErrInvalidRequest,
+	CodeInvalidArgument:       ErrInvalidRequest,
+	CodePayloadTooLarge:       ErrRequestTooLarge,
+	CodeUnsupportedFormat:     ErrUnsupportedFormat,
+	CodeChecksumMismatch:      ErrChecksumMismatch,
+	CodeFieldDecryptionFailed: ErrDecryptionFailed,
+	CodeJobAlreadyFinished:    ErrJobFinished,
+	CodePageOutOfRange:        ErrPageOutOfRange,
+	CodeImageFetchFailed:      ErrImageFetchFailed,
+	CodeQuotaExceeded:         ErrQuotaExceeded,
+	CodeResultExpired:         ErrResultExpired,
+}}
+
+// RegisterErrorCode 将服务端错误码归入错误分类 kind，之后该错误码的 ErrorResponse 满足 errors.Is(err, kind)
+//
+// 用于部署了自定义错误码的服务端，如 RegisterErrorCode("TPL_MISSING", docgen.ErrTemplateNotFound)；
+// 已注册的错误码（包括内置错误码）会被覆盖，kind 为 nil 时取消分类。通常在程序启动时调用，可并发使用
+func RegisterErrorCode(code string, kind error) {
+	errorCodes.mu.Lock()
+	defer errorCodes.mu.Unlock()
+	if kind == nil {
+		delete(errorCodes.kinds, code)
+		return
+	}
+	errorCodes.kinds[code] = kind
+}
+
+// errorKind 返回错误码对应的错误分类，未分类时返回 nil
+func errorKind(code string) error {
+	errorCodes.mu.RLock()
+	defer errorCodes.mu.RUnlock()
+	return errorCodes.kinds[code]
+}
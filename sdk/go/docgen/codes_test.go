@@ -0,0 +1,136 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// codeKinds 按错误码分类的哨兵错误，每个错误码最多匹配其中一个
+var codeKinds = []error{
+	docgen.ErrTemplateNotFound, docgen.ErrTemplateChanged, docgen.ErrTemplateInUse, docgen.ErrRenderFailed,
+	docgen.ErrInvalidRequest, docgen.ErrRequestTooLarge, docgen.ErrUnsupportedFormat, docgen.ErrChecksumMismatch,
+	docgen.ErrDecryptionFailed, docgen.ErrJobFinished, docgen.ErrPageOutOfRange, docgen.ErrImageFetchFailed,
+	docgen.ErrQuotaExceeded, docgen.ErrResultExpired,
+}
+
+// TestErrorCodesRoundTrip 每个错误码经服务端的错误响应返回后，errors.As 得到带有该错误码的 *ErrorResponse，
+// errors.Is 只匹配其分类（未分类的错误码不匹配任何分类），有专门类型的错误码转换为对应类型
+func TestErrorCodesRoundTrip(t *testing.T) {
+	tests := []struct {
+		code   string
+		status int
+		kind   error
+		// typed 检查专门的错误类型，为 nil 时不检查
+		typed func(error) bool
+	}{
+		{docgen.CodeTemplateNotFound, http.StatusNotFound, docgen.ErrTemplateNotFound, nil},
+		{docgen.CodeTemplateChanged, http.StatusConflict, docgen.ErrTemplateChanged, as[*docgen.TemplateChangedError]},
+		{docgen.CodeTemplateInUse, http.StatusConflict, docgen.ErrTemplateInUse, nil},
+		{docgen.CodeRenderError, http.StatusUnprocessableEntity, docgen.ErrRenderFailed, nil},
+		{docgen.CodeValidationError, http.StatusBadRequest, docgen.ErrInvalidRequest, nil},
+		{docgen.CodeInvalidArgument, http.StatusBadRequest, docgen.ErrInvalidRequest, nil},
+		{docgen.CodePayloadTooLarge, http.StatusRequestEntityTooLarge, docgen.ErrRequestTooLarge, nil},
+		{docgen.CodeUnsupportedFormat, http.StatusBadRequest, docgen.ErrUnsupportedFormat, nil},
+		{docgen.CodeIOError, http.StatusInternalServerError, nil, nil},
+		{docgen.CodeInternalError, http.StatusInternalServerError, nil, nil},
+		{docgen.CodeForbidden, http.StatusForbidden, nil, nil},
+		{docgen.CodeNotFound, http.StatusNotFound, nil, nil},
+		{docgen.CodeRateLimited, http.StatusTooManyRequests, nil, nil},
+		{docgen.CodeQuotaExceeded, http.StatusTooManyRequests, docgen.ErrQuotaExceeded, as[*docgen.QuotaExceededError]},
+		{docgen.CodeServiceUnavailable, http.StatusServiceUnavailable, nil, nil},
+		{docgen.CodeOverloaded, http.StatusServiceUnavailable, nil, nil},
+		{docgen.CodeChecksumMismatch, http.StatusBadRequest, docgen.ErrChecksumMismatch, nil},
+		{docgen.CodeFieldDecryptionFailed, http.StatusUnprocessableEntity, docgen.ErrDecryptionFailed, nil},
+		{docgen.CodeJobNotFound, http.StatusNotFound, nil, nil},
+		{docgen.CodeJobNotFinished, http.StatusConflict, nil, nil},
+		{docgen.CodeJobAlreadyFinished, http.StatusConflict, docgen.ErrJobFinished, nil},
+		{docgen.CodeResultExpired, http.StatusGone, docgen.ErrResultExpired, as[*docgen.ResultExpiredError]},
+		{docgen.CodePageOutOfRange, http.StatusBadRequest, docgen.ErrPageOutOfRange, nil},
+		{docgen.CodeUploadNotFound, http.StatusNotFound, nil, nil},
+		{docgen.CodeUploadIncomplete, http.StatusConflict, nil, nil},
+		{docgen.CodeOffsetMismatch, http.StatusConflict, nil, nil},
+		{docgen.CodeLinkNotFound, http.StatusNotFound, nil, nil},
+		{docgen.CodeLinkExpired, http.StatusGone, nil, nil},
+		{docgen.CodeImageFetchFailed, http.StatusUnprocessableEntity, docgen.ErrImageFetchFailed, nil},
+	}
+
+	// 表格覆盖 codes.go 中声明的全部错误码
+	covered := make(map[string]bool, len(tests))
+	for _, tt := range tests {
+		covered[tt.code] = true
+	}
+	for name, value := range declaredCodes(t) {
+		if !covered[value] {
+			t.Errorf("%s (%q) missing from the round-trip table", name, value)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, docgentest.ErrorResponse(tt.status, tt.code, "rejected: "+tt.code)))
+			defer srv.Close()
+			srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+
+			_, err := docgen.NewClient(srv.URL).GenerateWordWithMeta(context.Background(), wordReq)
+			var errResp *docgen.ErrorResponse
+			if !errors.As(err, &errResp) {
+				t.Fatalf("err = %v, want an *ErrorResponse", err)
+			}
+			if errResp.Code != tt.code || errResp.Status != tt.status || errResp.Message != "rejected: "+tt.code {
+				t.Errorf("ErrorResponse = %+v, want code %s status %d", errResp, tt.code, tt.status)
+			}
+			for _, kind := range codeKinds {
+				if got := errors.Is(err, kind); got != (kind == tt.kind) {
+					t.Errorf("errors.Is(err, %v) = %v", kind, got)
+				}
+			}
+			if tt.typed != nil && !tt.typed(err) {
+				t.Errorf("err = %T %v, want the code's typed error", err, err)
+			}
+		})
+	}
+}
+
+// as 返回 errors.As(err, *T) 的结果
+func as[T error](err error) bool {
+	var target T
+	return errors.As(err, &target)
+}
+
+// declaredCodes 解析 codes.go，返回声明的 Code* 常量名与值
+func declaredCodes(t *testing.T) map[string]string {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "codes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make(map[string]string)
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Code") || i >= len(spec.Values) {
+				continue
+			}
+			if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				codes[name.Name] = strings.Trim(lit.Value, `"`)
+			}
+		}
+		return true
+	})
+	if len(codes) == 0 {
+		t.Fatal("no Code constants found in codes.go")
+	}
+	return codes
+}
@@ -0,0 +1,229 @@
+package docgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 内容控件值序列化后的标记键，服务端据此按控件类型填充
+const (
+	// DropdownMarker 下拉列表 / 组合框选项，输出 {"$dropdown": "选项"}
+	DropdownMarker = "$dropdown"
+	// DateControlMarker 日期选择器，输出 {"$date": "2006-01-02"}
+	DateControlMarker = "$date"
+	// CheckboxMarker 复选框，输出 {"$checkbox": true}
+	CheckboxMarker = "$checkbox"
+)
+
+// dateControlLayout 日期控件值的格式
+const dateControlLayout = "2006-01-02"
+
+// ErrContentControlValue 内容控件的值与模板中控件的类型不符，具体错误类型为 *ContentControlError
+var ErrContentControlValue = errors.New("docgen: invalid content control value")
+
+// ContentControlKind 内容控件类型
+type ContentControlKind string
+
+const (
+	// ControlText 纯文本控件，值为字符串或数字
+	ControlText ContentControlKind = "text"
+	// ControlRichText 格式文本控件，值为字符串或数字
+	ControlRichText ContentControlKind = "richText"
+	// ControlDropdown 下拉列表，值由 DropdownValue 创建且必须是控件的选项之一
+	ControlDropdown ContentControlKind = "dropdown"
+	// ControlComboBox 组合框，值由 DropdownValue 创建或为任意字符串
+	ControlComboBox ContentControlKind = "comboBox"
+	// ControlDate 日期选择器，值由 DateControlValue 创建
+	ControlDate ContentControlKind = "date"
+	// ControlCheckbox 复选框，值由 CheckboxValue 创建
+	ControlCheckbox ContentControlKind = "checkbox"
+)
+
+// TemplateContentControl 模板中带标记的内容控件（结构化文档标记）
+type TemplateContentControl struct {
+	// Tag 控件标记，即 WordGenRequest.ContentControls 的键
+	Tag string `json:"tag"`
+	// Type 控件类型，为空时按 ControlText 处理
+	Type ContentControlKind `json:"type,omitempty"`
+	// Options 下拉列表与组合框的选项
+	Options []string `json:"options,omitempty"`
+}
+
+// ContentControlValue 下拉列表、日期或复选框控件的值，由 DropdownValue、DateControlValue、CheckboxValue 创建
+type ContentControlValue struct {
+	// Kind 控件类型：ControlDropdown、ControlDate 或 ControlCheckbox
+	Kind ContentControlKind
+	// Item 选中的选项（ControlDropdown）
+	Item string
+	// Date 日期（ControlDate），按其所在时区取日期
+	Date time.Time
+	// Checked 是否勾选（ControlCheckbox）
+	Checked bool
+}
+
+// DropdownValue 创建下拉列表或组合框的值，item 为选项的显示文本
+func DropdownValue(item string) ContentControlValue {
+	return ContentControlValue{Kind: ControlDropdown, Item: item}
+}
+
+// DateControlValue 创建日期选择器的值，只使用 t 在其所在时区的日期部分
+func DateControlValue(t time.Time) ContentControlValue {
+	return ContentControlValue{Kind: ControlDate, Date: t}
+}
+
+// CheckboxValue 创建复选框的值
+func CheckboxValue(checked bool) ContentControlValue {
+	return ContentControlValue{Kind: ControlCheckbox, Checked: checked}
+}
+
+// MarshalJSON 实现 json.Marshaler：按类型输出 {"$dropdown": "..."}、{"$date": "2006-01-02"} 或 {"$checkbox": true}
+func (v ContentControlValue) MarshalJSON() ([]byte, error) {
+	switch v.Kind {
+	case ControlDropdown:
+		return json.Marshal(map[string]string{DropdownMarker: v.Item})
+	case ControlDate:
+		return json.Marshal(map[string]string{DateControlMarker: v.Date.Format(dateControlLayout)})
+	case ControlCheckbox:
+		return json.Marshal(map[string]bool{CheckboxMarker: v.Checked})
+	}
+	return nil, fmt.Errorf("%w: unknown kind %q", ErrContentControlValue, v.Kind)
+}
+
+// ContentControlError 内容控件的值无效：类型与模板中的控件不符、不是控件的选项，或模板中没有该标记的控件
+//
+// errors.Is(err, ErrContentControlValue) 返回 true
+type ContentControlError struct {
+	// Tag 控件标记
+	Tag string
+	// Kind 模板中控件的类型，模板中没有该控件时为空
+	Kind ContentControlKind
+	// Reason 问题描述
+	Reason string
+}
+
+// Error 实现 error 接口
+func (e *ContentControlError) Error() string {
+	return fmt.Sprintf("%v: contentControls[%q]: %s", ErrContentControlValue, e.Tag, e.Reason)
+}
+
+// Is 使 errors.Is(err, ErrContentControlValue) 成立
+func (e *ContentControlError) Is(target error) bool {
+	return target == ErrContentControlValue
+}
+
+// checkContentControls 校验内容控件的值，需服务端支持 FeatureContentControls
+//
+// 能获取模板结构（GetTemplateVariables，带缓存）且其中列出内容控件时，按控件类型检查每个值；
+// 获取失败时只检查值本身，由实际请求报告其余错误
+func (c *Client) checkContentControls(ctx context.Context, templateName string, controls map[string]any) error {
+	if len(controls) == 0 {
+		return nil
+	}
+	if err := c.rejectUnsupported(ctx, FeatureContentControls); err != nil {
+		return err
+	}
+	var kinds map[string]TemplateContentControl
+	if schema, err := c.templateSchema(ctx, templateName); err == nil && len(schema.ContentControls) > 0 {
+		kinds = make(map[string]TemplateContentControl, len(schema.ContentControls))
+		for _, cc := range schema.ContentControls {
+			kinds[cc.Tag] = cc
+		}
+	}
+
+	tags := make([]string, 0, len(controls))
+	for tag := range controls {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		if tag == "" {
+			return &ContentControlError{Reason: "empty tag"}
+		}
+		value := controls[tag]
+		if kinds == nil {
+			if v, ok := value.(ContentControlValue); ok {
+				if _, err := v.MarshalJSON(); err != nil {
+					return &ContentControlError{Tag: tag, Reason: fmt.Sprintf("unknown value kind %q", v.Kind)}
+				}
+			}
+			continue
+		}
+		cc, ok := kinds[tag]
+		if !ok {
+			return &ContentControlError{Tag: tag, Reason: "template has no content control with this tag"}
+		}
+		if reason := contentControlMismatch(cc, value); reason != "" {
+			return &ContentControlError{Tag: tag, Kind: cc.Type, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// contentControlMismatch 检查值是否适用于控件，不适用时返回原因
+func contentControlMismatch(cc TemplateContentControl, value any) string {
+	v, isControlValue := value.(ContentControlValue)
+	switch cc.Type {
+	case ControlDropdown, ControlComboBox:
+		if !isControlValue {
+			if _, ok := value.(string); ok && cc.Type == ControlComboBox {
+				return ""
+			}
+			return fmt.Sprintf("%s control needs DropdownValue, got %T", cc.Type, value)
+		}
+		if v.Kind != ControlDropdown {
+			return fmt.Sprintf("%s control needs DropdownValue, got %s value", cc.Type, v.Kind)
+		}
+		if cc.Type == ControlDropdown && len(cc.Options) > 0 && !containsString(cc.Options, v.Item) {
+			return fmt.Sprintf("%q is not one of the options %s", v.Item, strings.Join(cc.Options, ", "))
+		}
+	case ControlDate:
+		if !isControlValue || v.Kind != ControlDate {
+			return fmt.Sprintf("date control needs DateControlValue, got %s", describeControlValue(value))
+		}
+	case ControlCheckbox:
+		if !isControlValue || v.Kind != ControlCheckbox {
+			return fmt.Sprintf("checkbox control needs CheckboxValue, got %s", describeControlValue(value))
+		}
+	default:
+		if isControlValue {
+			return fmt.Sprintf("%s control takes plain text, got %s value", withDefaultKind(cc.Type), v.Kind)
+		}
+		switch value.(type) {
+		case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		default:
+			return fmt.Sprintf("%s control takes plain text, got %T", withDefaultKind(cc.Type), value)
+		}
+	}
+	return ""
+}
+
+// describeControlValue 描述值的类型，用于错误信息
+func describeControlValue(value any) string {
+	if v, ok := value.(ContentControlValue); ok {
+		return string(v.Kind) + " value"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// withDefaultKind 控件类型为空时返回 ControlText
+func withDefaultKind(kind ContentControlKind) ContentControlKind {
+	if kind == "" {
+		return ControlText
+	}
+	return kind
+}
+
+// containsString 切片是否包含 s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// orderFormSchema 包含每种内容控件的模板结构
+var orderFormSchema = docgen.TemplateSchema{
+	TemplateName: "order.docx",
+	ContentControls: []docgen.TemplateContentControl{
+		{Tag: "Remark"},
+		{Tag: "Notes", Type: docgen.ControlRichText},
+		{Tag: "Quantity", Type: docgen.ControlText},
+		{Tag: "Region", Type: docgen.ControlDropdown, Options: []string{"华北", "华东", "华南"}},
+		{Tag: "Channel", Type: docgen.ControlComboBox, Options: []string{"线上", "线下"}},
+		{Tag: "Source", Type: docgen.ControlComboBox, Options: []string{"官网", "门店"}},
+		{Tag: "SignedOn", Type: docgen.ControlDate},
+		{Tag: "Approved", Type: docgen.ControlCheckbox},
+		{Tag: "Urgent", Type: docgen.ControlCheckbox},
+	},
+}
+
+// signedOn 东八区 4 月 1 日凌晨，UTC 仍是 3 月 31 日：日期控件按值所在时区取日期
+var signedOn = time.Date(2024, 4, 1, 1, 30, 0, 0, time.FixedZone("CST", 8*3600))
+
+type orderForm struct {
+	Customer string                     `json:"customer"`
+	Remark   string                     `docgen:"sdt:Remark"`
+	Notes    string                     `docgen:"sdt:Notes"`
+	Quantity int                        `docgen:"sdt:Quantity"`
+	Region   docgen.ContentControlValue `docgen:"sdt:Region"`
+	Channel  string                     `docgen:"sdt:Channel"`
+	Source   docgen.ContentControlValue `docgen:"sdt:Source"`
+	SignedOn time.Time                  `docgen:"sdt:SignedOn"`
+	Approved bool                       `docgen:"sdt:Approved"`
+	Urgent   bool                       `docgen:"sdt:Urgent"`
+}
+
+func newContentControlServer(t *testing.T) (*docgentest.Server, *docgen.Client) {
+	t.Helper()
+	srv := docgentest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddTemplate("order.docx", docgentest.MinimalDocx("{{customer}}"))
+	srv.SetTemplateSchema(orderFormSchema)
+	return srv, docgen.NewClient(srv.URL)
+}
+
+// TestContentControlWireFormat 固定每种内容控件值的线上格式；
+// 直接构造的请求与 GenerateWordFromStruct 转换 docgen:"sdt:..." 字段发送相同的请求体
+func TestContentControlWireFormat(t *testing.T) {
+	srv, client := newContentControlServer(t)
+
+	req := docgen.WordGenRequest{
+		TemplateName: "order.docx",
+		Data:         map[string]any{"customer": "某某公司"},
+		ContentControls: map[string]any{
+			"Remark":   "加急处理",
+			"Notes":    "详见附件",
+			"Quantity": 3,
+			"Region":   docgen.DropdownValue("华东"),
+			"Channel":  "线上",
+			"Source":   docgen.DropdownValue("门店"),
+			"SignedOn": docgen.DateControlValue(signedOn),
+			"Approved": docgen.CheckboxValue(true),
+			"Urgent":   docgen.CheckboxValue(false),
+		},
+		FileName: "order",
+	}
+	if _, err := client.GenerateWordWithMeta(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	docgentest.AssertJSONEqualGolden(t, srv.LastRequest().Body, "testdata/wire/content_controls.json", docgentest.GoldenOptions{})
+
+	form := orderForm{
+		Customer: "某某公司",
+		Remark:   "加急处理",
+		Notes:    "详见附件",
+		Quantity: 3,
+		Region:   docgen.DropdownValue("华东"),
+		Channel:  "线上",
+		Source:   docgen.DropdownValue("门店"),
+		SignedOn: signedOn,
+		Approved: true,
+	}
+	if _, err := client.GenerateWordFromStruct("order.docx", form, "order"); err != nil {
+		t.Fatal(err)
+	}
+	docgentest.AssertJSONEqualGolden(t, srv.LastRequest().Body, "testdata/wire/content_controls.json", docgentest.GoldenOptions{})
+}
+
+func TestContentControlValidation(t *testing.T) {
+	srv, client := newContentControlServer(t)
+
+	tests := []struct {
+		name  string
+		tag   string
+		value any
+	}{
+		{"text takes no dropdown", "Remark", docgen.DropdownValue("华东")},
+		{"text takes no struct", "Remark", struct{}{}},
+		{"dropdown needs DropdownValue", "Region", "华东"},
+		{"dropdown option", "Region", docgen.DropdownValue("西北")},
+		{"combo box takes no checkbox", "Channel", docgen.CheckboxValue(true)},
+		{"date needs DateControlValue", "SignedOn", "2024-04-01"},
+		{"checkbox needs CheckboxValue", "Approved", true},
+		{"unknown tag", "Missing", "x"},
+		{"unknown kind", "Remark", docgen.ContentControlValue{Kind: "slider"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(srv.RequestsTo(docgentest.EndpointWord))
+			_, err := client.GenerateWordWithMeta(context.Background(), docgen.WordGenRequest{
+				TemplateName:    "order.docx",
+				ContentControls: map[string]any{tt.tag: tt.value},
+			})
+			var ccErr *docgen.ContentControlError
+			if !errors.Is(err, docgen.ErrContentControlValue) || !errors.As(err, &ccErr) {
+				t.Fatalf("err = %v, want *ContentControlError", err)
+			}
+			if ccErr.Tag != tt.tag {
+				t.Errorf("Tag = %q, want %q", ccErr.Tag, tt.tag)
+			}
+			if n := len(srv.RequestsTo(docgentest.EndpointWord)) - before; n != 0 {
+				t.Errorf("%d requests sent despite the invalid value", n)
+			}
+		})
+	}
+}
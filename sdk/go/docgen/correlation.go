@@ -0,0 +1,79 @@
+package docgen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// CorrelationIDHeader 关联 ID 请求头，服务端日志记录同一个 ID
+const CorrelationIDHeader = "X-Correlation-Id"
+
+// correlationIDContextKey 关联 ID 在 context 中的键
+type correlationIDContextKey struct{}
+
+// WithCorrelationID 返回携带关联 ID 的 context，该 context 发出的所有请求都使用此 ID
+//
+// 通常传入上游请求的 ID，使调用方日志、服务端日志与 SDK 错误可以按同一个 ID 检索；
+// 未指定时 SDK 为每次调用生成 UUIDv7
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDContextKey{}, id)
+}
+
+// CorrelationID 返回 context 中通过 WithCorrelationID 指定的关联 ID，未指定时为空
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDContextKey{}).(string)
+	return id
+}
+
+// withCallCorrelationID context 中没有关联 ID 时生成一个，使一次调用内的预检请求与生成请求使用同一个 ID
+func withCallCorrelationID(ctx context.Context) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+	return WithCorrelationID(ctx, newCorrelationID())
+}
+
+// correlationIDFor 返回请求使用的关联 ID：context 中的 ID 优先，否则生成新的 ID
+func correlationIDFor(ctx context.Context) string {
+	if id := CorrelationID(ctx); id != "" {
+		return id
+	}
+	return newCorrelationID()
+}
+
+// requestCorrelationID 返回请求携带的关联 ID
+func requestCorrelationID(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	return req.Header.Get(CorrelationIDHeader)
+}
+
+// newCorrelationID 生成 UUIDv7（RFC 9562）：48 位毫秒时间戳加随机数，按时间排序；随机数不可用时返回空字符串
+func newCorrelationID() string {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		return ""
+	}
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(time.Now().UnixMilli()))
+	copy(u[:6], ts[2:])
+	u[6] = u[6]&0x0f | 0x70
+	u[8] = u[8]&0x3f | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
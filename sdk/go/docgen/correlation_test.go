@@ -0,0 +1,165 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+var uuidV7 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// receivedCorrelationID 返回模拟服务器收到的生成请求携带的关联 ID，全部相同时返回该 ID
+func receivedCorrelationID(t *testing.T, srv *docgentest.Server) string {
+	t.Helper()
+	sent := srv.RequestsTo(docgentest.EndpointWord)
+	if len(sent) == 0 {
+		t.Fatal("no generation request received")
+	}
+	id := sent[0].Header.Get(docgen.CorrelationIDHeader)
+	for i, r := range sent[1:] {
+		if got := r.Header.Get(docgen.CorrelationIDHeader); got != id {
+			t.Errorf("attempt %d carries %q, want the call's id %q", i+1, got, id)
+		}
+	}
+	return id
+}
+
+func TestCorrelationIDOnSuccess(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		override string
+	}{
+		{"generated", ""},
+		{"WithCorrelationID", "upstream-req-42"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := docgentest.NewServer()
+			defer srv.Close()
+			srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+			var metrics metricsRecorder
+			client := docgen.NewClient(srv.URL, docgen.WithMetricsHook(metrics.record))
+
+			ctx := context.Background()
+			if tt.override != "" {
+				ctx = docgen.WithCorrelationID(ctx, tt.override)
+			}
+			result, err := client.GenerateWordWithMeta(ctx, docgen.WordGenRequest{TemplateName: "t.docx"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			id := receivedCorrelationID(t, srv)
+			switch {
+			case tt.override != "" && id != tt.override:
+				t.Errorf("server received %q, want %q", id, tt.override)
+			case tt.override == "" && !uuidV7.MatchString(id):
+				t.Errorf("server received %q, want a UUIDv7", id)
+			}
+			if result.Meta.CorrelationID != id {
+				t.Errorf("Meta.CorrelationID = %q, want %q", result.Meta.CorrelationID, id)
+			}
+			if got := metrics.last(t).CorrelationID; got != id {
+				t.Errorf("metrics CorrelationID = %q, want %q", got, id)
+			}
+		})
+	}
+}
+
+func TestCorrelationIDOnFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		response docgentest.Response
+	}{
+		{"error response", docgentest.ErrorResponse(http.StatusUnprocessableEntity, docgen.CodeRenderError, "Render error in data.total")},
+		{"dropped connection", docgentest.DropConnection()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, tt.response, tt.response, tt.response, tt.response))
+			defer srv.Close()
+			srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+			dumps := t.TempDir()
+			client := docgen.NewClient(srv.URL, docgen.WithDebugDump(dumps))
+
+			_, err := client.GenerateWordWithMeta(context.Background(), docgen.WordGenRequest{TemplateName: "t.docx"})
+			if err == nil {
+				t.Fatal("request succeeded")
+			}
+			id := receivedCorrelationID(t, srv)
+			if id == "" {
+				t.Fatal("server received no correlation id")
+			}
+			if !strings.Contains(err.Error(), id) {
+				t.Errorf("error %q does not mention correlation id %q", err, id)
+			}
+			var opErr *docgen.OpError
+			if !errors.As(err, &opErr) || opErr.CorrelationID != id {
+				t.Errorf("OpError.CorrelationID = %v, want %q", opErr, id)
+			}
+
+			// 调试文件名同样包含关联 ID
+			if opErr == nil || opErr.DumpPath == "" {
+				t.Fatal("no debug dump for the failed request")
+			}
+			if !strings.Contains(filepath.Base(opErr.DumpPath), id) {
+				t.Errorf("dump %s does not carry correlation id %q", filepath.Base(opErr.DumpPath), id)
+			}
+			if _, err := os.Stat(opErr.DumpPath); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestCorrelationIDsAreUniquePerCall(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	client := docgen.NewClient(srv.URL)
+
+	const calls = 50
+	ids := make([]string, calls)
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := client.GenerateWordWithMeta(context.Background(), docgen.WordGenRequest{TemplateName: "t.docx"})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids[i] = result.Meta.CorrelationID
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, calls)
+	for _, id := range ids {
+		if !uuidV7.MatchString(id) {
+			t.Errorf("correlation id %q is not a UUIDv7", id)
+		}
+		if seen[id] {
+			t.Errorf("correlation id %q used by two calls", id)
+		}
+		seen[id] = true
+	}
+	received := make(map[string]bool, calls)
+	for _, r := range srv.RequestsTo(docgentest.EndpointWord) {
+		received[r.Header.Get(docgen.CorrelationIDHeader)] = true
+	}
+	for id := range seen {
+		if !received[id] {
+			t.Errorf("server never received correlation id %q", id)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package docgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDebugDumpFiles WithDebugDump 默认保留的文件数
+const DefaultDebugDumpFiles = 100
+
+// debugDumpPrefix 调试文件名前缀，清理时只处理带此前缀的文件
+const debugDumpPrefix = "docgen-"
+
+// debugDumper 将每次请求的请求与响应写入目录，便于排查生成结果不正确的问题
+type debugDumper struct {
+	dir string
+	seq atomic.Uint64
+	// pruneMu 串行化超出上限时的清理
+	pruneMu sync.Mutex
+}
+
+// debugRecord 一次请求对应的调试文件，由 newRequest 放入请求的 context
+type debugRecord struct {
+	path string
+}
+
+// debugRecordKey debugRecord 的 context 键
+type debugRecordKey struct{}
+
+// debugDump 调试文件内容
+type debugDump struct {
+	Time            time.Time           `json:"time"`
+	ElapsedMillis   int64               `json:"elapsedMillis"`
+	Method          string              `json:"method"`
+	URL             string              `json:"url"`
+	RequestHeaders  map[string][]string `json:"requestHeaders"`
+	Request         *debugBody          `json:"request,omitempty"`
+	Status          int                 `json:"status,omitempty"`
+	ResponseHeaders map[string][]string `json:"responseHeaders,omitempty"`
+	Response        *debugBody          `json:"response,omitempty"`
+	Error           string              `json:"error,omitempty"`
+}
+
+// debugBody 请求或响应体：JSON 内容（已脱敏）原样记录，其他内容（文档、multipart 表单）只记录大小与摘要
+type debugBody struct {
+	ContentType string          `json:"contentType,omitempty"`
+	Size        int64           `json:"size"`
+	SHA256      string          `json:"sha256,omitempty"`
+	JSON        json.RawMessage `json:"json,omitempty"`
+	// Streamed 文档流式写入调用方，未经过调试记录
+	Streamed bool `json:"streamed,omitempty"`
+}
+
+// debugSensitiveHeaders 调试文件中隐藏取值的请求头
+var debugSensitiveHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// withDebugRecord 启用调试输出时为请求准备调试记录
+func (c *Client) withDebugRecord(ctx context.Context) context.Context {
+	if c.debugDump == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, debugRecordKey{}, &debugRecord{})
+}
+
+// dumpExchange 写入一次请求的调试文件；body 为 nil 且 resp 非 nil 时表示响应体流式交给调用方
+func (c *Client) dumpExchange(req *http.Request, start time.Time, resp *http.Response, body []byte, err error) {
+	record, _ := req.Context().Value(debugRecordKey{}).(*debugRecord)
+	if c.debugDump == nil || record == nil {
+		return
+	}
+
+	dump := debugDump{
+		Time:           start,
+		ElapsedMillis:  time.Since(start).Milliseconds(),
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: debugHeaders(req.Header),
+		Request:        c.debugRequestBody(req),
+	}
+	if resp != nil {
+		dump.Status = resp.StatusCode
+		dump.ResponseHeaders = debugHeaders(resp.Header)
+		if body != nil {
+			dump.Response = c.debugBody(resp.Header.Get("Content-Type"), body)
+		} else {
+			dump.Response = &debugBody{ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength, Streamed: true}
+		}
+	}
+	if err != nil {
+		dump.Error = err.Error()
+	}
+	maxFiles := c.debugDumpLimit
+	if maxFiles <= 0 {
+		maxFiles = DefaultDebugDumpFiles
+	}
+	record.path = c.debugDump.write(req, start, &dump, maxFiles)
+}
+
+// debugRequestBody 读取请求体副本（需要 GetBody，bytes.Reader 等请求体由 http.NewRequest 自动设置）
+func (c *Client) debugRequestBody(req *http.Request) *debugBody {
+	if req.GetBody == nil || req.ContentLength == 0 {
+		return nil
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil
+	}
+	return c.debugBody(req.Header.Get("Content-Type"), data)
+}
+
+// debugBody 按内容类型记录请求或响应体
+func (c *Client) debugBody(contentType string, data []byte) *debugBody {
+	sum := sha256.Sum256(data)
+	b := &debugBody{ContentType: contentType, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) && json.Valid(data) {
+		b.JSON = c.redactor.Redact(data)
+	}
+	return b
+}
+
+// debugHeaders 复制 header 并隐藏认证信息
+func debugHeaders(h http.Header) map[string][]string {
+	out := make(map[string][]string, len(h))
+	for k, v := range h {
+		out[k] = v
+	}
+	for _, k := range debugSensitiveHeaders {
+		if _, ok := out[k]; ok {
+			out[k] = []string{RedactedValue}
+		}
+	}
+	return out
+}
+
+// write 写入调试文件并返回路径，失败时返回空字符串（调试输出不影响请求结果）
+//
+// 文件名由时间戳、序号、请求方法、路径与关联 ID 组成，先写入临时文件再重命名，并发请求不会写入同一个文件
+func (d *debugDumper) write(req *http.Request, start time.Time, dump *debugDump, maxFiles int) string {
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return ""
+	}
+	name := fmt.Sprintf("%s%s-%06d-%s-%s.json", debugDumpPrefix, start.UTC().Format("20060102T150405.000000000Z"),
+		d.seq.Add(1), req.Method, debugPathName(req.URL.Path))
+	if id := debugPathName(requestCorrelationID(req)); id != "" {
+		name = strings.TrimSuffix(name, ".json") + "-" + id + ".json"
+	}
+	path := filepath.Join(d.dir, name)
+
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return ""
+	}
+	tmp, err := os.CreateTemp(d.dir, ".docgen-dump-*")
+	if err != nil {
+		return ""
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return ""
+	}
+	d.prune(maxFiles)
+	return path
+}
+
+// prune 删除超出保留上限的最旧调试文件
+func (d *debugDumper) prune(maxFiles int) {
+	d.pruneMu.Lock()
+	defer d.pruneMu.Unlock()
+
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), debugDumpPrefix) && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= maxFiles {
+		return
+	}
+	// 文件名以时间戳开头，字典序即时间顺序
+	sort.Strings(names)
+	for _, name := range names[:len(names)-maxFiles] {
+		os.Remove(filepath.Join(d.dir, name))
+	}
+}
+
+// debugPathName 将请求路径转换为文件名的一部分，如 "/api/v1/doc/word" → "api_v1_doc_word"
+func debugPathName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.Trim(path, "/"))
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
@@ -0,0 +1,109 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// WarningDegradedOutput 服务端容量不足，文档由 WithDegradedMode 以简化渲染重新生成（由客户端报告），
+// Message 为原请求的错误
+const WarningDegradedOutput = "DEGRADED_OUTPUT"
+
+// isCapacityError 错误是否表示服务端容量不足：错误码为 CodeOverloaded、CodeServiceUnavailable，
+// 或没有错误码的 503 响应（包括响应体不是 JSON 的 503，如负载均衡器的错误页）
+//
+// 限流（CodeRateLimited）与配额耗尽按调用方计算，简化的请求同样会被拒绝，不属于容量不足；
+// 数据与模板错误、超时与网络错误同样不属于
+func isCapacityError(err error) bool {
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		return isOverloaded(errResp.Status, errResp.Code)
+	}
+	// 负载均衡器等返回的 503 通常没有 JSON 错误响应体
+	var statusErr *statusError
+	return errors.As(err, &statusErr) && isOverloaded(statusErr.status, "")
+}
+
+// isOverloaded 状态码与错误码是否表示服务端容量不足，没有错误码时只按 503 判断
+func isOverloaded(status int, code string) bool {
+	switch code {
+	case CodeOverloaded, CodeServiceUnavailable:
+		return true
+	case "":
+		return status == http.StatusServiceUnavailable
+	}
+	return false
+}
+
+// degradedRequest 启用 WithDegradedMode 且 err 表示容量不足（isCapacityError）时，
+// 返回设置了 SimpleRender 的请求，以 degradedContext 发送
+//
+// 请求已是简化渲染、调用 context 已结束或服务端明确不支持 FeatureSimpleRender 时不重试；
+// 重试与其他重试一样消耗 RetryBudget，预算用完时不重试，调用方返回原错误
+func (c *Client) degradedRequest(ctx context.Context, reqBody any, err error) (any, bool) {
+	if !c.degradedMode || !isCapacityError(err) || ctx.Err() != nil {
+		return nil, false
+	}
+	simple, ok := simpleRenderRequest(reqBody)
+	if !ok || c.rejectUnsupported(ctx, FeatureSimpleRender) != nil {
+		return nil, false
+	}
+	if c.spendRetry(ctx, "simplified render retry", err) != nil {
+		return nil, false
+	}
+	return simple, true
+}
+
+// degradedContext 返回简化渲染重试使用的 context：计入 EndpointStats.Retries
+func degradedContext(ctx context.Context) context.Context {
+	return withRetryAttempt(ctx)
+}
+
+// simpleRenderRequest 返回设置了 SimpleRender 的请求副本，请求已是简化渲染或不支持时返回 false
+func simpleRenderRequest(reqBody any) (any, bool) {
+	switch req := reqBody.(type) {
+	case WordGenRequest:
+		if !req.SimpleRender {
+			req.SimpleRender = true
+			return req, true
+		}
+	case WordBatchRequest:
+		if !req.SimpleRender {
+			req.SimpleRender = true
+			return req, true
+		}
+	case ExcelGenRequest:
+		if !req.SimpleRender {
+			req.SimpleRender = true
+			return req, true
+		}
+	case ExcelFillRequest:
+		if !req.SimpleRender {
+			req.SimpleRender = true
+			return req, true
+		}
+	case AssemblySpec:
+		if !req.SimpleRender {
+			req.SimpleRender = true
+			return req, true
+		}
+	}
+	return nil, false
+}
+
+// degradedRetryError 简化渲染重试也失败时的错误，errors.Is / errors.As 对两个错误均成立
+func degradedRetryError(err, retryErr error) error {
+	return fmt.Errorf("%w (simplified render retry: %w)", err, retryErr)
+}
+
+// markDegraded 标记文档为简化渲染的结果，并以 WarningDegradedOutput 报告给 WithWarningHandler
+func (c *Client) markDegraded(path string, meta *DocumentMeta, cause error) {
+	w := Warning{Code: WarningDegradedOutput, Message: cause.Error()}
+	meta.Degraded = true
+	meta.Warnings = append(meta.Warnings, w)
+	if c.warningHandler != nil {
+		c.warningHandler(http.MethodPost+" "+path, w)
+	}
+}
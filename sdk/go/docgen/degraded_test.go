@@ -0,0 +1,152 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// sheddingServer 完整渲染的 Word 生成请求被拒绝、简化渲染的请求正常生成的 docgentest.Server
+type sheddingServer struct {
+	*docgentest.Server
+}
+
+// newSheddingServer 完整渲染的请求返回 status / code，code 为空时响应没有错误码与响应体
+func newSheddingServer(t *testing.T, status int, code string) *sheddingServer {
+	t.Helper()
+	resp := docgentest.Malformed(status, "")
+	if code != "" {
+		resp = docgentest.ErrorResponse(status, code, "rejected")
+	}
+	srv := docgentest.NewServer(docgentest.WithLoadSheddingResponse(docgentest.EndpointWord, resp))
+	t.Cleanup(srv.Close)
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	return &sheddingServer{srv}
+}
+
+// requests 返回各次生成请求是否为简化渲染
+func (s *sheddingServer) requests() []bool {
+	var simple []bool
+	for _, r := range s.RequestsTo(docgentest.EndpointWord) {
+		var body struct {
+			SimpleRender bool `json:"simpleRender"`
+		}
+		_ = json.Unmarshal(r.Body, &body)
+		simple = append(simple, body.SimpleRender)
+	}
+	return simple
+}
+
+func TestDegradedModeOnlyForOverload(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   int
+		code     string
+		degraded bool
+	}{
+		{"503 overloaded", http.StatusServiceUnavailable, docgen.CodeOverloaded, true},
+		{"503 service unavailable", http.StatusServiceUnavailable, docgen.CodeServiceUnavailable, true},
+		{"503 without code", http.StatusServiceUnavailable, "", true},
+		{"429 overloaded", http.StatusTooManyRequests, docgen.CodeOverloaded, true},
+		{"429 rate limited", http.StatusTooManyRequests, docgen.CodeRateLimited, false},
+		{"429 quota exceeded", http.StatusTooManyRequests, docgen.CodeQuotaExceeded, false},
+		{"422 render error", http.StatusUnprocessableEntity, docgen.CodeRenderError, false},
+		{"404 template not found", http.StatusNotFound, docgen.CodeTemplateNotFound, false},
+		{"500 internal error", http.StatusInternalServerError, docgen.CodeInternalError, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings []docgen.Warning
+			calls := map[string]func(*docgen.Client) (*docgen.DocumentMeta, error){
+				"buffered": func(c *docgen.Client) (*docgen.DocumentMeta, error) {
+					res, err := c.GenerateWordWithMeta(context.Background(), wordReq)
+					if err != nil {
+						return nil, err
+					}
+					return &res.Meta, nil
+				},
+				"streaming": func(c *docgen.Client) (*docgen.DocumentMeta, error) {
+					return c.GenerateWordTo(context.Background(), wordReq, &bytes.Buffer{})
+				},
+			}
+			for path, call := range calls {
+				srv := newSheddingServer(t, tc.status, tc.code)
+				client := docgen.NewClient(srv.URL, docgen.WithDegradedMode(), docgen.WithWarningHandler(func(op string, w docgen.Warning) {
+					warnings = append(warnings, w)
+				}))
+				meta, err := call(client)
+				sent := srv.requests()
+				if !tc.degraded {
+					var errResp *docgen.ErrorResponse
+					if !errors.As(err, &errResp) || errResp.Status != tc.status {
+						t.Errorf("%s: err = %v, want the %d response", path, err, tc.status)
+					}
+					if len(sent) != 1 {
+						t.Errorf("%s: sent %d requests (simpleRender %v), want no fallback", path, len(sent), sent)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", path, err)
+				}
+				if !meta.Degraded || len(sent) != 2 || sent[0] || !sent[1] {
+					t.Errorf("%s: Degraded = %v, simpleRender per request %v", path, meta.Degraded, sent)
+				}
+			}
+			if got := len(warnings) > 0; got != tc.degraded {
+				t.Errorf("warnings = %v", warnings)
+			}
+			for _, w := range warnings {
+				if w.Code != docgen.WarningDegradedOutput {
+					t.Errorf("warning %+v", w)
+				}
+			}
+		})
+	}
+}
+
+// TestDegradedModeSpendsRetryBudget 简化渲染重试消耗共享的 RetryBudget：预算用完后不再重试，返回原来的容量不足错误
+func TestDegradedModeSpendsRetryBudget(t *testing.T) {
+	calls := map[string]func(*docgen.Client) error{
+		"buffered": func(c *docgen.Client) error {
+			_, err := c.GenerateWordWithMeta(context.Background(), wordReq)
+			return err
+		},
+		"streaming": func(c *docgen.Client) error {
+			_, err := c.GenerateWordTo(context.Background(), wordReq, &bytes.Buffer{})
+			return err
+		},
+	}
+	for path, call := range calls {
+		t.Run(path, func(t *testing.T) {
+			srv := newSheddingServer(t, http.StatusServiceUnavailable, docgen.CodeOverloaded)
+			budget := docgen.NewRetryBudget(1, time.Hour)
+			client := docgen.NewClient(srv.URL, docgen.WithDegradedMode(), docgen.WithRetryBudget(budget))
+
+			if err := call(client); err != nil {
+				t.Fatalf("first call: %v", err)
+			}
+			err := call(client)
+			var errResp *docgen.ErrorResponse
+			if !errors.As(err, &errResp) || errResp.Code != docgen.CodeOverloaded {
+				t.Fatalf("second call: err = %v, want the original overload response", err)
+			}
+			if errors.Is(err, docgen.ErrRetryBudgetExhausted) {
+				t.Errorf("err = %v, want the overload error rather than the budget error", err)
+			}
+			if sent := srv.requests(); len(sent) != 3 || !sent[1] || sent[2] {
+				t.Errorf("simpleRender per request = %v, want the second call's retry skipped", sent)
+			}
+			if state := budget.State(); state.Spent != 1 || state.Denied != 1 {
+				t.Errorf("budget Spent=%d Denied=%d, want 1 1", state.Spent, state.Denied)
+			}
+		})
+	}
+}
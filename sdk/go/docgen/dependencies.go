@@ -0,0 +1,112 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ErrTemplateInUse 模板仍被其他模板引用（include），删除后引用它的模板将无法渲染
+//
+// SDK 检查发现时具体错误类型为 *TemplateInUseError，可通过 errors.As 获取引用方名称
+var ErrTemplateInUse = errors.New("docgen: template in use")
+
+// TemplateInUseError 模板仍被其他模板引用
+//
+// errors.Is(err, ErrTemplateInUse) 返回 true
+type TemplateInUseError struct {
+	// TemplateName 要删除的模板文件名
+	TemplateName string
+	// Dependents 引用该模板的模板文件名（已排序）
+	Dependents []string
+}
+
+// Error 实现 error 接口
+func (e *TemplateInUseError) Error() string {
+	return fmt.Sprintf("%v: %s is included by %s", ErrTemplateInUse, e.TemplateName, strings.Join(e.Dependents, ", "))
+}
+
+// Is 使 errors.Is(err, ErrTemplateInUse) 成立
+func (e *TemplateInUseError) Is(target error) bool {
+	return target == ErrTemplateInUse
+}
+
+// DependencyGraph 模板的直接引用关系
+type DependencyGraph struct {
+	// TemplateName 模板文件名
+	TemplateName string `json:"templateName"`
+	// Includes 该模板引用的子模板（页眉块、条款库等）
+	Includes []string `json:"includes"`
+	// Dependents 引用该模板的模板
+	Dependents []string `json:"dependents"`
+}
+
+// GetTemplateDependencies 获取模板的引用关系：引用的子模板与引用它的模板
+//
+// templateName: 模板文件名
+//
+// 只返回直接引用，间接引用需对结果中的模板继续查询；服务端不支持时返回 *UnsupportedFeatureError
+func (c *Client) GetTemplateDependencies(templateName string) (*DependencyGraph, error) {
+	return c.templateDependencies(context.Background(), templateName)
+}
+
+// templateDependencies GetTemplateDependencies 的 context 版本
+func (c *Client) templateDependencies(ctx context.Context, templateName string) (*DependencyGraph, error) {
+	if err := c.requireFeature(ctx, FeatureTemplateDependencies); err != nil {
+		return nil, err
+	}
+	var graph DependencyGraph
+	if err := c.doJSON(ctx, http.MethodGet, c.templatePath(ctx, "/api/v1/template/dependencies", templateName), nil, &graph); err != nil {
+		return nil, err
+	}
+	graph.TemplateName = templateName
+	graph.Includes = c.unqualifyTemplates(ctx, graph.Includes)
+	graph.Dependents = c.unqualifyTemplates(ctx, graph.Dependents)
+	sort.Strings(graph.Includes)
+	sort.Strings(graph.Dependents)
+	return &graph, nil
+}
+
+// DeleteOptions DeleteTemplateWithOptions 的选项
+type DeleteOptions struct {
+	// CheckDependents 删除前检查引用关系，仍被其他模板引用时拒绝删除并返回 *TemplateInUseError
+	//
+	// 服务端不支持 FeatureTemplateDependencies 时返回 *UnsupportedFeatureError 而不删除
+	CheckDependents bool
+}
+
+// DeleteTemplateWithOptions 按选项删除模板文件
+//
+// 检查与删除之间其他客户端新增的引用无法发现；需要严格保证时应由服务端拒绝（CodeTemplateInUse）
+func (c *Client) DeleteTemplateWithOptions(ctx context.Context, templateName string, opts DeleteOptions) (*DeleteResponse, error) {
+	if opts.CheckDependents {
+		if err := c.checkDependents(ctx, templateName, nil); err != nil {
+			return nil, err
+		}
+	}
+	return c.deleteTemplateContext(ctx, templateName)
+}
+
+// checkDependents 模板被 ignore 以外的模板引用时返回 *TemplateInUseError
+func (c *Client) checkDependents(ctx context.Context, templateName string, ignore map[string]bool) error {
+	graph, err := c.templateDependencies(ctx, templateName)
+	if err != nil {
+		if isTemplateNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	var dependents []string
+	for _, name := range graph.Dependents {
+		if !ignore[name] {
+			dependents = append(dependents, name)
+		}
+	}
+	if len(dependents) > 0 {
+		return &TemplateInUseError{TemplateName: templateName, Dependents: dependents}
+	}
+	return nil
+}
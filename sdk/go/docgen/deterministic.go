@@ -0,0 +1,156 @@
+package docgen
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// normalizedModTime NormalizeDocument 使用的压缩包条目修改时间（ZIP 格式可表示的最早时间）
+var normalizedModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// normalizedTimestamp docProps/core.xml 中时间戳归零后的值
+const normalizedTimestamp = "1980-01-01T00:00:00Z"
+
+// 每次保存都会变化的 OOXML 内容
+var (
+	// rsidAttr Word 的修订会话 ID（w:rsidR、w:rsidRPr、w:rsidRDefault 等）
+	rsidAttr = regexp.MustCompile(`\s+w:rsid[A-Za-z]*="[^"]*"`)
+	// rsidList word/settings.xml 中记录全部修订会话 ID 的 w:rsids 元素
+	rsidList = regexp.MustCompile(`(?s)<w:rsids>.*?</w:rsids>|<w:rsids/>`)
+	// coreTimestamp docProps/core.xml 中的创建、修改与打印时间
+	coreTimestamp = regexp.MustCompile(`(<(dcterms:created|dcterms:modified|cp:lastPrinted)\b[^>]*>)[^<]*(</(?:dcterms:created|dcterms:modified|cp:lastPrinted)>)`)
+	// totalTime docProps/app.xml 中的累计编辑时间
+	totalTime = regexp.MustCompile(`<TotalTime>[^<]*</TotalTime>`)
+)
+
+// NormalizeDocument 重写 .docx / .xlsx 压缩包，使相同内容的文档逐字节相同
+//
+// 条目按名称排序（[Content_Types].xml 在最前）并以固定修改时间重新压缩；XML 部件去除 rsid 属性与 w:rsids 列表，
+// docProps 中的时间戳归零、累计编辑时间置 0。压缩包中的 .docx / .xlsx 条目（如批量压缩包）递归处理。
+// 文档内容与显示效果不变；doc 不是 ZIP 压缩包时返回错误
+func NormalizeDocument(doc []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(doc), int64(len(doc)))
+	if err != nil {
+		return nil, fmt.Errorf("docgen: normalize: invalid zip archive: %w", err)
+	}
+	files := append([]*zip.File(nil), zr.File...)
+	sort.SliceStable(files, func(i, j int) bool {
+		if (files[i].Name == "[Content_Types].xml") != (files[j].Name == "[Content_Types].xml") {
+			return files[i].Name == "[Content_Types].xml"
+		}
+		return files[i].Name < files[j].Name
+	})
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("docgen: normalize %s: %w", f.Name, err)
+		}
+		if data, err = normalizePart(f.Name, data); err != nil {
+			return nil, fmt.Errorf("docgen: normalize %s: %w", f.Name, err)
+		}
+		method := zip.Deflate
+		if strings.HasSuffix(f.Name, "/") {
+			method = zip.Store
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: method, Modified: normalizedModTime})
+		if err != nil {
+			return nil, fmt.Errorf("docgen: normalize %s: %w", f.Name, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("docgen: normalize %s: %w", f.Name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("docgen: normalize: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizePart 去除单个部件中的易变内容，嵌套的文档递归处理，其他部件原样返回
+func normalizePart(name string, data []byte) ([]byte, error) {
+	switch {
+	case isOfficeArchive(name):
+		return NormalizeDocument(data)
+	case strings.HasSuffix(name, ".xml"):
+		data = rsidAttr.ReplaceAll(data, nil)
+		data = rsidList.ReplaceAll(data, nil)
+		if strings.HasPrefix(name, "docProps/") {
+			data = coreTimestamp.ReplaceAll(data, []byte("${1}"+normalizedTimestamp+"${3}"))
+			data = totalTime.ReplaceAll(data, []byte("<TotalTime>0</TotalTime>"))
+		}
+	}
+	return data, nil
+}
+
+// isOfficeArchive 条目名称是否为 OOXML 文档
+func isOfficeArchive(name string) bool {
+	switch strings.ToLower(name[strings.LastIndexByte(name, '.')+1:]) {
+	case "docx", "docm", "xlsx", "xlsm":
+		return true
+	}
+	return false
+}
+
+// readZipFile 读取压缩包条目的内容
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// deterministicRequest 请求是否要求可复现的输出
+func deterministicRequest(reqBody any) bool {
+	switch req := reqBody.(type) {
+	case WordGenRequest:
+		return req.Deterministic
+	case WordBatchRequest:
+		return req.Deterministic
+	case ExcelGenRequest:
+		return req.Deterministic
+	case ExcelFillRequest:
+		return req.Deterministic
+	case AssemblySpec:
+		return req.Deterministic
+	}
+	return false
+}
+
+// needsNormalize 请求要求可复现的输出，且不能确认服务端支持 FeatureDeterministicOutput 时由 SDK 处理
+func (c *Client) needsNormalize(ctx context.Context, reqBody any) bool {
+	if !deterministicRequest(reqBody) {
+		return false
+	}
+	caps, err := c.Capabilities(ctx)
+	return err != nil || !caps.Supports(FeatureDeterministicOutput)
+}
+
+// normalizeResult 以 NormalizeDocument 处理结果并更新大小与摘要；PDF 等非压缩包文档原样保留
+func normalizeResult(result *DocumentResult) error {
+	if !bytes.HasPrefix(result.Data, []byte("PK")) {
+		return nil
+	}
+	doc, err := NormalizeDocument(result.Data)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(doc)
+	result.Data = doc
+	result.Meta.Size = int64(len(doc))
+	result.Meta.SHA256 = hex.EncodeToString(sum[:])
+	return nil
+}
@@ -0,0 +1,128 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// renderHash 生成两次相同的请求，返回各自文档的 SHA-256，并检查 Meta.SHA256 与文档一致
+func renderHash(t *testing.T, render func() ([]byte, *docgen.DocumentMeta, error)) [2]string {
+	t.Helper()
+	var sums [2]string
+	for i := range sums {
+		doc, meta, err := render()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(doc)
+		sums[i] = hex.EncodeToString(sum[:])
+		if meta.SHA256 != sums[i] || meta.Size != int64(len(doc)) {
+			t.Errorf("render %d: Meta SHA256=%s Size=%d, want %s %d", i, meta.SHA256, meta.Size, sums[i], len(doc))
+		}
+	}
+	return sums
+}
+
+// TestDeterministicRendersHashIdentically 服务端每次生成的字节不同（压缩包时间戳、随机 rsid）；
+// 设置 Deterministic 后两次生成的文档哈希相同，无论由服务端还是 SDK 的 NormalizeDocument 保证
+func TestDeterministicRendersHashIdentically(t *testing.T) {
+	servers := []struct {
+		name string
+		opts []docgentest.ServerOption
+	}{
+		{"server support", []docgentest.ServerOption{docgentest.WithVolatileOutput()}},
+		{"client fallback", []docgentest.ServerOption{docgentest.WithVolatileOutput(), docgentest.WithDisabledFeatures(docgen.FeatureDeterministicOutput)}},
+	}
+	for _, s := range servers {
+		t.Run(s.name, func(t *testing.T) {
+			srv := docgentest.NewServer(s.opts...)
+			defer srv.Close()
+			srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+			client := docgen.NewClient(srv.URL)
+			req := docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"name": "张三"}}
+
+			// 未要求可复现输出时两次生成确实不同，否则下面的断言没有意义
+			volatile := renderHash(t, func() ([]byte, *docgen.DocumentMeta, error) {
+				result, err := client.GenerateWordWithMeta(context.Background(), req)
+				if err != nil {
+					return nil, nil, err
+				}
+				return result.Data, &result.Meta, nil
+			})
+			if volatile[0] == volatile[1] {
+				t.Fatal("the volatile server rendered identical bytes without Deterministic")
+			}
+
+			req.Deterministic = true
+			buffered := renderHash(t, func() ([]byte, *docgen.DocumentMeta, error) {
+				result, err := client.GenerateWordWithMeta(context.Background(), req)
+				if err != nil {
+					return nil, nil, err
+				}
+				return result.Data, &result.Meta, nil
+			})
+			if buffered[0] != buffered[1] {
+				t.Errorf("GenerateWordWithMeta: renders hash to %s and %s", buffered[0], buffered[1])
+			}
+			streamed := renderHash(t, func() ([]byte, *docgen.DocumentMeta, error) {
+				var buf bytes.Buffer
+				meta, err := client.GenerateWordTo(context.Background(), req, &buf)
+				return buf.Bytes(), meta, err
+			})
+			if streamed[0] != streamed[1] || streamed[0] != buffered[0] {
+				t.Errorf("GenerateWordTo: renders hash to %s and %s, want %s", streamed[0], streamed[1], buffered[0])
+			}
+		})
+	}
+}
+
+func TestNormalizeDocument(t *testing.T) {
+	srv := docgentest.NewServer(docgentest.WithVolatileOutput())
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	client := docgen.NewClient(srv.URL)
+	req := docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"name": "张三"}}
+
+	var rendered, normalized [2][]byte
+	for i := range normalized {
+		result, err := client.GenerateWordWithMeta(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rendered[i] = result.Data
+		if normalized[i], err = docgen.NormalizeDocument(result.Data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 两次生成的 rsid 与时间戳不同，规范化后逐字节相同
+	if bytes.Equal(rendered[0], rendered[1]) || !bytes.Equal(normalized[0], normalized[1]) {
+		t.Error("normalized renders differ")
+	}
+	// 文档内容不变
+	want, err := docgentest.ExtractDocxText(rendered[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := docgentest.ExtractDocxText(normalized[0]); err != nil || got != want {
+		t.Errorf("normalized text = %q, %v; want %q", got, err, want)
+	}
+
+	// 规范化结果再次规范化不变
+	again, err := docgen.NormalizeDocument(normalized[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, normalized[0]) {
+		t.Error("NormalizeDocument is not idempotent")
+	}
+
+	if _, err := docgen.NormalizeDocument([]byte("%PDF-1.7")); err == nil {
+		t.Error("NormalizeDocument accepted a non-zip document")
+	}
+}
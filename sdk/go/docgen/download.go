@@ -0,0 +1,284 @@
+package docgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChecksumMismatch 响应内容的校验和与服务端提供的摘要不一致，具体摘要见 *ChecksumMismatchError
+var ErrChecksumMismatch = errors.New("docgen: checksum mismatch")
+
+// 续传重试参数
+const (
+	// resumeMaxAttempts 单次调用中连续中断（无新数据）后放弃前的最大尝试次数
+	resumeMaxAttempts = 5
+	// resumeBackoff 连续失败时的重试间隔步长（第一次续传立即进行）
+	resumeBackoff = 500 * time.Millisecond
+)
+
+// DownloadJobResultResumable 以可续传方式下载任务结果到 dest
+//
+// ctx: 取消后停止下载，已下载部分保留在 dest+".partial" 中
+// jobID: 任务 ID
+// dest: 目标文件路径
+//
+// 下载内容先写入 dest+".partial"，连接中断时使用 Range 请求从已下载位置继续；
+// 进程退出后再次调用同样会从 .partial 文件继续。服务端忽略 Range 或结果已变化时从头下载。
+// 完成后校验服务端提供的 SHA-256 摘要（Digest / Repr-Digest / X-Checksum-SHA256 响应头），
+// 不一致时删除 .partial 并返回 ErrChecksumMismatch，一致时原子重命名为 dest
+func (c *Client) DownloadJobResultResumable(ctx context.Context, jobID, dest string) error {
+	return c.downloadResumable(ctx, jobPath(jobID, "/result"), dest)
+}
+
+// DownloadTemplateResumable 以可续传方式下载模板文件到 dest，行为与 DownloadJobResultResumable 一致
+func (c *Client) DownloadTemplateResumable(ctx context.Context, templateName, dest string) error {
+	return c.downloadResumable(ctx, c.templatePath(ctx, "/api/v1/template/download", templateName), dest)
+}
+
+// partialMeta .partial 文件对应的资源版本信息，用于 If-Range 与最终校验
+type partialMeta struct {
+	ETag   string `json:"etag,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// downloadResumable 可续传下载的通用实现
+func (c *Client) downloadResumable(ctx context.Context, path, dest string) error {
+	partial := dest + ".partial"
+	metaPath := partial + ".meta"
+
+	failures := 0
+	attemptCtx := ctx
+	for {
+		written, err := c.downloadAttempt(attemptCtx, path, partial, metaPath)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil || !isResumable(err) {
+			return err
+		}
+		// 有进展时重新计数，只有连续失败才放弃
+		if written > 0 {
+			failures = 0
+		}
+		failures++
+		if failures >= resumeMaxAttempts {
+			return fmt.Errorf("download %s: giving up after %d consecutive failures: %w", path, failures, err)
+		}
+		if err := c.spendRetry(ctx, "download "+path, err); err != nil {
+			return err
+		}
+		attemptCtx = withRetryAttempt(ctx)
+
+		select {
+		case <-time.After(time.Duration(failures-1) * resumeBackoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	meta := readPartialMeta(metaPath)
+	if meta.SHA256 != "" {
+		sum, err := fileSHA256(partial)
+		if err != nil {
+			return err
+		}
+		if sum != meta.SHA256 {
+			os.Remove(partial)
+			os.Remove(metaPath)
+			return &ChecksumMismatchError{Endpoint: path, Expected: meta.SHA256, Actual: sum}
+		}
+	}
+
+	if err := os.Rename(partial, dest); err != nil {
+		return fmt.Errorf("failed to finalize download: %w", err)
+	}
+	os.Remove(metaPath)
+	return nil
+}
+
+// downloadAttempt 发送一次（可能带 Range 的）下载请求并写入 .partial
+//
+// 返回本次写入的字节数；返回 nil 错误表示 .partial 已下载完整
+func (c *Client) downloadAttempt(ctx context.Context, path, partial, metaPath string) (int64, error) {
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+	meta := readPartialMeta(metaPath)
+
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if meta.ETag != "" {
+			// 资源已变化时服务端返回完整内容（200），避免拼接出混合版本的文件
+			req.Header.Set("If-Range", meta.ETag)
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.doStream(req)
+	if err != nil {
+		return 0, err
+	}
+	c.withBodyReadTimeout(req, resp, start)
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, ok := contentRangeStart(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			// 服务端返回的区间与请求不一致，丢弃已下载部分重新开始
+			os.Remove(partial)
+			return 0, fmt.Errorf("%w: unexpected Content-Range %q for offset %d", errRestartDownload, resp.Header.Get("Content-Range"), offset)
+		}
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		// 首次下载，或服务端忽略 Range / 资源已变化：从头写入
+		flags |= os.O_TRUNC
+		meta = partialMeta{ETag: resp.Header.Get("ETag"), SHA256: responseSHA256(resp.Header)}
+		if err := writePartialMeta(metaPath, meta); err != nil {
+			return 0, err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// 上次已下载完整但未完成重命名
+		if total, ok := contentRangeTotal(resp.Header.Get("Content-Range")); ok && total == offset {
+			return 0, nil
+		}
+		// .partial 与服务端内容不匹配（如资源变小），从头下载
+		os.Remove(partial)
+		return 0, fmt.Errorf("%w: range not satisfiable at offset %d", errRestartDownload, offset)
+	default:
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return 0, c.parseErrorResponse(req, resp, respBody)
+	}
+
+	f, err := os.OpenFile(partial, flags, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", partial, err)
+	}
+	written, copyErr := io.Copy(f, resp.Body)
+	closeErr := f.Close()
+	if copyErr != nil {
+		if ctx.Err() != nil {
+			return written, ctx.Err()
+		}
+		return written, fmt.Errorf("%w: %w", errRestartDownload, copyErr)
+	}
+	if closeErr != nil {
+		return written, fmt.Errorf("failed to write %s: %w", partial, closeErr)
+	}
+	return written, nil
+}
+
+// errRestartDownload 连接中断或服务端响应需要重新发起下载请求
+var errRestartDownload = errors.New("download interrupted")
+
+// isResumable 判断下载错误是否可以通过再次请求继续
+func isResumable(err error) bool {
+	return errors.Is(err, errRestartDownload) || IsRetryable(err)
+}
+
+// contentRangeStart 解析 "bytes start-end/total" 中的起始位置
+func contentRangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	startStr, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	return start, err == nil
+}
+
+// contentRangeTotal 解析 "bytes */total" 或 "bytes start-end/total" 中的总长度
+func contentRangeTotal(header string) (int64, bool) {
+	_, totalStr, ok := strings.Cut(header, "/")
+	if !ok {
+		return 0, false
+	}
+	total, err := strconv.ParseInt(totalStr, 10, 64)
+	return total, err == nil
+}
+
+// responseSHA256 从响应头中读取 SHA-256 摘要（十六进制小写），未提供时返回空字符串
+//
+// 支持 X-Content-SHA256 / X-Checksum-SHA256: <hex>、Repr-Digest: sha-256=:<base64>:（RFC 9530）、
+// Digest: SHA-256=<base64>（RFC 3230），以及值为 64 位十六进制 SHA-256 的强 ETag
+func responseSHA256(h http.Header) string {
+	for _, name := range []string{"X-Content-SHA256", "X-Checksum-SHA256"} {
+		if v := strings.TrimSpace(h.Get(name)); len(v) == sha256.Size*2 {
+			if _, err := hex.DecodeString(v); err == nil {
+				return strings.ToLower(v)
+			}
+		}
+	}
+	for _, name := range []string{"Repr-Digest", "Digest"} {
+		for _, item := range strings.Split(h.Get(name), ",") {
+			alg, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+			if !ok || !strings.EqualFold(alg, "sha-256") {
+				continue
+			}
+			raw, err := base64.StdEncoding.DecodeString(strings.Trim(value, ":"))
+			if err == nil && len(raw) == sha256.Size {
+				return hex.EncodeToString(raw)
+			}
+		}
+	}
+	// 弱 ETag（W/ 前缀）不保证内容逐字节一致，不能作为摘要
+	if etag := strings.Trim(h.Get("ETag"), `"`); len(etag) == sha256.Size*2 {
+		if _, err := hex.DecodeString(etag); err == nil {
+			return strings.ToLower(etag)
+		}
+	}
+	return ""
+}
+
+// fileSHA256 计算文件的 SHA-256（十六进制小写）
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readPartialMeta 读取 .partial 的版本信息，不存在或损坏时返回零值
+func readPartialMeta(path string) partialMeta {
+	var meta partialMeta
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &meta)
+	}
+	return meta
+}
+
+// writePartialMeta 保存 .partial 的版本信息
+func writePartialMeta(path string, meta partialMeta) error {
+	data, _ := json.Marshal(meta)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
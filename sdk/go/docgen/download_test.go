@@ -0,0 +1,163 @@
+package docgen_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// jobResultEndpoint 任务 j1 的结果下载路径
+const jobResultEndpoint = docgentest.EndpointJobs + "/j1/result"
+
+// rangeServer 提供任务 j1 结果下载的 docgentest.Server，content 为结果内容
+type rangeServer struct {
+	*docgentest.Server
+	content []byte
+}
+
+// newRangeServer 任务 j1 的结果为 size 字节随机内容：第 n 次下载只输出 cuts[n] 字节后断开连接，之后的下载完整输出
+func newRangeServer(t *testing.T, size int, cuts ...int) *rangeServer {
+	t.Helper()
+	downloads := make([]docgentest.Response, len(cuts))
+	for i, cut := range cuts {
+		downloads[i] = docgentest.InterruptedDownload(int64(cut))
+	}
+	return newResultServer(t, size, docgentest.WithSequence(jobResultEndpoint, downloads...))
+}
+
+// newResultServer 任务 j1 的结果为 size 字节随机内容
+func newResultServer(t *testing.T, size int, opts ...docgentest.ServerOption) *rangeServer {
+	t.Helper()
+	s := &rangeServer{Server: docgentest.NewServer(opts...), content: make([]byte, size)}
+	t.Cleanup(s.Close)
+	_, _ = rand.Read(s.content)
+	s.AddJob(docgen.Job{ID: "j1", State: docgen.JobSucceeded}, s.content)
+	return s
+}
+
+// ranges 返回各次下载请求的 Range 请求头
+func (s *rangeServer) ranges() []string {
+	var ranges []string
+	for _, r := range s.RequestsTo(jobResultEndpoint) {
+		ranges = append(ranges, r.Header.Get("Range"))
+	}
+	return ranges
+}
+
+func assertDownloaded(t *testing.T, dest string, want []byte) {
+	t.Helper()
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sha256.Sum256(got) != sha256.Sum256(want) {
+		t.Errorf("downloaded %d bytes, want %d identical bytes", len(got), len(want))
+	}
+	if leftover, _ := filepath.Glob(dest + ".partial*"); len(leftover) != 0 {
+		t.Errorf("leftover files: %v", leftover)
+	}
+}
+
+func TestDownloadResumesWithRange(t *testing.T) {
+	srv := newRangeServer(t, 256<<10, 100<<10, 50<<10)
+	dest := filepath.Join(t.TempDir(), "result.docx")
+
+	if err := docgen.NewClient(srv.URL).DownloadJobResultResumable(context.Background(), "j1", dest); err != nil {
+		t.Fatal(err)
+	}
+	assertDownloaded(t, dest, srv.content)
+	if got, want := srv.ranges(), []string{"", "bytes=102400-", "bytes=153600-"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Range headers = %q, want %q", got, want)
+	}
+	for i, r := range srv.RequestsTo(jobResultEndpoint)[1:] {
+		if r.Header.Get("If-Range") == "" {
+			t.Errorf("resume %d sent no If-Range", i+1)
+		}
+	}
+}
+
+func TestDownloadRestartsWhenServerIgnoresRange(t *testing.T) {
+	srv := newResultServer(t, 256<<10, docgentest.WithoutRangeRequests(),
+		docgentest.WithSequence(jobResultEndpoint, docgentest.InterruptedDownload(100<<10), docgentest.InterruptedDownload(200<<10)))
+	dest := filepath.Join(t.TempDir(), "result.docx")
+
+	if err := docgen.NewClient(srv.URL).DownloadJobResultResumable(context.Background(), "j1", dest); err != nil {
+		t.Fatal(err)
+	}
+	// 200 响应从头覆盖 .partial，不会把完整内容拼接在已下载部分之后
+	assertDownloaded(t, dest, srv.content)
+	if got := srv.ranges(); len(got) != 3 || got[1] != "bytes=102400-" || got[2] != "bytes=204800-" {
+		t.Errorf("Range headers = %q", got)
+	}
+}
+
+func TestDownloadResumesAfterProcessRestart(t *testing.T) {
+	srv := newResultServer(t, 256<<10, docgentest.WithSequence(jobResultEndpoint, docgentest.StalledDownload(64<<10)))
+	dest := filepath.Join(t.TempDir(), "result.docx")
+
+	// 第一个"进程"在下载到一半时被终止
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- docgen.NewClient(srv.URL).DownloadJobResultResumable(ctx, "j1", dest) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if info, err := os.Stat(dest + ".partial"); err == nil && info.Size() == 64<<10 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("partial download never reached 64 KiB")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted download: err = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Fatalf("dest exists after interruption: %v", err)
+	}
+
+	// 新的客户端从 .partial 继续
+	if err := docgen.NewClient(srv.URL).DownloadJobResultResumable(context.Background(), "j1", dest); err != nil {
+		t.Fatal(err)
+	}
+	assertDownloaded(t, dest, srv.content)
+	if got := srv.ranges(); len(got) != 2 || got[1] != "bytes=65536-" {
+		t.Errorf("Range headers = %q, want a resume from 65536", got)
+	}
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	checksum := strings.Repeat("ab", sha256.Size)
+	// withChecksum 响应携带与内容不符的摘要
+	withChecksum := func(resp docgentest.Response) docgentest.Response {
+		resp.Header = http.Header{"X-Checksum-SHA256": {checksum}}
+		return resp
+	}
+	srv := newResultServer(t, 64<<10, docgentest.WithSequence(jobResultEndpoint,
+		withChecksum(docgentest.InterruptedDownload(10<<10)), withChecksum(docgentest.Delayed(0))))
+	dest := filepath.Join(t.TempDir(), "result.docx")
+
+	err := docgen.NewClient(srv.URL).DownloadJobResultResumable(context.Background(), "j1", dest)
+	var mismatch *docgen.ChecksumMismatchError
+	if !errors.Is(err, docgen.ErrChecksumMismatch) || !errors.As(err, &mismatch) || mismatch.Expected != checksum {
+		t.Fatalf("err = %v, want *ChecksumMismatchError", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("dest created despite mismatch: %v", err)
+	}
+	if leftover, _ := filepath.Glob(dest + ".partial*"); len(leftover) != 0 {
+		t.Errorf("leftover files: %v", leftover)
+	}
+}
@@ -0,0 +1,443 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// marshalRequest 序列化请求体，PluralValue 按请求语言解析，EncryptedField 按 WithFieldEncryption 加密
+//
+// 未设置 MaxRequestBytes 时直接使用 json.Marshal；设置后改为分段序列化到计数 writer，
+// 一旦累计大小超过上限立即中止，避免超大 DataList 在被服务端拒绝前耗费大量时间序列化和上传
+func (c *Client) marshalRequest(ctx context.Context, path string, reqBody any) ([]byte, error) {
+	reqBody, err := c.localizeValues(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	reqBody, err = c.sealFields(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if c.MaxRequestBytes <= 0 {
+		body, err := json.Marshal(reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		return body, nil
+	}
+
+	var buf bytes.Buffer
+	lw := &limitWriter{w: &buf, limit: c.MaxRequestBytes}
+	if err := streamJSON(lw, reflect.ValueOf(reqBody)); err != nil {
+		if lw.exceeded {
+			return nil, &RequestTooLargeError{Endpoint: path, Size: lw.n, Limit: c.MaxRequestBytes}
+		}
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// rewriteValues 遍历请求体，将 leaf 处理的值替换为其返回值，返回替换后的值以及是否发生了替换；未替换时返回原值
+//
+// leaf 的第二个返回值表示是否处理了该值，未处理的值继续遍历其字段、元素；
+// 结构体、map 与切片按需复制，调用方传入的数据不会被修改
+func rewriteValues(v reflect.Value, leaf func(reflect.Value) (reflect.Value, bool, error)) (reflect.Value, bool, error) {
+	if !v.IsValid() {
+		return v, false, nil
+	}
+	if out, handled, err := leaf(v); handled || err != nil {
+		return out, err == nil, err
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, false, nil
+		}
+		return rewriteValues(v.Elem(), leaf)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v, false, nil
+		}
+		elem, changed, err := rewriteValues(v.Elem(), leaf)
+		if !changed || err != nil {
+			return v, false, err
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(elem)
+		return p, true, nil
+	case reflect.Struct:
+		var out reflect.Value
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			fv, changed, err := rewriteValues(v.Field(i), leaf)
+			if err != nil {
+				return v, false, err
+			}
+			if changed {
+				if !out.IsValid() {
+					out = reflect.New(v.Type()).Elem()
+					out.Set(v)
+				}
+				out.Field(i).Set(fv)
+			}
+		}
+		if !out.IsValid() {
+			return v, false, nil
+		}
+		return out, true, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return v, false, nil
+		}
+		var out reflect.Value
+		iter := v.MapRange()
+		for iter.Next() {
+			ev, changed, err := rewriteValues(iter.Value(), leaf)
+			if err != nil {
+				return v, false, err
+			}
+			if changed && !out.IsValid() {
+				out = reflect.MakeMapWithSize(v.Type(), v.Len())
+				for _, key := range v.MapKeys() {
+					out.SetMapIndex(key, v.MapIndex(key))
+				}
+			}
+			if changed {
+				out.SetMapIndex(iter.Key(), ev)
+			}
+		}
+		if !out.IsValid() {
+			return v, false, nil
+		}
+		return out, true, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return v, false, nil
+		}
+		var out reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			ev, changed, err := rewriteValues(v.Index(i), leaf)
+			if err != nil {
+				return v, false, err
+			}
+			if changed {
+				if !out.IsValid() {
+					if v.Kind() == reflect.Slice {
+						out = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+					} else {
+						out = reflect.New(v.Type()).Elem()
+					}
+					reflect.Copy(out, v)
+				}
+				out.Index(i).Set(ev)
+			}
+		}
+		if !out.IsValid() {
+			return v, false, nil
+		}
+		return out, true, nil
+	}
+	return v, false, nil
+}
+
+// errLimitExceeded limitWriter 超出上限时返回的内部错误
+var errLimitExceeded = errors.New("request body exceeds limit")
+
+// limitWriter 计数 writer，累计写入字节数超过 limit 时返回错误
+type limitWriter struct {
+	w        io.Writer
+	n        int64
+	limit    int64
+	exceeded bool
+}
+
+// Write 实现 io.Writer
+func (lw *limitWriter) Write(p []byte) (int, error) {
+	lw.n += int64(len(p))
+	if lw.n > lw.limit {
+		lw.exceeded = true
+		return 0, errLimitExceeded
+	}
+	return lw.w.Write(p)
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// isMarshaler 类型是否自定义了序列化：实现 json.Marshaler 或 encoding.TextMarshaler（如 time.Time、net.IP）
+func isMarshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+// streamJSON 分段序列化，输出与 json.Marshal 一致
+//
+// 结构体按字段、切片按元素、map 按键（排序后）逐段写入，叶子值交给 json.Marshal，
+// 使计数 writer 能在大集合序列化过程中尽早发现超限
+func streamJSON(w io.Writer, v reflect.Value) error {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			_, err := io.WriteString(w, "null")
+			return err
+		}
+		if isMarshaler(v.Type()) {
+			break
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		_, err := io.WriteString(w, "null")
+		return err
+	}
+	// 与 encoding/json 一致：指针接收者的方法只在值可寻址（经指针或可寻址的结构体字段、数组元素到达）时使用
+	if isMarshaler(v.Type()) {
+		return writeMarshaled(w, v.Interface())
+	}
+	if v.CanAddr() && isMarshaler(reflect.PointerTo(v.Type())) {
+		return writeMarshaled(w, v.Addr().Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return streamStruct(w, v)
+	case reflect.Map:
+		if v.IsNil() {
+			_, err := io.WriteString(w, "null")
+			return err
+		}
+		if !isMapKeyType(v.Type().Key()) {
+			return writeMarshaled(w, v.Interface())
+		}
+		return streamMap(w, v)
+	case reflect.Slice:
+		if v.IsNil() {
+			_, err := io.WriteString(w, "null")
+			return err
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return writeMarshaled(w, v.Interface())
+		}
+		return streamArray(w, v)
+	case reflect.Array:
+		return streamArray(w, v)
+	default:
+		return writeMarshaled(w, v.Interface())
+	}
+}
+
+// streamStruct 逐字段序列化结构体（遵循 json 标签与 omitempty）
+func streamStruct(w io.Writer, v reflect.Value) error {
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+	first := true
+	if err := streamFields(w, v, &first); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "}")
+	return err
+}
+
+// streamFields 写入结构体字段，未指定 json 名称的嵌入结构体字段展开到外层（与 encoding/json 一致）
+func streamFields(w io.Writer, v reflect.Value, first *bool) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := streamFields(w, fv, first); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+		if omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if !*first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		*first = false
+		if err := writeMarshaled(w, name); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, ":"); err != nil {
+			return err
+		}
+		if err := streamJSON(w, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isMapKeyType map 键类型能否逐项序列化：字符串、整数或实现 encoding.TextMarshaler 的其他类型
+//
+// 实现 encoding.TextMarshaler 的字符串类型返回 false：encoding/json 的不同实现对其使用字符串本身或 MarshalText，
+// 这类 map 整体交给 json.Marshal，保证输出一致
+func isMapKeyType(t reflect.Type) bool {
+	if t.Implements(textMarshalerType) {
+		return t.Kind() != reflect.String
+	}
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// mapKeyName 返回 map 键在 JSON 中的名称：字符串键直接使用，其次使用 MarshalText（nil 指针为空字符串），整数键转为十进制
+func mapKeyName(k reflect.Value) (string, error) {
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.String:
+		return k.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	default:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+}
+
+// streamMap 按排序后的键名逐项序列化 map
+func streamMap(w io.Writer, v reflect.Value) error {
+	type entry struct {
+		name string
+		key  reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		name, err := mapKeyName(key)
+		if err != nil {
+			return fmt.Errorf("json: error calling MarshalText for type %s: %w", key.Type(), err)
+		}
+		entries = append(entries, entry{name, key})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+	for i, e := range entries {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := writeMarshaled(w, e.name); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, ":"); err != nil {
+			return err
+		}
+		if err := streamJSON(w, v.MapIndex(e.key)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "}")
+	return err
+}
+
+// streamArray 逐元素序列化切片或数组
+func streamArray(w io.Writer, v reflect.Value) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := streamJSON(w, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// writeMarshaled 使用 json.Marshal 序列化叶子值并写入
+func writeMarshaled(w io.Writer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// parseJSONTag 解析字段的 json 标签，返回字段名、是否 omitempty、是否跳过
+func parseJSONTag(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+// isEmptyValue 与 encoding/json 的 omitempty 判定规则一致
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package docgen_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// orderID 值接收者实现 encoding.TextMarshaler，可作为 map 键
+type orderID int
+
+func (id orderID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("ORD-%05d", int(id))), nil
+}
+
+// ticketID 指针接收者实现 encoding.TextMarshaler，只在可寻址时生效
+type ticketID struct {
+	Queue string
+	N     int
+}
+
+func (id *ticketID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%s#%d", id.Queue, id.N)), nil
+}
+
+// upperKey 实现 encoding.TextMarshaler 的字符串类型键，整个 map 交给 json.Marshal
+type upperKey string
+
+func (k upperKey) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(k))), nil
+}
+
+type ticketHolder struct {
+	Ticket ticketID    `json:"ticket"`
+	Owner  orderID     `json:"owner"`
+	Addr   netip.Addr  `json:"addr"`
+	Ptr    *ticketID   `json:"ptr"`
+	Tags   [1]ticketID `json:"tags"`
+}
+
+// TestStreamJSONTextMarshaler 设置 MaxRequestBytes 后分段序列化的请求体与 json.Marshal 完全一致，
+// 包括实现 encoding.TextMarshaler 的值（值接收者与指针接收者）与 map 键
+func TestStreamJSONTextMarshaler(t *testing.T) {
+	ticket := ticketID{Queue: "ops", N: 7}
+	data := map[string]any{
+		"ip":        net.ParseIP("192.0.2.1"),
+		"ips":       []net.IP{net.ParseIP("2001:db8::1"), nil},
+		"addr":      netip.MustParseAddr("192.0.2.2"),
+		"order":     orderID(42),
+		"orderPtr":  func() *orderID { id := orderID(43); return &id }(),
+		"ticketPtr": &ticket,
+		// 不可寻址的值不使用指针接收者的方法，按结构体序列化
+		"ticketValue": ticket,
+		"holder":      &ticketHolder{Ticket: ticket, Owner: 1, Addr: netip.MustParseAddr("::1"), Ptr: &ticket},
+		"holderValue": ticketHolder{Ticket: ticket},
+		"byOrder":     map[orderID]int{10: 1, 2: 2, 300: 3},
+		"byIP":        map[netip.Addr]string{netip.MustParseAddr("10.0.0.2"): "b", netip.MustParseAddr("10.0.0.1"): "a"},
+		"byUpper":     map[upperKey]int{"b": 1, "a": 2},
+		"byInt":       map[int]string{10: "x", 9: "y"},
+		"signed":      time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+	}
+	req := docgen.WordGenRequest{TemplateName: "t.docx", Data: data}
+	want, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{order}}"))
+	client := docgen.NewClient(srv.URL)
+	client.MaxRequestBytes = 1 << 20
+	if _, err := client.GenerateWordWithMeta(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.LastRequest().Body; string(got) != string(want) {
+		t.Errorf("streamed body:\n%s\nwant json.Marshal:\n%s", got, want)
+	}
+	docgentest.AssertJSONPath(t, srv.LastRequest(), "data.addr", "192.0.2.2")
+	docgentest.AssertJSONPath(t, srv.LastRequest(), "data.ticketPtr", "ops#7")
+	docgentest.AssertJSONPath(t, srv.LastRequest(), "data.holder.ticket", "ops#7")
+	docgentest.AssertJSONPath(t, srv.LastRequest(), "data.byOrder.ORD-00010", 1.0)
+}
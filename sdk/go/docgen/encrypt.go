@@ -0,0 +1,298 @@
+package docgen
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// EncryptedFieldMarker 加密字段序列化后的标记键，渲染引擎在替换变量前解密该对象
+//
+// 序列化格式：{"$encrypted":{"v":1,"alg":"AES-GCM","kid":"<密钥 ID>","iv":"<base64>","ct":"<base64>"}}
+const EncryptedFieldMarker = "$encrypted"
+
+// 字段加密错误，可配合 errors.Is 判断
+var (
+	// ErrEncryptionNotConfigured 请求包含 EncryptedField，但客户端未通过 WithFieldEncryption 配置密钥
+	ErrEncryptionNotConfigured = errors.New("docgen: field encryption not configured")
+	// ErrDecryptionFailed 加密字段无法解密（密钥 ID 未知、密钥错误或密文被篡改）
+	ErrDecryptionFailed = errors.New("docgen: field decryption failed")
+)
+
+// EncryptedField 需要在应用层加密的字段值（如身份证号、薪资）
+//
+// 放入 Data / DataList / ListData 中任意位置，客户端发送请求前使用 WithFieldEncryption
+// 配置的当前密钥以 AES-GCM 加密；未配置密钥时请求在序列化阶段失败，明文不会被发送。
+// 以 fmt 输出时只显示 "[encrypted]"，避免明文出现在日志中
+type EncryptedField struct {
+	// Value 明文值，按 JSON 序列化后加密
+	Value any
+
+	sealed *sealedField
+	// digest RequestHash 计算时明文的 HMAC，只用于哈希，不会发送
+	digest string
+}
+
+// Encrypt 包装需要加密的字段值
+func Encrypt(v any) EncryptedField {
+	return EncryptedField{Value: v}
+}
+
+// sealedField 加密后的字段
+type sealedField struct {
+	Version int    `json:"v"`
+	Alg     string `json:"alg"`
+	KeyID   string `json:"kid"`
+	IV      []byte `json:"iv"`
+	Data    []byte `json:"ct"`
+}
+
+// MarshalJSON 实现 json.Marshaler：只输出密文，未加密时返回 ErrEncryptionNotConfigured
+func (f EncryptedField) MarshalJSON() ([]byte, error) {
+	if f.digest != "" {
+		return json.Marshal(map[string]map[string]string{EncryptedFieldMarker: {"hmac": f.digest}})
+	}
+	if f.sealed == nil {
+		return nil, ErrEncryptionNotConfigured
+	}
+	return json.Marshal(map[string]*sealedField{EncryptedFieldMarker: f.sealed})
+}
+
+// String 实现 fmt.Stringer，不输出明文
+func (f EncryptedField) String() string {
+	return "[encrypted]"
+}
+
+// GoString 实现 fmt.GoStringer，不输出明文
+func (f EncryptedField) GoString() string {
+	return "docgen.EncryptedField{[encrypted]}"
+}
+
+// KeyProvider 字段加密密钥来源，需与服务端共享
+type KeyProvider interface {
+	// CurrentKey 返回当前用于加密的密钥及其 ID（16、24 或 32 字节）
+	CurrentKey() (keyID string, key []byte, err error)
+}
+
+// KeyFunc 按密钥 ID 查找解密密钥
+type KeyFunc func(keyID string) ([]byte, error)
+
+// Keyring 支持轮换的内存密钥环，同时实现 KeyProvider 与解密所需的 Key 查找
+//
+// 轮换后旧密钥仍保留，用于解密轮换前加密的数据
+type Keyring struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewKeyring 创建密钥环，keyID 对应的 key 为当前加密密钥
+func NewKeyring(keyID string, key []byte) *Keyring {
+	k := &Keyring{keys: make(map[string][]byte)}
+	k.Rotate(keyID, key)
+	return k
+}
+
+// Rotate 添加新密钥并设为当前加密密钥
+func (k *Keyring) Rotate(keyID string, key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[keyID] = append([]byte(nil), key...)
+	k.current = keyID
+}
+
+// Retire 移除不再需要解密的旧密钥，不能移除当前密钥
+func (k *Keyring) Retire(keyID string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if keyID != k.current {
+		delete(k.keys, keyID)
+	}
+}
+
+// CurrentKey 实现 KeyProvider
+func (k *Keyring) CurrentKey() (string, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current, k.keys[k.current], nil
+}
+
+// Key 按 ID 查找密钥，可作为 KeyFunc 使用
+func (k *Keyring) Key(keyID string) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", keyID)
+	}
+	return key, nil
+}
+
+// String 实现 fmt.Stringer，不输出密钥
+func (k *Keyring) String() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return fmt.Sprintf("Keyring{current: %q, keys: %d}", k.current, len(k.keys))
+}
+
+// sealFields 使用当前密钥加密请求中的所有 EncryptedField，返回可直接序列化的请求体
+//
+// 请求体按需复制，调用方传入的数据不会被修改；同一请求中的字段使用同一个密钥
+func (c *Client) sealFields(reqBody any) (any, error) {
+	if c.fieldKeys == nil {
+		return reqBody, nil
+	}
+	keyID, key, err := c.fieldKeys.CurrentKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key %q: %w", keyID, err)
+	}
+
+	s := &fieldSealer{keyID: keyID, aead: aead}
+	sealed, changed, err := s.seal(reflect.ValueOf(reqBody))
+	if err != nil || !changed {
+		return reqBody, err
+	}
+	return sealed.Interface(), nil
+}
+
+var encryptedFieldType = reflect.TypeOf(EncryptedField{})
+
+// fieldSealer 遍历请求体并替换 EncryptedField
+type fieldSealer struct {
+	keyID string
+	aead  cipher.AEAD
+}
+
+// seal 返回替换后的值以及是否发生了替换；未替换时返回原值
+func (s *fieldSealer) seal(v reflect.Value) (reflect.Value, bool, error) {
+	return rewriteValues(v, func(v reflect.Value) (reflect.Value, bool, error) {
+		if v.Type() != encryptedFieldType {
+			return v, false, nil
+		}
+		field, err := s.encrypt(v.Interface().(EncryptedField))
+		return reflect.ValueOf(field), true, err
+	})
+}
+
+// encrypt 加密单个字段，密钥 ID 作为附加认证数据
+func (s *fieldSealer) encrypt(f EncryptedField) (EncryptedField, error) {
+	plaintext, err := json.Marshal(f.Value)
+	if err != nil {
+		return f, fmt.Errorf("failed to marshal encrypted field: %w", err)
+	}
+	iv := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return f, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return EncryptedField{sealed: &sealedField{
+		Version: 1,
+		Alg:     "AES-GCM",
+		KeyID:   s.keyID,
+		IV:      iv,
+		Data:    s.aead.Seal(nil, iv, plaintext, []byte(s.keyID)),
+	}}, nil
+}
+
+// newAEAD 创建 AES-GCM 实例
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// DecryptFields 解密 JSON 文档中的所有加密字段，返回替换为明文值后的 JSON
+//
+// data: 包含加密字段标记的 JSON（如捕获的请求体）
+// keys: 按密钥 ID 查找密钥，如 Keyring.Key
+//
+// 供服务端实现与测试替身使用；任一字段无法解密时返回 ErrDecryptionFailed
+func DecryptFields(data []byte, keys KeyFunc) ([]byte, error) {
+	var doc any
+	if err := decodeJSON(data, &doc); err != nil {
+		return nil, err
+	}
+	doc, err := decryptValue(doc, keys)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// decryptValue 递归解密通用 JSON 值
+func decryptValue(v any, keys KeyFunc) (any, error) {
+	switch val := v.(type) {
+	case map[string]any:
+		if raw, ok := val[EncryptedFieldMarker]; ok && len(val) == 1 {
+			return decryptMarker(raw, keys)
+		}
+		for k, item := range val {
+			dec, err := decryptValue(item, keys)
+			if err != nil {
+				return nil, err
+			}
+			val[k] = dec
+		}
+	case []any:
+		for i, item := range val {
+			dec, err := decryptValue(item, keys)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = dec
+		}
+	}
+	return v, nil
+}
+
+// decryptMarker 解密单个加密字段标记
+func decryptMarker(raw any, keys KeyFunc) (any, error) {
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptionFailed, err)
+	}
+	var sealed sealedField
+	if err := json.Unmarshal(encoded, &sealed); err != nil {
+		return nil, fmt.Errorf("%w: malformed field: %w", ErrDecryptionFailed, err)
+	}
+	if sealed.Version != 1 {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrDecryptionFailed, sealed.Version)
+	}
+	key, err := keys(sealed.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q: %w", ErrDecryptionFailed, sealed.KeyID, err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q: %w", ErrDecryptionFailed, sealed.KeyID, err)
+	}
+	if len(sealed.IV) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce length", ErrDecryptionFailed)
+	}
+	plaintext, err := aead.Open(nil, sealed.IV, sealed.Data, []byte(sealed.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q: authentication failed", ErrDecryptionFailed, sealed.KeyID)
+	}
+	var value any
+	if err := decodeJSON(plaintext, &value); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptionFailed, err)
+	}
+	return value, nil
+}
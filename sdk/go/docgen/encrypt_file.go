@@ -0,0 +1,127 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// encryptedDocMagic 加密文档文件的起始标记
+const encryptedDocMagic = "DGEF"
+
+// encryptedDocVersion 加密文档格式版本
+const encryptedDocVersion = 1
+
+// ErrDocumentDecryptionFailed 加密文档无法解密（不是加密文档、格式版本不支持、密钥 ID 未知、密钥错误或内容被篡改）
+var ErrDocumentDecryptionFailed = errors.New("docgen: document decryption failed")
+
+// EncryptDocument 以 KeyProvider 的当前密钥（AES-256，32 字节）加密文档
+//
+// 输出格式：4 字节标记 "DGEF"、1 字节版本、1 字节密钥 ID 长度、密钥 ID、12 字节 nonce，之后为 AES-GCM 密文（含认证标签）。
+// 整个头部作为附加认证数据，修改密钥 ID 或版本同样无法解密。错误信息中不包含密钥
+func EncryptDocument(doc []byte, keys KeyProvider) ([]byte, error) {
+	keyID, key, err := keys.CurrentKey()
+	if err != nil {
+		return nil, fmt.Errorf("docgen: get encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("docgen: encryption key %q: AES-256 needs 32 bytes, got %d", keyID, len(key))
+	}
+	if len(keyID) > 255 {
+		return nil, fmt.Errorf("docgen: encryption key id longer than 255 bytes")
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("docgen: encryption key %q: %w", keyID, err)
+	}
+
+	header := make([]byte, 0, len(encryptedDocMagic)+2+len(keyID)+aead.NonceSize())
+	header = append(header, encryptedDocMagic...)
+	header = append(header, encryptedDocVersion, byte(len(keyID)))
+	header = append(header, keyID...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("docgen: generate nonce: %w", err)
+	}
+	header = append(header, nonce...)
+	return aead.Seal(header, nonce, doc, header), nil
+}
+
+// DecryptDocument 解密 EncryptDocument 的输出，keys 按头部中的密钥 ID 查找密钥（如 Keyring.Key）
+//
+// 无法解密时返回的错误满足 errors.Is(err, ErrDocumentDecryptionFailed)
+func DecryptDocument(data []byte, keys KeyFunc) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(encryptedDocMagic)) {
+		return nil, fmt.Errorf("%w: not an encrypted document", ErrDocumentDecryptionFailed)
+	}
+	rest := data[len(encryptedDocMagic):]
+	if len(rest) < 2 {
+		return nil, fmt.Errorf("%w: truncated header", ErrDocumentDecryptionFailed)
+	}
+	if rest[0] != encryptedDocVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrDocumentDecryptionFailed, rest[0])
+	}
+	idLen := int(rest[1])
+	rest = rest[2:]
+	if len(rest) < idLen {
+		return nil, fmt.Errorf("%w: truncated header", ErrDocumentDecryptionFailed)
+	}
+	keyID := string(rest[:idLen])
+	key, err := keys(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q: %w", ErrDocumentDecryptionFailed, keyID, err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q: %w", ErrDocumentDecryptionFailed, keyID, err)
+	}
+	rest = rest[idLen:]
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, fmt.Errorf("%w: truncated document", ErrDocumentDecryptionFailed)
+	}
+	headerLen := len(data) - len(rest) + aead.NonceSize()
+	doc, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], data[:headerLen])
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q: wrong key or tampered content", ErrDocumentDecryptionFailed, keyID)
+	}
+	return doc, nil
+}
+
+// EncryptAndSave 加密文档并写入 path：先写入同目录下的临时文件（权限 0600）再重命名，中断时不会留下不完整的文件
+func EncryptAndSave(result *DocumentResult, path string, keys KeyProvider) error {
+	data, err := EncryptDocument(result.Data, keys)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// OpenEncryptedDocument 读取并解密 EncryptAndSave 写入的文件
+func OpenEncryptedDocument(path string, keys KeyFunc) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptDocument(data, keys)
+}
+
+// SaveWordEncrypted 生成 Word 文档并加密保存，见 EncryptAndSave
+func (c *Client) SaveWordEncrypted(templateName string, data map[string]any, outputPath string, keys KeyProvider) error {
+	result, err := c.GenerateWordWithMeta(context.Background(), WordGenRequest{TemplateName: templateName, Data: data})
+	if err != nil {
+		return err
+	}
+	return EncryptAndSave(result, outputPath, keys)
+}
+
+// SaveExcelEncrypted 动态生成 Excel 文档并加密保存，见 EncryptAndSave
+func (c *Client) SaveExcelEncrypted(sheetName string, headers []string, data [][]any, outputPath string, keys KeyProvider) error {
+	result, err := c.GenerateExcelWithMeta(context.Background(), ExcelGenRequest{SheetName: sheetName, Headers: headers, Data: data})
+	if err != nil {
+		return err
+	}
+	return EncryptAndSave(result, outputPath, keys)
+}
@@ -0,0 +1,143 @@
+package docgen_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+func TestEncryptedDocumentRoundTripWithRotatedKeys(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	client := docgen.NewClient(srv.URL)
+
+	dir := t.TempDir()
+	ring := docgen.NewKeyring("k1", testKey1)
+	wordPath := filepath.Join(dir, "report.docx.enc")
+	if err := client.SaveWordEncrypted("t.docx", map[string]any{"idNumber": secretID}, wordPath, ring); err != nil {
+		t.Fatal(err)
+	}
+	ring.Rotate("k2", testKey2)
+	excelPath := filepath.Join(dir, "report.xlsx.enc")
+	if err := client.SaveExcelEncrypted("Sheet1", []string{"ID"}, [][]any{{secretID}}, excelPath, ring); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		path, keyID string
+	}{{wordPath, "k1"}, {excelPath, "k2"}} {
+		raw, err := os.ReadFile(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(raw, append([]byte("DGEF\x01\x02"), tt.keyID...)) {
+			t.Errorf("%s: header %q, want magic, version 1 and key id %q", filepath.Base(tt.path), raw[:8], tt.keyID)
+		}
+		assertNoSecrets(t, filepath.Base(tt.path), raw)
+		if mode := fileMode(t, tt.path); mode != 0o600 {
+			t.Errorf("%s: mode = %v, want 0600", filepath.Base(tt.path), mode)
+		}
+
+		doc, err := docgen.OpenEncryptedDocument(tt.path, ring.Key)
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(tt.path), err)
+		}
+		if !bytes.HasPrefix(doc, []byte("PK")) {
+			t.Errorf("%s: decrypted content is not a zip document", filepath.Base(tt.path))
+		}
+	}
+	if text, _ := func() (string, error) {
+		doc, _ := docgen.OpenEncryptedDocument(wordPath, ring.Key)
+		return docgentest.ExtractDocxText(doc)
+	}(); !strings.Contains(text, secretID) {
+		t.Errorf("decrypted word text %q does not contain the data", text)
+	}
+
+	// 原子写入：目录中只有两个最终文件
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("files in output dir = %v, want only the two documents", names)
+	}
+
+	// 移除旧密钥后，旧密钥加密的文档无法打开
+	ring.Retire("k1")
+	if _, err := docgen.OpenEncryptedDocument(wordPath, ring.Key); !errors.Is(err, docgen.ErrDocumentDecryptionFailed) {
+		t.Errorf("open k1 document after retiring k1: err = %v, want ErrDocumentDecryptionFailed", err)
+	}
+}
+
+func TestEncryptedDocumentTamperDetection(t *testing.T) {
+	ring := docgen.NewKeyring("k1", testKey1)
+	plain := []byte("PK document with " + secretID)
+	sealed, err := docgen.EncryptDocument(plain, ring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 头部：4 字节标记、版本、密钥 ID 长度、"k1"、12 字节 nonce
+	const versionAt, keyIDAt, nonceAt = 4, 6, 8
+	const bodyAt = nonceAt + 12
+
+	other := docgen.NewKeyring("k1", testKey2)
+	other.Rotate("k2", testKey1)
+	modified := func(fn func([]byte) []byte) []byte {
+		return fn(append([]byte(nil), sealed...))
+	}
+	tests := []struct {
+		name string
+		data []byte
+		keys docgen.KeyFunc
+	}{
+		{"ciphertext", modified(func(b []byte) []byte { b[bodyAt] ^= 1; return b }), ring.Key},
+		{"tag", modified(func(b []byte) []byte { b[len(b)-1] ^= 1; return b }), ring.Key},
+		{"nonce", modified(func(b []byte) []byte { b[nonceAt] ^= 1; return b }), ring.Key},
+		// 密钥 ID 受认证保护：改为另一个指向正确密钥的 ID 也无法解密
+		{"key id", modified(func(b []byte) []byte { b[keyIDAt+1] = '2'; return b }), other.Key},
+		{"unknown key id", modified(func(b []byte) []byte { b[keyIDAt+1] = '9'; return b }), ring.Key},
+		{"version", modified(func(b []byte) []byte { b[versionAt] = 2; return b }), ring.Key},
+		{"magic", modified(func(b []byte) []byte { b[0] = 'X'; return b }), ring.Key},
+		{"truncated header", sealed[:keyIDAt+1], ring.Key},
+		{"truncated body", sealed[:bodyAt+4], ring.Key},
+		{"appended bytes", append(append([]byte(nil), sealed...), 0), ring.Key},
+		{"wrong key", sealed, other.Key},
+		{"plaintext", plain, ring.Key},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := docgen.DecryptDocument(tt.data, tt.keys)
+			if !errors.Is(err, docgen.ErrDocumentDecryptionFailed) {
+				t.Fatalf("err = %v, want ErrDocumentDecryptionFailed", err)
+			}
+			if doc != nil {
+				t.Error("returned content despite the error")
+			}
+			assertNoSecrets(t, "error", []byte(err.Error()))
+		})
+	}
+
+	// 篡改后的文件同样无法打开
+	path := filepath.Join(t.TempDir(), "tampered.enc")
+	if err := os.WriteFile(path, tests[0].data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := docgen.OpenEncryptedDocument(path, ring.Key); !errors.Is(err, docgen.ErrDocumentDecryptionFailed) {
+		t.Errorf("OpenEncryptedDocument: err = %v, want ErrDocumentDecryptionFailed", err)
+	}
+
+	// 非 AES-256 密钥被拒绝，错误中不包含密钥
+	_, err = docgen.EncryptDocument(plain, docgen.NewKeyring("short", testKey1[:16]))
+	if err == nil {
+		t.Fatal("EncryptDocument accepted a 16-byte key")
+	}
+	assertNoSecrets(t, "error", []byte(err.Error()))
+}
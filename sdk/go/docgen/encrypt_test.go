@@ -0,0 +1,214 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// 测试密钥，所有检查明文泄露的断言同时检查密钥的各种编码
+var (
+	testKey1 = bytes.Repeat([]byte{0x11}, 32)
+	testKey2 = bytes.Repeat([]byte{0x22}, 32)
+)
+
+const secretID = "110101199001011234"
+
+// assertNoSecrets 检查 data 中不包含明文与密钥
+func assertNoSecrets(t *testing.T, where string, data []byte) {
+	t.Helper()
+	secrets := []string{secretID}
+	for _, key := range [][]byte{testKey1, testKey2} {
+		secrets = append(secrets, string(key), hex.EncodeToString(key), base64.StdEncoding.EncodeToString(key))
+	}
+	for _, s := range secrets {
+		if bytes.Contains(data, []byte(s)) {
+			t.Errorf("%s leaks %q", where, s)
+		}
+	}
+}
+
+// sealedKeyID 返回捕获的请求体中 data.idNumber 使用的密钥 ID
+func sealedKeyID(t *testing.T, body []byte) string {
+	t.Helper()
+	var req struct {
+		Data struct {
+			IDNumber map[string]struct {
+				KeyID string `json:"kid"`
+			} `json:"idNumber"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	sealed, ok := req.Data.IDNumber[docgen.EncryptedFieldMarker]
+	if !ok {
+		t.Fatalf("idNumber is not an encrypted field: %s", body)
+	}
+	return sealed.KeyID
+}
+
+func TestFieldEncryptionRoundTripWithRotatedKeys(t *testing.T) {
+	ring := docgen.NewKeyring("k1", testKey1)
+	srv := docgentest.NewServer(docgentest.WithFieldDecryption(ring.Key))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+
+	dumps := t.TempDir()
+	var metrics []string
+	client := docgen.NewClient(srv.URL,
+		docgen.WithFieldEncryption(ring),
+		docgen.WithDebugDump(dumps),
+		docgen.WithMetricsHook(func(m docgen.RequestMetrics) { metrics = append(metrics, fmt.Sprintf("%+v", m)) }),
+	)
+	req := docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"idNumber": docgen.Encrypt(secretID)}}
+
+	var bodies [][]byte
+	for _, keyID := range []string{"k1", "k2"} {
+		if keyID == "k2" {
+			ring.Rotate("k2", testKey2)
+		}
+		result, err := client.GenerateWordWithMeta(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s: %v", keyID, err)
+		}
+		// 模拟服务器解密后渲染，文档中是明文
+		text, err := docgentest.ExtractDocxText(result.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(text, secretID) {
+			t.Errorf("%s: rendered text %q does not contain the decrypted value", keyID, text)
+		}
+		body := srv.LastRequest().Body
+		assertNoSecrets(t, keyID+" request body", body)
+		if got := sealedKeyID(t, body); got != keyID {
+			t.Errorf("sealed with key %q, want %q", got, keyID)
+		}
+		bodies = append(bodies, body)
+	}
+
+	// 轮换后旧密钥加密的数据仍可解密，移除旧密钥后不能
+	if _, err := docgen.DecryptFields(bodies[0], ring.Key); err != nil {
+		t.Errorf("decrypt k1 body after rotation: %v", err)
+	}
+	ring.Retire("k1")
+	if _, err := docgen.DecryptFields(bodies[0], ring.Key); !errors.Is(err, docgen.ErrDecryptionFailed) {
+		t.Errorf("decrypt k1 body after retiring k1: err = %v, want ErrDecryptionFailed", err)
+	}
+	if _, err := docgen.DecryptFields(bodies[1], ring.Key); err != nil {
+		t.Errorf("decrypt k2 body: %v", err)
+	}
+
+	// 调试文件、指标与格式化输出都不包含明文或密钥
+	files, _ := filepath.Glob(filepath.Join(dumps, "*"))
+	if len(files) == 0 {
+		t.Fatal("no debug dumps written")
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertNoSecrets(t, "debug dump "+filepath.Base(f), data)
+	}
+	assertNoSecrets(t, "metrics", []byte(strings.Join(metrics, "\n")))
+	assertNoSecrets(t, "formatted request", []byte(fmt.Sprintf("%v %+v %#v %s", req, req, req, ring)))
+}
+
+func TestFieldEncryptionTamperDetection(t *testing.T) {
+	ring := docgen.NewKeyring("k1", testKey1)
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	client := docgen.NewClient(srv.URL, docgen.WithFieldEncryption(ring))
+	req := docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"idNumber": docgen.Encrypt(secretID)}}
+	if _, err := client.GenerateWordWithMeta(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	body := srv.LastRequest().Body
+
+	var doc map[string]any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatal(err)
+	}
+	field := doc["data"].(map[string]any)["idNumber"].(map[string]any)
+	sealed := field[docgen.EncryptedFieldMarker].(map[string]any)
+	tamper := func(field string, fn func([]byte)) []byte {
+		raw, _ := base64.StdEncoding.DecodeString(sealed[field].(string))
+		fn(raw)
+		orig := sealed[field]
+		sealed[field] = base64.StdEncoding.EncodeToString(raw)
+		out, _ := json.Marshal(doc)
+		sealed[field] = orig
+		return out
+	}
+	withKeyID := func(kid string) []byte {
+		orig := sealed["kid"]
+		sealed["kid"] = kid
+		out, _ := json.Marshal(doc)
+		sealed["kid"] = orig
+		return out
+	}
+
+	other := docgen.NewKeyring("k1", testKey2)
+	other.Rotate("k2", testKey1)
+	tests := []struct {
+		name string
+		body []byte
+		keys docgen.KeyFunc
+	}{
+		{"ciphertext", tamper("ct", func(b []byte) { b[0] ^= 1 }), ring.Key},
+		{"tag", tamper("ct", func(b []byte) { b[len(b)-1] ^= 1 }), ring.Key},
+		{"nonce", tamper("iv", func(b []byte) { b[0] ^= 1 }), ring.Key},
+		// 密钥 ID 是附加认证数据，换成另一个能找到正确密钥的 ID 也无法解密
+		{"key id", withKeyID("k2"), other.Key},
+		{"unknown key id", withKeyID("k9"), ring.Key},
+		{"wrong key", body, other.Key},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := docgen.DecryptFields(tt.body, tt.keys)
+			if !errors.Is(err, docgen.ErrDecryptionFailed) {
+				t.Fatalf("err = %v, want ErrDecryptionFailed", err)
+			}
+			assertNoSecrets(t, "error", []byte(err.Error()))
+		})
+	}
+
+	// 服务端使用不同的密钥：请求返回 FIELD_DECRYPTION_FAILED
+	wrong := docgentest.NewServer(docgentest.WithFieldDecryption(other.Key))
+	defer wrong.Close()
+	wrong.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	_, err := docgen.NewClient(wrong.URL, docgen.WithFieldEncryption(ring)).GenerateWordWithMeta(context.Background(), req)
+	if !errors.Is(err, docgen.ErrDecryptionFailed) {
+		t.Errorf("server with the wrong key: err = %v, want ErrDecryptionFailed", err)
+	}
+}
+
+func TestFieldEncryptionNotConfigured(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+
+	client := docgen.NewClient(srv.URL)
+	req := docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"idNumber": docgen.Encrypt(secretID)}}
+	_, err := client.GenerateWordWithMeta(context.Background(), req)
+	if !errors.Is(err, docgen.ErrEncryptionNotConfigured) {
+		t.Fatalf("err = %v, want ErrEncryptionNotConfigured", err)
+	}
+	if n := len(srv.RequestsTo(docgentest.EndpointWord)); n != 0 {
+		t.Errorf("%d requests sent without an encryption key", n)
+	}
+}
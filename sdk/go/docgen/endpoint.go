@@ -0,0 +1,92 @@
+package docgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// APIVersion 服务端 API 版本
+type APIVersion string
+
+const (
+	// APIv1 /api/v1 接口（默认）
+	APIv1 APIVersion = "v1"
+	// APIv2 /api/v2 接口
+	APIv2 APIVersion = "v2"
+)
+
+// defaultAPIPrefix 默认 API 路径前缀
+const defaultAPIPrefix = "/api"
+
+// endpointVersion 按接口覆盖的 API 版本
+type endpointVersion struct {
+	// endpoint 不含前缀与版本的接口路径，如 "/doc/word/batch"
+	endpoint string
+	version  APIVersion
+}
+
+// resolveURL 将内部接口路径（"/api/v1/..."）转换为完整请求 URL
+//
+// 按 WithAPIPrefix、WithAPIVersion 与 WithEndpointVersion 替换前缀与版本；
+// 其他路径原样拼接到 BaseURL 之后。BaseURL 末尾的 "/" 会被忽略
+func (c *Client) resolveURL(path string) (string, error) {
+	base := strings.TrimRight(c.BaseURL, "/")
+	rest, ok := strings.CutPrefix(path, defaultAPIPrefix+"/"+string(APIv1))
+	if !ok || rest != "" && rest[0] != '/' && rest[0] != '?' {
+		return base + path, nil
+	}
+
+	version := c.versionFor(rest)
+	if version != APIv1 && version != APIv2 {
+		return "", fmt.Errorf("docgen: unsupported API version %q", version)
+	}
+	return base + c.apiPrefix() + "/" + string(version) + rest, nil
+}
+
+// apiPrefix 返回规范化的 API 路径前缀：以 "/" 开头、不以 "/" 结尾，空前缀返回 ""
+func (c *Client) apiPrefix() string {
+	if !c.customAPIPrefix {
+		return defaultAPIPrefix
+	}
+	prefix := strings.Trim(c.apiPathPrefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// versionFor 返回接口使用的 API 版本：最长匹配的 WithEndpointVersion 覆盖优先，其次为 WithAPIVersion
+//
+// rest: 去掉前缀与版本后的接口路径，可带查询参数
+func (c *Client) versionFor(rest string) APIVersion {
+	endpoint, _, _ := strings.Cut(rest, "?")
+	version, matched := c.apiVersion, -1
+	for _, o := range c.endpointVersions {
+		if len(o.endpoint) > matched && endpointMatches(endpoint, o.endpoint) {
+			version, matched = o.version, len(o.endpoint)
+		}
+	}
+	if version == "" {
+		return APIv1
+	}
+	return version
+}
+
+// endpointMatches 判断 endpoint 是否为 pattern 或其下级路径（按路径段匹配）
+func endpointMatches(endpoint, pattern string) bool {
+	if !strings.HasPrefix(endpoint, pattern) {
+		return false
+	}
+	return len(endpoint) == len(pattern) || strings.HasSuffix(pattern, "/") || endpoint[len(pattern)] == '/'
+}
+
+// normalizeEndpoint 规范化 WithEndpointVersion 的接口路径：去掉 "/api/v1" 等前缀，以 "/" 开头、不以 "/" 结尾
+func normalizeEndpoint(endpoint string) string {
+	endpoint = "/" + strings.Trim(endpoint, "/")
+	for _, v := range []APIVersion{APIv1, APIv2} {
+		if rest, ok := strings.CutPrefix(endpoint, defaultAPIPrefix+"/"+string(v)); ok && (rest == "" || rest[0] == '/') {
+			return "/" + strings.TrimPrefix(rest, "/")
+		}
+	}
+	return endpoint
+}
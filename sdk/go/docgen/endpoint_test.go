@@ -0,0 +1,96 @@
+package docgen_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// TestResolveURL 固定每种组合实际请求的路径：BaseURL 有无末尾 "/"、有无已有的路径前缀，
+// 与 WithAPIPrefix、WithAPIVersion、WithEndpointVersion（固定版本）的组合
+func TestResolveURL(t *testing.T) {
+	bases := []string{"", "/", "/gateway", "/gateway/"}
+	tests := []struct {
+		name string
+		opts []docgen.Option
+		// word、batch 相对 BaseURL 路径的请求路径
+		word, batch string
+	}{
+		{"default", nil, "/api/v1/doc/word", "/api/v1/doc/word/batch"},
+		{"v2", []docgen.Option{docgen.WithAPIVersion(docgen.APIv2)}, "/api/v2/doc/word", "/api/v2/doc/word/batch"},
+		{"batch pinned to v2", []docgen.Option{docgen.WithEndpointVersion("/doc/word/batch", docgen.APIv2)},
+			"/api/v1/doc/word", "/api/v2/doc/word/batch"},
+		{"batch pinned with the full path", []docgen.Option{docgen.WithEndpointVersion("/api/v1/doc/word/batch/", docgen.APIv2)},
+			"/api/v1/doc/word", "/api/v2/doc/word/batch"},
+		{"parent pinned covers the sub path", []docgen.Option{docgen.WithAPIVersion(docgen.APIv2), docgen.WithEndpointVersion("/doc/word", docgen.APIv1)},
+			"/api/v1/doc/word", "/api/v1/doc/word/batch"},
+		{"longest pin wins", []docgen.Option{docgen.WithEndpointVersion("/doc/word", docgen.APIv2), docgen.WithEndpointVersion("/doc/word/batch", docgen.APIv1)},
+			"/api/v2/doc/word", "/api/v1/doc/word/batch"},
+		{"pin does not match a sibling", []docgen.Option{docgen.WithEndpointVersion("/doc/wo", docgen.APIv2)},
+			"/api/v1/doc/word", "/api/v1/doc/word/batch"},
+		{"prefix", []docgen.Option{docgen.WithAPIPrefix("/edge/docgen/")}, "/edge/docgen/v1/doc/word", "/edge/docgen/v1/doc/word/batch"},
+		{"prefix without slashes", []docgen.Option{docgen.WithAPIPrefix("edge")}, "/edge/v1/doc/word", "/edge/v1/doc/word/batch"},
+		{"prefix and pin", []docgen.Option{docgen.WithAPIPrefix("/edge"), docgen.WithEndpointVersion("/doc/word/batch", docgen.APIv2)},
+			"/edge/v1/doc/word", "/edge/v2/doc/word/batch"},
+		{"empty prefix", []docgen.Option{docgen.WithAPIPrefix("")}, "/v1/doc/word", "/v1/doc/word/batch"},
+		{"empty prefix and pin", []docgen.Option{docgen.WithAPIPrefix("/"), docgen.WithAPIVersion(docgen.APIv2), docgen.WithEndpointVersion("/doc/word", docgen.APIv1)},
+			"/v1/doc/word", "/v1/doc/word/batch"},
+	}
+
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	for _, base := range bases {
+		for _, tt := range tests {
+			t.Run(tt.name+"/base "+base, func(t *testing.T) {
+				client := docgen.NewClient(srv.URL+base, tt.opts...)
+				basePath := strings.TrimRight(base, "/")
+				calls := []struct {
+					want string
+					call func()
+				}{
+					{basePath + tt.word, func() {
+						client.GenerateWordContext(context.Background(), wordReq)
+					}},
+					{basePath + tt.batch, func() {
+						client.BatchGenerateWordContext(context.Background(), docgen.WordBatchRequest{TemplateName: "t.docx", DataList: []map[string]any{{}}})
+					}},
+				}
+				for _, c := range calls {
+					// 模拟服务端不提供带前缀或 v2 的接口，这里只检查请求路径，不检查结果
+					srv.ResetRequests()
+					c.call()
+					var paths []string
+					for _, r := range srv.Requests() {
+						if strings.Contains(r.Path, "/doc/word") {
+							paths = append(paths, r.Path)
+						}
+					}
+					if len(paths) != 1 || paths[0] != c.want {
+						t.Errorf("requested %v, want [%s]", paths, c.want)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestResolveURLUnsupportedVersion 不支持的 API 版本在发送前返回错误，不发出请求
+func TestResolveURLUnsupportedVersion(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	for _, opt := range []docgen.Option{docgen.WithAPIVersion("v3"), docgen.WithEndpointVersion("/doc/word", "v0")} {
+		client := docgen.NewClient(srv.URL, opt)
+		srv.ResetRequests()
+		if _, err := client.GenerateWordContext(context.Background(), wordReq); err == nil || !strings.Contains(err.Error(), "unsupported API version") {
+			t.Errorf("err = %v, want the unsupported version error", err)
+		}
+		for _, r := range srv.Requests() {
+			if strings.Contains(r.Path, "/doc/word") {
+				t.Errorf("request sent to %s", r.Path)
+			}
+		}
+	}
+}
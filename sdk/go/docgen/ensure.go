@@ -0,0 +1,243 @@
+package docgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// EnsureOptions EnsureTemplates 的配置
+type EnsureOptions struct {
+	// Root fsys 中模板所在的目录，默认 "."；只处理该目录下的文件，不进入子目录，以 "." 开头的文件被忽略
+	Root string
+	// Prune 删除服务端存在但 fsys 中没有的模板；服务端支持 FeatureTemplateDependencies 时，
+	// 仍被保留的模板引用的模板不会删除，记录在 SyncReport.InUse 中
+	Prune bool
+	// DryRun 只比较并报告需要执行的变更，不上传或删除
+	DryRun bool
+}
+
+// SyncReport EnsureTemplates 的执行结果，每个模板只出现在一个列表中，名称均已排序
+type SyncReport struct {
+	// Created 服务端原本不存在、已上传的模板
+	Created []string
+	// Updated 内容不一致、已覆盖上传的模板
+	Updated []string
+	// Unchanged 内容已一致的模板（包括由其他副本同时上传的模板）
+	Unchanged []string
+	// Deleted 已删除的服务端模板（Prune）
+	Deleted []string
+	// InUse 仍被其他保留模板引用、因此未删除的服务端模板（Prune）
+	InUse []string
+}
+
+// Changed 是否执行（或在 DryRun 时需要执行）了任何变更
+func (r *SyncReport) Changed() bool {
+	return len(r.Created)+len(r.Updated)+len(r.Deleted) > 0
+}
+
+// EnsureTemplates 使服务端模板与 fsys（如 go:embed 的 embed.FS）中的文件保持一致
+//
+// 逐个比较 SHA-256（见 GetTemplateChecksum），上传缺失的模板、覆盖内容不一致的模板，
+// 启用 Prune 时删除 fsys 中没有、且未被保留模板引用的服务端模板。重复执行不会产生多余的变更；
+// 多个副本同时启动并发执行时，其他副本已完成的上传与删除被视为成功，不计入本次变更
+func (c *Client) EnsureTemplates(ctx context.Context, fsys fs.FS, opts EnsureOptions) (*SyncReport, error) {
+	local, err := readTemplateFS(fsys, opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Prune && len(local) == 0 {
+		// 目录配置错误时不应清空服务端模板
+		return nil, fmt.Errorf("docgen: ensure templates: no templates found in %q, refusing to prune", rootOrDot(opts.Root))
+	}
+
+	remoteNames, err := c.ListTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("docgen: ensure templates: %w", err)
+	}
+	remote := make(map[string]bool, len(remoteNames))
+	for _, name := range remoteNames {
+		remote[name] = true
+	}
+
+	report := &SyncReport{}
+	names := make([]string, 0, len(local))
+	for name := range local {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data := local[name]
+		sum := sha256.Sum256(data)
+		want := hex.EncodeToString(sum[:])
+
+		if remote[name] {
+			got, err := c.GetTemplateChecksum(ctx, name)
+			if err != nil && !isTemplateNotFound(err) {
+				return report, fmt.Errorf("docgen: ensure templates: %s: %w", name, err)
+			}
+			if err == nil && got == want {
+				report.Unchanged = append(report.Unchanged, name)
+				continue
+			}
+			remote[name] = err == nil
+		}
+
+		if !opts.DryRun {
+			if _, err := c.uploadBytes(ctx, data, name, UploadOptions{Overwrite: true}); err != nil {
+				// 并发执行时其他副本可能已上传相同内容
+				if got, sumErr := c.GetTemplateChecksum(ctx, name); sumErr == nil && got == want {
+					report.Unchanged = append(report.Unchanged, name)
+					continue
+				}
+				return report, fmt.Errorf("docgen: ensure templates: upload %s: %w", name, err)
+			}
+		}
+		if remote[name] {
+			report.Updated = append(report.Updated, name)
+		} else {
+			report.Created = append(report.Created, name)
+		}
+	}
+
+	if opts.Prune {
+		sort.Strings(remoteNames)
+		pruned := make(map[string]bool)
+		for _, name := range remoteNames {
+			if _, ok := local[name]; !ok {
+				pruned[name] = true
+			}
+		}
+		// 旧版服务端无法查询引用关系，按原方式删除
+		checkDependents := c.requireFeature(ctx, FeatureTemplateDependencies) == nil
+		for _, name := range remoteNames {
+			if !pruned[name] {
+				continue
+			}
+			if checkDependents {
+				// 引用方也将被删除时不阻止删除
+				err := c.checkDependents(ctx, name, pruned)
+				if errors.Is(err, ErrTemplateInUse) {
+					report.InUse = append(report.InUse, name)
+					continue
+				}
+				if err != nil {
+					return report, fmt.Errorf("docgen: ensure templates: dependencies of %s: %w", name, err)
+				}
+			}
+			if !opts.DryRun {
+				if _, err := c.deleteTemplateContext(ctx, name); err != nil {
+					if isTemplateNotFound(err) {
+						// 已被其他副本删除
+						continue
+					}
+					if errors.Is(err, ErrTemplateInUse) {
+						// 服务端拒绝删除仍被引用的模板
+						report.InUse = append(report.InUse, name)
+						continue
+					}
+					return report, fmt.Errorf("docgen: ensure templates: delete %s: %w", name, err)
+				}
+			}
+			report.Deleted = append(report.Deleted, name)
+		}
+	}
+	return report, nil
+}
+
+// GetTemplateChecksum 获取服务端模板内容的 SHA-256（十六进制小写）
+//
+// 优先读取模板下载接口 HEAD 响应中的摘要（X-Content-SHA256、Digest 等，见 responseSHA256），
+// 服务端未提供时下载模板并计算
+func (c *Client) GetTemplateChecksum(ctx context.Context, templateName string) (string, error) {
+	path := c.templatePath(ctx, "/api/v1/template/download", templateName)
+	req, err := c.newRequest(ctx, http.MethodHead, path, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, _, err := c.executeResponse(req)
+	if err != nil {
+		return "", err
+	}
+	if sum := responseSHA256(resp.Header); sum != "" {
+		return sum, nil
+	}
+
+	req, err = c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+	data, err := c.execute(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// deleteTemplateContext 删除模板，DeleteTemplate 的 context 版本
+func (c *Client) deleteTemplateContext(ctx context.Context, templateName string) (*DeleteResponse, error) {
+	defer c.invalidateSchema(c.qualifyTemplate(ctx, templateName))
+	req, err := c.newRequest(ctx, http.MethodDelete, c.templatePath(ctx, "/api/v1/template", templateName), nil)
+	if err != nil {
+		return nil, err
+	}
+	var result DeleteResponse
+	if err := c.executeJSON(req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// readTemplateFS 读取 root 目录下的模板文件（不含子目录与以 "." 开头的文件）
+func readTemplateFS(fsys fs.FS, root string) (map[string][]byte, error) {
+	root = rootOrDot(root)
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, fmt.Errorf("docgen: ensure templates: %w", err)
+	}
+	files := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !entry.Type().IsRegular() {
+			continue
+		}
+		f, err := fsys.Open(path.Join(root, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("docgen: ensure templates: %w", err)
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("docgen: ensure templates: read %s: %w", entry.Name(), err)
+		}
+		files[entry.Name()] = data
+	}
+	return files, nil
+}
+
+// rootOrDot 返回规范化的目录，空字符串视为 "."
+func rootOrDot(root string) string {
+	if root == "" {
+		return "."
+	}
+	return path.Clean(root)
+}
+
+// isTemplateNotFound 判断错误是否表示模板不存在（404 或 422 TEMPLATE_NOT_FOUND；HEAD 响应没有响应体，见 headErrorResponse）
+func isTemplateNotFound(err error) bool {
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		return false
+	}
+	return errResp.Status == http.StatusNotFound ||
+		errResp.Status == http.StatusUnprocessableEntity && (errResp.Code == "" || errResp.Code == CodeTemplateNotFound)
+}
@@ -0,0 +1,212 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 预定义错误，可配合 errors.Is 判断错误类型
+var (
+	// ErrServiceDown 服务可连接，但健康检查报告不可用（如 actuator 返回 503 + DOWN）
+	ErrServiceDown = errors.New("docgen: service is down")
+	// ErrUnreachable 服务无法连接（连接被拒绝、DNS 解析失败等传输层错误）
+	ErrUnreachable = errors.New("docgen: service unreachable")
+	// ErrTimeout 请求超时（客户端超时、context 截止时间或网络层超时）
+	ErrTimeout = errors.New("docgen: request timed out")
+	// ErrRequestTooLarge 请求体超过 Client.MaxRequestBytes 限制
+	ErrRequestTooLarge = errors.New("docgen: request too large")
+	// ErrUnexpectedContent 响应内容不是预期格式的文档（如代理返回 200 状态的 HTML 错误页）
+	ErrUnexpectedContent = errors.New("docgen: unexpected content")
+	// ErrTemplateNotFound 请求的模板不存在（错误码 CodeTemplateNotFound）
+	ErrTemplateNotFound = errors.New("docgen: template not found")
+)
+
+// OpError 带有操作上下文的错误，便于日志记录
+//
+// 可通过 errors.As 获取，errors.Is / errors.As 可继续匹配原始错误
+type OpError struct {
+	// Op 操作名称，如 "GenerateWordWithFallback" 或 "POST /api/v1/doc/word"
+	Op string
+	// Templates 按顺序尝试过的模板
+	Templates []string
+	// TemplateName 产生该错误的模板（最后尝试的模板）
+	TemplateName string
+	// DumpPath 该请求的调试文件（WithDebugDump），未启用时为空
+	DumpPath string
+	// CorrelationID 出错请求的关联 ID（X-Correlation-Id），可据此检索服务端日志
+	CorrelationID string
+	// Err 原始错误
+	Err error
+}
+
+// Error 实现 error 接口
+func (e *OpError) Error() string {
+	var b strings.Builder
+	b.WriteString("docgen: ")
+	b.WriteString(e.Op)
+	if e.TemplateName != "" {
+		b.WriteString(" " + e.TemplateName)
+	}
+	if len(e.Templates) > 1 {
+		b.WriteString(" (tried " + strings.Join(e.Templates, ", ") + ")")
+	}
+	if e.CorrelationID != "" {
+		b.WriteString(" [correlation " + e.CorrelationID + "]")
+	}
+	if e.DumpPath != "" {
+		b.WriteString(" [dump " + e.DumpPath + "]")
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+// Unwrap 返回原始错误
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// requestError 将请求错误包装为带有关联 ID 与调试文件路径（WithDebugDump）的 *OpError，已包装的错误原样返回
+func requestError(req *http.Request, err error) error {
+	var opErr *OpError
+	if err == nil || errors.As(err, &opErr) && opErr.CorrelationID != "" {
+		return err
+	}
+	opErr = &OpError{Op: req.Method + " " + req.URL.Path, CorrelationID: requestCorrelationID(req), Err: err}
+	if record, _ := req.Context().Value(debugRecordKey{}).(*debugRecord); record != nil {
+		opErr.DumpPath = record.path
+	}
+	if opErr.CorrelationID == "" && opErr.DumpPath == "" {
+		return err
+	}
+	return opErr
+}
+
+// TimeoutError 请求超时错误
+//
+// errors.Is(err, ErrTimeout) 返回 true，可通过 errors.As 获取耗时与接口信息；
+// 超时阶段不是 TimeoutTotal 时，errors.Is 对相应的 ErrDialTimeout、ErrResponseHeaderTimeout 等同样返回 true
+type TimeoutError struct {
+	// Method 请求方法
+	Method string
+	// Endpoint 请求的接口路径，如 "/api/v1/doc/word"
+	Endpoint string
+	// Elapsed 从发送请求到超时的耗时
+	Elapsed time.Duration
+	// Phase 超时发生的阶段，见 TimeoutPolicy
+	Phase TimeoutPhase
+	// Err 原始错误
+	Err error
+}
+
+// Error 实现 error 接口
+func (e *TimeoutError) Error() string {
+	if e.Phase != "" && e.Phase != TimeoutTotal {
+		return fmt.Sprintf("request %s %s timed out (%s) after %s: %v", e.Method, e.Endpoint, e.Phase, e.Elapsed.Round(time.Millisecond), e.Err)
+	}
+	return fmt.Sprintf("request %s %s timed out after %s: %v", e.Method, e.Endpoint, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrTimeout) 与对应阶段的 errors.Is(err, ErrDialTimeout) 等成立
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout || target != nil && target == timeoutPhaseErrors[e.Phase]
+}
+
+// newTimeoutError 根据请求创建超时错误，按原始错误判断超时阶段
+func newTimeoutError(req *http.Request, start time.Time, err error) *TimeoutError {
+	return &TimeoutError{
+		Method:   req.Method,
+		Endpoint: req.URL.Path,
+		Elapsed:  time.Since(start),
+		Phase:    timeoutPhase(req, err),
+		Err:      err,
+	}
+}
+
+// RequestTooLargeError 请求体超过大小限制错误
+//
+// errors.Is(err, ErrRequestTooLarge) 返回 true
+type RequestTooLargeError struct {
+	// Endpoint 请求的接口路径
+	Endpoint string
+	// Size 中止序列化时已产生的字节数（实际请求体不小于该值）
+	Size int64
+	// Limit 配置的上限
+	Limit int64
+}
+
+// Error 实现 error 接口
+func (e *RequestTooLargeError) Error() string {
+	return fmt.Sprintf("request to %s exceeds MaxRequestBytes (%d bytes observed, limit %d); "+
+		"split the data into smaller batches or use streaming/chunked modes", e.Endpoint, e.Size, e.Limit)
+}
+
+// Is 使 errors.Is(err, ErrRequestTooLarge) 成立
+func (e *RequestTooLargeError) Is(target error) bool {
+	return target == ErrRequestTooLarge
+}
+
+// UnexpectedContentError 生成结果未通过文档格式校验
+//
+// errors.Is(err, ErrUnexpectedContent) 返回 true
+type UnexpectedContentError struct {
+	// Format 预期的文档格式
+	Format Format
+	// Reason 校验失败原因
+	Reason string
+	// Prefix 响应体开头（最多 64 字节），便于识别 HTML 错误页等内容
+	Prefix string
+}
+
+// Error 实现 error 接口
+func (e *UnexpectedContentError) Error() string {
+	return fmt.Sprintf("%v: expected %s document, %s (body starts with %q)", ErrUnexpectedContent, e.Format, e.Reason, e.Prefix)
+}
+
+// Is 使 errors.Is(err, ErrUnexpectedContent) 成立
+func (e *UnexpectedContentError) Is(target error) bool {
+	return target == ErrUnexpectedContent
+}
+
+// isTimeout 判断错误是否为超时错误
+//
+// 覆盖 context.DeadlineExceeded、http.Client.Timeout 以及实现了 net.Error 的网络层超时
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsRetryable 判断错误是否可重试
+//
+// 超时、服务无法连接，以及 429/502/503/504 错误响应视为可重试；配额耗尽（ErrQuotaExceeded）的 429 不可重试
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrQuotaExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrTimeout) || errors.Is(err, ErrUnreachable) {
+		return true
+	}
+
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		switch errResp.Status {
+		case http.StatusTooManyRequests, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
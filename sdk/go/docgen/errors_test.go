@@ -0,0 +1,109 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// sleepyServer 在响应前等待 d 或请求被取消
+func sleepyServer(t *testing.T, d time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 读完请求体后服务端才能感知客户端断开
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(d):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("PK"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+var wordReq = docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"a": 1}}
+
+func TestPerCallDeadlineIsTimeout(t *testing.T) {
+	srv := sleepyServer(t, 500*time.Millisecond)
+	client := docgen.NewClient(srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GenerateWordContext(ctx, wordReq)
+	if !errors.Is(err, docgen.ErrTimeout) || !docgen.IsRetryable(err) {
+		t.Fatalf("err = %v, want retryable ErrTimeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want it to match context.DeadlineExceeded", err)
+	}
+	var timeoutErr *docgen.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("err = %v, want *TimeoutError", err)
+	}
+	if timeoutErr.Endpoint != "/api/v1/doc/word" || timeoutErr.Elapsed < 40*time.Millisecond {
+		t.Errorf("TimeoutError = %+v", timeoutErr)
+	}
+}
+
+func TestClientTimeoutIsTimeout(t *testing.T) {
+	srv := sleepyServer(t, 500*time.Millisecond)
+	client := docgen.NewClientWithTimeout(srv.URL, 50*time.Millisecond)
+
+	_, err := client.GenerateWordContext(context.Background(), wordReq)
+	if !errors.Is(err, docgen.ErrTimeout) || !docgen.IsRetryable(err) {
+		t.Fatalf("err = %v, want retryable ErrTimeout", err)
+	}
+	if errors.Is(err, docgen.ErrUnreachable) {
+		t.Errorf("timeout also reported as ErrUnreachable: %v", err)
+	}
+}
+
+func TestCanceledRequestIsNotRetryable(t *testing.T) {
+	srv := sleepyServer(t, 500*time.Millisecond)
+	client := docgen.NewClient(srv.URL)
+
+	calls := map[string]func(ctx context.Context) error{
+		"buffered": func(ctx context.Context) error {
+			_, err := client.GenerateWordContext(ctx, wordReq)
+			return err
+		},
+		"streaming": func(ctx context.Context) error {
+			_, err := client.GenerateWordTo(ctx, wordReq, &bytes.Buffer{})
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			err := call(ctx)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want context.Canceled", err)
+			}
+			if errors.Is(err, docgen.ErrUnreachable) || errors.Is(err, docgen.ErrTimeout) || docgen.IsRetryable(err) {
+				t.Errorf("canceled request classified as retryable: %v", err)
+			}
+		})
+	}
+}
+
+func TestUnreachableIsRetryable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	_, err := docgen.NewClient(url).GenerateWordContext(context.Background(), wordReq)
+	if !errors.Is(err, docgen.ErrUnreachable) || !docgen.IsRetryable(err) {
+		t.Fatalf("err = %v, want retryable ErrUnreachable", err)
+	}
+}
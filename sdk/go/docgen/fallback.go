@@ -0,0 +1,69 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+)
+
+// GenerateWordWithFallback 按顺序尝试 templates 生成 Word 文档，如 {"invoice_tenantA.docx", "invoice_default.docx"}
+//
+// 只有模板不存在（ErrTemplateNotFound）时才尝试下一个模板，渲染或数据错误立即返回；
+// 返回的错误为 *OpError，记录尝试过的模板与出错的模板
+func (c *Client) GenerateWordWithFallback(templates []string, data map[string]any, fileName string) ([]byte, error) {
+	return documentData(c.GenerateWordWithFallbackMeta(context.Background(), templates, WordGenRequest{Data: data, FileName: fileName}))
+}
+
+// GenerateWordWithFallbackMeta GenerateWordWithFallback 的完整请求版本，req.TemplateName 被忽略；
+// Meta.TemplateName 为实际使用的模板
+func (c *Client) GenerateWordWithFallbackMeta(ctx context.Context, templates []string, req WordGenRequest) (*DocumentResult, error) {
+	return withFallback("GenerateWordWithFallback", templates, func(name string) (*DocumentResult, error) {
+		r := req
+		r.TemplateName = name
+		return c.GenerateWordWithMeta(ctx, r)
+	})
+}
+
+// FillExcelTemplateWithFallback 按顺序尝试 templates 填充 Excel 模板，行为与 GenerateWordWithFallback 一致
+func (c *Client) FillExcelTemplateWithFallback(templates []string, data map[string]any, listData map[string][]map[string]any, fileName string) ([]byte, error) {
+	req := ExcelFillRequest{Data: data, ListData: listData, FileName: fileName}
+	return documentData(c.FillExcelTemplateWithFallbackMeta(context.Background(), templates, req))
+}
+
+// FillExcelTemplateWithFallbackMeta FillExcelTemplateWithFallback 的完整请求版本，req.TemplateName 被忽略；
+// Meta.TemplateName 为实际使用的模板
+func (c *Client) FillExcelTemplateWithFallbackMeta(ctx context.Context, templates []string, req ExcelFillRequest) (*DocumentResult, error) {
+	return withFallback("FillExcelTemplateWithFallback", templates, func(name string) (*DocumentResult, error) {
+		r := req
+		r.TemplateName = name
+		return c.FillExcelTemplateWithMeta(ctx, r)
+	})
+}
+
+// withFallback 依次以 templates 调用 generate，直到成功或遇到模板不存在以外的错误
+func withFallback(op string, templates []string, generate func(name string) (*DocumentResult, error)) (*DocumentResult, error) {
+	if len(templates) == 0 {
+		return nil, &OpError{Op: op, Err: errors.New("no templates given")}
+	}
+	var err error
+	for i, name := range templates {
+		var result *DocumentResult
+		if result, err = generate(name); err == nil {
+			result.Meta.TemplateName = name
+			return result, nil
+		}
+		if !errors.Is(err, ErrTemplateNotFound) {
+			return nil, fallbackError(op, templates[:i+1], err)
+		}
+	}
+	return nil, fallbackError(op, templates, err)
+}
+
+// fallbackError 创建记录尝试过的模板的 *OpError，保留请求错误中的关联 ID 与调试文件路径
+func fallbackError(op string, tried []string, err error) *OpError {
+	opErr := &OpError{Op: op, Templates: tried, TemplateName: tried[len(tried)-1], Err: err}
+	var reqErr *OpError
+	if errors.As(err, &reqErr) && reqErr.Templates == nil {
+		opErr.DumpPath, opErr.CorrelationID, opErr.Err = reqErr.DumpPath, reqErr.CorrelationID, reqErr.Err
+	}
+	return opErr
+}
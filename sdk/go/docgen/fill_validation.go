@@ -0,0 +1,246 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// templateSchemaTTL 模板占位符结构的缓存时间，上传或删除模板时立即失效
+const templateSchemaTTL = 5 * time.Minute
+
+// WarningUnknownList ListData 中的键没有对应的列表区域（由 WithFillValidation 在客户端报告），Placeholder 为键名
+const WarningUnknownList = "UNKNOWN_LIST"
+
+// ErrFillValidation Excel 填充数据与模板占位符不匹配，具体错误类型为 *FillValidationError
+var ErrFillValidation = errors.New("docgen: fill data does not match template")
+
+// FillProblemKind 填充数据问题类型
+type FillProblemKind string
+
+const (
+	// FillUnknownList ListData 中的键没有对应的列表区域（{.field} 行循环）
+	FillUnknownList FillProblemKind = "unknown-list"
+	// FillMissingField 列表行缺少区域的必填字段
+	FillMissingField FillProblemKind = "missing-field"
+	// FillMissingVariable Data 缺少模板中的 {variable} 占位符
+	FillMissingVariable FillProblemKind = "missing-variable"
+)
+
+// FillProblem 填充数据中的一个问题
+type FillProblem struct {
+	// Kind 问题类型
+	Kind FillProblemKind
+	// Path 出问题的位置，如 `listData["orders"][12]`、`listData["foo"]`、`data`
+	Path string
+	// Field 缺少的字段或占位符名称，FillUnknownList 时为空
+	Field string
+}
+
+// String 返回问题描述，如 `listData["orders"][12] missing "amount"`
+func (p FillProblem) String() string {
+	if p.Kind == FillUnknownList {
+		return p.Path + " has no matching list region"
+	}
+	return p.Path + " missing " + strconv.Quote(p.Field)
+}
+
+// FillValidation ValidateFillRequest 的结果
+type FillValidation struct {
+	// TemplateName 模板文件名
+	TemplateName string
+	// Problems 发现的问题，按位置排序；为空表示数据与模板一致
+	Problems []FillProblem
+}
+
+// Valid 是否没有发现问题
+func (v *FillValidation) Valid() bool {
+	return len(v.Problems) == 0
+}
+
+// Err 有问题时返回 *FillValidationError，否则返回 nil
+func (v *FillValidation) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return &FillValidationError{TemplateName: v.TemplateName, Problems: v.Problems}
+}
+
+// FillValidationError Excel 填充数据与模板占位符不匹配
+//
+// errors.Is(err, ErrFillValidation) 返回 true
+type FillValidationError struct {
+	// TemplateName 模板文件名
+	TemplateName string
+	// Problems 发现的问题
+	Problems []FillProblem
+}
+
+// Error 实现 error 接口
+func (e *FillValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		problems[i] = p.String()
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrFillValidation, e.TemplateName, strings.Join(problems, "; "))
+}
+
+// Is 使 errors.Is(err, ErrFillValidation) 成立
+func (e *FillValidationError) Is(target error) bool {
+	return target == ErrFillValidation
+}
+
+// FillValidationMode WithFillValidation 的检查方式
+type FillValidationMode int
+
+const (
+	// FillValidationOff 不检查（默认）
+	FillValidationOff FillValidationMode = iota
+	// FillValidationWarn 将问题作为 Warning 交给 WithWarningHandler，仍然发送请求
+	FillValidationWarn
+	// FillValidationStrict 有问题时返回 *FillValidationError，不发送请求
+	FillValidationStrict
+)
+
+// ValidateFillRequest 按模板占位符结构（GetTemplateVariables）检查 Excel 填充请求
+//
+// 检查 ListData 的每个键都有对应的列表区域、每行都包含区域的必填字段、Data 覆盖所有 {variable} 占位符。
+// 检查的是经过 WithDataTransformer 与 WithFlattenedData 处理后、实际发送的数据；
+// 模板结构会缓存一段时间，上传或删除该模板时失效。获取模板结构失败时返回错误
+func (c *Client) ValidateFillRequest(req ExcelFillRequest) (*FillValidation, error) {
+	ctx := context.Background()
+	if err := c.transformFill(&req); err != nil {
+		return nil, err
+	}
+	if err := c.flattenFill(&req); err != nil {
+		return nil, err
+	}
+	return c.validateFill(ctx, req)
+}
+
+// validateFill 检查已转换的填充请求，TemplateName 为未添加租户前缀的名称
+func (c *Client) validateFill(ctx context.Context, req ExcelFillRequest) (*FillValidation, error) {
+	schema, err := c.templateSchema(ctx, req.TemplateName)
+	if err != nil {
+		return nil, err
+	}
+	return checkFill(schema, req), nil
+}
+
+// checkFill 比较填充数据与模板结构
+func checkFill(schema *TemplateSchema, req ExcelFillRequest) *FillValidation {
+	result := &FillValidation{TemplateName: req.TemplateName}
+	for _, v := range schema.Variables {
+		if _, ok := req.Data[v.Name]; !ok {
+			result.Problems = append(result.Problems, FillProblem{Kind: FillMissingVariable, Path: "data", Field: v.Name})
+		}
+	}
+
+	lists := make(map[string]TemplateList, len(schema.Lists))
+	for _, l := range schema.Lists {
+		lists[l.Name] = l
+	}
+	names := make([]string, 0, len(req.ListData))
+	for name := range req.ListData {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list, ok := lists[name]
+		if !ok {
+			result.Problems = append(result.Problems, FillProblem{Kind: FillUnknownList, Path: fmt.Sprintf("listData[%q]", name)})
+			continue
+		}
+		for i, row := range req.ListData[name] {
+			for _, f := range list.Fields {
+				if _, ok := row[f.Name]; f.Required && !ok {
+					result.Problems = append(result.Problems, FillProblem{Kind: FillMissingField, Path: fmt.Sprintf("listData[%q][%d]", name, i), Field: f.Name})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// precheckFill 启用 WithFillValidation 时在发送前检查填充请求
+//
+// 获取模板结构失败（如模板不存在、旧版服务端不支持内省）时不检查，由实际请求报告错误
+func (c *Client) precheckFill(ctx context.Context, req ExcelFillRequest) error {
+	if c.fillValidation == FillValidationOff {
+		return nil
+	}
+	result, err := c.validateFill(ctx, req)
+	if err != nil || result.Valid() {
+		return nil
+	}
+	if c.fillValidation == FillValidationStrict {
+		return result.Err()
+	}
+	if c.warningHandler != nil {
+		op := http.MethodPost + " /api/v1/doc/excel/fill"
+		for _, p := range result.Problems {
+			c.warningHandler(op, fillWarning(p))
+		}
+	}
+	return nil
+}
+
+// fillWarning 将填充问题转换为 Warning
+func fillWarning(p FillProblem) Warning {
+	if p.Kind == FillUnknownList {
+		name := strings.TrimSuffix(strings.TrimPrefix(p.Path, "listData["), "]")
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+		return Warning{Code: WarningUnknownList, Placeholder: name, Location: p.Path, Message: p.String()}
+	}
+	return Warning{Code: WarningMissingPlaceholder, Placeholder: p.Field, Location: p.Path, Message: p.String()}
+}
+
+// schemaCache 模板占位符结构缓存，键为添加租户前缀后的模板名称
+type schemaCache struct {
+	mu      sync.Mutex
+	entries map[string]schemaEntry
+}
+
+// schemaEntry 缓存的模板结构
+type schemaEntry struct {
+	schema  *TemplateSchema
+	fetched time.Time
+}
+
+// templateSchema 返回模板结构，缓存未命中或过期时请求 GetTemplateVariables
+func (c *Client) templateSchema(ctx context.Context, templateName string) (*TemplateSchema, error) {
+	key := c.qualifyTemplate(ctx, templateName)
+	c.schemaCache.mu.Lock()
+	entry, ok := c.schemaCache.entries[key]
+	c.schemaCache.mu.Unlock()
+	if ok && time.Since(entry.fetched) < templateSchemaTTL {
+		return entry.schema, nil
+	}
+
+	schema, err := c.templateVariables(ctx, templateName)
+	if err != nil {
+		return nil, err
+	}
+	c.schemaCache.mu.Lock()
+	defer c.schemaCache.mu.Unlock()
+	if c.schemaCache.entries == nil {
+		c.schemaCache.entries = make(map[string]schemaEntry)
+	}
+	c.schemaCache.entries[key] = schemaEntry{schema: schema, fetched: time.Now()}
+	return schema, nil
+}
+
+// invalidateSchema 模板被上传或删除后丢弃缓存的结构，qualifiedName 为添加租户前缀后的名称
+func (c *Client) invalidateSchema(qualifiedName string) {
+	c.schemaCache.mu.Lock()
+	defer c.schemaCache.mu.Unlock()
+	delete(c.schemaCache.entries, qualifiedName)
+}
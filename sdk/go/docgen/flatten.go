@@ -0,0 +1,209 @@
+package docgen
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrKeyCollision 展开后的键名与已有键名重复，如字面键 "order.customer.name" 与嵌套的 order → customer → name
+var ErrKeyCollision = errors.New("docgen: flattened key collision")
+
+// DefaultFlattenSeparator FlattenData 与 WithFlattenedData 的默认分隔符
+const DefaultFlattenSeparator = "."
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// FlattenData 将嵌套的 map 与结构体展开为以 sep 连接的键名，如 {"order": {"customer": {"name": "A"}}}
+// 展开为 {"order.customer.name": "A"}，对应 Excel 模板中的 {order.customer.name}
+//
+// 展开规则：
+//   - 键为字符串（或其他基础类型）的 map 与结构体逐层展开，结构体字段名遵循 json 标签（"-" 跳过、omitempty 省略零值），
+//     匿名嵌入的结构体字段提升到上一层
+//   - 指针与接口取其指向的值，nil 指针作为 nil 值保留
+//   - 空 map 与 nil map 不产生任何键
+//   - 切片、数组，以及实现了 json.Marshaler / encoding.TextMarshaler 的类型（如 time.Time、EncryptedField）
+//     作为整体值保留，不再展开
+//
+// 多个路径展开为同一键名时返回 ErrKeyCollision；sep 为空时使用 "."。data 不会被修改
+func FlattenData(data map[string]any, sep string) (map[string]any, error) {
+	if data == nil {
+		return nil, nil
+	}
+	if sep == "" {
+		sep = DefaultFlattenSeparator
+	}
+	f := &flattener{sep: sep, out: make(map[string]any, len(data))}
+	if err := f.walk("", reflect.ValueOf(data)); err != nil {
+		return nil, err
+	}
+	return f.out, nil
+}
+
+// flattener 展开过程的状态
+type flattener struct {
+	sep string
+	out map[string]any
+}
+
+// walk 展开 v，prefix 为已展开的键名前缀
+func (f *flattener) walk(prefix string, v reflect.Value) error {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return f.put(prefix, nil)
+		}
+		if isFlattenLeaf(v.Type()) {
+			return f.put(prefix, v.Interface())
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return f.put(prefix, nil)
+	}
+	if isFlattenLeaf(v.Type()) {
+		return f.put(prefix, v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if !isFlattenKey(v.Type().Key()) {
+			return f.put(prefix, v.Interface())
+		}
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k.Interface())
+		}
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		// 按键名排序，使冲突报告稳定
+		sort.Slice(order, func(a, b int) bool { return names[order[a]] < names[order[b]] })
+		for _, i := range order {
+			if err := f.walk(f.join(prefix, names[i]), v.MapIndex(keys[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		return f.walkStruct(prefix, v)
+	}
+	return f.put(prefix, v.Interface())
+}
+
+// walkStruct 按 json 标签展开结构体字段
+func (f *flattener) walkStruct(prefix string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isFlattenLeaf(field.Type) {
+				if fv.Kind() == reflect.Pointer && fv.IsNil() {
+					continue
+				}
+				if err := f.walkStruct(prefix, reflect.Indirect(fv)); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && fv.IsZero() {
+			continue
+		}
+		if err := f.walk(f.join(prefix, name), fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put 写入展开后的键值，键名重复时返回 ErrKeyCollision
+func (f *flattener) put(key string, value any) error {
+	if key == "" {
+		// 顶层值本身不是 map（如 nil），不产生键
+		return nil
+	}
+	if _, ok := f.out[key]; ok {
+		return fmt.Errorf("%w: %q", ErrKeyCollision, key)
+	}
+	f.out[key] = value
+	return nil
+}
+
+// join 连接键名前缀
+func (f *flattener) join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + f.sep + key
+}
+
+// isFlattenLeaf 判断类型是否作为整体值保留
+func isFlattenLeaf(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return true
+	case reflect.Interface:
+		return false
+	}
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		t.Kind() != reflect.Pointer && (reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType))
+}
+
+// isFlattenKey 判断 map 键类型能否转换为键名
+func isFlattenKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// flattenFill 启用 WithFlattenedData 时展开 Excel 填充请求的 Data 与 ListData 每一行
+func (c *Client) flattenFill(req *ExcelFillRequest) error {
+	if c.flattenSep == "" {
+		return nil
+	}
+	data, err := FlattenData(req.Data, c.flattenSep)
+	if err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+	req.Data = data
+
+	if req.ListData != nil {
+		listData := make(map[string][]map[string]any, len(req.ListData))
+		for name, rows := range req.ListData {
+			flat := make([]map[string]any, len(rows))
+			for i, row := range rows {
+				if flat[i], err = FlattenData(row, c.flattenSep); err != nil {
+					return fmt.Errorf("listData[%s][%d]: %w", name, i, err)
+				}
+			}
+			listData[name] = flat
+		}
+		req.ListData = listData
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package docgen_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+type flattenAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type flattenAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type flattenCustomer struct {
+	flattenAudit
+	Name     string          `json:"name"`
+	Address  *flattenAddress `json:"address"`
+	Billing  *flattenAddress `json:"billing"`
+	Tags     []string        `json:"tags"`
+	Secret   string          `json:"-"`
+	internal string
+}
+
+func TestFlattenData(t *testing.T) {
+	name := "Alice"
+	var nilAddress *flattenAddress
+	signed := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		data map[string]any
+		sep  string
+		want map[string]any
+	}{
+		{"nil data", nil, "", nil},
+		{"empty data", map[string]any{}, "", map[string]any{}},
+		{"nested maps", map[string]any{"order": map[string]any{"customer": map[string]any{"name": "A"}, "no": 7}},
+			"", map[string]any{"order.customer.name": "A", "order.no": 7}},
+		{"custom separator", map[string]any{"order": map[string]any{"no": 7}}, "_", map[string]any{"order_no": 7}},
+		{"non-string map keys", map[string]any{"qty": map[int]int{1: 10, 2: 20}}, "", map[string]any{"qty.1": 10, "qty.2": 20}},
+		// 切片与数组整体保留，不按下标展开
+		{"slice", map[string]any{"items": []any{map[string]any{"a": 1}}, "tags": []string{"x", "y"}},
+			"", map[string]any{"items": []any{map[string]any{"a": 1}}, "tags": []string{"x", "y"}}},
+		{"array", map[string]any{"pair": [2]int{1, 2}}, "", map[string]any{"pair": [2]int{1, 2}}},
+		{"nil slice", map[string]any{"items": []any(nil)}, "", map[string]any{"items": []any(nil)}},
+		// nil 与空 map 不产生键
+		{"nil map", map[string]any{"order": map[string]any(nil), "no": 1}, "", map[string]any{"no": 1}},
+		{"empty map", map[string]any{"order": map[string]any{}}, "", map[string]any{}},
+		{"nested nil map", map[string]any{"order": map[string]any{"customer": map[string]any(nil), "no": 1}},
+			"", map[string]any{"order.no": 1}},
+		{"nil map value", map[string]any{"order": map[string]any{"customer": nil}}, "", map[string]any{"order.customer": nil}},
+		// 指针取其指向的值，nil 指针作为 nil 值保留
+		{"pointer to scalar", map[string]any{"name": &name}, "", map[string]any{"name": "Alice"}},
+		{"pointer to map", map[string]any{"order": &map[string]any{"no": 1}}, "", map[string]any{"order.no": 1}},
+		{"pointer to struct", map[string]any{"addr": &flattenAddress{City: "Beijing"}}, "", map[string]any{"addr.city": "Beijing"}},
+		{"nil pointer", map[string]any{"addr": nilAddress}, "", map[string]any{"addr": nil}},
+		{"pointer to pointer", map[string]any{"name": func() **string { p := &name; return &p }()}, "", map[string]any{"name": "Alice"}},
+		// 结构体按 json 标签展开
+		{"struct fields", map[string]any{"customer": flattenCustomer{
+			flattenAudit: flattenAudit{CreatedBy: "bob"},
+			Name:         "Alice",
+			Address:      &flattenAddress{City: "Beijing", Zip: "100000"},
+			Tags:         []string{"vip"},
+			Secret:       "s",
+			internal:     "i",
+		}}, "", map[string]any{
+			"customer.createdBy":    "bob",
+			"customer.name":         "Alice",
+			"customer.address.city": "Beijing",
+			"customer.address.zip":  "100000",
+			"customer.billing":      nil,
+			"customer.tags":         []string{"vip"},
+		}},
+		// 实现 json.Marshaler / encoding.TextMarshaler 的值整体保留
+		{"time", map[string]any{"signed": signed, "at": &signed}, "", map[string]any{"signed": signed, "at": &signed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := docgen.FlattenData(tt.data, tt.sep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlattenData = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenDataCollision(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]any
+	}{
+		{"literal and nested", map[string]any{"order.customer.name": "A", "order": map[string]any{"customer": map[string]any{"name": "B"}}}},
+		{"literal and struct", map[string]any{"addr.city": "A", "addr": flattenAddress{City: "B"}}},
+		{"literal and pointer", map[string]any{"addr.city": "A", "addr": &flattenAddress{City: "B"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := docgen.FlattenData(tt.data, ""); !errors.Is(err, docgen.ErrKeyCollision) {
+				t.Errorf("err = %v, want ErrKeyCollision", err)
+			}
+		})
+	}
+}
+
+// TestFlattenDataDoesNotMutate FlattenData 与 WithFlattenedData 不修改调用方的 map
+func TestFlattenDataDoesNotMutate(t *testing.T) {
+	row := map[string]any{"item": map[string]any{"sku": "A1"}}
+	data := map[string]any{"order": map[string]any{"no": 1}}
+	req := docgen.ExcelFillRequest{TemplateName: "t.xlsx", Data: data, ListData: map[string][]map[string]any{"rows": {row}}}
+
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.xlsx", docgentest.MinimalXlsx())
+	client := docgen.NewClient(srv.URL, docgen.WithFlattenedData("."))
+	if _, err := client.FillExcelTemplateWithMeta(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	var sent docgen.ExcelFillRequest
+	if err := json.Unmarshal(srv.LastRequest().Body, &sent); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Data["order.no"] != 1.0 || sent.ListData["rows"][0]["item.sku"] != "A1" {
+		t.Errorf("sent data %v, list data %v; want the flattened keys", sent.Data, sent.ListData)
+	}
+
+	if !reflect.DeepEqual(data, map[string]any{"order": map[string]any{"no": 1}}) ||
+		!reflect.DeepEqual(row, map[string]any{"item": map[string]any{"sku": "A1"}}) {
+		t.Errorf("caller's data modified: %v %v", data, row)
+	}
+}
@@ -0,0 +1,59 @@
+package docgen
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FontOptions 生成文档的字体选项，用于在缺少模板字体（如方正字体）的机器上保持版式
+type FontOptions struct {
+	// EmbedFonts 将模板使用的字体嵌入生成的文档，需服务端支持 FeatureFontEmbedding
+	EmbedFonts bool `json:"embedFonts,omitempty"`
+	// Substitutions 字体替换表，键为模板中的字体名称，值为替换后的字体名称，如 "方正小标宋简体" → "SimSun"
+	Substitutions map[string]string `json:"substitutions,omitempty"`
+}
+
+// Validate 校验字体替换表：字体名称与替换目标均不能为空
+func (o *FontOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+	names := make([]string, 0, len(o.Substitutions))
+	for name := range o.Substitutions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("docgen: font substitution with empty source font")
+		}
+		if strings.TrimSpace(o.Substitutions[name]) == "" {
+			return fmt.Errorf("docgen: font substitution for %q has empty target", name)
+		}
+	}
+	return nil
+}
+
+// resolveFontOptions 返回请求实际使用的字体选项：请求未设置时使用 WithFontOptions 的默认值
+//
+// 请求嵌入字体而服务端能力接口明确未列出 FeatureFontEmbedding 时返回 *UnsupportedFeatureError；
+// 能力由探测得到或查询失败时无法判断，按原样发送
+func (c *Client) resolveFontOptions(ctx context.Context, opts *FontOptions) (*FontOptions, error) {
+	if opts == nil {
+		opts = c.fontOptions
+	}
+	if opts == nil {
+		return nil, nil
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.EmbedFonts {
+		if err := c.rejectUnsupported(ctx, FeatureFontEmbedding); err != nil {
+			return nil, err
+		}
+	}
+	return opts, nil
+}
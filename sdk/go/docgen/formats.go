@@ -0,0 +1,196 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ErrLegacyFormat 模板为旧版二进制格式（.doc、.xls），渲染引擎无法处理
+//
+// 具体错误类型为 *LegacyFormatError
+var ErrLegacyFormat = errors.New("docgen: legacy template format")
+
+// ErrMacroTemplatesDisabled 模板启用了宏（.docm、.xlsm），而客户端未通过 WithMacroTemplates(true) 允许
+var ErrMacroTemplatesDisabled = errors.New("docgen: macro-enabled templates are disabled")
+
+// zipSignature OOXML（.docx、.docm、.xlsx、.xlsm）文件头
+var zipSignature = []byte("PK\x03\x04")
+
+// oleSignature OLE 复合文档文件头，旧版 .doc、.xls 以及加密的 OOXML 文件均使用此格式
+var oleSignature = []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")
+
+// LegacyFormatError 模板为旧版二进制 Office 格式，渲染引擎只能处理 Office Open XML
+//
+// errors.Is(err, ErrLegacyFormat) 返回 true
+type LegacyFormatError struct {
+	// Name 模板文件名
+	Name string
+	// Want 应转换为的格式（FormatDocx 或 FormatXlsx）
+	Want Format
+}
+
+// Error 实现 error 接口，包含转换建议
+func (e *LegacyFormatError) Error() string {
+	return fmt.Sprintf("%v: %s: the render engine only reads Office Open XML; open it in Office, save it as .%s "+
+		"(File > Save As, without a password) and upload the new file", ErrLegacyFormat, e.Name, e.Want)
+}
+
+// Is 使 errors.Is(err, ErrLegacyFormat) 成立
+func (e *LegacyFormatError) Is(target error) bool {
+	return target == ErrLegacyFormat
+}
+
+// MacroOutput 启用宏的模板（.docm、.xlsm）生成文档时对宏的处理方式
+type MacroOutput string
+
+const (
+	// MacroStrip 去掉宏，输出 .docx / .xlsx（默认）
+	MacroStrip MacroOutput = "strip"
+	// MacroKeep 保留宏，输出 .docm / .xlsm
+	MacroKeep MacroOutput = "keep"
+)
+
+// ContentType 返回格式对应的 MIME 类型，未知格式返回 "application/octet-stream"
+func (f Format) ContentType() string {
+	switch f {
+	case FormatDocx:
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	case FormatDocm:
+		return "application/vnd.ms-word.document.macroEnabled.12"
+	case FormatXlsx:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatXlsm:
+		return "application/vnd.ms-excel.sheet.macroEnabled.12"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// templateFormat 根据扩展名判断模板格式，不是 OOXML 模板时返回空字符串
+func templateFormat(name string) Format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".docx":
+		return FormatDocx
+	case ".docm":
+		return FormatDocm
+	case ".xlsx":
+		return FormatXlsx
+	case ".xlsm":
+		return FormatXlsm
+	}
+	return ""
+}
+
+// isSpreadsheet 格式是否为 Excel 工作簿
+func isSpreadsheet(f Format) bool {
+	return f == FormatXlsx || f == FormatXlsm
+}
+
+// isMacroEnabled 格式是否启用宏
+func isMacroEnabled(f Format) bool {
+	return f == FormatDocm || f == FormatXlsm
+}
+
+// legacyFormat 旧版扩展名（.doc、.xls）返回 *LegacyFormatError，否则返回 nil
+func legacyFormat(name string) error {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".doc":
+		return &LegacyFormatError{Name: name, Want: FormatDocx}
+	case ".xls":
+		return &LegacyFormatError{Name: name, Want: FormatXlsx}
+	}
+	return nil
+}
+
+// checkTemplateName 检查模板名称引用的格式可以使用：旧版格式返回 *LegacyFormatError，
+// 未允许宏时启用宏的模板返回 ErrMacroTemplatesDisabled
+func (c *Client) checkTemplateName(name string) error {
+	if err := legacyFormat(name); err != nil {
+		return err
+	}
+	if isMacroEnabled(templateFormat(name)) && !c.macroTemplates {
+		return fmt.Errorf("%w: %s (enable with WithMacroTemplates(true))", ErrMacroTemplatesDisabled, name)
+	}
+	return nil
+}
+
+// checkTemplateUpload 上传前按名称与文件头检查模板
+//
+// head 为文件开头（至少 8 字节，文件更短时为全部内容）。OLE 复合文档返回 *LegacyFormatError，
+// OOXML 扩展名的文件缺少 zip 文件头时返回 *UnexpectedContentError；其他扩展名交由服务端判断
+func (c *Client) checkTemplateUpload(name string, head []byte) error {
+	if err := c.checkTemplateName(name); err != nil {
+		return err
+	}
+	format := templateFormat(name)
+	if bytes.HasPrefix(head, oleSignature) {
+		want := FormatDocx
+		if isSpreadsheet(format) {
+			want = FormatXlsx
+		}
+		return &LegacyFormatError{Name: name, Want: want}
+	}
+	if format != "" && !bytes.HasPrefix(head, zipSignature) {
+		return fmt.Errorf("template %s: %w", name, newUnexpectedContent(head, format, "missing zip signature"))
+	}
+	return nil
+}
+
+// checkTemplateFile 读取文件开头并按 checkTemplateUpload 检查，之后将读取位置恢复到开头
+func (c *Client) checkTemplateFile(name string, f io.ReadSeeker) error {
+	head := make([]byte, len(oleSignature))
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := c.checkTemplateUpload(name, head[:n]); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
+
+// resolveOutputFormat 检查请求引用的模板格式；模板启用宏且未指定输出格式时，按 WithMacroOutput 设置
+func (c *Client) resolveOutputFormat(ctx context.Context, templateName string, output *Format) error {
+	if err := c.checkTemplateName(templateName); err != nil {
+		return err
+	}
+	format := templateFormat(templateName)
+	if !isMacroEnabled(format) {
+		return nil
+	}
+	if err := c.rejectUnsupported(ctx, FeatureMacroTemplates); err != nil {
+		return err
+	}
+	if *output == "" {
+		*output = c.macroOutputFormat(format)
+	}
+	return nil
+}
+
+// macroOutputFormat 启用宏的模板格式按 WithMacroOutput 对应的输出格式，其他格式原样返回
+func (c *Client) macroOutputFormat(format Format) Format {
+	if c.macroOutput == MacroKeep {
+		return format
+	}
+	return strippedFormat(format)
+}
+
+// strippedFormat 启用宏的格式去掉宏后的格式，其他格式原样返回
+func strippedFormat(format Format) Format {
+	switch format {
+	case FormatDocm:
+		return FormatDocx
+	case FormatXlsm:
+		return FormatXlsx
+	}
+	return format
+}
@@ -0,0 +1,102 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TemplateChecksumHeader 模板校验失败时，服务端在此响应头中返回模板当前的 SHA-256
+const TemplateChecksumHeader = "X-Template-SHA256"
+
+// ErrTemplateChanged 模板内容与请求期望的校验和不一致（模板已被替换），具体错误类型为 *TemplateChangedError
+var ErrTemplateChanged = errors.New("docgen: template changed")
+
+// TemplateChangedError 模板内容与期望的校验和不一致
+//
+// errors.Is(err, ErrTemplateChanged) 返回 true
+type TemplateChangedError struct {
+	// TemplateName 模板文件名（服务端校验失败时为请求中的名称，可能含租户前缀）
+	TemplateName string
+	// Expected 请求期望的 SHA-256
+	Expected string
+	// Current 模板当前的 SHA-256，服务端未返回时为空
+	Current string
+	// Err 服务端返回的原始错误，由 SDK 预检发现时为 nil
+	Err *ErrorResponse
+}
+
+// Error 实现 error 接口
+func (e *TemplateChangedError) Error() string {
+	if e.Current == "" {
+		return fmt.Sprintf("%v: %s: expected sha256 %s", ErrTemplateChanged, e.TemplateName, e.Expected)
+	}
+	return fmt.Sprintf("%v: %s: expected sha256 %s, current %s", ErrTemplateChanged, e.TemplateName, e.Expected, e.Current)
+}
+
+// Unwrap 返回服务端原始错误
+func (e *TemplateChangedError) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrTemplateChanged) 成立
+func (e *TemplateChangedError) Is(target error) bool {
+	return target == ErrTemplateChanged
+}
+
+// EnsureTemplateUpToDate 检查服务端模板的 SHA-256 是否仍为 expectedChecksum
+//
+// templateName: 模板文件名
+// expectedChecksum: 缓存模板信息时记录的 SHA-256（十六进制，不区分大小写）
+//
+// 不一致时返回 *TemplateChangedError，其中包含模板当前的校验和
+func (c *Client) EnsureTemplateUpToDate(templateName string, expectedChecksum string) error {
+	return c.ensureTemplateUpToDate(context.Background(), templateName, expectedChecksum)
+}
+
+// ensureTemplateUpToDate EnsureTemplateUpToDate 的 context 版本
+func (c *Client) ensureTemplateUpToDate(ctx context.Context, templateName, expectedChecksum string) error {
+	current, err := c.GetTemplateChecksum(ctx, templateName)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(current, expectedChecksum) {
+		return &TemplateChangedError{TemplateName: templateName, Expected: expectedChecksum, Current: current}
+	}
+	return nil
+}
+
+// precheckTemplate 启用 WithStrictTemplateChecksums 且服务端未声明 FeatureTemplateChecksum 时，
+// 在发送生成请求前由 SDK 检查模板校验和
+//
+// templateName 为未添加租户前缀的名称
+func (c *Client) precheckTemplate(ctx context.Context, templateName, expectedChecksum string) error {
+	if expectedChecksum == "" || !c.strictTemplateChecksums {
+		return nil
+	}
+	if caps, err := c.Capabilities(ctx); err == nil && caps.Supports(FeatureTemplateChecksum) {
+		return nil
+	}
+	return c.ensureTemplateUpToDate(ctx, templateName, expectedChecksum)
+}
+
+// annotateTemplateChanged 为服务端返回的 *TemplateChangedError 补充请求中的模板名称与期望校验和
+func annotateTemplateChanged(err error, reqBody any) error {
+	var changed *TemplateChangedError
+	if !errors.As(err, &changed) {
+		return err
+	}
+	switch req := reqBody.(type) {
+	case WordGenRequest:
+		changed.TemplateName, changed.Expected = req.TemplateName, req.ExpectedTemplateChecksum
+	case WordBatchRequest:
+		changed.TemplateName, changed.Expected = req.TemplateName, req.ExpectedTemplateChecksum
+	case ExcelFillRequest:
+		changed.TemplateName, changed.Expected = req.TemplateName, req.ExpectedTemplateChecksum
+	}
+	return err
+}
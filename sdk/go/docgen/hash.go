@@ -0,0 +1,222 @@
+package docgen
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// requestHashVersion 规范化规则的版本，规则变化时递增，使旧的哈希值不再匹配
+const requestHashVersion = "docgen-request-hash-v1"
+
+// DefaultHashExclusions RequestHash 默认忽略的字段：不影响生成内容、只影响命名或排队的字段
+//
+// 字段以请求体中的 JSON 名称表示，嵌套字段以 "." 分隔（如 "fontOptions.embedFonts"）
+var DefaultHashExclusions = []string{"fileName", "priority"}
+
+// RequestHashOptions RequestHashWithOptions 的选项
+type RequestHashOptions struct {
+	// Exclude 忽略的字段，为 nil 时使用 DefaultHashExclusions；需要在默认值基础上增加时应包含默认值，
+	// 空切片表示不忽略任何字段
+	Exclude []string
+	// FieldKey 计算未加密的 EncryptedField 的 HMAC-SHA256 所用的密钥，为空时使用固定的公开密钥；
+	// 哈希值会被持久化且加密字段的取值范围较小（如薪资）时应设置，防止由哈希值穷举明文
+	FieldKey []byte
+}
+
+// RequestHash 计算生成请求的规范化 SHA-256（十六进制小写），相同含义的请求得到相同的值
+//
+// 哈希覆盖接口路径与请求体：对象的键按字典序排列，数值按精确值规范化（1、1.0、1e0 相同），
+// 值为 null 的字段与缺少该字段相同，DefaultHashExclusions 中的字段（FileName、Priority）被忽略。
+// 尚未加密的 EncryptedField 以其明文的规范形式的 HMAC-SHA256 参与计算（见 RequestHashOptions.FieldKey），
+// 不需要配置 WithFieldEncryption。关联 ID、幂等键、租户等请求头不参与计算。SDK 内部需要判断"同一请求"的地方
+// （发件箱去重、RunBatchFromJSONL 断点续跑、发件箱与 WithHedging 自动生成的 Idempotency-Key）均使用此函数的规则
+func RequestHash(req GenerationRequest) (string, error) {
+	return RequestHashWithOptions(req, RequestHashOptions{})
+}
+
+// RequestHashWithOptions 按选项计算生成请求的规范化 SHA-256，规则与 RequestHash 相同
+func RequestHashWithOptions(req GenerationRequest, opts RequestHashOptions) (string, error) {
+	endpoint, body, err := req.target()
+	if err != nil {
+		return "", err
+	}
+	exclude := opts.Exclude
+	if exclude == nil {
+		exclude = DefaultHashExclusions
+	}
+	fieldKey := opts.FieldKey
+	if len(fieldKey) == 0 {
+		fieldKey = []byte(requestHashVersion)
+	}
+	if body, err = digestFields(body, fieldKey); err != nil {
+		return "", fmt.Errorf("docgen: request hash: %w", err)
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("docgen: request hash: %w", err)
+	}
+	return canonicalHash(endpoint, raw, exclude)
+}
+
+// canonicalHash 按 RequestHash 的规则计算接口路径与 JSON 请求体的哈希，exclude 中的字段被忽略
+func canonicalHash(endpoint string, raw []byte, exclude []string) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return "", fmt.Errorf("docgen: request hash: %w", err)
+	}
+	for _, path := range exclude {
+		removeField(tree, strings.Split(path, "."))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(requestHashVersion + "\n" + endpoint + "\n")
+	if err := writeCanonical(&buf, tree); err != nil {
+		return "", fmt.Errorf("docgen: request hash: %w", err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// requestIdempotencyKey 由请求体计算 Idempotency-Key：规则与 RequestHash 相同但不忽略任何字段，
+// 使相同的键总是对应完全相同的请求体
+func requestIdempotencyKey(endpoint string, raw []byte) (string, error) {
+	return canonicalHash(endpoint, raw, []string{})
+}
+
+// digestFields 将请求体中未加密的 EncryptedField 替换为其明文的 HMAC-SHA256，使请求无需加密即可序列化
+func digestFields(body any, key []byte) (any, error) {
+	out, changed, err := rewriteValues(reflect.ValueOf(body), func(v reflect.Value) (reflect.Value, bool, error) {
+		if v.Type() != encryptedFieldType {
+			return v, false, nil
+		}
+		f := v.Interface().(EncryptedField)
+		if f.sealed != nil {
+			return v, true, nil
+		}
+		plaintext, err := json.Marshal(f.Value)
+		if err != nil {
+			return v, true, fmt.Errorf("encrypted field: %w", err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(plaintext))
+		dec.UseNumber()
+		var tree any
+		if err := dec.Decode(&tree); err != nil {
+			return v, true, fmt.Errorf("encrypted field: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := writeCanonical(&buf, tree); err != nil {
+			return v, true, fmt.Errorf("encrypted field: %w", err)
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write(buf.Bytes())
+		return reflect.ValueOf(EncryptedField{digest: hex.EncodeToString(mac.Sum(nil))}), true, nil
+	})
+	if err != nil || !changed {
+		return body, err
+	}
+	return out.Interface(), nil
+}
+
+// target 返回请求对应的接口路径与请求体
+func (r GenerationRequest) target() (string, any, error) {
+	if r.count() != 1 {
+		return "", nil, fmt.Errorf("docgen: request must set exactly one of Word, WordBatch, Excel, ExcelFill")
+	}
+	switch {
+	case r.Word != nil:
+		return "/api/v1/doc/word", r.Word, nil
+	case r.WordBatch != nil:
+		return "/api/v1/doc/word/batch", r.WordBatch, nil
+	case r.Excel != nil:
+		return "/api/v1/doc/excel", r.Excel, nil
+	default:
+		return "/api/v1/doc/excel/fill", r.ExcelFill, nil
+	}
+}
+
+// removeField 删除对象中 path 指向的字段
+func removeField(v any, path []string) {
+	obj, ok := v.(map[string]any)
+	if !ok || len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(obj, path[0])
+		return
+	}
+	removeField(obj[path[0]], path[1:])
+}
+
+// writeCanonical 以规范形式写出 JSON 值：键排序、无空白、数值规范化、对象中的 null 省略
+func writeCanonical(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k, val := range v {
+			if val != nil {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case json.Number:
+		n, err := canonicalNumber(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(n)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
+
+// canonicalNumber 返回数值的精确规范形式：整数为十进制整数，其他为分数 "p/q"（最简）
+func canonicalNumber(n json.Number) (string, error) {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return "", fmt.Errorf("invalid number %q", n)
+	}
+	if r.IsInt() {
+		return r.Num().String(), nil
+	}
+	return r.String(), nil
+}
@@ -0,0 +1,210 @@
+package docgen_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+func wordHash(t *testing.T, req docgen.WordGenRequest) string {
+	t.Helper()
+	h, err := docgen.RequestHash(docgen.GenerationRequest{Word: &req})
+	if err != nil {
+		t.Fatalf("RequestHash: %v", err)
+	}
+	return h
+}
+
+func TestRequestHashIgnoresMapOrder(t *testing.T) {
+	want := ""
+	for i := 0; i < 50; i++ {
+		data := map[string]any{}
+		for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			data[k] = map[string]any{"x": k, "y": []any{1, k}}
+		}
+		h := wordHash(t, docgen.WordGenRequest{TemplateName: "t.docx", Data: data})
+		if want == "" {
+			want = h
+		} else if h != want {
+			t.Fatalf("hash %d = %s, want %s", i, h, want)
+		}
+	}
+}
+
+func TestRequestHashNormalizesNumbers(t *testing.T) {
+	base := wordHash(t, docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"n": 1, "big": int64(9223372036854775807)}})
+	for _, n := range []any{1.0, json.Number("1.0"), json.Number("1e0"), json.Number("10e-1"), uint8(1)} {
+		h := wordHash(t, docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"n": n, "big": json.Number("9223372036854775807")}})
+		if h != base {
+			t.Errorf("number %v (%T): hash differs", n, n)
+		}
+	}
+	if h := wordHash(t, docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"n": 1, "big": json.Number("9223372036854775806")}}); h == base {
+		t.Error("values differing beyond float64 precision hash the same")
+	}
+	if h := wordHash(t, docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"n": "1", "big": int64(9223372036854775807)}}); h == base {
+		t.Error("string and number hash the same")
+	}
+}
+
+func TestRequestHashExclusions(t *testing.T) {
+	req := docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"a": 1}}
+	base := wordHash(t, req)
+
+	named := req
+	named.FileName = "other"
+	if wordHash(t, named) != base {
+		t.Error("fileName is excluded by default")
+	}
+	nulled := req
+	nulled.Data = map[string]any{"a": 1, "b": nil}
+	if wordHash(t, nulled) != base {
+		t.Error("null field should equal missing field")
+	}
+
+	all, err := docgen.RequestHashWithOptions(docgen.GenerationRequest{Word: &named}, docgen.RequestHashOptions{Exclude: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all == base {
+		t.Error("empty Exclude should hash fileName")
+	}
+	custom, err := docgen.RequestHashWithOptions(docgen.GenerationRequest{Word: &req}, docgen.RequestHashOptions{Exclude: []string{"data.a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := req
+	other.Data = map[string]any{"a": 2}
+	custom2, err := docgen.RequestHashWithOptions(docgen.GenerationRequest{Word: &other}, docgen.RequestHashOptions{Exclude: []string{"data.a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if custom != custom2 {
+		t.Error("nested exclusion data.a not applied")
+	}
+}
+
+func TestRequestHashStructAndRawJSON(t *testing.T) {
+	built := docgen.WordGenRequest{
+		TemplateName: "contract.docx",
+		Data: map[string]any{
+			"amount":  int64(1200),
+			"ratio":   0.5,
+			"parties": []map[string]any{{"name": "甲方", "count": 2}},
+		},
+	}
+	var parsed docgen.WordGenRequest
+	raw := `{"data":{"parties":[{"count":2.0,"name":"甲方"}],"ratio":5e-1,"amount":1200},"templateName":"contract.docx"}`
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := wordHash(t, built), wordHash(t, parsed); a != b {
+		t.Fatalf("struct-built %s != raw-JSON-built %s", a, b)
+	}
+}
+
+func TestRequestHashEncryptedField(t *testing.T) {
+	a := wordHash(t, docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"salary": docgen.Encrypt(1000)}})
+	b := wordHash(t, docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"salary": docgen.Encrypt(1000.0)}})
+	c := wordHash(t, docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"salary": docgen.Encrypt(2000)}})
+	if a != b {
+		t.Error("equal plaintexts hash differently")
+	}
+	if a == c {
+		t.Error("different plaintexts hash the same")
+	}
+	keyed, err := docgen.RequestHashWithOptions(docgen.GenerationRequest{Word: &docgen.WordGenRequest{
+		TemplateName: "t.docx", Data: map[string]any{"salary": docgen.Encrypt(1000)},
+	}}, docgen.RequestHashOptions{FieldKey: []byte("secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyed == a {
+		t.Error("FieldKey does not change the digest")
+	}
+}
+
+func TestEnqueueGenerationWithEncryptedField(t *testing.T) {
+	keys := docgen.NewKeyring("k1", make([]byte, 32))
+	srv := docgentest.NewServer(docgentest.WithFieldDecryption(keys.Key))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+
+	store, err := docgen.NewFileOutboxStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := docgen.NewClient(srv.URL, docgen.WithFieldEncryption(keys), docgen.WithOutbox(store, docgen.OutboxOptions{}))
+	defer client.Close()
+
+	out := t.TempDir()
+	req := docgen.GenerationRequest{Word: &docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"salary": docgen.Encrypt(1000)}}}
+	if _, err := client.EnqueueGeneration(req, docgen.DirSink(out)); err != nil {
+		t.Fatalf("EnqueueGeneration: %v", err)
+	}
+	waitDelivered(t, out)
+
+	want, err := docgen.RequestHashWithOptions(req, docgen.RequestHashOptions{Exclude: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := srv.RequestsTo(docgentest.EndpointWord)
+	if len(sent) != 1 {
+		t.Fatalf("got %d requests", len(sent))
+	}
+	if got := sent[0].Header.Get(docgen.IdempotencyKeyHeader); got != want {
+		t.Errorf("Idempotency-Key = %q, want RequestHash %q", got, want)
+	}
+	if strings.Contains(string(sent[0].Body), "1000") {
+		t.Error("plaintext sent to the server")
+	}
+}
+
+func waitDelivered(t *testing.T, dir string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) > 0 {
+			if info, err := os.Stat(files[0]); err == nil && info.Size() > 0 {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("outbox entry not delivered")
+}
+
+func TestHedgedRequestsShareDerivedIdempotencyKey(t *testing.T) {
+	srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, docgentest.Delayed(300*time.Millisecond)))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	client := docgen.NewClient(srv.URL, docgen.WithHedging(20*time.Millisecond, 1))
+
+	req := docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"a": 1}}
+	if _, err := client.GenerateWordWithMeta(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GenerateWordWithMeta(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	sent := srv.RequestsTo(docgentest.EndpointWord)
+	if len(sent) < 3 {
+		t.Fatalf("got %d requests, want a hedge on the first call", len(sent))
+	}
+	key := sent[0].Header.Get(docgen.IdempotencyKeyHeader)
+	if key == "" {
+		t.Fatal("no Idempotency-Key")
+	}
+	for i, r := range sent {
+		if got := r.Header.Get(docgen.IdempotencyKeyHeader); got != key {
+			t.Errorf("request %d key %q, want %q", i, got, key)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package docgen
+
+import (
+	"sync"
+	"time"
+)
+
+// healthCache 健康状态缓存，过期后由第一个调用方发起探测，并发调用方共享同一次探测结果
+type healthCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	state     HealthState
+	checkedAt time.Time
+	valid     bool
+	inflight  *healthProbe
+}
+
+// healthProbe 进行中的健康探测
+type healthProbe struct {
+	done  chan struct{}
+	state HealthState
+}
+
+// get 返回缓存的健康状态，过期或 force 为 true 时探测
+func (h *healthCache) get(force bool, probe func() HealthState) HealthState {
+	h.mu.Lock()
+	if !force && h.valid && time.Since(h.checkedAt) < h.ttl {
+		state := h.state
+		h.mu.Unlock()
+		return state
+	}
+	if p := h.inflight; p != nil {
+		// 已有探测在进行，其结果同样是最新的
+		h.mu.Unlock()
+		<-p.done
+		return p.state
+	}
+	p := &healthProbe{done: make(chan struct{})}
+	h.inflight = p
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.state, h.checkedAt, h.valid = p.state, time.Now(), true
+		h.inflight = nil
+		h.mu.Unlock()
+		close(p.done)
+	}()
+	// probe panic 时等待方得到 HealthUnreachable
+	p.state = HealthUnreachable
+	p.state = probe()
+	return p.state
+}
+
+// cached 返回未过期的缓存结果，不发起探测；未启用或没有可用结果时 ok 为 false
+func (h *healthCache) cached() (state HealthState, ok bool) {
+	if h == nil {
+		return 0, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.valid || time.Since(h.checkedAt) >= h.ttl {
+		return 0, false
+	}
+	return h.state, true
+}
+
+// invalidate 丢弃缓存结果，下次调用重新探测
+func (h *healthCache) invalidate() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	h.valid = false
+	h.mu.Unlock()
+}
+
+// observeUnreachable 其他请求遇到连接错误时立即更新缓存，避免缓存掩盖服务不可达
+func (h *healthCache) observeUnreachable() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	h.state, h.checkedAt, h.valid = HealthUnreachable, time.Now(), true
+	h.mu.Unlock()
+}
+
+// InvalidateHealthCache 丢弃缓存的健康状态，下次 IsHealthy / HealthState 调用重新探测
+//
+// 未启用 WithHealthCache 时无效果
+func (c *Client) InvalidateHealthCache() {
+	c.healthCache.invalidate()
+}
+
+// RefreshHealthState 立即探测健康状态并更新缓存（与进行中的探测合并）
+//
+// 未启用 WithHealthCache 时等同于 HealthState
+func (c *Client) RefreshHealthState() HealthState {
+	if c.healthCache == nil {
+		return c.probeHealthState()
+	}
+	return c.healthCache.get(true, c.probeHealthState)
+}
@@ -0,0 +1,185 @@
+package docgen
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader 幂等键请求头，服务端对相同幂等键的重复请求返回相同结果
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// hedgeConfig WithHedging 的配置
+type hedgeConfig struct {
+	delay     time.Duration
+	maxHedges int
+}
+
+// hedgeOutcome 对冲请求的结果统计
+type hedgeOutcome struct {
+	// hedges 额外发出的请求数
+	hedges int
+	// won 采用的是对冲请求的响应
+	won bool
+}
+
+// idempotencyKeyContextKey 幂等键在 context 中的键
+type idempotencyKeyContextKey struct{}
+
+// hedgeableContextKey 标记可对冲的生成请求
+type hedgeableContextKey struct{}
+
+// WithIdempotencyKey 返回携带幂等键的 context，请求随 Idempotency-Key 头发送
+//
+// 启用 WithHedging 时生成请求未指定幂等键会自动生成；需要与业务重试共用同一个键时使用此函数
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKeyFor 返回 context 中的幂等键
+func idempotencyKeyFor(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// withHedgeable 启用 WithHedging 时将生成请求标记为可对冲
+func (c *Client) withHedgeable(ctx context.Context) context.Context {
+	if c.hedging == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, hedgeableContextKey{}, true)
+}
+
+// hedgeable 判断请求是否可对冲：已标记为生成请求，带有幂等键，且请求体可重放
+func (c *Client) hedgeable(req *http.Request) bool {
+	marked, _ := req.Context().Value(hedgeableContextKey{}).(bool)
+	if c.hedging == nil || !marked || req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if req.Header.Get(IdempotencyKeyHeader) == "" {
+		key, err := bodyIdempotencyKey(req)
+		if err != nil {
+			return false
+		}
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return true
+}
+
+// bodyIdempotencyKey 按 RequestHash 的规则由请求路径与请求体计算幂等键（见 requestIdempotencyKey）
+func bodyIdempotencyKey(req *http.Request) (string, error) {
+	if req.GetBody == nil {
+		return requestIdempotencyKey(req.URL.Path, []byte("null"))
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return requestIdempotencyKey(req.URL.Path, raw)
+}
+
+// hedgeAttempt 一次请求尝试的结果
+type hedgeAttempt[T any] struct {
+	index int
+	value T
+	err   error
+}
+
+// hedge 发送请求，delay 内没有结果时再发出副本（最多 maxHedges 个），采用最先完成的结果并取消其他请求
+//
+// do 发送一次请求，返回错误响应也视为完成；release 释放未采用的结果（如关闭响应体）。
+// 返回的 CancelFunc 取消采用结果的 context，调用方读完响应后调用
+func hedge[T any](c *Client, req *http.Request, do func(*http.Request) (T, error), release func(T)) (T, hedgeOutcome, context.CancelFunc, error) {
+	cfg := c.hedging
+	results := make(chan hedgeAttempt[T], cfg.maxHedges+1)
+	var cancels []context.CancelFunc
+	launch := func() error {
+		ctx, cancel := context.WithCancel(req.Context())
+		attemptReq := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return err
+			}
+			attemptReq.Body = body
+		}
+		i := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			v, err := do(attemptReq)
+			results <- hedgeAttempt[T]{index: i, value: v, err: err}
+		}()
+		return nil
+	}
+
+	var outcome hedgeOutcome
+	var zero T
+	if err := launch(); err != nil {
+		return zero, outcome, nil, err
+	}
+	pending := 1
+	timer := time.NewTimer(cfg.delay)
+	defer timer.Stop()
+
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			if err := launch(); err == nil {
+				outcome.hedges++
+				pending++
+			}
+			if outcome.hedges < cfg.maxHedges {
+				timer.Reset(cfg.delay)
+			}
+		case r := <-results:
+			pending--
+			if r.err != nil {
+				cancels[r.index]()
+				if firstErr == nil {
+					firstErr = r.err
+				}
+				// 传输层错误不触发对冲，仍有请求未完成时等待其结果
+				if pending == 0 {
+					return zero, outcome, nil, firstErr
+				}
+				continue
+			}
+			outcome.won = r.index > 0
+			for i, cancel := range cancels {
+				if i != r.index {
+					cancel()
+				}
+			}
+			// 释放被取消的请求已收到的结果
+			go func(n int) {
+				for ; n > 0; n-- {
+					if loser := <-results; loser.err == nil {
+						release(loser.value)
+					}
+				}
+			}(pending)
+			return r.value, outcome, cancels[r.index], nil
+		}
+	}
+}
+
+// cancelOnClose 关闭响应体时取消对应请求的 context
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close 关闭响应体并取消 context
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
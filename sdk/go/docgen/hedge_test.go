@@ -0,0 +1,205 @@
+package docgen_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// instancesServer 模拟负载均衡后的多个实例：Word 生成请求按到达顺序编号，slow 中的请求落在慢实例上，
+// 阻塞到客户端取消；其余请求立即返回标记了编号的文档。其他接口延迟 otherDelay 后按默认逻辑处理
+type instancesServer struct {
+	*docgentest.Server
+}
+
+func newInstancesServer(t *testing.T, otherDelay time.Duration, slow ...int) *instancesServer {
+	t.Helper()
+	isSlow := make(map[int]bool)
+	last := 0
+	for _, n := range slow {
+		isSlow[n] = true
+		if n+1 > last {
+			last = n + 1
+		}
+	}
+	instances := make([]docgentest.Response, last+1)
+	for n := range instances {
+		if isSlow[n] {
+			instances[n] = docgentest.Delayed(5 * time.Second)
+		} else {
+			instances[n] = docgentest.Document([]byte(fmt.Sprintf("PK instance-%d", n)), "application/octet-stream")
+		}
+	}
+	s := &instancesServer{docgentest.NewServer(
+		docgentest.WithSequence(docgentest.EndpointWord, instances...),
+		docgentest.WithLatency(docgentest.EndpointWord, 0),
+		docgentest.WithLatency(docgentest.AnyEndpoint, otherDelay),
+	)}
+	t.Cleanup(s.Close)
+	s.AddTemplate("t.docx", docgentest.MinimalDocx("{{a}}"))
+	return s
+}
+
+// waitCanceled 等待慢实例上的请求被取消，want 为请求编号，按升序排列
+func (s *instancesServer) waitCanceled(t *testing.T, want ...int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		number := make(map[*docgentest.CapturedRequest]int)
+		for n, r := range s.RequestsTo(docgentest.EndpointWord) {
+			number[r] = n
+		}
+		var canceled []int
+		for _, r := range s.CanceledRequests() {
+			canceled = append(canceled, number[r])
+		}
+		sort.Ints(canceled)
+		got := fmt.Sprint(canceled)
+		if got == fmt.Sprint(want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("canceled requests = %s, want %v", got, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// metricsRecorder 收集 WithMetricsHook 的调用
+type metricsRecorder struct {
+	mu  sync.Mutex
+	all []docgen.RequestMetrics
+}
+
+func (m *metricsRecorder) record(rm docgen.RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.all = append(m.all, rm)
+}
+
+func (m *metricsRecorder) last(t *testing.T) docgen.RequestMetrics {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.all) == 0 {
+		t.Fatal("metrics hook not called")
+	}
+	return m.all[len(m.all)-1]
+}
+
+func TestHedgingAroundSlowInstance(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxHedges int
+		slow      []int
+		// winner 采用的响应来自的实例
+		winner    int
+		wantSent  int
+		hedgeWon  bool
+		wantHedge int
+	}{
+		{"fast primary", 1, nil, 0, 1, false, 0},
+		{"slow primary", 1, []int{0}, 1, 2, true, 1},
+		{"first hedge also slow", 2, []int{0, 1}, 2, 3, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newInstancesServer(t, 0, tt.slow...)
+			var metrics metricsRecorder
+			client := docgen.NewClient(srv.URL, docgen.WithHedging(20*time.Millisecond, tt.maxHedges), docgen.WithMetricsHook(metrics.record))
+
+			start := time.Now()
+			result, err := client.GenerateWordWithMeta(context.Background(), docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"a": 1}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("took %v, the slow instance was not hedged", elapsed)
+			}
+			if want := fmt.Sprintf("PK instance-%d", tt.winner); string(result.Data) != want {
+				t.Errorf("result %q, want %q", result.Data, want)
+			}
+
+			sent := srv.RequestsTo(docgentest.EndpointWord)
+			if len(sent) != tt.wantSent {
+				t.Fatalf("sent %d requests, want %d", len(sent), tt.wantSent)
+			}
+			// 所有副本携带相同的自动生成的幂等键
+			key := sent[0].Header.Get(docgen.IdempotencyKeyHeader)
+			if key == "" {
+				t.Error("no Idempotency-Key on a hedgeable request")
+			}
+			for i, r := range sent[1:] {
+				if got := r.Header.Get(docgen.IdempotencyKeyHeader); got != key {
+					t.Errorf("hedge %d key %q, want %q", i+1, got, key)
+				}
+			}
+
+			m := metrics.last(t)
+			if m.Hedges != tt.wantHedge || m.HedgeWon != tt.hedgeWon {
+				t.Errorf("metrics Hedges=%d HedgeWon=%v, want %d %v", m.Hedges, m.HedgeWon, tt.wantHedge, tt.hedgeWon)
+			}
+			// 落后的请求通过 context 取消
+			srv.waitCanceled(t, tt.slow...)
+		})
+	}
+}
+
+func TestHedgingKeepsCallerIdempotencyKey(t *testing.T) {
+	srv := newInstancesServer(t, 0, 0)
+	client := docgen.NewClient(srv.URL, docgen.WithHedging(20*time.Millisecond, 1))
+
+	ctx := docgen.WithIdempotencyKey(context.Background(), "order-42")
+	if _, err := client.GenerateWordWithMeta(ctx, docgen.WordGenRequest{TemplateName: "t.docx"}); err != nil {
+		t.Fatal(err)
+	}
+	sent := srv.RequestsTo(docgentest.EndpointWord)
+	if len(sent) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(sent))
+	}
+	for i, r := range sent {
+		if got := r.Header.Get(docgen.IdempotencyKeyHeader); got != "order-42" {
+			t.Errorf("request %d key %q, want order-42", i, got)
+		}
+	}
+}
+
+func TestHedgingSkipsUploadsAndDeletes(t *testing.T) {
+	// 非生成接口比对冲延迟慢得多，仍然只发送一次
+	srv := newInstancesServer(t, 100*time.Millisecond)
+	var metrics metricsRecorder
+	client := docgen.NewClient(srv.URL, docgen.WithHedging(10*time.Millisecond, 3), docgen.WithMetricsHook(metrics.record))
+
+	if _, err := client.UploadTemplateFromBytes(docgentest.MinimalDocx("hello"), "t.docx"); err != nil {
+		t.Fatal(err)
+	}
+	if m := metrics.last(t); m.Hedges != 0 {
+		t.Errorf("upload metrics Hedges = %d, want 0", m.Hedges)
+	}
+	if _, err := client.DeleteTemplate("t.docx"); err != nil {
+		t.Fatal(err)
+	}
+	if m := metrics.last(t); m.Hedges != 0 {
+		t.Errorf("delete metrics Hedges = %d, want 0", m.Hedges)
+	}
+
+	counts := make(map[string]int)
+	for _, r := range srv.Requests() {
+		counts[r.Method+" "+r.Path]++
+		if r.Method != http.MethodGet && r.Header.Get(docgen.IdempotencyKeyHeader) != "" {
+			t.Errorf("%s %s carries an Idempotency-Key", r.Method, r.Path)
+		}
+	}
+	for endpoint, n := range counts {
+		if n != 1 {
+			t.Errorf("%s sent %d times, want 1", endpoint, n)
+		}
+	}
+}
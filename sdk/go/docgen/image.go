@@ -0,0 +1,141 @@
+package docgen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImageURLMarker 图片 URL 序列化后的标记键，服务端据此下载图片并嵌入单元格或文档
+const ImageURLMarker = "$image"
+
+// 图片下载统计响应头
+const (
+	// ImagesFetchedHeader 成功下载并嵌入的图片数
+	ImagesFetchedHeader = "X-Images-Fetched"
+	// ImagesFailedHeader 下载失败的图片数
+	ImagesFailedHeader = "X-Images-Failed"
+)
+
+// ErrInvalidImageURL ImageURL 的地址不合法或不在 AllowedHosts 中，请求在序列化阶段失败
+var ErrInvalidImageURL = errors.New("docgen: invalid image url")
+
+// ImageFailurePolicy 单张图片下载失败时的处理方式
+type ImageFailurePolicy string
+
+const (
+	// ImageFailPlaceholder 嵌入占位图片（默认）
+	ImageFailPlaceholder ImageFailurePolicy = "placeholder"
+	// ImageFailWarn 留空并在渲染警告中报告（Code 为 WarningImageFetchFailed）
+	ImageFailWarn ImageFailurePolicy = "warn"
+	// ImageFailError 整个生成请求失败（错误码 CodeImageFetchFailed）
+	ImageFailError ImageFailurePolicy = "error"
+)
+
+// ImageFetchOptions 服务端下载图片的选项
+type ImageFetchOptions struct {
+	// Timeout 单张图片的下载超时（可选，默认由服务端决定），按毫秒发送
+	Timeout time.Duration
+	// MaxBytes 单张图片的大小上限（可选，默认由服务端决定）
+	MaxBytes int64
+	// AllowedHosts 允许的主机名（可选），如 "img.example.com"、"*.cdn.example.com"；
+	// 设置后 SDK 在发送前拒绝其他主机的地址，服务端同样按此校验
+	AllowedHosts []string
+	// OnError 下载失败时的处理方式（可选，默认 ImageFailPlaceholder）
+	OnError ImageFailurePolicy
+}
+
+// ImageValue 由服务端下载并嵌入的图片，由 ImageURL 创建
+//
+// 可放入 ExcelGenRequest.Data 的单元格、ExcelFillRequest.ListData 的行以及 WordGenRequest.Data 中
+type ImageValue struct {
+	// URL 图片地址
+	URL string
+	// Options 下载选项
+	Options ImageFetchOptions
+}
+
+// ImageURL 创建由服务端下载并嵌入的图片值，避免在请求中以 base64 传输图片内容
+//
+// 地址需为 http 或 https 的绝对地址；不合法时请求在序列化阶段返回 ErrInvalidImageURL
+func ImageURL(url string, opts ImageFetchOptions) ImageValue {
+	return ImageValue{URL: url, Options: opts}
+}
+
+// imageSpec ImageValue 的线上格式
+type imageSpec struct {
+	URL          string             `json:"url"`
+	TimeoutMs    int64              `json:"timeoutMs,omitempty"`
+	MaxBytes     int64              `json:"maxBytes,omitempty"`
+	AllowedHosts []string           `json:"allowedHosts,omitempty"`
+	OnError      ImageFailurePolicy `json:"onError,omitempty"`
+}
+
+// MarshalJSON 实现 json.Marshaler：校验地址后输出 {"$image": {...}}
+func (v ImageValue) MarshalJSON() ([]byte, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	spec := imageSpec{
+		URL:          v.URL,
+		TimeoutMs:    v.Options.Timeout.Milliseconds(),
+		MaxBytes:     v.Options.MaxBytes,
+		AllowedHosts: v.Options.AllowedHosts,
+		OnError:      v.Options.OnError,
+	}
+	return json.Marshal(map[string]imageSpec{ImageURLMarker: spec})
+}
+
+// Validate 校验图片地址与选项
+func (v ImageValue) Validate() error {
+	u, err := url.Parse(v.URL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidImageURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%w: %q: must be an absolute http(s) url", ErrInvalidImageURL, v.URL)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: %q: must not contain credentials", ErrInvalidImageURL, v.URL)
+	}
+	if len(v.Options.AllowedHosts) > 0 && !hostAllowed(u.Hostname(), v.Options.AllowedHosts) {
+		return fmt.Errorf("%w: host %q is not in AllowedHosts", ErrInvalidImageURL, u.Hostname())
+	}
+	if v.Options.Timeout < 0 || v.Options.MaxBytes < 0 {
+		return fmt.Errorf("%w: negative Timeout or MaxBytes", ErrInvalidImageURL)
+	}
+	switch v.Options.OnError {
+	case "", ImageFailPlaceholder, ImageFailWarn, ImageFailError:
+	default:
+		return fmt.Errorf("%w: unknown OnError policy %q", ErrInvalidImageURL, v.Options.OnError)
+	}
+	return nil
+}
+
+// hostAllowed 判断主机名是否匹配允许列表，"*.example.com" 匹配其任意子域名（不含 example.com 本身）
+func hostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// imageCounts 从响应头读取图片下载统计
+func imageCounts(h http.Header) (fetched, failed int64) {
+	fetched, _ = strconv.ParseInt(h.Get(ImagesFetchedHeader), 10, 64)
+	failed, _ = strconv.ParseInt(h.Get(ImagesFailedHeader), 10, 64)
+	return fetched, failed
+}
@@ -0,0 +1,123 @@
+package docgen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+// DefaultInlineTemplateTTL 内联模板（InlineTemplate）未设置 TTL 时临时模板的过期时间
+const DefaultInlineTemplateTTL = 10 * time.Minute
+
+// inlineCleanupTimeout WithAutoCleanup 删除临时模板的超时，不受调用 context 取消的影响
+const inlineCleanupTimeout = 30 * time.Second
+
+// WarningTemplateCleanupFailed WithAutoCleanup 删除内联模板失败（由客户端报告），Placeholder 为模板名称；
+// 模板仍会在过期时间后由服务端删除
+const WarningTemplateCleanupFailed = "TEMPLATE_CLEANUP_FAILED"
+
+// InlineTemplate 随调用提供的模板内容，无需预先上传
+type InlineTemplate struct {
+	// Name 模板文件名（需包含扩展名），上传时加随机前缀，不会与已有模板重名
+	Name string
+	// Data 模板内容
+	Data []byte
+	// TTL 临时模板的过期时间（可选，默认 DefaultInlineTemplateTTL）
+	TTL time.Duration
+}
+
+// GenerateWordInline 以调用方提供的模板内容生成 Word 文档，req.TemplateName 被忽略
+//
+// 模板作为临时模板上传（见 UploadTemporaryTemplate），生成后由服务端在 TTL 后删除；
+// 启用 WithAutoCleanup 时在返回前删除，生成失败时同样删除。Meta.TemplateName 为上传后的模板名称
+func (c *Client) GenerateWordInline(ctx context.Context, tmpl InlineTemplate, req WordGenRequest) (*DocumentResult, error) {
+	return c.withInlineTemplate(ctx, tmpl, func(name string) (*DocumentResult, error) {
+		req.TemplateName = name
+		return c.GenerateWordWithMeta(ctx, req)
+	})
+}
+
+// FillExcelTemplateInline 以调用方提供的模板内容填充 Excel 模板，行为与 GenerateWordInline 一致
+func (c *Client) FillExcelTemplateInline(ctx context.Context, tmpl InlineTemplate, req ExcelFillRequest) (*DocumentResult, error) {
+	return c.withInlineTemplate(ctx, tmpl, func(name string) (*DocumentResult, error) {
+		req.TemplateName = name
+		return c.FillExcelTemplateWithMeta(ctx, req)
+	})
+}
+
+// withInlineTemplate 上传临时模板并以其名称调用 generate，启用 WithAutoCleanup 时随后删除
+func (c *Client) withInlineTemplate(ctx context.Context, tmpl InlineTemplate, generate func(name string) (*DocumentResult, error)) (*DocumentResult, error) {
+	ttl := tmpl.TTL
+	if ttl <= 0 {
+		ttl = DefaultInlineTemplateTTL
+	}
+	name, err := inlineTemplateName(tmpl.Name)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := c.uploadBytes(ctx, tmpl.Data, name, UploadOptions{TTL: ttl})
+	if err != nil {
+		return nil, err
+	}
+	if c.autoCleanup {
+		defer c.cleanupInlineTemplate(ctx, uploaded.FileName)
+	}
+
+	result, err := generate(uploaded.FileName)
+	if err != nil {
+		return nil, err
+	}
+	result.Meta.TemplateName = uploaded.FileName
+	return result, nil
+}
+
+// cleanupInlineTemplate 删除内联模板；调用 context 已取消时仍会删除，失败时报告 WarningTemplateCleanupFailed
+func (c *Client) cleanupInlineTemplate(ctx context.Context, name string) {
+	ctx, cancel := context.WithTimeout(detachedContext{ctx}, inlineCleanupTimeout)
+	defer cancel()
+	resp, err := c.deleteTemplateContext(ctx, name)
+	if err == nil && !resp.Success {
+		err = fmt.Errorf("%s", resp.Message)
+	}
+	if err != nil && c.warningHandler != nil {
+		c.warningHandler(http.MethodDelete+" /api/v1/template", Warning{
+			Code: WarningTemplateCleanupFailed, Placeholder: name, Message: err.Error(),
+		})
+	}
+}
+
+// inlineTemplateName 为内联模板生成不与已有模板重名的名称，如 "inline-3f2a9c1e-contract.docx"
+func inlineTemplateName(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("docgen: inline template needs a name with an extension")
+	}
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate inline template name: %w", err)
+	}
+	return "inline-" + hex.EncodeToString(b) + "-" + filepath.Base(name), nil
+}
+
+// detachedContext 保留父 context 的值（租户、审计信息等），但不随其取消或超时
+type detachedContext struct {
+	context.Context
+}
+
+// Deadline 没有截止时间
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done 永不关闭
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err 总是 nil
+func (detachedContext) Err() error {
+	return nil
+}
@@ -0,0 +1,283 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// JobState 异步任务状态
+type JobState string
+
+const (
+	// JobQueued 已提交，等待执行
+	JobQueued JobState = "QUEUED"
+	// JobRunning 执行中
+	JobRunning JobState = "RUNNING"
+	// JobSucceeded 已成功完成，结果可下载
+	JobSucceeded JobState = "SUCCEEDED"
+	// JobFailed 执行失败，失败原因见 Job.Error
+	JobFailed JobState = "FAILED"
+	// JobCancelled 已取消
+	JobCancelled JobState = "CANCELLED"
+)
+
+// Terminal 是否为终止状态（成功、失败或已取消）
+func (s JobState) Terminal() bool {
+	switch s {
+	case JobSucceeded, JobFailed, JobCancelled:
+		return true
+	}
+	return false
+}
+
+// 异步任务的来源请求类型（Job.RequestType）
+const (
+	JobTypeWord      = "WORD"
+	JobTypeWordBatch = "WORD_BATCH"
+	JobTypeExcel     = "EXCEL"
+	JobTypeExcelFill = "EXCEL_FILL"
+)
+
+// JobProgress 任务进度
+type JobProgress struct {
+	// Done 已完成的条目数
+	Done int64 `json:"done"`
+	// Total 条目总数，未知时为 0
+	Total int64 `json:"total"`
+}
+
+// Job 异步生成任务
+type Job struct {
+	// ID 任务 ID
+	ID string `json:"id"`
+	// State 任务状态
+	State JobState `json:"state"`
+	// RequestType 来源请求类型，如 JobTypeWordBatch
+	RequestType string `json:"requestType"`
+	// TemplateName 使用的模板文件名（动态生成 Excel 时为空）
+	TemplateName string `json:"templateName,omitempty"`
+	// Priority 队列优先级
+	Priority Priority `json:"priority,omitempty"`
+	// CreatedAt 提交时间
+	CreatedAt time.Time `json:"createdAt"`
+	// StartedAt 开始执行时间，未开始时为 nil
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	// FinishedAt 结束时间，未结束时为 nil
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// Progress 执行进度
+	Progress JobProgress `json:"progress"`
+	// Error 失败原因，仅 State 为 JobFailed 时非空
+	Error *ErrorResponse `json:"error,omitempty"`
+}
+
+// WordJobRequest 异步 Word 生成请求，DataList 只有一条数据时生成单页文档
+type WordJobRequest struct {
+	WordBatchRequest
+	// Callback 任务结束时的回调配置（可选）
+	Callback *Callback `json:"callback,omitempty"`
+}
+
+// ExcelJobRequest 异步 Excel 模板填充请求
+type ExcelJobRequest struct {
+	ExcelFillRequest
+	// Priority 服务端队列优先级（可选，默认使用 WithDefaultPriority 设置的值）
+	Priority Priority `json:"priority,omitempty"`
+	// Callback 任务结束时的回调配置（可选）
+	Callback *Callback `json:"callback,omitempty"`
+}
+
+// JobFilter 任务列表过滤条件，零值字段不参与过滤
+type JobFilter struct {
+	// States 任务状态，多个状态为"或"关系
+	States []JobState
+	// TemplateName 模板文件名
+	TemplateName string
+	// CreatedAfter 仅返回在该时间之后提交的任务
+	CreatedAfter time.Time
+	// CreatedBefore 仅返回在该时间之前提交的任务
+	CreatedBefore time.Time
+	// Limit 最多返回的任务数，0 表示使用服务端默认值
+	Limit int
+}
+
+// ListJobsResponse 任务列表响应
+type ListJobsResponse struct {
+	Success bool  `json:"success"`
+	Count   int64 `json:"count"`
+	Jobs    []Job `json:"jobs"`
+}
+
+// ErrJobFinished 任务已结束，无法取消
+var ErrJobFinished = errors.New("docgen: job already finished")
+
+// JobFinishedError 取消已结束任务时返回的错误
+//
+// errors.Is(err, ErrJobFinished) 返回 true。该错误不表示操作失败，
+// 管理工具批量取消任务时通常可以忽略
+type JobFinishedError struct {
+	// JobID 任务 ID
+	JobID string
+	// Err 服务端返回的原始错误
+	Err *ErrorResponse
+}
+
+// Error 实现 error 接口
+func (e *JobFinishedError) Error() string {
+	return fmt.Sprintf("job %s already finished: %s", e.JobID, e.Err.Message)
+}
+
+// Unwrap 返回服务端原始错误
+func (e *JobFinishedError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrJobFinished) 成立
+func (e *JobFinishedError) Is(target error) bool {
+	return target == ErrJobFinished
+}
+
+// SubmitWordJob 提交异步 Word 生成任务
+//
+// 返回已排队的任务，可通过 JobStatus 查询进度，完成后通过 DownloadJobResult 下载结果
+func (c *Client) SubmitWordJob(req WordJobRequest) (*Job, error) {
+	if err := c.prepareWordBatch(context.Background(), &req.WordBatchRequest); err != nil {
+		return nil, err
+	}
+	return c.submitJob("/api/v1/jobs/word", req)
+}
+
+// SubmitExcelJob 提交异步 Excel 模板填充任务
+func (c *Client) SubmitExcelJob(req ExcelJobRequest) (*Job, error) {
+	if err := c.prepareFill(context.Background(), &req.ExcelFillRequest); err != nil {
+		return nil, err
+	}
+	priority, err := c.resolvePriority(req.Priority)
+	if err != nil {
+		return nil, err
+	}
+	req.Priority = priority
+	return c.submitJob("/api/v1/jobs/excel", req)
+}
+
+// JobStatus 查询任务状态
+//
+// jobID: 任务 ID
+func (c *Client) JobStatus(jobID string) (*Job, error) {
+	req, err := c.newRequest(context.Background(), http.MethodGet, jobPath(jobID, ""), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Job
+	if err := c.executeJSON(req, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ListJobs 按条件列出任务
+//
+// filter: 过滤条件，零值表示列出全部任务
+func (c *Client) ListJobs(filter JobFilter) ([]Job, error) {
+	query := url.Values{}
+	for _, state := range filter.States {
+		query.Add("state", string(state))
+	}
+	if filter.TemplateName != "" {
+		query.Set("template", c.qualifyTemplate(context.Background(), filter.TemplateName))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query.Set("createdAfter", filter.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query.Set("createdBefore", filter.CreatedBefore.UTC().Format(time.RFC3339))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	path := "/api/v1/jobs"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := c.newRequest(context.Background(), http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ListJobsResponse
+	if err := c.executeJSON(req, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Jobs, nil
+}
+
+// CancelJob 取消排队中或执行中的任务
+//
+// jobID: 任务 ID
+//
+// 任务已结束时返回 *JobFinishedError（errors.Is(err, ErrJobFinished) 为 true）
+func (c *Client) CancelJob(jobID string) error {
+	req, err := c.newRequest(context.Background(), http.MethodPost, jobPath(jobID, "/cancel"), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.execute(req)
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && errResp.Status == http.StatusConflict {
+		return &JobFinishedError{JobID: jobID, Err: errResp}
+	}
+	return err
+}
+
+// DownloadJobResult 下载已完成任务的生成结果
+//
+// jobID: 任务 ID
+func (c *Client) DownloadJobResult(jobID string) ([]byte, error) {
+	req, err := c.newRequest(context.Background(), http.MethodGet, jobPath(jobID, "/result"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/octet-stream")
+	return c.execute(req)
+}
+
+// DeleteJobResult 删除任务及其生成结果，用于清理已下载或不再需要的任务
+//
+// jobID: 任务 ID
+func (c *Client) DeleteJobResult(jobID string) error {
+	req, err := c.newRequest(context.Background(), http.MethodDelete, jobPath(jobID, ""), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.execute(req)
+	return err
+}
+
+// submitJob 提交异步任务
+func (c *Client) submitJob(path string, reqBody any) (*Job, error) {
+	if err := c.requireFeature(context.Background(), FeatureAsyncJobs); err != nil {
+		return nil, err
+	}
+	var result Job
+	if err := c.doJSON(context.Background(), http.MethodPost, path, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// jobPath 构建任务接口路径，suffix 如 "/cancel"
+func jobPath(jobID, suffix string) string {
+	return "/api/v1/jobs/" + url.PathEscape(jobID) + suffix
+}
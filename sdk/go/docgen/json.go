@@ -0,0 +1,87 @@
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// decodeJSON 解析 JSON 响应
+//
+// 使用 UseNumber 解码，未声明具体类型的数值以 json.Number 保存，避免超过 2^53 的整数丢失精度
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// Extra 扩展字段集合（如健康检查组件详情）
+//
+// 数值统一以 json.Number 保存，请使用 Int64 / Float64 等访问方法读取，
+// 不要直接断言为 float64
+type Extra map[string]any
+
+// UnmarshalJSON 实现 json.Unmarshaler，无论调用方使用何种解码器均保留整数精度
+func (e *Extra) UnmarshalJSON(data []byte) error {
+	var m map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+	*e = m
+	return nil
+}
+
+// Int64 读取整数字段，字段不存在或不是整数时返回 false
+func (e Extra) Int64(key string) (int64, bool) {
+	switch v := e[key].(type) {
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// Float64 读取浮点数字段，字段不存在或不是数值时返回 false
+func (e Extra) Float64(key string) (float64, bool) {
+	switch v := e[key].(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// String 读取字符串字段，字段不存在或不是字符串时返回 false
+func (e Extra) String(key string) (string, bool) {
+	v, ok := e[key].(string)
+	return v, ok
+}
+
+// Bool 读取布尔字段，字段不存在或不是布尔值时返回 false
+func (e Extra) Bool(key string) (bool, bool) {
+	v, ok := e[key].(bool)
+	return v, ok
+}
+
+// Map 读取嵌套对象字段，字段不存在或不是对象时返回 false
+func (e Extra) Map(key string) (Extra, bool) {
+	v, ok := e[key].(map[string]any)
+	return Extra(v), ok
+}
@@ -0,0 +1,111 @@
+package docgen_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// jsonServer 按路径返回固定的 JSON 响应体
+func jsonServer(t *testing.T, bodies map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestInt64BoundariesSurviveDecoding(t *testing.T) {
+	srv := jsonServer(t, map[string]string{
+		"/api/v1/template/info": `{"success":true,"count":9223372036854775807,"templates":[
+			{"name":"max.docx","size":9223372036854775807,"lastModified":"2024-01-02T03:04:05Z"},
+			{"name":"above-2^53.docx","size":9007199254740993,"lastModified":"2024-01-02T03:04:05Z"}]}`,
+		"/api/v1/jobs/job-1": `{"id":"job-1","state":"running","requestType":"word-batch","createdAt":"2024-01-02T03:04:05Z",
+			"progress":{"done":9223372036854775806,"total":9223372036854775807}}`,
+		"/actuator/health": `{"status":"UP","components":{"diskSpace":{"status":"UP",
+			"details":{"total":9223372036854775807,"free":-9223372036854775808,"threshold":9007199254740993,"ratio":0.25}}}}`,
+	})
+	client := docgen.NewClient(srv.URL)
+
+	infos, err := client.ListTemplateInfos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 || infos[0].Size != math.MaxInt64 || infos[1].Size != 9007199254740993 {
+		t.Errorf("ListTemplateInfos = %+v", infos)
+	}
+
+	job, err := client.JobStatus("job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Progress.Done != math.MaxInt64-1 || job.Progress.Total != math.MaxInt64 {
+		t.Errorf("JobStatus progress = %+v", job.Progress)
+	}
+
+	health, err := client.Health()
+	if err != nil {
+		t.Fatal(err)
+	}
+	details := health.Components["diskSpace"].Details
+	for key, want := range map[string]int64{"total": math.MaxInt64, "free": math.MinInt64, "threshold": 9007199254740993} {
+		if got, ok := details.Int64(key); !ok || got != want {
+			t.Errorf("Details.Int64(%q) = %d, %v; want %d", key, got, ok, want)
+		}
+	}
+	if _, ok := details.Int64("ratio"); ok {
+		t.Error("Int64 accepted a fractional value")
+	}
+	if got, ok := details.Float64("ratio"); !ok || got != 0.25 {
+		t.Errorf("Details.Float64(ratio) = %v, %v", got, ok)
+	}
+}
+
+func TestExtraKeepsPrecisionWithPlainUnmarshal(t *testing.T) {
+	var component docgen.HealthComponent
+	if err := json.Unmarshal([]byte(`{"status":"UP","details":{"n":9223372036854775807}}`), &component); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := component.Details.Int64("n"); !ok || got != math.MaxInt64 {
+		t.Errorf("Int64 = %d, %v", got, ok)
+	}
+	// 重新序列化后数值不变
+	raw, err := json.Marshal(component.Details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"n":9223372036854775807}` {
+		t.Errorf("re-encoded details = %s", raw)
+	}
+}
+
+// TestImageCountsInt64 X-Images-Fetched / X-Images-Failed 响应头按 int64 解析
+func TestImageCountsInt64(t *testing.T) {
+	resp := docgentest.Document(docgentest.MinimalDocx("x"), docgen.FormatDocx.ContentType())
+	resp.Header.Set(docgen.ImagesFetchedHeader, "9223372036854775807")
+	resp.Header.Set(docgen.ImagesFailedHeader, "4294967296")
+	srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, resp))
+	defer srv.Close()
+	client := docgen.NewClient(srv.URL)
+
+	doc, err := client.GenerateWordWithMeta(context.Background(), wordReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Meta.ImagesFetched != math.MaxInt64 || doc.Meta.ImagesFailed != 1<<32 {
+		t.Errorf("ImagesFetched, ImagesFailed = %d, %d", doc.Meta.ImagesFetched, doc.Meta.ImagesFailed)
+	}
+}
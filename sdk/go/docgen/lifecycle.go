@@ -0,0 +1,172 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// ErrClientClosed 客户端已调用 Close，不再接受新请求
+var ErrClientClosed = errors.New("docgen: client closed")
+
+// UploadClosePolicy Close 时对进行中的分片上传会话的处理方式
+type UploadClosePolicy int
+
+const (
+	// UploadSessionsPersist 停止上传并保留本地清单与服务端会话，下次 UploadTemplateLarge 从断点继续（默认）
+	UploadSessionsPersist UploadClosePolicy = iota
+	// UploadSessionsAbort 停止上传并放弃服务端会话、删除本地清单
+	UploadSessionsAbort
+)
+
+// lifecycle 客户端生命周期：跟踪后台 goroutine 与进行中的上传会话，Close 时统一停止
+type lifecycle struct {
+	mu      sync.Mutex
+	closed  bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+	uploads map[string]uploadManifest
+
+	// ownedClient 客户端自行创建的 HTTPClient，Close 时关闭其空闲连接
+	ownedClient *http.Client
+	// closeUserTransport 对用户替换的 HTTPClient 同样关闭空闲连接
+	closeUserTransport bool
+	// uploadPolicy 进行中的上传会话处理方式
+	uploadPolicy UploadClosePolicy
+}
+
+// shutdownContextKey 标记 Close 内部发出的请求，不受 ErrClientClosed 限制
+type shutdownContextKey struct{}
+
+// newLifecycle 创建生命周期状态
+func newLifecycle(owned *http.Client) *lifecycle {
+	return &lifecycle{
+		done:        make(chan struct{}),
+		uploads:     make(map[string]uploadManifest),
+		ownedClient: owned,
+	}
+}
+
+// Close 关闭客户端，用于服务优雅退出
+//
+// 依次执行：拒绝新请求（返回 ErrClientClosed）；停止进度流等后台 goroutine 并等待其退出；
+// 按 WithUploadClosePolicy 保留或放弃被中断的分片上传会话；关闭客户端自行创建的连接池中的空闲连接
+// （替换过 HTTPClient 时仅在启用 WithCloseUserTransport 后关闭）。
+// 进行中的普通请求不会被中断。重复调用 Close 是安全的，之后的调用直接返回 nil
+func (c *Client) Close() error {
+	l := c.life
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.done)
+	l.mu.Unlock()
+
+	l.wg.Wait()
+	c.healthCache.invalidate()
+
+	var errs []error
+	if l.uploadPolicy == UploadSessionsAbort {
+		ctx := context.WithValue(context.Background(), shutdownContextKey{}, true)
+		for manifestPath, manifest := range l.uploads {
+			err := c.doJSON(ctx, http.MethodDelete, uploadSessionPath(manifest.SessionID, ""), nil, nil)
+			var errResp *ErrorResponse
+			if err == nil || errors.As(err, &errResp) && errResp.Status == http.StatusNotFound {
+				os.Remove(manifestPath)
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	if c.HTTPClient == l.ownedClient || l.closeUserTransport {
+		c.HTTPClient.CloseIdleConnections()
+	}
+	return errors.Join(errs...)
+}
+
+// checkOpen 客户端已关闭时返回 ErrClientClosed
+func (c *Client) checkOpen(ctx context.Context) error {
+	l := c.life
+	if l == nil || ctx.Value(shutdownContextKey{}) != nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClientClosed
+	}
+	return nil
+}
+
+// track 登记一个需要在 Close 时停止的长时间操作
+//
+// 返回的 context 在 ctx 结束或客户端关闭时取消；操作结束后必须调用 done
+func (c *Client) track(ctx context.Context) (context.Context, func(), error) {
+	l := c.life
+	if l == nil {
+		return ctx, func() {}, nil
+	}
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, nil, ErrClientClosed
+	}
+	l.wg.Add(1)
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-l.done:
+			cancel()
+		case <-stop:
+		}
+	}()
+	return ctx, func() {
+		close(stop)
+		cancel()
+		l.wg.Done()
+	}, nil
+}
+
+// closing 客户端是否已开始关闭
+func (c *Client) closing() bool {
+	if c.life == nil {
+		return false
+	}
+	select {
+	case <-c.life.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// registerUpload 登记进行中的上传会话
+func (c *Client) registerUpload(manifestPath string, manifest uploadManifest) {
+	if c.life == nil {
+		return
+	}
+	c.life.mu.Lock()
+	c.life.uploads[manifestPath] = manifest
+	c.life.mu.Unlock()
+}
+
+// unregisterUpload 上传结束（完成或因关闭以外的原因失败）后取消登记
+func (c *Client) unregisterUpload(manifestPath string) {
+	if c.life == nil {
+		return
+	}
+	c.life.mu.Lock()
+	delete(c.life.uploads, manifestPath)
+	c.life.mu.Unlock()
+}
@@ -0,0 +1,149 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// waitGoroutines 等待 goroutine 数回落到 baseline，超时时输出所有 goroutine 的调用栈
+func waitGoroutines(t *testing.T, baseline int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<20)
+			t.Fatalf("%d goroutines after Close, want at most %d:\n%s", runtime.NumGoroutine(), baseline, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCloseStopsBackgroundGoroutines(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	path, _ := largeTemplate(t, 4)
+
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddJob(docgen.Job{ID: "j1", State: docgen.JobRunning}, nil)
+	srv.PublishJobEvent(docgen.JobEvent{Type: docgen.JobEventProgress, JobID: "j1"})
+	// 第 2 个分片请求阻塞，上传在 Close 时仍在进行
+	gate := newUploadGate(t, srv, 2)
+	store, err := docgen.NewFileOutboxStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseline := runtime.NumGoroutine()
+
+	client := docgen.NewClient(gate.URL, docgen.WithOutbox(store, docgen.OutboxOptions{}), docgen.WithHealthCache(time.Minute))
+	client.UploadChunkSize = uploadChunk
+	ctx := context.Background()
+
+	// 进度流：收到第一个事件后保持连接
+	events, err := client.StreamJobProgress(ctx, "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event from the progress stream")
+	}
+
+	// 分片上传：阻塞在第 2 个分片
+	uploadErr := make(chan error, 1)
+	go func() {
+		_, err := client.UploadTemplateLarge(ctx, path)
+		uploadErr <- err
+	}()
+	select {
+	case <-gate.reached:
+	case <-time.After(5 * time.Second):
+		t.Fatal("upload never reached the blocked chunk")
+	}
+	if _, err := client.Health(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Close 返回时后台操作都已结束
+	select {
+	case err := <-uploadErr:
+		if !errors.Is(err, docgen.ErrClientClosed) {
+			t.Errorf("upload err = %v, want ErrClientClosed", err)
+		}
+	default:
+		t.Error("upload still running after Close returned")
+	}
+	for range events {
+	}
+	if got := uploadManifests(t, tmp); len(got) != 1 {
+		t.Errorf("manifests after Close = %v, want the interrupted upload to persist", got)
+	}
+
+	// 重复 Close 是安全的，之后的调用返回 ErrClientClosed
+	if err := client.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if _, err := client.Health(); !errors.Is(err, docgen.ErrClientClosed) {
+		t.Errorf("Health after Close: err = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.StreamJobProgress(ctx, "j1"); !errors.Is(err, docgen.ErrClientClosed) {
+		t.Errorf("StreamJobProgress after Close: err = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.UploadTemplateLarge(ctx, path); !errors.Is(err, docgen.ErrClientClosed) {
+		t.Errorf("UploadTemplateLarge after Close: err = %v, want ErrClientClosed", err)
+	}
+
+	// 关闭空闲连接后，客户端与服务端的连接 goroutine 也应退出
+	waitGoroutines(t, baseline)
+}
+
+// idleCounter 记录 CloseIdleConnections 调用次数的 RoundTripper
+type idleCounter struct {
+	http.RoundTripper
+	closed atomic.Int32
+}
+
+func (c *idleCounter) CloseIdleConnections() {
+	c.closed.Add(1)
+}
+
+func TestCloseLeavesUserTransportOpen(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		name string
+		opts []docgen.Option
+		want int32
+	}{
+		{"default", nil, 0},
+		{"WithCloseUserTransport", []docgen.Option{docgen.WithCloseUserTransport()}, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &idleCounter{RoundTripper: http.DefaultTransport}
+			client := docgen.NewClient(srv.URL, tt.opts...)
+			client.HTTPClient = &http.Client{Transport: transport}
+			if _, err := client.Health(); err != nil {
+				t.Fatal(err)
+			}
+			client.Close()
+			client.Close()
+			if got := transport.closed.Load(); got != tt.want {
+				t.Errorf("CloseIdleConnections calls = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package docgen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// 签名下载链接有效期的取值范围
+const (
+	// MinLinkTTL 最短有效期
+	MinLinkTTL = time.Minute
+	// MaxLinkTTL 最长有效期
+	MaxLinkTTL = 7 * 24 * time.Hour
+)
+
+// 签名下载链接指向的资源类型
+const (
+	linkResourceJob      = "job"
+	linkResourceTemplate = "template"
+)
+
+// SignedLink 限时签名下载链接，浏览器可直接下载而无需经过应用服务转发
+type SignedLink struct {
+	// ID 链接 ID，用于 RevokeDownloadLink
+	ID string `json:"id"`
+	// URL 下载地址（已包含签名）
+	URL string `json:"url"`
+	// ExpiresAt 过期时间
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Expired 链接是否已过期
+func (l *SignedLink) Expired() bool {
+	return !time.Now().Before(l.ExpiresAt)
+}
+
+// ExpiresWithin 链接是否将在 d 内过期，便于调用方提前刷新
+func (l *SignedLink) ExpiresWithin(d time.Duration) bool {
+	return time.Until(l.ExpiresAt) < d
+}
+
+// CreateDownloadLink 为异步任务结果创建限时签名下载链接
+//
+// resultID: 任务 ID
+// ttl: 有效期，取值范围为 [MinLinkTTL, MaxLinkTTL]
+func (c *Client) CreateDownloadLink(resultID string, ttl time.Duration) (*SignedLink, error) {
+	return c.createLink(linkResourceJob, resultID, ttl)
+}
+
+// CreateTemplateDownloadLink 为已存储的模板创建限时签名下载链接
+//
+// templateName: 模板文件名
+// ttl: 有效期，取值范围为 [MinLinkTTL, MaxLinkTTL]
+func (c *Client) CreateTemplateDownloadLink(templateName string, ttl time.Duration) (*SignedLink, error) {
+	return c.createLink(linkResourceTemplate, c.qualifyTemplate(context.Background(), templateName), ttl)
+}
+
+// RevokeDownloadLink 在过期前使签名链接失效
+//
+// linkID: SignedLink.ID
+func (c *Client) RevokeDownloadLink(linkID string) error {
+	return c.doJSON(context.Background(), http.MethodDelete, "/api/v1/links/"+url.PathEscape(linkID), nil, nil)
+}
+
+// createLink 请求服务端签发下载链接
+func (c *Client) createLink(resourceType, resourceID string, ttl time.Duration) (*SignedLink, error) {
+	if ttl < MinLinkTTL || ttl > MaxLinkTTL {
+		return nil, fmt.Errorf("docgen: link ttl %s out of range [%s, %s]", ttl, MinLinkTTL, MaxLinkTTL)
+	}
+	if err := c.requireFeature(context.Background(), FeatureSignedLinks); err != nil {
+		return nil, err
+	}
+
+	req := struct {
+		ResourceType string `json:"resourceType"`
+		ResourceID   string `json:"resourceId"`
+		TTLSeconds   int64  `json:"ttlSeconds"`
+	}{resourceType, resourceID, int64(ttl / time.Second)}
+
+	var link SignedLink
+	if err := c.doJSON(context.Background(), http.MethodPost, "/api/v1/links", req, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,24 @@
+package docgen
+
+import "context"
+
+// localeContextKey 请求级语言在 context 中的键
+type localeContextKey struct{}
+
+// WithRequestLocale 返回指定语言的 context，覆盖 WithLocale 设置的客户端语言
+//
+// 适用于按终端用户语言返回错误信息的服务，如：
+//
+//	ctx := docgen.WithRequestLocale(r.Context(), r.Header.Get("Accept-Language"))
+//	doc, err := client.GenerateWordContext(ctx, req)
+func WithRequestLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// localeFor 返回请求使用的语言：context 中的语言优先，其次为客户端语言
+func (c *Client) localeFor(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeContextKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return c.locale
+}
@@ -0,0 +1,128 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// TestLocaleHeader WithLocale 与 WithRequestLocale 设置的 Accept-Language 随 JSON、二进制与 multipart 接口的请求一起发送，
+// 请求级语言优先；均未设置时不发送
+func TestLocaleHeader(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+
+	endpoints := []struct {
+		name     string
+		endpoint string
+		call     func(ctx context.Context, client *docgen.Client) error
+	}{
+		{"JSON", docgentest.EndpointTemplateInfo, func(ctx context.Context, client *docgen.Client) error {
+			_, err := client.ListTemplateInfosContext(ctx)
+			return err
+		}},
+		{"binary generation", docgentest.EndpointWord, func(ctx context.Context, client *docgen.Client) error {
+			_, err := client.GenerateWordContext(ctx, wordReq)
+			return err
+		}},
+		{"binary download", docgentest.EndpointTemplateDownload, func(ctx context.Context, client *docgen.Client) error {
+			_, err := client.DownloadTemplateContext(ctx, "t.docx")
+			return err
+		}},
+		{"multipart", docgentest.EndpointTemplateUpload, func(ctx context.Context, client *docgen.Client) error {
+			_, err := client.UploadTemplateFromBytesContext(ctx, docgentest.MinimalDocx("x"), "upload.docx", docgen.UploadOptions{Overwrite: true})
+			return err
+		}},
+	}
+	locales := []struct {
+		name    string
+		client  string
+		request string
+		want    string
+	}{
+		{"unset", "", "", ""},
+		{"client", "en-US", "", "en-US"},
+		{"request", "", "en-US", "en-US"},
+		{"request overrides client", "en-US", "zh-CN", "zh-CN"},
+		{"language list", "en-US,en;q=0.9,zh-CN;q=0.8", "", "en-US,en;q=0.9,zh-CN;q=0.8"},
+	}
+	for _, e := range endpoints {
+		for _, l := range locales {
+			t.Run(e.name+"/"+l.name, func(t *testing.T) {
+				var opts []docgen.Option
+				if l.client != "" {
+					opts = append(opts, docgen.WithLocale(l.client))
+				}
+				client := docgen.NewClient(srv.URL, opts...)
+				ctx := context.Background()
+				if l.request != "" {
+					ctx = docgen.WithRequestLocale(ctx, l.request)
+				}
+
+				srv.ResetRequests()
+				if err := e.call(ctx, client); err != nil {
+					t.Fatal(err)
+				}
+				var sent int
+				for _, r := range srv.Requests() {
+					if strings.HasPrefix(r.Path, e.endpoint) {
+						sent++
+					}
+					// 同一调用发出的其他请求（如能力探测）使用相同的语言
+					if got := r.Header.Get("Accept-Language"); got != l.want {
+						t.Errorf("%s %s: Accept-Language = %q, want %q", r.Method, r.Path, got, l.want)
+					}
+					if _, set := r.Header["Accept-Language"]; l.want == "" && set {
+						t.Errorf("%s %s: Accept-Language sent without a locale", r.Method, r.Path)
+					}
+				}
+				if sent == 0 {
+					t.Fatalf("no request to %s", e.endpoint)
+				}
+			})
+		}
+	}
+}
+
+// TestLocaleErrorLanguage 错误响应的 Content-Language 记录在 ErrorResponse.Language，JSON、二进制与 multipart 接口一致
+func TestLocaleErrorLanguage(t *testing.T) {
+	calls := map[string]func(ctx context.Context, client *docgen.Client) error{
+		"JSON": func(ctx context.Context, client *docgen.Client) error {
+			_, err := client.GetResultInfoContext(ctx, "missing")
+			return err
+		},
+		"binary": func(ctx context.Context, client *docgen.Client) error {
+			_, err := client.GenerateWordContext(ctx, docgen.WordGenRequest{TemplateName: "missing.docx"})
+			return err
+		},
+		"multipart": func(ctx context.Context, client *docgen.Client) error {
+			_, err := client.UploadTemplateFromBytesContext(ctx, docgentest.MinimalDocx("x"), "upload.docx", docgen.UploadOptions{})
+			return err
+		},
+	}
+	for name, call := range calls {
+		for _, want := range []string{"zh-CN", "en-US"} {
+			t.Run(name+"/"+want, func(t *testing.T) {
+				// 模拟服务端接受任何非空的上传，multipart 接口的错误由 WithSequence 返回
+				srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointTemplateUpload,
+					docgentest.ErrorResponse(http.StatusBadRequest, docgen.CodeInvalidArgument, "file is required")))
+				defer srv.Close()
+				client := docgen.NewClient(srv.URL, docgen.WithLocale("zh-CN"))
+				err := call(docgen.WithRequestLocale(context.Background(), want), client)
+				var errResp *docgen.ErrorResponse
+				if !errors.As(err, &errResp) {
+					t.Fatalf("err = %v, want an *ErrorResponse", err)
+				}
+				if errResp.Language != want {
+					t.Errorf("Language = %q, want %q", errResp.Language, want)
+				}
+			})
+		}
+	}
+}
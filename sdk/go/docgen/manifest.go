@@ -0,0 +1,546 @@
+package docgen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ManifestVersion 当前支持的清单格式版本
+const ManifestVersion = 1
+
+// ErrInvalidManifest 生成清单格式错误，具体错误类型为 *ManifestError
+var ErrInvalidManifest = errors.New("docgen: invalid manifest")
+
+// Manifest 声明式生成清单：一次运行要生成的全部文档
+//
+// JSON 格式示例：
+//
+//	{
+//	  "version": 1,
+//	  "concurrency": 4,
+//	  "entries": [
+//	    {"name": "contract", "template": "contract.docx", "data": {"file": "contract.json"}, "output": {"path": "out/contract.docx"}},
+//	    {"template": "report.xlsx", "data": {"jsonl": "rows.jsonl", "list": "rows"}, "output": {"url": "https://bucket.example.com/report.xlsx?sig=..."}}
+//	  ]
+//	}
+type Manifest struct {
+	// Version 清单格式版本，必须为 ManifestVersion
+	Version int `json:"version"`
+	// Concurrency 并发生成数，默认 4
+	Concurrency int `json:"concurrency,omitempty"`
+	// Entries 要生成的文档
+	Entries []ManifestEntry `json:"entries"`
+	// BaseDir 解析相对路径（数据文件、输出路径）的目录，LoadManifest 设置为清单所在目录，为空时使用当前目录
+	BaseDir string `json:"-"`
+}
+
+// ManifestEntry 清单中的一个文档
+type ManifestEntry struct {
+	// Name 条目名称（可选），出现在报告中便于定位
+	Name string `json:"name,omitempty"`
+	// Template 模板文件名，.docx 使用 Word 生成，.xlsx 使用 Excel 模板填充
+	Template string `json:"template"`
+	// Data 数据来源，均为空时使用空数据
+	Data ManifestData `json:"data"`
+	// Output 输出位置，必须且只能设置一项
+	Output ManifestOutput `json:"output"`
+	// Options 生成选项
+	Options ManifestOptions `json:"options"`
+}
+
+// ManifestData 条目的数据来源，最多设置 Inline、File、JSONL 中的一项
+type ManifestData struct {
+	// Inline 直接写在清单中的单值数据
+	Inline map[string]any `json:"inline,omitempty"`
+	// File JSON 数据文件，内容为单值数据对象
+	File string `json:"file,omitempty"`
+	// JSONL JSONL 数据文件，每行一条记录：Word 模板以全部记录批量生成一个文档，
+	// Excel 模板将记录作为列表 List 的行
+	JSONL string `json:"jsonl,omitempty"`
+	// List JSONL 记录对应的 Excel 列表名称（{.field} 行循环），仅用于 .xlsx 模板且为必填
+	List string `json:"list,omitempty"`
+}
+
+// ManifestOutput 条目的输出位置，必须且只能设置一项；Path 与 Dir 为调用方给出的路径，不做清理
+type ManifestOutput struct {
+	// Path 输出文件路径，原子写入
+	Path string `json:"path,omitempty"`
+	// Dir 输出目录，文件名取服务端建议的名称（经 SanitizeFileName 清理，WithRawFileNames 时不清理），
+	// 服务端未提供时使用条目名称或 "entry-<下标>"；同一次运行中重名时追加序号，目录中已有的同名文件被覆盖
+	Dir string `json:"dir,omitempty"`
+	// URL 预签名上传地址，以 PUT 上传且不携带客户端的认证头
+	URL string `json:"url,omitempty"`
+	// Writer 写入目标，只能在 Go 代码中设置；并发运行时各条目应使用不同的 Writer
+	Writer io.Writer `json:"-"`
+}
+
+// ManifestOptions 条目的生成选项
+type ManifestOptions struct {
+	// Format 期望的输出格式（docx、docm、xlsx、xlsm），设置时须与模板类型一致（启用宏的模板也可为去掉宏后的格式），并在输出前以 ValidateDocument 校验内容
+	Format Format `json:"format,omitempty"`
+	// Priority 服务端队列优先级，默认使用 WithDefaultPriority 设置的值。
+	// 只有 Word 批量生成（.docx 模板配合 jsonl 数据）的请求携带优先级，其他条目忽略此项
+	Priority Priority `json:"priority,omitempty"`
+}
+
+// ManifestError 清单格式错误
+//
+// errors.Is(err, ErrInvalidManifest) 返回 true
+type ManifestError struct {
+	// Problems 发现的问题，每项以位置开头，如 "entries[2].output: ..." 或 "line 7, column 12: ..."
+	Problems []string
+}
+
+// Error 实现 error 接口
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidManifest, strings.Join(e.Problems, "; "))
+}
+
+// Is 使 errors.Is(err, ErrInvalidManifest) 成立
+func (e *ManifestError) Is(target error) bool {
+	return target == ErrInvalidManifest
+}
+
+// ManifestReport RunManifest 的运行报告，可直接以 JSON 写入审计日志
+type ManifestReport struct {
+	// Version 清单格式版本
+	Version int `json:"version"`
+	// StartedAt 开始时间
+	StartedAt time.Time `json:"startedAt"`
+	// Duration 总耗时
+	Duration time.Duration `json:"duration"`
+	// Succeeded 成功条目数
+	Succeeded int `json:"succeeded"`
+	// Failed 失败条目数
+	Failed int `json:"failed"`
+	// Entries 各条目的结果，顺序与 Manifest.Entries 一致；ctx 取消后未执行的条目 Error 为取消原因
+	Entries []ManifestEntryResult `json:"entries"`
+}
+
+// ManifestEntryResult 单个条目的结果
+type ManifestEntryResult struct {
+	// Index 条目在 Manifest.Entries 中的下标
+	Index int `json:"index"`
+	// Name 条目名称
+	Name string `json:"name,omitempty"`
+	// Template 模板文件名
+	Template string `json:"template"`
+	// Output 输出位置：文件路径（output.dir 时为实际写入的路径）、去掉查询参数（签名）的 URL，或 "writer"
+	Output string `json:"output"`
+	// Size 文档大小（字节）
+	Size int64 `json:"size,omitempty"`
+	// SHA256 文档的 SHA-256（十六进制小写）
+	SHA256 string `json:"sha256,omitempty"`
+	// CorrelationID 生成请求的关联 ID
+	CorrelationID string `json:"correlationId,omitempty"`
+	// Duration 该条目的耗时
+	Duration time.Duration `json:"duration"`
+	// Error 失败原因，成功时为空
+	Error string `json:"error,omitempty"`
+}
+
+// LoadManifest 读取并校验 JSON 清单文件，相对路径以清单所在目录为基准
+func LoadManifest(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := ParseManifest(f)
+	if err != nil {
+		return nil, err
+	}
+	m.BaseDir = filepath.Dir(path)
+	return m, nil
+}
+
+// ParseManifest 解析并校验 JSON 清单，错误为 *ManifestError 并给出行列号或字段路径
+//
+// 未知字段视为错误，以便及早发现拼写错误
+func ParseManifest(r io.Reader) (*Manifest, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	dec.UseNumber()
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		return nil, &ManifestError{Problems: []string{manifestSyntaxProblem(data, err)}}
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// manifestSyntaxProblem 将 JSON 解析错误转换为带行列号的描述
+func manifestSyntaxProblem(data []byte, err error) string {
+	var offset int64 = -1
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+		if typeErr.Field != "" {
+			return fmt.Sprintf("%s: %s: cannot use %s as %s", manifestLocation(data, offset), manifestFieldPath(typeErr.Field), typeErr.Value, typeErr.Type)
+		}
+	default:
+		// 未知字段的错误不带偏移量，以字段名首次出现的位置近似
+		if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			if i := bytes.Index(data, []byte(name)); i >= 0 {
+				offset = int64(i)
+			}
+		}
+	}
+	if offset < 0 {
+		return err.Error()
+	}
+	return manifestLocation(data, offset) + ": " + err.Error()
+}
+
+// manifestFieldPath 将 encoding/json 的字段路径（entries.0.template）转换为 entries[0].template 的形式
+func manifestFieldPath(field string) string {
+	parts := strings.Split(field, ".")
+	var b strings.Builder
+	for i, p := range parts {
+		if _, err := strconv.Atoi(p); err == nil && i > 0 {
+			b.WriteString("[" + p + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// manifestLocation 返回字节偏移对应的行列号
+func manifestLocation(data []byte, offset int64) string {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return fmt.Sprintf("line %d, column %d", line, column)
+}
+
+// Validate 校验清单，返回的 *ManifestError 列出全部问题及其字段路径
+func (m *Manifest) Validate() error {
+	var problems []string
+	add := func(path, format string, args ...any) {
+		problems = append(problems, path+": "+fmt.Sprintf(format, args...))
+	}
+	if m.Version != ManifestVersion {
+		add("version", "unsupported version %d (want %d)", m.Version, ManifestVersion)
+	}
+	if m.Concurrency < 0 {
+		add("concurrency", "must not be negative")
+	}
+	if len(m.Entries) == 0 {
+		add("entries", "must not be empty")
+	}
+	paths := make(map[string]int)
+	for i, e := range m.Entries {
+		at := fmt.Sprintf("entries[%d]", i)
+		format := templateFormat(e.Template)
+		var legacy *LegacyFormatError
+		switch {
+		case e.Template == "":
+			add(at+".template", "is required")
+		case errors.As(legacyFormat(e.Template), &legacy):
+			add(at+".template", "legacy format %s is not supported; save it as .%s and upload again", filepath.Ext(e.Template), legacy.Want)
+		case format == "":
+			add(at+".template", "unsupported extension %q (want .docx, .docm, .xlsx or .xlsm)", filepath.Ext(e.Template))
+		}
+
+		sources := 0
+		for _, set := range []bool{e.Data.Inline != nil, e.Data.File != "", e.Data.JSONL != ""} {
+			if set {
+				sources++
+			}
+		}
+		if sources > 1 {
+			add(at+".data", "set at most one of inline, file and jsonl")
+		}
+		switch {
+		case isSpreadsheet(format) && e.Data.JSONL != "" && e.Data.List == "":
+			add(at+".data.list", "is required with jsonl for an Excel template")
+		case e.Data.List != "" && (!isSpreadsheet(format) || e.Data.JSONL == ""):
+			add(at+".data.list", "only applies to jsonl data for an Excel template")
+		}
+
+		outputs := 0
+		for _, set := range []bool{e.Output.Path != "", e.Output.Dir != "", e.Output.URL != "", e.Output.Writer != nil} {
+			if set {
+				outputs++
+			}
+		}
+		if outputs != 1 {
+			add(at+".output", "set exactly one of path, dir and url")
+		}
+		if e.Output.URL != "" {
+			if u, err := url.Parse(e.Output.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				add(at+".output.url", "must be an absolute http(s) URL")
+			}
+		}
+		if e.Output.Path != "" {
+			key := filepath.Clean(e.Output.Path)
+			if first, dup := paths[key]; dup {
+				add(at+".output.path", "same as entries[%d].output.path", first)
+			} else {
+				paths[key] = i
+			}
+		}
+
+		if f := e.Options.Format; f != "" && format != "" && f != format && f != strippedFormat(format) {
+			add(at+".options.format", "%q does not match template type .%s", f, format)
+		}
+		if !e.Options.Priority.Valid() {
+			add(at+".options.priority", "invalid priority %q", e.Options.Priority)
+		}
+	}
+	if len(problems) > 0 {
+		return &ManifestError{Problems: problems}
+	}
+	return nil
+}
+
+// RunManifest 执行生成清单
+//
+// 条目以 Manifest.Concurrency 的并发数执行，单个条目失败（数据文件错误、生成失败、上传失败）记录在报告中，
+// 不影响其他条目。清单不合法时返回 *ManifestError 且不执行任何条目；
+// ctx 取消时停止启动新条目，返回已完成部分的报告和 ctx.Err()
+func RunManifest(ctx context.Context, c *Client, manifest Manifest) (*ManifestReport, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	concurrency := manifest.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	report := &ManifestReport{
+		Version:   manifest.Version,
+		StartedAt: time.Now(),
+		Entries:   make([]ManifestEntryResult, len(manifest.Entries)),
+	}
+	names := &manifestNames{used: make(map[string]bool)}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, entry := range manifest.Entries {
+		report.Entries[i] = ManifestEntryResult{Index: i, Name: entry.Name, Template: entry.Template, Output: entry.Output.describe()}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			report.Entries[i].Error = ctx.Err().Error()
+			continue
+		}
+		wg.Add(1)
+		go func(result *ManifestEntryResult, entry ManifestEntry) {
+			defer func() { <-sem; wg.Done() }()
+			start := time.Now()
+			err := c.runManifestEntry(ctx, manifest.BaseDir, names, result, entry)
+			result.Duration = time.Since(start)
+			if err != nil {
+				result.Error = err.Error()
+			}
+		}(&report.Entries[i], entry)
+	}
+	wg.Wait()
+
+	for _, e := range report.Entries {
+		if e.Error == "" {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+	report.Duration = time.Since(report.StartedAt)
+	return report, ctx.Err()
+}
+
+// describe 返回报告中的输出位置，URL 去掉可能包含签名的查询参数
+func (o ManifestOutput) describe() string {
+	switch {
+	case o.Path != "":
+		return o.Path
+	case o.Dir != "":
+		return o.Dir
+	case o.URL != "":
+		if u, err := url.Parse(o.URL); err == nil {
+			u.RawQuery, u.Fragment = "", ""
+			return u.String()
+		}
+		return o.URL
+	}
+	return "writer"
+}
+
+// manifestNames 本次运行中 output.dir 已使用的文件路径
+type manifestNames struct {
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+// claim 在目录中为文档选择本次运行未使用的文件名，返回完整路径
+func (n *manifestNames) claim(c *Client, dir, name, fallback string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	taken := func(name string) bool { return n.used[filepath.Join(dir, name)] }
+	safe := c.derivedFileName(name, taken)
+	if safe == "" {
+		safe = c.derivedFileName(fallback, taken)
+	}
+	path := filepath.Join(dir, safe)
+	n.used[path] = true
+	return path
+}
+
+// runManifestEntry 读取数据、生成文档并写入输出，结果写入 report
+func (c *Client) runManifestEntry(ctx context.Context, baseDir string, names *manifestNames, report *ManifestEntryResult, entry ManifestEntry) error {
+	req, err := manifestRequest(baseDir, entry)
+	if err != nil {
+		return err
+	}
+	result, err := c.generateRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+	report.Size, report.SHA256, report.CorrelationID = result.Meta.Size, result.Meta.SHA256, result.Meta.CorrelationID
+	if entry.Options.Format != "" {
+		if err := ValidateDocument(result.Data, entry.Options.Format); err != nil {
+			return err
+		}
+	}
+
+	switch out := entry.Output; {
+	case out.Path != "" || out.Dir != "":
+		path := manifestPath(baseDir, out.Path)
+		if out.Dir != "" {
+			fallback := entry.Name
+			if fallback == "" {
+				fallback = fmt.Sprintf("entry-%d", report.Index)
+			}
+			fallback += "." + string(c.macroOutputFormat(templateFormat(entry.Template)))
+			path = names.claim(c, manifestPath(baseDir, out.Dir), result.Meta.FileName, fallback)
+			report.Output = path
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		err = writeFileAtomic(path, result.Data)
+	case out.URL != "":
+		err = c.putResult(ctx, out.URL, result)
+	default:
+		_, err = out.Writer.Write(result.Data)
+	}
+	if err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+// manifestRequest 根据模板类型与数据来源构造生成请求
+func manifestRequest(baseDir string, entry ManifestEntry) (GenerationRequest, error) {
+	var data map[string]any
+	var rows []map[string]any
+	switch d := entry.Data; {
+	case d.Inline != nil:
+		data = d.Inline
+	case d.File != "":
+		if err := readManifestJSON(manifestPath(baseDir, d.File), &data); err != nil {
+			return GenerationRequest{}, err
+		}
+	case d.JSONL != "":
+		var err error
+		if rows, err = readManifestJSONL(manifestPath(baseDir, d.JSONL)); err != nil {
+			return GenerationRequest{}, err
+		}
+	}
+
+	priority := entry.Options.Priority
+	if isSpreadsheet(templateFormat(entry.Template)) {
+		req := &ExcelFillRequest{TemplateName: entry.Template, Data: data}
+		if rows != nil {
+			req.ListData = map[string][]map[string]any{entry.Data.List: rows}
+		}
+		return GenerationRequest{ExcelFill: req}, nil
+	}
+	if rows != nil {
+		return GenerationRequest{WordBatch: &WordBatchRequest{TemplateName: entry.Template, DataList: rows, Priority: priority}}, nil
+	}
+	return GenerationRequest{Word: &WordGenRequest{TemplateName: entry.Template, Data: data}}, nil
+}
+
+// manifestPath 以清单目录为基准解析相对路径
+func manifestPath(baseDir, path string) string {
+	if baseDir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+// readManifestJSON 读取 JSON 数据文件，数值保留为 json.Number 以避免精度丢失
+func readManifestJSON(path string, v any) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read data: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("parse data %s: %s", path, manifestSyntaxProblem(raw, err))
+	}
+	return nil
+}
+
+// readManifestJSONL 读取 JSONL 数据文件，空行忽略
+func readManifestJSONL(path string) ([]map[string]any, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read data: %w", err)
+	}
+	defer f.Close()
+
+	rows := []map[string]any{}
+	br := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		raw, err := br.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 {
+			var row map[string]any
+			dec := json.NewDecoder(bytes.NewReader(trimmed))
+			dec.UseNumber()
+			if derr := dec.Decode(&row); derr != nil || row == nil {
+				if derr == nil {
+					derr = errors.New("record must be a JSON object")
+				}
+				return nil, fmt.Errorf("parse data %s: line %d: %w", path, line, derr)
+			}
+			rows = append(rows, row)
+		}
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read data %s: line %d: %w", path, line, err)
+		}
+	}
+}
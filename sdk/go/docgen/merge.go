@@ -0,0 +1,131 @@
+package docgen
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// wordPageBreak 合并文档时插入的分页段落
+const wordPageBreak = `<w:p><w:r><w:br w:type="page"/></w:r></w:p>`
+
+// MergeWordDocuments 按顺序合并多个 .docx 文档，文档之间插入分页符
+//
+// 以第一个文档为基础（样式、页眉页脚、页面设置均沿用第一个文档），依次追加其他文档的正文段落与表格。
+// 只合并 word/document.xml 的正文：后续文档中引用的图片、超链接、编号等关系不会复制，
+// 适用于由同类模板生成、正文以文字和表格为主的文档
+func MergeWordDocuments(docs ...[]byte) ([]byte, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("docgen: merge: no documents")
+	}
+	if len(docs) == 1 {
+		return docs[0], nil
+	}
+
+	baseXML, err := readDocxPart(docs[0], "word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("docgen: merge document 0: %w", err)
+	}
+	head, baseBody, tail, err := splitWordBody(baseXML)
+	if err != nil {
+		return nil, fmt.Errorf("docgen: merge document 0: %w", err)
+	}
+
+	var merged strings.Builder
+	merged.WriteString(head)
+	merged.WriteString(baseBody)
+	for i, doc := range docs[1:] {
+		xml, err := readDocxPart(doc, "word/document.xml")
+		if err != nil {
+			return nil, fmt.Errorf("docgen: merge document %d: %w", i+1, err)
+		}
+		_, body, _, err := splitWordBody(xml)
+		if err != nil {
+			return nil, fmt.Errorf("docgen: merge document %d: %w", i+1, err)
+		}
+		merged.WriteString(wordPageBreak)
+		merged.WriteString(body)
+	}
+	merged.WriteString(tail)
+
+	return replaceDocxPart(docs[0], "word/document.xml", []byte(merged.String()))
+}
+
+// splitWordBody 将 document.xml 拆分为 正文之前（含 <w:body>）、正文内容、正文之后（含节属性与 </w:body>）
+func splitWordBody(doc string) (head, body, tail string, err error) {
+	open := strings.Index(doc, "<w:body")
+	if open < 0 {
+		return "", "", "", fmt.Errorf("missing w:body")
+	}
+	openEnd := strings.IndexByte(doc[open:], '>')
+	closeIdx := strings.LastIndex(doc, "</w:body>")
+	if openEnd < 0 || closeIdx < 0 || open+openEnd+1 > closeIdx {
+		return "", "", "", fmt.Errorf("malformed w:body")
+	}
+	start := open + openEnd + 1
+	content := doc[start:closeIdx]
+
+	// 正文末尾的 w:sectPr 是整篇文档的节属性；段落内的 w:sectPr 属于正文
+	end := len(content)
+	if idx := strings.LastIndex(content, "<w:sectPr"); idx >= 0 && !strings.Contains(content[idx:], "</w:p>") {
+		end = idx
+	}
+	return doc[:start], content[:end], content[end:] + doc[closeIdx:], nil
+}
+
+// readDocxPart 读取 OOXML 压缩包中的部件
+func readDocxPart(doc []byte, name string) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(doc), int64(len(doc)))
+	if err != nil {
+		return "", fmt.Errorf("invalid zip archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return "", fmt.Errorf("missing %s", name)
+}
+
+// replaceDocxPart 复制 OOXML 压缩包并替换指定部件的内容
+func replaceDocxPart(doc []byte, name string, content []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(doc), int64(len(doc)))
+	if err != nil {
+		return nil, fmt.Errorf("docgen: invalid zip archive: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		if f.Name != name {
+			if err := zw.Copy(f); err != nil {
+				return nil, fmt.Errorf("docgen: copy %s: %w", f.Name, err)
+			}
+			continue
+		}
+		hdr := f.FileHeader
+		w, err := zw.CreateHeader(&hdr)
+		if err != nil {
+			return nil, fmt.Errorf("docgen: write %s: %w", name, err)
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, fmt.Errorf("docgen: write %s: %w", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("docgen: write archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
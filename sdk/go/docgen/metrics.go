@@ -0,0 +1,54 @@
+package docgen
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestMetrics 一次 API 调用的指标，由 WithMetricsHook 接收
+type RequestMetrics struct {
+	// Method 请求方法
+	Method string
+	// Endpoint 请求路径，如 "/api/v1/doc/word"
+	Endpoint string
+	// Status 响应状态码，未收到响应时为 0
+	Status int
+	// Duration 从发送到收到响应（缓冲请求为读完响应体）的耗时
+	Duration time.Duration
+	// Err 传输层错误（超时、无法连接等），错误响应不在此列
+	Err error
+	// Hedges WithHedging 额外发出的对冲请求数
+	Hedges int
+	// HedgeWon 最终采用的是对冲请求的响应
+	HedgeWon bool
+	// CorrelationID 请求的关联 ID，用于将指标与日志、追踪关联（如作为 exemplar）；
+	// 每次调用取值不同，不应作为指标标签，以免标签基数无限增长
+	CorrelationID string
+	// RetryBudget 调用使用的重试预算在调用结束时的状态，未使用预算时为 nil
+	RetryBudget *RetryBudgetState
+}
+
+// emitMetrics 将一次调用的指标交给 WithMetricsHook
+func (c *Client) emitMetrics(req *http.Request, start time.Time, resp *http.Response, err error, hedge hedgeOutcome) {
+	if c.metricsHook == nil {
+		return
+	}
+	m := RequestMetrics{
+		Method:   req.Method,
+		Endpoint: req.URL.Path,
+		Duration: time.Since(start),
+		Err:      err,
+		Hedges:   hedge.hedges,
+		HedgeWon: hedge.won,
+
+		CorrelationID: requestCorrelationID(req),
+	}
+	if resp != nil {
+		m.Status = resp.StatusCode
+	}
+	if b := c.retryBudgetFor(req.Context()); b != nil {
+		state := b.State()
+		m.RetryBudget = &state
+	}
+	c.metricsHook(m)
+}
@@ -0,0 +1,177 @@
+package docgen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrMalformedMultipart multipart/mixed 生成响应无法解析，具体错误类型为 *MultipartError
+var ErrMalformedMultipart = errors.New("docgen: malformed multipart response")
+
+// maxMetadataPartBytes 元数据部分的大小上限
+const maxMetadataPartBytes = 1 << 20
+
+// renderMetadata multipart/mixed 响应中 application/json 部分的内容
+type renderMetadata struct {
+	Warnings []Warning `json:"warnings"`
+	// TimingsMs 各阶段耗时（毫秒），如 {"render": 120, "convert": 35}
+	TimingsMs map[string]float64 `json:"timingsMs"`
+	// Failures 批量生成（ContinueOnError）中渲染失败的条目
+	Failures []ItemFailure `json:"failures"`
+}
+
+// MultipartError multipart/mixed 生成响应格式不正确
+//
+// errors.Is(err, ErrMalformedMultipart) 返回 true
+type MultipartError struct {
+	// Reason 解析失败原因
+	Reason string
+	// Prefix 响应体开头（最多 64 字节，已按 WithRedactedKeys 脱敏）
+	Prefix string
+}
+
+// Error 实现 error 接口
+func (e *MultipartError) Error() string {
+	return fmt.Sprintf("%v: %s (body starts with %q)", ErrMalformedMultipart, e.Reason, e.Prefix)
+}
+
+// Is 使 errors.Is(err, ErrMalformedMultipart) 成立
+func (e *MultipartError) Is(target error) bool {
+	return target == ErrMalformedMultipart
+}
+
+// multipartBoundary 响应为 multipart/mixed 时返回分隔符
+func multipartBoundary(resp *http.Response) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		return "", false
+	}
+	return params["boundary"], true
+}
+
+// documentPart 从 multipart/mixed 响应中读出的文档部分
+type documentPart struct {
+	header   textproto.MIMEHeader
+	size     int64
+	sha256   string
+	metadata *renderMetadata
+}
+
+// readMultipartDocument 解析 multipart/mixed 响应：第一个非 JSON 部分为文档，写入 w；application/json 部分为渲染元数据
+//
+// prefix 返回已读取的响应体开头，用于错误信息。文档部分带有 X-Content-SHA256 等摘要时校验部分内容
+func (c *Client) readMultipartDocument(req *http.Request, body io.Reader, boundary string, w io.Writer, prefix func() []byte) (*documentPart, error) {
+	fail := func(format string, args ...any) error {
+		return &MultipartError{Reason: fmt.Sprintf(format, args...), Prefix: c.multipartPrefix(prefix())}
+	}
+	if boundary == "" {
+		return nil, fail("missing boundary")
+	}
+
+	var doc *documentPart
+	var metadata *renderMetadata
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch {
+		case mediaType == "application/json" && metadata == nil:
+			data, err := io.ReadAll(io.LimitReader(part, maxMetadataPartBytes+1))
+			if err != nil {
+				return nil, fail("read metadata part: %v", err)
+			}
+			if len(data) > maxMetadataPartBytes {
+				return nil, fail("metadata part exceeds %d bytes", maxMetadataPartBytes)
+			}
+			metadata = &renderMetadata{}
+			if err := decodeJSON(data, metadata); err != nil {
+				return nil, fail("metadata part: %v", err)
+			}
+		case doc == nil && mediaType != "application/json":
+			h := sha256.New()
+			n, err := io.Copy(io.MultiWriter(w, h), part)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read response: %w", err)
+			}
+			doc = &documentPart{header: part.Header, size: n, sha256: hex.EncodeToString(h.Sum(nil))}
+			if expected := responseSHA256(http.Header(part.Header)); expected != "" && expected != doc.sha256 {
+				return nil, &ChecksumMismatchError{Endpoint: req.URL.Path, Expected: expected, Actual: doc.sha256}
+			}
+		}
+		part.Close()
+	}
+	if doc == nil {
+		return nil, fail("no document part")
+	}
+	doc.metadata = metadata
+	return doc, nil
+}
+
+// applyTo 用文档部分的头与渲染元数据补全 meta
+func (p *documentPart) applyTo(meta *DocumentMeta) {
+	header := http.Header(p.header)
+	meta.Size = p.size
+	meta.SHA256 = p.sha256
+	if ct := header.Get("Content-Type"); ct != "" {
+		meta.ContentType = ct
+	}
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		meta.FileName = params["filename"]
+	}
+	if responseSHA256(header) != "" {
+		meta.Verified = true
+	}
+	if p.metadata == nil {
+		return
+	}
+	if len(p.metadata.Warnings) > 0 {
+		meta.Warnings = p.metadata.Warnings
+	}
+	if len(p.metadata.TimingsMs) > 0 {
+		meta.Timings = make(map[string]time.Duration, len(p.metadata.TimingsMs))
+		for stage, ms := range p.metadata.TimingsMs {
+			meta.Timings[stage] = time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+}
+
+// multipartPrefix 截取并脱敏响应体开头
+func (c *Client) multipartPrefix(data []byte) string {
+	if len(data) > contentPrefixLen {
+		data = data[:contentPrefixLen]
+	}
+	return strings.ToValidUTF8(string(c.redactor.Redact(data)), string(utf8.RuneError))
+}
+
+// prefixRecorder 记录读取内容的开头，用于错误信息
+type prefixRecorder struct {
+	buf bytes.Buffer
+}
+
+// Write 实现 io.Writer 接口
+func (p *prefixRecorder) Write(b []byte) (int, error) {
+	if room := contentPrefixLen - p.buf.Len(); room > 0 {
+		if len(b) < room {
+			room = len(b)
+		}
+		p.buf.Write(b[:room])
+	}
+	return len(b), nil
+}
@@ -0,0 +1,416 @@
+package docgen
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Option 客户端配置选项，在 NewClient / NewClientWithTimeout 中传入
+type Option func(*Client)
+
+// WithQueryTemplateNames 始终以查询参数（?name=...）传递模板名称
+//
+// 默认仅在模板名称包含路径分隔符时使用查询参数形式，
+// 适用于网关或 servlet 容器会解码路径中转义字符的部署环境
+func WithQueryTemplateNames() Option {
+	return func(c *Client) {
+		c.queryTemplateNames = true
+	}
+}
+
+// WithAPIKey 设置 API 密钥，以 "Authorization: Bearer <key>" 请求头随每个请求发送
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithOutputValidation 在每次生成成功后校验文档格式（见 ValidateDocument），
+// 校验失败时返回 ErrUnexpectedContent 而不是文档字节
+func WithOutputValidation() Option {
+	return func(c *Client) {
+		c.validateOutput = true
+	}
+}
+
+// WithDefaultPriority 设置请求的默认队列优先级，仅在请求未指定 Priority 时生效
+//
+// 交互式服务可设置为 PriorityHigh，避免被批量导出任务阻塞
+func WithDefaultPriority(p Priority) Option {
+	return func(c *Client) {
+		c.defaultPriority = p
+	}
+}
+
+// WithFieldEncryption 启用字段级加密：请求中的 EncryptedField 在发送前使用 keys 的当前密钥以 AES-GCM 加密
+//
+// 适用于中间代理会记录请求体的部署环境；密钥需与服务端共享，轮换密钥时服务端需保留旧密钥用于解密
+func WithFieldEncryption(keys KeyProvider) Option {
+	return func(c *Client) {
+		c.fieldKeys = keys
+	}
+}
+
+// WithRedactedKeys 设置脱敏键名，错误信息与调试输出中的请求/响应体按键名替换为 "[REDACTED]"
+//
+// keys: 键名或正则表达式（完整匹配、不区分大小写），如 WithRedactedKeys("idCard", "salary", ".*phone.*")
+func WithRedactedKeys(keys ...string) Option {
+	return func(c *Client) {
+		c.redactor = NewRedactor(keys...)
+	}
+}
+
+// WithAuditContext 为每个请求附加审计信息（X-Audit-User-Id / X-Audit-Operation / X-Audit-Reason 请求头）
+//
+// fn 接收请求的 context，通常从中读取当前登录用户；调用方通过 WithAudit 放入 context 的非空字段优先
+func WithAuditContext(fn func(ctx context.Context) AuditInfo) Option {
+	return func(c *Client) {
+		c.auditFunc = fn
+	}
+}
+
+// WithTenant 设置租户 ID，以 X-Tenant-ID 请求头随每个请求发送；可通过 WithRequestTenant 按请求覆盖
+//
+// 访问其他租户的资源时服务端返回 403，客户端转换为 ErrTenantForbidden
+func WithTenant(tenantID string) Option {
+	return func(c *Client) {
+		c.tenant = tenantID
+	}
+}
+
+// WithTenantNamespace 在租户命名空间内使用模板名称
+//
+// 生成、任务与模板管理接口中的模板名称自动添加 "<tenant>/" 前缀（见 TenantTemplateName），
+// ListTemplates 等列表接口只返回当前租户的模板并去掉前缀；未设置租户时不生效
+func WithTenantNamespace() Option {
+	return func(c *Client) {
+		c.tenantNamespace = true
+	}
+}
+
+// WithHealthCache 缓存健康状态，IsHealthy / HealthState 在 ttl 内直接返回上次结果
+//
+// 适用于每个入站请求都检查健康状态的中间件；缓存过期时并发调用只触发一次探测。
+// 其他请求遇到连接错误时缓存立即更新为 HealthUnreachable；可通过 RefreshHealthState 强制探测，
+// InvalidateHealthCache 丢弃缓存
+func WithHealthCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		if ttl > 0 {
+			c.healthCache = &healthCache{ttl: ttl}
+		}
+	}
+}
+
+// WithLocale 设置 Accept-Language 请求头，服务端据此返回对应语言的错误信息（如 "zh-CN"、"en-US"）
+//
+// 可通过 WithRequestLocale 按请求覆盖；实际使用的语言见 ErrorResponse.Language
+func WithLocale(locale string) Option {
+	return func(c *Client) {
+		c.locale = locale
+	}
+}
+
+// WithUploadClosePolicy 设置 Close 时对进行中的分片上传会话的处理方式，默认 UploadSessionsPersist
+func WithUploadClosePolicy(policy UploadClosePolicy) Option {
+	return func(c *Client) {
+		c.life.uploadPolicy = policy
+	}
+}
+
+// WithCloseUserTransport Close 时同样关闭用户替换的 HTTPClient 的空闲连接
+//
+// 默认只关闭客户端自行创建的连接池，避免影响与其他代码共享的 HTTP 客户端
+func WithCloseUserTransport() Option {
+	return func(c *Client) {
+		c.life.closeUserTransport = true
+	}
+}
+
+// WithAPIPrefix 设置 API 路径前缀，替换默认的 "/api"
+//
+// 适用于网关将服务挂载在其他路径下的部署环境，如 WithAPIPrefix("/gateway/docgen/api") 时
+// 请求路径为 "/gateway/docgen/api/v1/doc/word"；prefix 为空时版本号直接跟在 BaseURL 之后
+func WithAPIPrefix(prefix string) Option {
+	return func(c *Client) {
+		c.apiPathPrefix = prefix
+		c.customAPIPrefix = true
+	}
+}
+
+// WithAPIVersion 设置所有接口使用的 API 版本（APIv1 或 APIv2），默认 APIv1
+//
+// 不支持的版本在发送请求时返回错误
+func WithAPIVersion(version APIVersion) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// WithEndpointVersion 为指定接口覆盖 API 版本，适用于服务端只有部分接口升级的混合部署
+//
+// endpoint: 不含前缀与版本的接口路径，如 "/doc/word/batch"；同时匹配其下级路径，多个覆盖按最长路径匹配。
+// 例如 WithEndpointVersion("/doc/word/batch", APIv2) 使批量生成使用 /api/v2，其他接口保持 WithAPIVersion 的设置
+func WithEndpointVersion(endpoint string, version APIVersion) Option {
+	return func(c *Client) {
+		c.endpointVersions = append(c.endpointVersions, endpointVersion{endpoint: normalizeEndpoint(endpoint), version: version})
+	}
+}
+
+// WithFlattenedData 发送 Excel 模板填充请求（FillExcelTemplate、SubmitExcelJob 等）前，
+// 将 Data 与 ListData 每一行中的嵌套 map 与结构体展开为以 separator 连接的键名（见 FlattenData），
+// 对应模板中的 {order.customer.name}；separator 为空时使用 "."。
+// 字面键与展开后的键重复时请求返回 ErrKeyCollision
+func WithFlattenedData(separator string) Option {
+	return func(c *Client) {
+		if separator == "" {
+			separator = DefaultFlattenSeparator
+		}
+		c.flattenSep = separator
+	}
+}
+
+// WithDataTransformer 添加模板数据转换函数，在序列化前应用于 Data、DataList 与 ListData 中的每个值
+//
+// 可多次使用，同一个值按添加顺序依次经过各转换函数；嵌套 map 与切片深度优先转换，元素先于所属容器。
+// 转换作用于副本，不修改调用方的数据；转换函数返回的错误会附带键路径，如 "transform dataList[2].status: ..."。
+// 内置转换函数：TrimStrings、ZeroTimeAsEmpty、EnumMapper
+func WithDataTransformer(fn DataTransformer) Option {
+	return func(c *Client) {
+		c.transformers = append(c.transformers, fn)
+	}
+}
+
+// WithFontOptions 设置生成请求的默认字体选项，仅在请求未设置 FontOptions 时生效
+//
+// 适用于模板使用方正等非系统字体、文档需在其他机器上保持版式的场景
+func WithFontOptions(opts FontOptions) Option {
+	return func(c *Client) {
+		if opts.Substitutions != nil {
+			subs := make(map[string]string, len(opts.Substitutions))
+			for k, v := range opts.Substitutions {
+				subs[k] = v
+			}
+			opts.Substitutions = subs
+		}
+		c.fontOptions = &opts
+	}
+}
+
+// WithStrictTemplateChecksums 服务端不支持 FeatureTemplateChecksum 时，对设置了 ExpectedTemplateChecksum 的生成请求
+// 先调用 EnsureTemplateUpToDate 检查模板，模板已被替换时返回 *TemplateChangedError 而不生成文档
+//
+// 每个请求多一次轻量的 HEAD 请求；预检与生成之间模板仍可能被替换，只有服务端校验才能完全避免
+func WithStrictTemplateChecksums() Option {
+	return func(c *Client) {
+		c.strictTemplateChecksums = true
+	}
+}
+
+// WithDebugDump 将每次请求写入 dir 下的调试文件（默认关闭），用于向支持人员提供"实际发送的内容"
+//
+// 每个文件包含请求方法与 URL、请求头（隐藏 Authorization 等认证信息）、请求 JSON（按 WithRedactedKeys 脱敏）、
+// 响应状态与响应头，以及 JSON 响应体；文档等二进制内容只记录大小与 SHA-256。
+// 最多保留 DefaultDebugDumpFiles 个文件（见 WithDebugDumpLimit），超出时删除最旧的文件。
+// 启用后请求失败返回的错误包装为 *OpError，其 DumpPath 指向对应的调试文件；dir 为空时关闭
+func WithDebugDump(dir string) Option {
+	return func(c *Client) {
+		c.debugDump = nil
+		if dir != "" {
+			c.debugDump = &debugDumper{dir: dir}
+		}
+	}
+}
+
+// WithDebugDumpLimit 设置 WithDebugDump 保留的最大文件数，n <= 0 时使用 DefaultDebugDumpFiles
+func WithDebugDumpLimit(n int) Option {
+	return func(c *Client) {
+		c.debugDumpLimit = n
+	}
+}
+
+// WithAdaptiveThrottle 按服务端的 X-RateLimit-Remaining / X-RateLimit-Reset 响应头主动限速（默认关闭）
+//
+// 剩余配额低于 threshold 时，后续请求在发送前等待，使剩余请求均匀分布到窗口重置前；配额耗尽时等待至窗口重置。
+// 等待期间 ctx 结束时请求返回 ctx.Err()；threshold <= 0 时关闭。最新限流状态见 Client.RateLimitStatus
+func WithAdaptiveThrottle(threshold int) Option {
+	return func(c *Client) {
+		c.throttleThreshold = threshold
+	}
+}
+
+// WithWarningHandler 为每个生成结果中的警告调用 fn（默认不调用），便于集中记录日志
+//
+// op 为请求，如 "POST /api/v1/doc/word"；警告同时保留在 DocumentMeta.Warnings 中。fn 可能被并发调用
+func WithWarningHandler(fn func(op string, w Warning)) Option {
+	return func(c *Client) {
+		c.warningHandler = fn
+	}
+}
+
+// WithStrictWarnings 将指定警告码的警告转换为 *WarningError，如 WithStrictWarnings(docgen.WarningMissingPlaceholder)，
+// 使 CI 中的渲染尽早失败；不指定警告码时所有警告均视为错误
+//
+// 流式生成（GenerateWordTo 等）返回 *WarningError 时 w 已收到全部内容，调用方应丢弃
+func WithStrictWarnings(codes ...string) Option {
+	return func(c *Client) {
+		c.strictAllWarnings = len(codes) == 0
+		c.strictWarnings = make(map[string]bool, len(codes))
+		for _, code := range codes {
+			c.strictWarnings[code] = true
+		}
+	}
+}
+
+// WithHedging 对生成请求启用对冲（默认关闭）：delay 内未收到响应时发出相同的请求，最多 maxHedges 个，
+// 采用最先完成的响应并通过 context 取消其他请求，用于降低个别慢实例造成的长尾延迟
+//
+// 对冲请求携带相同的 Idempotency-Key（未通过 WithIdempotencyKey 指定时按 RequestHash 的规则由请求体计算），只用于生成接口，
+// 不用于模板上传、删除等请求。对冲次数见 WithMetricsHook 的 RequestMetrics.Hedges；delay <= 0 或 maxHedges <= 0 时关闭
+func WithHedging(delay time.Duration, maxHedges int) Option {
+	return func(c *Client) {
+		c.hedging = nil
+		if delay > 0 && maxHedges > 0 {
+			c.hedging = &hedgeConfig{delay: delay, maxHedges: maxHedges}
+		}
+	}
+}
+
+// WithMetricsHook 每次 API 调用收到响应或失败后调用 fn（默认不调用），用于上报请求数、耗时与对冲次数等指标
+//
+// fn 在发起请求的 goroutine 中同步调用，应尽快返回
+func WithMetricsHook(fn func(RequestMetrics)) Option {
+	return func(c *Client) {
+		c.metricsHook = fn
+	}
+}
+
+// WithResultSpooling 启用生成结果暂存（默认关闭）：GenerateWordSpooled 等方法流式读取响应，
+// 超过 threshold 字节的文档写入 dir 下的暂存文件（dir 为空时使用系统临时目录），较小的文档保存在内存中
+//
+// 启用后 SaveWord 等 Save* 方法同样经由暂存文件保存，并通过重命名完成，不在内存中保存完整文档。
+// 请求失败或 SpooledResult.Close 时删除暂存文件；threshold <= 0 时所有文档都暂存到磁盘
+func WithResultSpooling(dir string, threshold int64) Option {
+	return func(c *Client) {
+		c.spool = &spoolConfig{dir: dir, threshold: threshold}
+	}
+}
+
+// WithOutbox 启用发件箱（默认关闭）：EnqueueGeneration 将生成请求写入 store，客户端的后台调度器按退避策略重试，
+// 直到生成成功并投递到 DeliverySink，或遇到不可重试的错误进入死信列表
+//
+// 创建客户端时恢复 store 中已有的条目（包括上次进程崩溃时未完成的条目）；Close 时在 DrainTimeout 内尝试投递剩余条目。
+// 同一个 store 同时只应由一个客户端使用
+func WithOutbox(store OutboxStore, opts OutboxOptions) Option {
+	return func(c *Client) {
+		if opts.MinBackoff <= 0 {
+			opts.MinBackoff = defaultOutboxMinBackoff
+		}
+		if opts.MaxBackoff <= 0 {
+			opts.MaxBackoff = defaultOutboxMaxBackoff
+		}
+		if opts.MaxBackoff < opts.MinBackoff {
+			opts.MaxBackoff = opts.MinBackoff
+		}
+		if opts.DrainTimeout == 0 {
+			opts.DrainTimeout = defaultOutboxDrainTimeout
+		}
+		c.outbox = &outbox{store: store, opts: opts, wake: make(chan struct{}, 1)}
+	}
+}
+
+// WithFillValidation 在发送 Excel 填充请求前按模板占位符结构检查数据（默认 FillValidationOff），见 ValidateFillRequest
+//
+// FillValidationWarn 将问题交给 WithWarningHandler（缺少字段为 WarningMissingPlaceholder，未知列表为 WarningUnknownList）后继续发送；
+// FillValidationStrict 返回 *FillValidationError 而不发送。获取模板结构失败时不检查
+func WithFillValidation(mode FillValidationMode) Option {
+	return func(c *Client) {
+		c.fillValidation = mode
+	}
+}
+
+// WithRawFileNames 不清理 SDK 自行决定的本地文件名，原样使用服务端建议的名称（Content-Disposition）与数据字段的值
+//
+// 默认以 SanitizeFileName 清理：RunBatchFromJSONL 的 FileNameField、发件箱 DirSink 与清单 output.dir 的文件名。
+// 调用方显式给出的路径（如 SaveWord 的 outputPath、清单 output.path）从不清理
+func WithRawFileNames() Option {
+	return func(c *Client) {
+		c.rawFileNames = true
+	}
+}
+
+// WithMacroTemplates 允许上传与使用启用宏的模板（.docm、.xlsm），默认不允许：
+// 上传或引用这类模板时返回 ErrMacroTemplatesDisabled。需服务端支持 FeatureMacroTemplates
+func WithMacroTemplates(enabled bool) Option {
+	return func(c *Client) {
+		c.macroTemplates = enabled
+	}
+}
+
+// WithMacroOutput 设置启用宏的模板生成文档时对宏的处理方式（默认 MacroStrip，输出 .docx / .xlsx）
+//
+// 请求未指定 OutputFormat 时，SDK 按此设置填写；MacroKeep 输出与模板相同的 .docm / .xlsm
+func WithMacroOutput(mode MacroOutput) Option {
+	return func(c *Client) {
+		c.macroOutput = mode
+	}
+}
+
+// WithRetryBudget 为客户端的所有调用设置共享的重试预算（默认不限制）
+//
+// SDK 内部的重试（可续传下载的续传、任务事件流的重连、发件箱的再次尝试、WithDegradedMode 的简化渲染重试）
+// 在每次重试前消耗一个令牌；预算用完时同步调用立即返回 *RetryBudgetExhaustedError（errors.Is(err, ErrRetryBudgetExhausted)），
+// 简化渲染不再重试而是返回原错误，发件箱条目推迟到下一个退避间隔。首次请求不消耗预算。WithRetryBudgetContext 可为部分调用指定其他预算，
+// 预算状态见 WithMetricsHook 的 RequestMetrics.RetryBudget
+func WithRetryBudget(b *RetryBudget) Option {
+	return func(c *Client) {
+		c.retryBudget = b
+	}
+}
+
+// WithAutoCleanup 内联生成（GenerateWordInline、FillExcelTemplateInline）在返回前删除上传的临时模板，
+// 生成失败或调用 context 已取消时同样删除（默认不删除，由服务端在过期后删除）
+//
+// 删除失败不影响生成结果，以 WarningTemplateCleanupFailed 报告给 WithWarningHandler
+func WithAutoCleanup() Option {
+	return func(c *Client) {
+		c.autoCleanup = true
+	}
+}
+
+// WithTimeoutPolicy 设置分阶段的超时（见 TimeoutPolicy），超时错误的 Phase 指明超时阶段
+//
+// DialTimeout、TLSHandshakeTimeout、ResponseHeaderTimeout 设置到客户端自建的 HTTPClient 的 Transport 上，
+// 对所有请求生效（替换 HTTPClient 后需自行在 Transport 上设置）；BodyReadTimeout 用于可续传下载与
+// *To / *Spooled 等流式读取。整体超时仍由 NewClientWithTimeout 的 timeout 控制，流式读取不受其限制
+func WithTimeoutPolicy(p TimeoutPolicy) Option {
+	return func(c *Client) {
+		c.timeouts = p
+		if t, ok := c.HTTPClient.Transport.(*http.Transport); ok {
+			p.applyTransport(t)
+		}
+	}
+}
+
+// WithDegradedMode 服务端因容量不足拒绝生成请求（CodeOverloaded、CodeServiceUnavailable 或没有错误码的 503）时，
+// 以简化渲染（SimpleRender：跳过图片与图表、只保留基本样式）重试一次，得到可读但简化的文档而不是错误（默认不重试）
+//
+// 简化的结果 Meta.Degraded 为 true，并以 WarningDegradedOutput 报告给 WithWarningHandler；数据与模板错误、
+// 限流与配额耗尽不会触发；服务端明确不支持 FeatureSimpleRender 时不重试。
+// 简化渲染重试消耗 WithRetryBudget 的令牌，预算用完时返回原错误
+func WithDegradedMode() Option {
+	return func(c *Client) {
+		c.degradedMode = true
+	}
+}
+
+// WithNoRedirectFollow 服务端以 303 See Other 将结果指向其他位置（如对象存储的预签名 URL）时不下载结果，
+// 而是返回 *ResultLocation（errors.Is(err, ErrResultRedirected)），由调用方将地址交给浏览器或其他服务
+//
+// 默认跟随 303：跨源的地址不携带 Authorization 与租户等请求头，摘要校验与流式写入照常进行，
+// 实际下载的地址记录在 DocumentMeta.ResultURL
+func WithNoRedirectFollow() Option {
+	return func(c *Client) {
+		c.noRedirectFollow = true
+	}
+}
@@ -0,0 +1,602 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrOutboxNotConfigured 客户端未通过 WithOutbox 配置发件箱
+var ErrOutboxNotConfigured = errors.New("docgen: outbox not configured")
+
+// ErrOutboxEntryNotFound 发件箱中没有指定 ID 的条目
+var ErrOutboxEntryNotFound = errors.New("docgen: outbox entry not found")
+
+// 发件箱默认配置
+const (
+	defaultOutboxMinBackoff   = time.Second
+	defaultOutboxMaxBackoff   = 5 * time.Minute
+	defaultOutboxDrainTimeout = 10 * time.Second
+)
+
+// GenerationRequest 发件箱中的生成请求，恰好设置一个字段
+type GenerationRequest struct {
+	// Word 生成 Word（GenerateWordWithMeta）
+	Word *WordGenRequest `json:"word,omitempty"`
+	// WordBatch 批量生成 Word（BatchGenerateWordWithMeta）
+	WordBatch *WordBatchRequest `json:"wordBatch,omitempty"`
+	// Excel 生成 Excel（GenerateExcelWithMeta）
+	Excel *ExcelGenRequest `json:"excel,omitempty"`
+	// ExcelFill 填充 Excel 模板（FillExcelTemplateWithMeta）
+	ExcelFill *ExcelFillRequest `json:"excelFill,omitempty"`
+}
+
+// count 返回已设置的字段数
+func (r GenerationRequest) count() int {
+	n := 0
+	for _, set := range []bool{r.Word != nil, r.WordBatch != nil, r.Excel != nil, r.ExcelFill != nil} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// DeliverySink 生成结果的投递目标，恰好设置一个字段；随条目持久化，崩溃恢复后仍可投递
+type DeliverySink struct {
+	// Dir 写入本地目录，文件名为 "<条目 ID>-<服务端建议的文件名>"（服务端未提供文件名时为条目 ID）
+	Dir string `json:"dir,omitempty"`
+	// URL 以 PUT 上传到预签名 URL
+	URL string `json:"url,omitempty"`
+	// Callback 调用 OutboxOptions.Callbacks 中注册的同名回调
+	Callback string `json:"callback,omitempty"`
+}
+
+// DirSink 将结果写入本地目录
+func DirSink(dir string) DeliverySink {
+	return DeliverySink{Dir: dir}
+}
+
+// URLSink 将结果以 PUT 上传到预签名 URL
+func URLSink(url string) DeliverySink {
+	return DeliverySink{URL: url}
+}
+
+// CallbackSink 将结果交给 OutboxOptions.Callbacks 中名为 name 的回调
+func CallbackSink(name string) DeliverySink {
+	return DeliverySink{Callback: name}
+}
+
+// OutboxCallback 发件箱投递回调，返回错误时按退避策略重新生成并投递
+type OutboxCallback func(ctx context.Context, entry OutboxEntry, result *DocumentResult) error
+
+// OutboxEntry 发件箱条目
+type OutboxEntry struct {
+	// ID 条目 ID
+	ID string `json:"id"`
+	// Request 生成请求（Plural 等值已解析，EncryptedField 已加密）
+	Request GenerationRequest `json:"request"`
+	// Sink 投递目标
+	Sink DeliverySink `json:"sink"`
+	// CreatedAt 入队时间
+	CreatedAt time.Time `json:"createdAt"`
+	// Attempts 已失败的尝试次数
+	Attempts int `json:"attempts"`
+	// NextAttempt 下一次尝试的时间
+	NextAttempt time.Time `json:"nextAttempt"`
+	// LastError 最近一次失败的错误信息
+	LastError string `json:"lastError,omitempty"`
+	// Dead 已放弃（不可重试的错误或达到 MaxAttempts），进入死信列表，可通过 RequeueOutboxEntry 重新入队
+	Dead bool `json:"dead,omitempty"`
+	// RequestHash 入队时生成请求的 RequestHash（加密前计算），用于 OutboxOptions.Dedupe
+	RequestHash string `json:"requestHash,omitempty"`
+	// IdempotencyKey 生成请求的 Idempotency-Key，入队时按 RequestHash 的规则（不忽略任何字段）由加密前的请求计算，
+	// 每次尝试都使用同一个键；为空时（旧版本写入的条目）使用 ID
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// OutboxStore 发件箱持久化存储，实现需可并发调用
+type OutboxStore interface {
+	// Put 添加或覆盖条目
+	Put(entry OutboxEntry) error
+	// Delete 删除条目，条目不存在时返回 nil
+	Delete(id string) error
+	// List 返回所有条目（顺序不限）
+	List() ([]OutboxEntry, error)
+}
+
+// FileOutboxStore 基于目录的发件箱存储：每个条目保存为一个 JSON 文件，写入通过临时文件与重命名完成，
+// 进程崩溃不会留下不完整的条目
+type FileOutboxStore struct {
+	dir string
+}
+
+// NewFileOutboxStore 创建（或打开已有的）目录存储
+func NewFileOutboxStore(dir string) (*FileOutboxStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("docgen: outbox: %w", err)
+	}
+	return &FileOutboxStore{dir: dir}, nil
+}
+
+// Put 实现 OutboxStore
+func (s *FileOutboxStore) Put(entry OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("docgen: outbox: %w", err)
+	}
+	return writeFileAtomic(s.path(entry.ID), data)
+}
+
+// Delete 实现 OutboxStore
+func (s *FileOutboxStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("docgen: outbox: %w", err)
+	}
+	return nil
+}
+
+// List 实现 OutboxStore，跳过写入中断留下的临时文件
+func (s *FileOutboxStore) List() ([]OutboxEntry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("docgen: outbox: %w", err)
+	}
+	var entries []OutboxEntry
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, f.Name()))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("docgen: outbox: %w", err)
+		}
+		var entry OutboxEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("docgen: outbox: %s: %w", f.Name(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// path 返回条目文件路径
+func (s *FileOutboxStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// OutboxOptions WithOutbox 的选项
+type OutboxOptions struct {
+	// Callbacks CallbackSink 可引用的回调，按名称注册
+	Callbacks map[string]OutboxCallback
+	// MaxAttempts 最多尝试次数，达到后进入死信列表；<= 0 表示一直重试直到成功
+	MaxAttempts int
+	// MinBackoff 首次失败后的重试间隔（默认 1 秒），之后每次翻倍
+	MinBackoff time.Duration
+	// MaxBackoff 重试间隔上限（默认 5 分钟）
+	MaxBackoff time.Duration
+	// DrainTimeout Close 时尝试投递剩余条目的最长时间（默认 10 秒），负值表示不尝试；
+	// 未投递的条目保留在存储中，下次创建客户端时继续
+	DrainTimeout time.Duration
+	// Dedupe 入队时若已有 RequestHash 与投递目标都相同、且未进入死信列表的条目，
+	// 不再新增条目，直接返回已有条目的 ID
+	Dedupe bool
+}
+
+// outbox 发件箱状态
+type outbox struct {
+	store OutboxStore
+	opts  OutboxOptions
+	// mu 串行化条目的读-改-写
+	mu   sync.Mutex
+	wake chan struct{}
+}
+
+// backoff 返回第 attempts 次失败后的重试间隔
+func (o *outbox) backoff(attempts int) time.Duration {
+	d := o.opts.MinBackoff
+	for i := 1; i < attempts && d < o.opts.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > o.opts.MaxBackoff {
+		d = o.opts.MaxBackoff
+	}
+	return d
+}
+
+// EnqueueGeneration 将生成请求写入发件箱并立即返回条目 ID，由后台调度器重试直到成功，结果投递到 sink
+//
+// 用于不需要同步获得结果、但必须最终完成的生成（如通知类文档）：服务暂时不可用时按退避策略重试，
+// 进程重启后从存储中恢复；不可重试的错误（如模板不存在、参数校验失败）使条目进入死信列表。
+// 每次尝试以条目的 IdempotencyKey 发送，内容相同的请求使用相同的键；投递失败时会重新生成。需先通过 WithOutbox 配置
+func (c *Client) EnqueueGeneration(req GenerationRequest, sink DeliverySink) (string, error) {
+	o := c.outbox
+	if o == nil {
+		return "", ErrOutboxNotConfigured
+	}
+	if err := c.checkOpen(context.Background()); err != nil {
+		return "", err
+	}
+	if req.count() != 1 {
+		return "", fmt.Errorf("docgen: outbox: request must set exactly one of Word, WordBatch, Excel, ExcelFill")
+	}
+	if err := o.validateSink(sink); err != nil {
+		return "", err
+	}
+
+	hash, err := RequestHash(req)
+	if err != nil {
+		return "", fmt.Errorf("docgen: outbox: %w", err)
+	}
+	key, err := RequestHashWithOptions(req, RequestHashOptions{Exclude: []string{}})
+	if err != nil {
+		return "", fmt.Errorf("docgen: outbox: %w", err)
+	}
+	if o.opts.Dedupe {
+		// 查找与新增在同一把锁内完成，并发入队相同请求时只保留一个条目
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if id, err := o.pendingDuplicate(hash, sink); err != nil || id != "" {
+			return id, err
+		}
+	}
+
+	// 入队时解析语言相关的值并加密字段，明文不会写入存储
+	sealed, err := c.localizeValues(context.Background(), req)
+	if err == nil {
+		sealed, err = c.sealFields(sealed)
+	}
+	if err != nil {
+		return "", fmt.Errorf("docgen: outbox: %w", err)
+	}
+	id, err := newOutboxID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	entry := OutboxEntry{ID: id, Request: sealed.(GenerationRequest), Sink: sink, CreatedAt: now, NextAttempt: now, RequestHash: hash, IdempotencyKey: key}
+	if err := o.store.Put(entry); err != nil {
+		return "", err
+	}
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+// newOutboxID 生成随机的条目 ID
+func newOutboxID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("docgen: outbox: generate entry id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// pendingDuplicate 返回 RequestHash 与投递目标相同、未进入死信列表的条目 ID，没有时返回空字符串
+func (o *outbox) pendingDuplicate(hash string, sink DeliverySink) (string, error) {
+	entries, err := o.store.List()
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if !e.Dead && e.RequestHash == hash && e.Sink == sink {
+			return e.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// validateSink 检查投递目标恰好设置一项，且回调已注册
+func (o *outbox) validateSink(sink DeliverySink) error {
+	n := 0
+	for _, s := range []string{sink.Dir, sink.URL, sink.Callback} {
+		if s != "" {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("docgen: outbox: sink must set exactly one of Dir, URL, Callback")
+	}
+	if sink.Callback != "" && o.opts.Callbacks[sink.Callback] == nil {
+		return fmt.Errorf("docgen: outbox: callback %q not registered", sink.Callback)
+	}
+	return nil
+}
+
+// OutboxPending 返回等待生成或重试的条目数（不含死信）
+func (c *Client) OutboxPending() (int, error) {
+	if c.outbox == nil {
+		return 0, ErrOutboxNotConfigured
+	}
+	entries, err := c.outbox.store.List()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range entries {
+		if !e.Dead {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// OutboxDeadLetters 返回已放弃的条目，按入队时间排序，LastError 为最后一次失败的原因
+func (c *Client) OutboxDeadLetters() ([]OutboxEntry, error) {
+	if c.outbox == nil {
+		return nil, ErrOutboxNotConfigured
+	}
+	entries, err := c.outbox.store.List()
+	if err != nil {
+		return nil, err
+	}
+	var dead []OutboxEntry
+	for _, e := range entries {
+		if e.Dead {
+			dead = append(dead, e)
+		}
+	}
+	sortOutboxEntries(dead)
+	return dead, nil
+}
+
+// RequeueOutboxEntry 将死信条目重新入队，尝试次数清零；条目不存在时返回 ErrOutboxEntryNotFound
+func (c *Client) RequeueOutboxEntry(id string) error {
+	o := c.outbox
+	if o == nil {
+		return ErrOutboxNotConfigured
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries, err := o.store.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ID != id {
+			continue
+		}
+		e.Dead, e.Attempts, e.NextAttempt = false, 0, time.Now()
+		if err := o.store.Put(e); err != nil {
+			return err
+		}
+		select {
+		case o.wake <- struct{}{}:
+		default:
+		}
+		return nil
+	}
+	return ErrOutboxEntryNotFound
+}
+
+// sortOutboxEntries 按入队时间排序
+func sortOutboxEntries(entries []OutboxEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+}
+
+// startOutbox 启动发件箱调度器，由 Close 停止
+func (c *Client) startOutbox() {
+	l := c.life
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		for {
+			wait := c.dispatchOutbox(context.Background(), false)
+			var timer *time.Timer
+			var due <-chan time.Time
+			if wait >= 0 {
+				timer = time.NewTimer(wait)
+				due = timer.C
+			}
+			select {
+			case <-l.done:
+				c.drainOutbox()
+				return
+			case <-c.outbox.wake:
+			case <-due:
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+	}()
+}
+
+// drainOutbox Close 时在 DrainTimeout 内对所有待处理条目各尝试一次
+func (c *Client) drainOutbox() {
+	timeout := c.outbox.opts.DrainTimeout
+	if timeout < 0 {
+		return
+	}
+	ctx := context.WithValue(context.Background(), shutdownContextKey{}, true)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	c.dispatchOutbox(ctx, true)
+}
+
+// dispatchOutbox 处理到期的条目（all 为 true 时处理全部待处理条目），返回距下一个条目到期的时间；
+// 没有待处理条目时返回 -1
+func (c *Client) dispatchOutbox(ctx context.Context, all bool) time.Duration {
+	o := c.outbox
+	entries, err := o.store.List()
+	if err != nil {
+		return o.opts.MinBackoff
+	}
+	sortOutboxEntries(entries)
+	next := time.Duration(-1)
+	for _, entry := range entries {
+		if entry.Dead {
+			continue
+		}
+		if wait := time.Until(entry.NextAttempt); wait > 0 && !all {
+			if next < 0 || wait < next {
+				next = wait
+			}
+			continue
+		}
+		if ctx.Err() != nil || !all && c.closing() {
+			return 0
+		}
+		if entry = c.attemptOutbox(ctx, entry); !entry.Dead && entry.Attempts > 0 {
+			if wait := time.Until(entry.NextAttempt); next < 0 || wait < next {
+				next = wait
+			}
+		}
+	}
+	return next
+}
+
+// attemptOutbox 生成并投递一个条目：成功时从存储中删除，失败时记录错误并安排重试或转入死信
+func (c *Client) attemptOutbox(ctx context.Context, entry OutboxEntry) OutboxEntry {
+	o := c.outbox
+	if entry.Attempts > 0 {
+		// 重试预算用完时推迟到下一个退避间隔，不计入尝试次数，LastError 保留上一次失败的原因
+		if err := c.spendRetry(ctx, "outbox entry "+entry.ID, errors.New(entry.LastError)); err != nil {
+			o.mu.Lock()
+			defer o.mu.Unlock()
+			entry.NextAttempt = time.Now().Add(o.backoff(entry.Attempts))
+			o.store.Put(entry)
+			return entry
+		}
+		ctx = withRetryAttempt(ctx)
+	}
+	key := entry.IdempotencyKey
+	if key == "" {
+		key = entry.ID
+	}
+	result, err := c.generateRequest(WithIdempotencyKey(ctx, key), entry.Request)
+	if err == nil {
+		err = c.deliver(ctx, entry, result)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err == nil {
+		o.store.Delete(entry.ID)
+		return OutboxEntry{}
+	}
+	entry.Attempts++
+	entry.LastError = err.Error()
+	var quotaErr *QuotaExceededError
+	switch {
+	case !outboxRetryable(err) || o.opts.MaxAttempts > 0 && entry.Attempts >= o.opts.MaxAttempts:
+		entry.Dead = true
+	case errors.As(err, &quotaErr) && quotaErr.Reset.After(time.Now()):
+		// 配额重置前重试没有意义
+		entry.NextAttempt = quotaErr.Reset
+	default:
+		entry.NextAttempt = time.Now().Add(o.backoff(entry.Attempts))
+	}
+	o.store.Put(entry)
+	return entry
+}
+
+// generateRequest 按请求类型调用对应的生成方法
+func (c *Client) generateRequest(ctx context.Context, req GenerationRequest) (*DocumentResult, error) {
+	switch {
+	case req.Word != nil:
+		return c.GenerateWordWithMeta(ctx, *req.Word)
+	case req.WordBatch != nil:
+		return c.BatchGenerateWordWithMeta(ctx, *req.WordBatch)
+	case req.Excel != nil:
+		return c.GenerateExcelWithMeta(ctx, *req.Excel)
+	case req.ExcelFill != nil:
+		return c.FillExcelTemplateWithMeta(ctx, *req.ExcelFill)
+	}
+	return nil, fmt.Errorf("docgen: outbox: empty request")
+}
+
+// deliveryError 投递失败，总是可重试
+type deliveryError struct {
+	err error
+}
+
+// Error 实现 error 接口
+func (e *deliveryError) Error() string {
+	return "docgen: outbox: deliver: " + e.err.Error()
+}
+
+// Unwrap 返回原始错误
+func (e *deliveryError) Unwrap() error {
+	return e.err
+}
+
+// deliver 将结果投递到条目的 sink
+func (c *Client) deliver(ctx context.Context, entry OutboxEntry, result *DocumentResult) error {
+	var err error
+	switch sink := entry.Sink; {
+	case sink.Dir != "":
+		name := entry.ID
+		if fileName := c.derivedFileName(result.Meta.FileName, nil); fileName != "" {
+			name += "-" + fileName
+		}
+		if err = os.MkdirAll(sink.Dir, 0o755); err == nil {
+			err = writeFileAtomic(filepath.Join(sink.Dir, name), result.Data)
+		}
+	case sink.URL != "":
+		err = c.putResult(ctx, sink.URL, result)
+	case sink.Callback != "":
+		fn := c.outbox.opts.Callbacks[sink.Callback]
+		if fn == nil {
+			err = fmt.Errorf("callback %q not registered", sink.Callback)
+		} else {
+			err = fn(ctx, entry, result)
+		}
+	}
+	if err != nil {
+		return &deliveryError{err: err}
+	}
+	return nil
+}
+
+// putResult 以 PUT 上传结果到预签名 URL，不携带客户端的认证头
+func (c *Client) putResult(ctx context.Context, url string, result *DocumentResult) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(result.Data))
+	if err != nil {
+		return err
+	}
+	if result.Meta.ContentType != "" {
+		req.Header.Set("Content-Type", result.Meta.ContentType)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("upload failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// outboxRetryable 发件箱是否重试：投递失败、IsRetryable 的错误、配额耗尽、5xx 响应以及被 Close 中断的请求可重试，
+// 其他错误（4xx 响应、请求校验失败等）重试也不会成功
+func outboxRetryable(err error) bool {
+	var delivery *deliveryError
+	if errors.As(err, &delivery) || IsRetryable(err) || errors.Is(err, ErrQuotaExceeded) ||
+		errors.Is(err, ErrClientClosed) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var errResp *ErrorResponse
+	return errors.As(err, &errResp) && errResp.Status >= http.StatusInternalServerError
+}
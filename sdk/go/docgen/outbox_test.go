@@ -0,0 +1,130 @@
+package docgen_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// TestOutboxRecoversAfterRestart 服务不可用时入队的条目在客户端关闭（不尝试投递剩余条目）后保留在存储中；
+// 使用同一目录的新客户端启动后，每个条目恰好投递一次
+func TestOutboxRecoversAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	store, err := docgen.NewFileOutboxStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 第一个进程：服务无法连接，生成全部失败
+	down := httptest.NewServer(nil)
+	down.Close()
+	var lost sync.Map
+	first := docgen.NewClient(down.URL, docgen.WithOutbox(store, docgen.OutboxOptions{
+		Callbacks: map[string]docgen.OutboxCallback{
+			"deliver": func(ctx context.Context, entry docgen.OutboxEntry, result *docgen.DocumentResult) error {
+				lost.Store(entry.ID, true)
+				return nil
+			},
+		},
+		MinBackoff:   20 * time.Millisecond,
+		DrainTimeout: -1,
+	}))
+	const jobs = 5
+	ids := make(map[string]bool, jobs)
+	for i := 0; i < jobs; i++ {
+		req := docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"n": i}}
+		id, err := first.EnqueueGeneration(docgen.GenerationRequest{Word: &req}, docgen.CallbackSink("deliver"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[id] = true
+	}
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lost.Range(func(id, _ any) bool {
+		t.Errorf("entry %v delivered by the client that could not reach the server", id)
+		return true
+	})
+
+	// 关闭后条目仍在磁盘上（包括已失败过、等待重试的条目）
+	pending, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != jobs {
+		t.Fatalf("%d entries left in the store after Close, want %d", len(pending), jobs)
+	}
+	keys := make(map[string]string, jobs)
+	for _, e := range pending {
+		keys[e.ID] = e.IdempotencyKey
+	}
+
+	// 第二个进程：同一目录，服务恢复
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{n}}"))
+	reopened, err := docgen.NewFileOutboxStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	delivered := make(map[string]int)
+	done := make(chan struct{})
+	second := docgen.NewClient(srv.URL, docgen.WithOutbox(reopened, docgen.OutboxOptions{
+		Callbacks: map[string]docgen.OutboxCallback{
+			"deliver": func(ctx context.Context, entry docgen.OutboxEntry, result *docgen.DocumentResult) error {
+				mu.Lock()
+				defer mu.Unlock()
+				delivered[entry.ID]++
+				if len(delivered) == jobs {
+					close(done)
+				}
+				return nil
+			},
+		},
+		MinBackoff: 20 * time.Millisecond,
+	}))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending entries were not delivered after the restart")
+	}
+	if err := second.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for id, n := range delivered {
+		if !ids[id] || n != 1 {
+			t.Errorf("entry %s delivered %d times (enqueued: %v), want once", id, n, ids[id])
+		}
+	}
+	// 每个条目只生成一次，使用入队时确定的幂等键
+	sent := srv.RequestsTo(docgentest.EndpointWord)
+	if len(sent) != jobs {
+		t.Errorf("server received %d generation requests, want %d", len(sent), jobs)
+	}
+	used := make(map[string]bool, jobs)
+	for _, r := range sent {
+		used[r.Header.Get(docgen.IdempotencyKeyHeader)] = true
+	}
+	for id, key := range keys {
+		if !used[key] {
+			t.Errorf("entry %s: idempotency key %q not used after the restart", id, key)
+		}
+	}
+	if n, err := second.OutboxPending(); err != nil || n != 0 {
+		t.Errorf("OutboxPending = %d, %v; want 0", n, err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d files left in the outbox directory", len(files))
+	}
+}
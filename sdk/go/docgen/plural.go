@@ -0,0 +1,126 @@
+package docgen
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PluralValue 按数量选择单复数形式的文本，由 Plural 创建
+//
+// 放入 Data / DataList / ListData 中任意位置，客户端序列化请求时解析为字符串，服务端只收到最终文本。
+// 单复数规则取决于请求语言（WithRequestLocale、WithLocale），未设置语言或语言没有内置规则时按英语规则：
+// 数量的绝对值为 1 时使用单数形式
+type PluralValue struct {
+	// Count 数量
+	Count int
+	// Singular 单数形式
+	Singular string
+	// Plural 复数形式
+	Plural string
+
+	locale string
+}
+
+// Plural 按数量选择单复数形式，形式中的 "%d" 替换为数量，如 Plural(n, "%d item", "%d items")
+//
+// 内置规则：英语、德语等数量绝对值为 1 时用单数；法语、葡萄牙语、印地语 0 和 1 用单数；
+// 俄语、乌克兰语个位为 1（11 除外）时用单数；中文、日语、韩语等没有单复数变化，总是使用复数形式
+func Plural(count int, singular, plural string) PluralValue {
+	return PluralValue{Count: count, Singular: singular, Plural: plural}
+}
+
+// String 按解析时的语言（未解析时按英语规则）返回文本
+func (v PluralValue) String() string {
+	form := v.Plural
+	if pluralRule(v.locale)(v.Count) {
+		form = v.Singular
+	}
+	return strings.ReplaceAll(form, "%d", strconv.Itoa(v.Count))
+}
+
+// MarshalJSON 实现 json.Marshaler：输出解析后的字符串
+func (v PluralValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// ConditionalValue 按条件选择的文本，由 Conditional 创建
+type ConditionalValue struct {
+	// Cond 条件
+	Cond bool
+	// IfTrue 条件成立时的文本
+	IfTrue string
+	// IfFalse 条件不成立时的文本
+	IfFalse string
+}
+
+// Conditional 按条件选择文本，客户端序列化请求时解析为字符串，如 Conditional(user.Female, "女士", "先生")
+func Conditional(cond bool, ifTrue, ifFalse string) ConditionalValue {
+	return ConditionalValue{Cond: cond, IfTrue: ifTrue, IfFalse: ifFalse}
+}
+
+// String 返回选择的文本
+func (v ConditionalValue) String() string {
+	if v.Cond {
+		return v.IfTrue
+	}
+	return v.IfFalse
+}
+
+// MarshalJSON 实现 json.Marshaler：输出选择的文本
+func (v ConditionalValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// pluralRule 返回语言的单数判断规则，locale 可以是 Accept-Language 列表，只使用第一个语言的主标签
+func pluralRule(locale string) func(n int) bool {
+	tag, _, _ := strings.Cut(locale, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+	tag, _, _ = strings.Cut(tag, "_")
+	switch strings.ToLower(tag) {
+	case "zh", "ja", "ko", "vi", "th", "id", "ms":
+		return func(int) bool { return false }
+	case "fr", "pt", "hi":
+		return func(n int) bool { return abs(n) <= 1 }
+	case "ru", "uk", "be":
+		return func(n int) bool {
+			n = abs(n)
+			return n%10 == 1 && n%100 != 11
+		}
+	default:
+		return func(n int) bool { return abs(n) == 1 }
+	}
+}
+
+// abs 返回绝对值
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+var pluralValueType = reflect.TypeOf(PluralValue{})
+
+// localizeValues 将请求中的 PluralValue 绑定到请求语言；未设置语言时按默认规则解析，不遍历请求体
+func (c *Client) localizeValues(ctx context.Context, reqBody any) (any, error) {
+	locale := c.localeFor(ctx)
+	if locale == "" {
+		return reqBody, nil
+	}
+	out, changed, err := rewriteValues(reflect.ValueOf(reqBody), func(v reflect.Value) (reflect.Value, bool, error) {
+		if v.Type() != pluralValueType {
+			return v, false, nil
+		}
+		p := v.Interface().(PluralValue)
+		p.locale = locale
+		return reflect.ValueOf(p), true, nil
+	})
+	if err != nil || !changed {
+		return reqBody, err
+	}
+	return out.Interface(), nil
+}
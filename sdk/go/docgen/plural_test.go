@@ -0,0 +1,148 @@
+package docgen_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+func TestPluralString(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{0, "0 items"},
+		{1, "1 item"},
+		{-1, "-1 item"},
+		{2, "2 items"},
+		{-2, "-2 items"},
+		{11, "11 items"},
+		{21, "21 items"},
+	}
+	for _, tt := range tests {
+		if got := docgen.Plural(tt.count, "%d item", "%d items").String(); got != tt.want {
+			t.Errorf("Plural(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+	// 形式中可以不包含数量，也可以包含多次
+	if got := docgen.Plural(1, "one", "many").String(); got != "one" {
+		t.Errorf("Plural without %%d = %q", got)
+	}
+	if got := docgen.Plural(3, "%d/%d", "%d of %d").String(); got != "3 of 3" {
+		t.Errorf("Plural with two %%d = %q", got)
+	}
+}
+
+// TestPluralLocale 单复数规则按请求语言解析，Plural 放在 DataList 条目、嵌套 map 与切片中同样生效，服务端只收到字符串
+func TestPluralLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		count  int
+		want   string
+	}{
+		// 未设置语言与没有内置规则的语言按英语规则
+		{"", 0, "0 P"},
+		{"", 1, "1 S"},
+		{"", -1, "-1 S"},
+		{"en-US", 1, "1 S"},
+		{"en-US", 0, "0 P"},
+		{"de", -1, "-1 S"},
+		{"xx", 2, "2 P"},
+		// 法语、葡萄牙语：0 与 1 用单数
+		{"fr-FR", 0, "0 S"},
+		{"fr-FR", 1, "1 S"},
+		{"fr-FR", -1, "-1 S"},
+		{"fr-FR", 2, "2 P"},
+		{"pt_BR", -2, "-2 P"},
+		// 俄语：个位为 1（11 除外）用单数
+		{"ru", 1, "1 S"},
+		{"ru", 21, "21 S"},
+		{"ru", -21, "-21 S"},
+		{"ru", 11, "11 P"},
+		{"ru", 111, "111 P"},
+		{"ru", 0, "0 P"},
+		// 中文没有单复数变化
+		{"zh-CN", 1, "1 P"},
+		{"zh-CN", 0, "0 P"},
+		{"zh-CN", -1, "-1 P"},
+		// Accept-Language 列表只使用第一个语言
+		{"fr;q=0.9, en", 0, "0 S"},
+		{"en, fr", 0, "0 P"},
+	}
+
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{n}}"))
+	client := docgen.NewClient(srv.URL)
+	for _, tt := range tests {
+		p := docgen.Plural(tt.count, "%d S", "%d P")
+		ctx := context.Background()
+		if tt.locale != "" {
+			ctx = docgen.WithRequestLocale(ctx, tt.locale)
+		}
+		req := docgen.WordBatchRequest{TemplateName: "t.docx", DataList: []map[string]any{
+			{"n": p, "nested": map[string]any{"n": p}, "list": []any{p}, "ptr": &p},
+		}}
+		if _, err := client.BatchGenerateWordWithMeta(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		var sent struct {
+			DataList []struct {
+				N      string            `json:"n"`
+				Nested map[string]string `json:"nested"`
+				List   []string          `json:"list"`
+				Ptr    string            `json:"ptr"`
+			} `json:"dataList"`
+		}
+		if err := json.Unmarshal(srv.LastRequest().Body, &sent); err != nil {
+			t.Fatalf("%q %d: %v (body %s)", tt.locale, tt.count, err, srv.LastRequest().Body)
+		}
+		row := sent.DataList[0]
+		if row.N != tt.want || row.Nested["n"] != tt.want || len(row.List) != 1 || row.List[0] != tt.want || row.Ptr != tt.want {
+			t.Errorf("locale %q count %d: sent %+v, want %q everywhere", tt.locale, tt.count, row, tt.want)
+		}
+	}
+	// 按请求语言解析不修改调用方的值
+	shared := map[string]any{"n": docgen.Plural(0, "%d S", "%d P")}
+	ctx := docgen.WithRequestLocale(context.Background(), "fr")
+	if _, err := client.GenerateWordWithMeta(ctx, docgen.WordGenRequest{TemplateName: "t.docx", Data: shared}); err != nil {
+		t.Fatal(err)
+	}
+	docgentest.AssertJSONPath(t, srv.LastRequest(), "data.n", "0 S")
+	if got := shared["n"].(docgen.PluralValue).String(); got != "0 P" {
+		t.Errorf("caller's value resolves to %q after a French request, want %q", got, "0 P")
+	}
+}
+
+func TestConditional(t *testing.T) {
+	tests := []struct {
+		cond bool
+		want string
+	}{
+		{true, "女士"},
+		{false, "先生"},
+	}
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.xlsx", docgentest.MinimalXlsx())
+	client := docgen.NewClient(srv.URL, docgen.WithLocale("fr"))
+	for _, tt := range tests {
+		c := docgen.Conditional(tt.cond, "女士", "先生")
+		if got := c.String(); got != tt.want {
+			t.Errorf("Conditional(%v) = %q, want %q", tt.cond, got, tt.want)
+		}
+
+		// ListData 行中的 Conditional 与 Plural 一起解析
+		req := docgen.ExcelFillRequest{TemplateName: "t.xlsx", ListData: map[string][]map[string]any{
+			"rows": {{"title": c, "count": docgen.Plural(0, "%d article", "%d articles")}},
+		}}
+		if _, err := client.FillExcelTemplateWithMeta(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+		docgentest.AssertJSONPath(t, srv.LastRequest(), "listData.rows[0].title", tt.want)
+		docgentest.AssertJSONPath(t, srv.LastRequest(), "listData.rows[0].count", "0 article")
+	}
+}
@@ -0,0 +1,142 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PreviewFormat 分页预览的输出格式
+type PreviewFormat string
+
+const (
+	// PreviewPDF 单页 PDF
+	PreviewPDF PreviewFormat = "pdf"
+	// PreviewPNG 单页 PNG 图片
+	PreviewPNG PreviewFormat = "png"
+)
+
+// ErrPageOutOfRange 请求的页码超出文档页数，具体错误类型为 *PageOutOfRangeError
+var ErrPageOutOfRange = errors.New("docgen: page out of range")
+
+// PageOutOfRangeError 请求的页码超出文档页数
+//
+// errors.Is(err, ErrPageOutOfRange) 返回 true
+type PageOutOfRangeError struct {
+	// ResultID 任务 ID
+	ResultID string
+	// Page 请求的页码（从 1 开始）
+	Page int
+	// PageCount 文档实际页数，无法获取时为 -1
+	PageCount int
+}
+
+// Error 实现 error 接口
+func (e *PageOutOfRangeError) Error() string {
+	if e.PageCount < 0 {
+		return fmt.Sprintf("%v: %s: page %d", ErrPageOutOfRange, e.ResultID, e.Page)
+	}
+	return fmt.Sprintf("%v: %s: page %d of %d", ErrPageOutOfRange, e.ResultID, e.Page, e.PageCount)
+}
+
+// Is 使 errors.Is(err, ErrPageOutOfRange) 成立
+func (e *PageOutOfRangeError) Is(target error) bool {
+	return target == ErrPageOutOfRange
+}
+
+// pageCountResponse 页数接口的响应
+type pageCountResponse struct {
+	PageCount int `json:"pageCount"`
+}
+
+// GetDocumentPageCount 获取已完成异步任务结果的页数
+//
+// resultID: 任务 ID
+//
+// 需服务端支持 FeaturePagedPreview，能力接口明确不支持时返回 *UnsupportedFeatureError
+func (c *Client) GetDocumentPageCount(resultID string) (int, error) {
+	return c.documentPageCount(context.Background(), resultID)
+}
+
+// documentPageCount GetDocumentPageCount 的 context 版本
+func (c *Client) documentPageCount(ctx context.Context, resultID string) (int, error) {
+	if err := c.rejectUnsupported(ctx, FeaturePagedPreview); err != nil {
+		return 0, err
+	}
+	var resp pageCountResponse
+	if err := c.doJSON(ctx, http.MethodGet, jobPath(resultID, "/pages"), nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.PageCount, nil
+}
+
+// GetDocumentPage 获取已完成异步任务结果的单页预览，无需下载完整文档
+//
+// resultID: 任务 ID
+// page: 页码，从 1 开始
+// format: 输出格式，PreviewPDF 或 PreviewPNG
+//
+// 页码超出范围时返回 *PageOutOfRangeError（包含实际页数）。服务端提供摘要时校验内容，
+// 不一致返回 *ChecksumMismatchError；需服务端支持 FeaturePagedPreview
+func (c *Client) GetDocumentPage(resultID string, page int, format PreviewFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := c.GetDocumentPageTo(context.Background(), resultID, page, format, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetDocumentPageTo 获取单页预览并直接写入 w，行为与 GetDocumentPage 一致
+func (c *Client) GetDocumentPageTo(ctx context.Context, resultID string, page int, format PreviewFormat, w io.Writer) (*DocumentMeta, error) {
+	if format != PreviewPDF && format != PreviewPNG {
+		return nil, fmt.Errorf("docgen: unsupported preview format %q (want %q or %q)", format, PreviewPDF, PreviewPNG)
+	}
+	if err := c.rejectUnsupported(ctx, FeaturePagedPreview); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		return nil, c.pageOutOfRange(ctx, resultID, page)
+	}
+
+	path := jobPath(resultID, "/pages/"+strconv.Itoa(page)) + "?format=" + url.QueryEscape(string(format))
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", previewContentType(format))
+	meta, err := c.streamDocument(req, w)
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && (errResp.Code == CodePageOutOfRange || errResp.Status == http.StatusRequestedRangeNotSatisfiable) {
+		return nil, c.pageOutOfRange(ctx, resultID, page)
+	}
+	return meta, err
+}
+
+// GetDocumentPageSpooled 获取单页预览，超过 WithResultSpooling 阈值时暂存到磁盘，行为与 GenerateWordSpooled 一致
+func (c *Client) GetDocumentPageSpooled(ctx context.Context, resultID string, page int, format PreviewFormat) (*SpooledResult, error) {
+	return c.spoolResult(func(w io.Writer) (*DocumentMeta, error) {
+		return c.GetDocumentPageTo(ctx, resultID, page, format, w)
+	})
+}
+
+// previewContentType 预览格式对应的 Content-Type
+func previewContentType(format PreviewFormat) string {
+	if format == PreviewPNG {
+		return "image/png"
+	}
+	return "application/pdf"
+}
+
+// pageOutOfRange 构造 *PageOutOfRangeError，并查询实际页数
+func (c *Client) pageOutOfRange(ctx context.Context, resultID string, page int) error {
+	count, err := c.documentPageCount(ctx, resultID)
+	if err != nil {
+		count = -1
+	}
+	return &PageOutOfRangeError{ResultID: resultID, Page: page, PageCount: count}
+}
@@ -0,0 +1,35 @@
+package docgen
+
+import "fmt"
+
+// Priority 服务端队列优先级
+type Priority string
+
+const (
+	// PriorityLow 低优先级，适用于夜间批量导出等后台任务
+	PriorityLow Priority = "low"
+	// PriorityNormal 普通优先级（服务端默认）
+	PriorityNormal Priority = "normal"
+	// PriorityHigh 高优先级，适用于用户正在等待结果的交互式请求
+	PriorityHigh Priority = "high"
+)
+
+// Valid 是否为合法的优先级（空值表示使用服务端默认值，视为合法）
+func (p Priority) Valid() bool {
+	switch p {
+	case "", PriorityLow, PriorityNormal, PriorityHigh:
+		return true
+	}
+	return false
+}
+
+// resolvePriority 请求未指定优先级时使用客户端默认值，并校验取值
+func (c *Client) resolvePriority(p Priority) (Priority, error) {
+	if p == "" {
+		p = c.defaultPriority
+	}
+	if !p.Valid() {
+		return "", fmt.Errorf("docgen: invalid priority %q (want %q, %q or %q)", p, PriorityLow, PriorityNormal, PriorityHigh)
+	}
+	return p, nil
+}
@@ -0,0 +1,175 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// 健康检查接口路径
+const (
+	healthPath          = "/actuator/health"
+	livenessProbePath   = "/actuator/health/liveness"
+	readinessProbePath  = "/actuator/health/readiness"
+	defaultWaitInterval = time.Second
+)
+
+// Probe 健康检查探针类型
+type Probe int
+
+const (
+	// ProbeReadiness 就绪探针：服务可以生成文档（模板存储等依赖已就绪）
+	ProbeReadiness Probe = iota
+	// ProbeLiveness 存活探针：服务进程正常运行，不代表可以生成文档
+	ProbeLiveness
+	// ProbeAggregate 聚合健康检查（/actuator/health），与 IsHealthy 一致
+	ProbeAggregate
+)
+
+// String 返回探针名称
+func (p Probe) String() string {
+	switch p {
+	case ProbeReadiness:
+		return "readiness"
+	case ProbeLiveness:
+		return "liveness"
+	case ProbeAggregate:
+		return "health"
+	default:
+		return fmt.Sprintf("Probe(%d)", int(p))
+	}
+}
+
+// path 返回探针的接口路径
+func (p Probe) path() string {
+	switch p {
+	case ProbeReadiness:
+		return readinessProbePath
+	case ProbeLiveness:
+		return livenessProbePath
+	default:
+		return healthPath
+	}
+}
+
+// ProbeResult 探针检查结果
+type ProbeResult struct {
+	// Probe 请求的探针
+	Probe Probe
+	// State 探针状态，Status 为 "UP" 时为 HealthUp
+	State HealthState
+	// Status 服务端报告的原始状态，如 "UP"、"DOWN"、"OUT_OF_SERVICE"；无法连接时为空
+	Status string
+	// Components 各组件健康状态（需服务端开启 show-components）
+	Components map[string]HealthComponent
+	// Fallback 服务端未提供探针接口（返回 404），结果来自聚合健康检查
+	Fallback bool
+}
+
+// Liveness 检查存活探针（/actuator/health/liveness）
+//
+// 服务端未提供该接口时回退到聚合健康检查，此时 Fallback 为 true。
+// 服务报告不可用（包括 503 响应）不视为错误，通过 State 返回；无法连接或超时时 State 为 HealthUnreachable 并返回错误
+func (c *Client) Liveness(ctx context.Context) (*ProbeResult, error) {
+	return c.probe(ctx, ProbeLiveness)
+}
+
+// Readiness 检查就绪探针（/actuator/health/readiness）
+//
+// 服务启动后模板存储预热完成前，聚合健康检查已报告 UP，而就绪探针报告 OUT_OF_SERVICE；
+// 在首次生成前检查 Readiness 可避免请求失败。回退与错误规则同 Liveness
+func (c *Client) Readiness(ctx context.Context) (*ProbeResult, error) {
+	return c.probe(ctx, ProbeReadiness)
+}
+
+// probe 请求探针接口，404 时回退到聚合健康检查
+func (c *Client) probe(ctx context.Context, p Probe) (*ProbeResult, error) {
+	result := &ProbeResult{Probe: p}
+	health, status, err := c.getHealth(ctx, p.path())
+	if status == http.StatusNotFound && p != ProbeAggregate {
+		result.Fallback = true
+		health, _, err = c.getHealth(ctx, healthPath)
+	}
+	if health != nil {
+		result.Status = health.Status
+		result.Components = health.Components
+	}
+	switch {
+	case err == nil && health.Status == "UP":
+		result.State = HealthUp
+	case err == nil, health != nil && errors.Is(err, ErrServiceDown):
+		result.State = HealthDown
+		return result, nil
+	case errors.Is(err, ErrUnreachable) || errors.Is(err, ErrTimeout):
+		result.State = HealthUnreachable
+	default:
+		result.State = HealthDown
+	}
+	return result, err
+}
+
+// getHealth 请求健康检查接口，返回解析结果与响应状态码（请求失败时为 0）
+//
+// 503 且响应体可解析时同时返回解析结果和 ErrServiceDown
+func (c *Client) getHealth(ctx context.Context, path string) (*HealthResponse, int, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, respBody, err := c.roundTrip(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// actuator 在组件不健康时返回 503，响应体仍包含状态详情
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		var result HealthResponse
+		if err := decodeJSON(respBody, &result); err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("%w: health check failed with status %d: %s", ErrServiceDown, resp.StatusCode, string(respBody))
+		}
+		return &result, resp.StatusCode, fmt.Errorf("%w: status %s", ErrServiceDown, result.Status)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("health check failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result HealthResponse
+	if err := decodeJSON(respBody, &result); err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return &result, resp.StatusCode, nil
+}
+
+// WaitOptions WaitUntilHealthy 的选项
+type WaitOptions struct {
+	// Probe 轮询的探针（默认 ProbeReadiness）
+	Probe Probe
+	// Interval 轮询间隔（默认 1 秒）
+	Interval time.Duration
+}
+
+// WaitUntilHealthy 轮询探针直到服务报告 UP，或 ctx 结束
+//
+// 默认轮询就绪探针，适合在服务启动后等待模板存储预热完成；只需确认进程已启动时使用 ProbeLiveness。
+// ctx 结束时返回最后一次检查结果与包装了 ctx.Err() 的错误
+func (c *Client) WaitUntilHealthy(ctx context.Context, opts WaitOptions) (*ProbeResult, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+	for {
+		result, err := c.probe(ctx, opts.Probe)
+		if err == nil && result.State == HealthUp {
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			return result, fmt.Errorf("docgen: %s probe not UP (last state %s): %w", opts.Probe, result.State, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package docgen
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 服务端限流响应头，每个响应都会携带
+const (
+	// RateLimitLimitHeader 当前窗口内允许的请求数
+	RateLimitLimitHeader = "X-RateLimit-Limit"
+	// RateLimitRemainingHeader 当前窗口内剩余的请求数
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader 窗口重置时间：距今秒数，或 Unix 时间戳（秒）
+	RateLimitResetHeader = "X-RateLimit-Reset"
+)
+
+// resetEpochThreshold 大于此值的 X-RateLimit-Reset 视为 Unix 时间戳而非秒数
+const resetEpochThreshold = 1_000_000_000
+
+// RateLimitStatus 最近一次响应报告的限流状态
+type RateLimitStatus struct {
+	// Limit 窗口内允许的请求数，服务端未提供时为 0
+	Limit int `json:"limit"`
+	// Remaining 窗口内剩余的请求数
+	Remaining int `json:"remaining"`
+	// Reset 窗口重置时间，服务端未提供时为零值
+	Reset time.Time `json:"reset"`
+	// Observed 收到该响应的时间
+	Observed time.Time `json:"observed"`
+}
+
+// rateLimitTracker 保存最近一次限流状态，可并发读写
+type rateLimitTracker struct {
+	status atomic.Pointer[RateLimitStatus]
+	// next WithAdaptiveThrottle 下一个可用的发送时间（UnixNano）
+	next atomic.Int64
+}
+
+// observe 从响应头更新限流状态，响应不含 X-RateLimit-Remaining（或取值无法解析）时保持不变
+//
+// X-RateLimit-Limit 无法解析时 Limit 为 0；X-RateLimit-Reset 缺失或无法解析时按 Retry-After（秒数或 HTTP 日期）
+// 计算重置时间，均无法解析时 Reset 为零值
+func (t *rateLimitTracker) observe(h http.Header, now time.Time) {
+	remaining, err := strconv.Atoi(strings.TrimSpace(h.Get(RateLimitRemainingHeader)))
+	if err != nil {
+		return
+	}
+	status := &RateLimitStatus{Remaining: remaining, Observed: now}
+	status.Limit, _ = strconv.Atoi(strings.TrimSpace(h.Get(RateLimitLimitHeader)))
+	if reset, err := strconv.ParseInt(strings.TrimSpace(h.Get(RateLimitResetHeader)), 10, 64); err == nil && reset >= 0 {
+		if reset > resetEpochThreshold {
+			status.Reset = time.Unix(reset, 0)
+		} else {
+			status.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	} else {
+		status.Reset = retryAfter(h.Get("Retry-After"), now)
+	}
+	t.status.Store(status)
+}
+
+// retryAfter 解析 Retry-After 响应头（秒数或 HTTP 日期），无法解析时返回零值
+func retryAfter(value string, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return time.Time{}
+		}
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return at
+	}
+	return time.Time{}
+}
+
+// RateLimitStatus 返回最近一次响应报告的限流状态，尚未收到带限流响应头的响应时 ok 为 false
+//
+// 可在其他 goroutine 中调用，用于按剩余配额调整并发数
+func (c *Client) RateLimitStatus() (status RateLimitStatus, ok bool) {
+	if p := c.rateLimit.status.Load(); p != nil {
+		return *p, true
+	}
+	return RateLimitStatus{}, false
+}
+
+// throttleDelay 按限流状态计算发送间隔：剩余配额低于阈值时将剩余请求均匀分布到窗口重置前；
+// exhausted 为 true 表示配额已耗尽，需等待至窗口重置
+func throttleDelay(status *RateLimitStatus, threshold int, now time.Time) (d time.Duration, exhausted bool) {
+	if status == nil || status.Remaining >= threshold || status.Reset.IsZero() {
+		return 0, false
+	}
+	untilReset := status.Reset.Sub(now)
+	if untilReset <= 0 {
+		return 0, false
+	}
+	if status.Remaining <= 0 {
+		return untilReset, true
+	}
+	return untilReset / time.Duration(status.Remaining+1), false
+}
+
+// throttle 启用 WithAdaptiveThrottle 时在发送前按剩余配额等待，ctx 结束时返回 ctx.Err()
+//
+// 并发请求依次预约发送时间，相邻请求至少间隔 throttleDelay，避免同时等待后一起发出
+func (c *Client) throttle(ctx context.Context) error {
+	if c.throttleThreshold <= 0 {
+		return nil
+	}
+	now := time.Now()
+	interval, exhausted := throttleDelay(c.rateLimit.status.Load(), c.throttleThreshold, now)
+	if interval <= 0 {
+		return nil
+	}
+	at := now.Add(interval)
+	if !exhausted {
+		for {
+			prev := c.rateLimit.next.Load()
+			slot := now.UnixNano()
+			if prev > slot {
+				slot = prev
+			}
+			slot += int64(interval)
+			if c.rateLimit.next.CompareAndSwap(prev, slot) {
+				at = time.Unix(0, slot)
+				break
+			}
+		}
+	}
+	timer := time.NewTimer(at.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,181 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// assertReset 检查重置时间与 want 相差不超过 2 秒（响应头以秒为单位），want 为零值时要求 Reset 为零值
+func assertReset(t *testing.T, got, want time.Time) {
+	t.Helper()
+	if want.IsZero() != got.IsZero() {
+		t.Errorf("Reset = %v, want %v", got, want)
+		return
+	}
+	if d := got.Sub(want); d < -2*time.Second || d > 2*time.Second {
+		t.Errorf("Reset = %v, want about %v", got, want)
+	}
+}
+
+// TestRateLimitStatusFromServer 模拟服务端的固定窗口限流：每个响应的限流头更新 RateLimitStatus，
+// 超出配额时的 429 同样更新
+func TestRateLimitStatusFromServer(t *testing.T) {
+	srv := docgentest.NewServer(docgentest.WithRateLimit(2, time.Minute))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	client := docgen.NewClient(srv.URL)
+
+	if _, ok := client.RateLimitStatus(); ok {
+		t.Fatal("RateLimitStatus reported before any response")
+	}
+	start := time.Now()
+	for want := 1; want >= 0; want-- {
+		if _, err := client.GenerateWordWithMeta(context.Background(), wordReq); err != nil {
+			t.Fatal(err)
+		}
+		status, ok := client.RateLimitStatus()
+		if !ok || status.Limit != 2 || status.Remaining != want || status.Observed.Before(start) {
+			t.Fatalf("RateLimitStatus = %+v, %v; want limit 2, remaining %d", status, ok, want)
+		}
+		assertReset(t, status.Reset, start.Add(time.Minute))
+	}
+
+	_, err := client.GenerateWordWithMeta(context.Background(), wordReq)
+	var errResp *docgen.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Code != docgen.CodeRateLimited {
+		t.Fatalf("err = %v, want RATE_LIMITED", err)
+	}
+	status, _ := client.RateLimitStatus()
+	if status.Remaining != 0 || status.Limit != 2 {
+		t.Errorf("RateLimitStatus = %+v after the 429, want remaining 0", status)
+	}
+	assertReset(t, status.Reset, start.Add(time.Minute))
+}
+
+// TestRateLimitStatusHeaders 限流响应头的解析，包括畸形取值：X-RateLimit-Remaining 无法解析时保持之前的状态，
+// X-RateLimit-Limit 无法解析时为 0，X-RateLimit-Reset 无法解析时按 Retry-After 计算，都无法解析时为零值
+func TestRateLimitStatusHeaders(t *testing.T) {
+	retryAt := time.Now().Add(45 * time.Second).UTC().Truncate(time.Second)
+	// withRetryAfter 返回携带指定限流头与 Retry-After 的 429 响应
+	withRetryAfter := func(remaining, reset, after string) docgentest.Response {
+		resp := docgentest.ErrorResponse(http.StatusTooManyRequests, docgen.CodeRateLimited, "too many requests")
+		for name, value := range map[string]string{docgen.RateLimitRemainingHeader: remaining, docgen.RateLimitResetHeader: reset, "Retry-After": after} {
+			if value != "" {
+				resp.Header.Set(name, value)
+			}
+		}
+		return resp
+	}
+
+	// 每个用例先收到 limit 100、remaining 50、60 秒后重置的状态，再收到用例的响应
+	previous := docgen.RateLimitStatus{Limit: 100, Remaining: 50}
+	tests := []struct {
+		name string
+		resp docgentest.Response
+		want docgen.RateLimitStatus
+		// reset 重置时间距今的时长，noReset 表示 Reset 为零值，unchanged 表示保持之前的状态
+		reset              time.Duration
+		noReset, unchanged bool
+	}{
+		{"seconds", docgentest.RateLimitHeaders("10", "4", "30"), docgen.RateLimitStatus{Limit: 10, Remaining: 4}, 30 * time.Second, false, false},
+		{"whitespace", docgentest.RateLimitHeaders(" 10 ", " 4 ", " 30 "), docgen.RateLimitStatus{Limit: 10, Remaining: 4}, 30 * time.Second, false, false},
+		{"zero reset", docgentest.RateLimitHeaders("10", "4", "0"), docgen.RateLimitStatus{Limit: 10, Remaining: 4}, 0, false, false},
+		{"missing limit", docgentest.RateLimitHeaders("", "4", "30"), docgen.RateLimitStatus{Remaining: 4}, 30 * time.Second, false, false},
+		{"malformed limit", docgentest.RateLimitHeaders("ten", "4", "30"), docgen.RateLimitStatus{Remaining: 4}, 30 * time.Second, false, false},
+		{"negative remaining", docgentest.RateLimitHeaders("10", "-1", "30"), docgen.RateLimitStatus{Limit: 10, Remaining: -1}, 30 * time.Second, false, false},
+		{"missing reset", docgentest.RateLimitHeaders("10", "4", ""), docgen.RateLimitStatus{Limit: 10, Remaining: 4}, 0, true, false},
+		{"malformed reset", docgentest.RateLimitHeaders("10", "4", "soon"), docgen.RateLimitStatus{Limit: 10, Remaining: 4}, 0, true, false},
+		{"negative reset", docgentest.RateLimitHeaders("10", "4", "-5"), docgen.RateLimitStatus{Limit: 10, Remaining: 4}, 0, true, false},
+		{"fractional reset", docgentest.RateLimitHeaders("10", "4", "1.5"), docgen.RateLimitStatus{Limit: 10, Remaining: 4}, 0, true, false},
+		{"missing remaining", docgentest.RateLimitHeaders("10", "", "30"), previous, 0, false, true},
+		{"malformed remaining", docgentest.RateLimitHeaders("10", "many", "30"), previous, 0, false, true},
+		{"rate limited", docgentest.RateLimited(20 * time.Second), docgen.RateLimitStatus{}, 20 * time.Second, false, false},
+		{"Retry-After seconds", withRetryAfter("0", "", "15"), docgen.RateLimitStatus{}, 15 * time.Second, false, false},
+		{"Retry-After after a malformed reset", withRetryAfter("0", "later", "15"), docgen.RateLimitStatus{}, 15 * time.Second, false, false},
+		{"malformed Retry-After", withRetryAfter("0", "", "soon"), docgen.RateLimitStatus{}, 0, true, false},
+		{"Retry-After without remaining", withRetryAfter("", "", "15"), previous, 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, docgentest.RateLimitHeaders("100", "50", "60"), tt.resp))
+			defer srv.Close()
+			srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+			client := docgen.NewClient(srv.URL)
+
+			if _, err := client.GenerateWordWithMeta(context.Background(), wordReq); err != nil {
+				t.Fatal(err)
+			}
+			before, _ := client.RateLimitStatus()
+			now := time.Now()
+			client.GenerateWordWithMeta(context.Background(), wordReq)
+			status, ok := client.RateLimitStatus()
+			if !ok || status.Limit != tt.want.Limit || status.Remaining != tt.want.Remaining {
+				t.Errorf("RateLimitStatus = %+v, want limit %d remaining %d", status, tt.want.Limit, tt.want.Remaining)
+			}
+			switch {
+			case tt.unchanged:
+				if status != before {
+					t.Errorf("RateLimitStatus = %+v, want the previous status %+v", status, before)
+				}
+			case tt.noReset:
+				assertReset(t, status.Reset, time.Time{})
+			default:
+				assertReset(t, status.Reset, now.Add(tt.reset))
+			}
+		})
+	}
+
+	t.Run("Retry-After date", func(t *testing.T) {
+		srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, withRetryAfter("0", "", retryAt.Format(http.TimeFormat))))
+		defer srv.Close()
+		client := docgen.NewClient(srv.URL)
+		client.GenerateWordWithMeta(context.Background(), wordReq)
+		if status, _ := client.RateLimitStatus(); !status.Reset.Equal(retryAt) {
+			t.Errorf("Reset = %v, want %v", status.Reset, retryAt)
+		}
+	})
+	t.Run("epoch reset", func(t *testing.T) {
+		srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, docgentest.RateLimitHeaders("10", "4", "2000000000")))
+		defer srv.Close()
+		srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+		client := docgen.NewClient(srv.URL)
+		client.GenerateWordWithMeta(context.Background(), wordReq)
+		if status, _ := client.RateLimitStatus(); !status.Reset.Equal(time.Unix(2000000000, 0)) {
+			t.Errorf("Reset = %v, want the Unix timestamp", status.Reset)
+		}
+	})
+}
+
+// TestRateLimitStatusConcurrent 并发请求更新状态时可在其他 goroutine 中读取（配合 -race 运行）
+func TestRateLimitStatusConcurrent(t *testing.T) {
+	srv := docgentest.NewServer(docgentest.WithRateLimit(1000, time.Minute))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	client := docgen.NewClient(srv.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.GenerateWordWithMeta(context.Background(), wordReq)
+		}()
+		go func() {
+			defer wg.Done()
+			if status, ok := client.RateLimitStatus(); ok && (status.Limit != 1000 || status.Remaining < 992) {
+				t.Errorf("RateLimitStatus = %+v", status)
+			}
+		}()
+	}
+	wg.Wait()
+	if status, ok := client.RateLimitStatus(); !ok || status.Remaining < 992 || status.Remaining > 999 {
+		t.Errorf("RateLimitStatus = %+v, %v after 8 requests", status, ok)
+	}
+}
@@ -0,0 +1,109 @@
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue 脱敏后替换原值的占位符
+const RedactedValue = "[REDACTED]"
+
+// Redactor 按键名脱敏 JSON 内容，用于日志、调试输出与错误信息
+//
+// 匹配键的值（包括对象与数组）整体替换为 "[REDACTED]"，其余结构保持不变，
+// 嵌套 map、DataList 条目与 ListData 行中的键同样生效
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor 创建脱敏器
+//
+// keys: 键名或正则表达式，按完整键名匹配且不区分大小写，如 "idCard"、"salary"、".*phone.*"；
+// 不是合法正则表达式的值按字面键名匹配
+func NewRedactor(keys ...string) *Redactor {
+	r := &Redactor{}
+	for _, key := range keys {
+		re, err := regexp.Compile("^(?i:" + key + ")$")
+		if err != nil {
+			re = regexp.MustCompile("^(?i:" + regexp.QuoteMeta(key) + ")$")
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r
+}
+
+// matches 判断键名是否需要脱敏
+func (r *Redactor) matches(key string) bool {
+	for _, re := range r.patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact 脱敏 JSON 内容，返回新的字节切片
+//
+// 完整的 JSON 按结构替换；无法解析的内容（如截断的错误响应片段）按 "key": value 文本模式替换标量值。
+// r 为 nil 或未配置键名时原样返回
+func (r *Redactor) Redact(body []byte) []byte {
+	if r == nil || len(r.patterns) == 0 || len(body) == 0 {
+		return body
+	}
+
+	var doc any
+	if err := decodeJSON(body, &doc); err == nil {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(r.redactValue(doc)); err == nil {
+			return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+		}
+	}
+	return r.redactText(body)
+}
+
+// redactValue 递归替换匹配键的值
+func (r *Redactor) redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			if r.matches(k) {
+				out[k] = RedactedValue
+			} else {
+				out[k] = r.redactValue(item)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.redactValue(item)
+		}
+		return out
+	}
+	return v
+}
+
+// jsonPairPattern 匹配文本中的 "key": 标量值
+var jsonPairPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"?|-?[0-9][0-9.eE+-]*|true|false|null)`)
+
+// redactText 对无法解析的 JSON 片段按文本模式脱敏
+func (r *Redactor) redactText(body []byte) []byte {
+	return jsonPairPattern.ReplaceAllFunc(body, func(m []byte) []byte {
+		sub := jsonPairPattern.FindSubmatch(m)
+		if !r.matches(string(sub[1])) {
+			return m
+		}
+		var out strings.Builder
+		out.WriteByte('"')
+		out.Write(sub[1])
+		out.WriteByte('"')
+		out.Write(sub[2])
+		out.WriteString(`"` + RedactedValue + `"`)
+		return []byte(out.String())
+	})
+}
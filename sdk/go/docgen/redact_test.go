@@ -0,0 +1,210 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// 脱敏测试使用的敏感值，任何输出中都不应出现
+const (
+	secretIDCard = "110101199003077777"
+	secretPhone  = "13800138000"
+)
+
+// redactedKeys 脱敏测试的键名：字面键名与正则表达式各一个
+var redactedKeys = []string{"idCard", ".*phone.*"}
+
+// assertNoSecret 检查 out 不包含敏感值
+func assertNoSecret(t *testing.T, where, out string) {
+	t.Helper()
+	for _, secret := range []string{secretIDCard, secretPhone} {
+		if strings.Contains(out, secret) {
+			t.Errorf("%s leaks %q: %s", where, secret, out)
+		}
+	}
+}
+
+// assertRedacted 检查 out 不包含敏感值，且包含 "[REDACTED]"（脱敏而不是丢弃）
+func assertRedacted(t *testing.T, where, out string) {
+	t.Helper()
+	assertNoSecret(t, where, out)
+	if !strings.Contains(out, docgen.RedactedValue) {
+		t.Errorf("%s has no %s placeholder: %s", where, docgen.RedactedValue, out)
+	}
+}
+
+// readDumps 返回调试目录中全部调试文件的内容
+func readDumps(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "docgen-*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dumps []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dumps = append(dumps, string(data))
+	}
+	return dumps
+}
+
+// TestRedactRequestDump 调试文件中的请求 JSON 按键名脱敏：嵌套 map、DataList 条目与 ListData 行中的匹配键
+// 替换为 "[REDACTED]"，其余字段保留
+func TestRedactRequestDump(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	srv.AddTemplate("t.xlsx", docgentest.MinimalXlsx())
+	ctx := context.Background()
+	person := func() map[string]any {
+		return map[string]any{"name": "Alice", "idCard": secretIDCard, "contact": map[string]any{"mobilePhone": secretPhone}}
+	}
+
+	calls := map[string]func(client *docgen.Client) error{
+		"nested map": func(client *docgen.Client) error {
+			_, err := client.GenerateWordWithMeta(ctx, docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"applicant": person()}})
+			return err
+		},
+		"DataList": func(client *docgen.Client) error {
+			_, err := client.BatchGenerateWordWithMeta(ctx, docgen.WordBatchRequest{TemplateName: "t.docx", DataList: []map[string]any{person(), person()}})
+			return err
+		},
+		"ListData": func(client *docgen.Client) error {
+			_, err := client.FillExcelTemplateWithMeta(ctx, docgen.ExcelFillRequest{TemplateName: "t.xlsx", ListData: map[string][]map[string]any{"people": {person()}}})
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			client := docgen.NewClient(srv.URL, docgen.WithDebugDump(dir), docgen.WithRedactedKeys(redactedKeys...))
+			srv.ResetRequests()
+			if err := call(client); err != nil {
+				t.Fatal(err)
+			}
+			// 服务端收到的是原始数据，只有调试输出被脱敏
+			if sent := srv.LastRequest(); sent == nil || !strings.Contains(string(sent.Body), secretIDCard) {
+				t.Fatal("the request sent to the server was redacted")
+			}
+
+			// 除生成请求外还可能有能力探测等请求，全部调试文件都不能包含敏感值
+			var requests int
+			for _, dump := range readDumps(t, dir) {
+				var record struct {
+					Request struct {
+						JSON json.RawMessage `json:"json"`
+					} `json:"request"`
+				}
+				if err := json.Unmarshal([]byte(dump), &record); err != nil {
+					t.Fatal(err)
+				}
+				if len(record.Request.JSON) == 0 {
+					assertNoSecret(t, "debug file", dump)
+					continue
+				}
+				requests++
+				assertRedacted(t, "debug file", dump)
+				// 结构保持不变：未匹配的键照常记录
+				var compact bytes.Buffer
+				json.Compact(&compact, record.Request.JSON)
+				if body := compact.String(); !strings.Contains(body, `"name":"Alice"`) || !strings.Contains(body, `"mobilePhone":"[REDACTED]"`) {
+					t.Errorf("request JSON = %s, want the structure kept with only the matching values replaced", body)
+				}
+			}
+			if requests != 1 {
+				t.Errorf("%d debug files with a request body, want 1", requests)
+			}
+		})
+	}
+}
+
+// TestRedactResponseDump 调试文件中的 JSON 响应体同样按键名脱敏
+func TestRedactResponseDump(t *testing.T) {
+	srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, docgentest.JSON(http.StatusUnprocessableEntity, map[string]any{
+		"status": http.StatusUnprocessableEntity, "code": docgen.CodeValidationError, "message": "invalid applicant",
+		"details": map[string]any{"field": "applicant.idCard", "idCard": secretIDCard, "homePhone": secretPhone},
+	})))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	dir := t.TempDir()
+	client := docgen.NewClient(srv.URL, docgen.WithDebugDump(dir), docgen.WithRedactedKeys(redactedKeys...))
+
+	_, err := client.GenerateWordWithMeta(context.Background(), wordReq)
+	if err == nil {
+		t.Fatal("expected the validation error")
+	}
+	dumps := readDumps(t, dir)
+	if len(dumps) != 1 {
+		t.Fatalf("%d debug files, want 1", len(dumps))
+	}
+	assertRedacted(t, "debug file", dumps[0])
+	if !strings.Contains(dumps[0], "applicant.idCard") {
+		t.Errorf("debug file dropped the unmatched fields: %s", dumps[0])
+	}
+}
+
+// TestRedactErrorBody 无法解析为 ErrorResponse 的错误响应体（如截断的 JSON）按文本模式脱敏后放入错误信息，
+// 包括租户请求的 403
+func TestRedactErrorBody(t *testing.T) {
+	body := `{"code":"BAD_GATEWAY","applicant":{"idCard":"` + secretIDCard + `","workPhone":` + secretPhone + `,"name":"Al`
+	tests := []struct {
+		name   string
+		status int
+		opts   []docgen.Option
+	}{
+		{"status error", http.StatusBadGateway, nil},
+		{"tenant forbidden", http.StatusForbidden, []docgen.Option{docgen.WithTenant("acme")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, docgentest.Malformed(tt.status, body)))
+			defer srv.Close()
+			srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+			client := docgen.NewClient(srv.URL, append(tt.opts, docgen.WithRedactedKeys(redactedKeys...))...)
+
+			_, err := client.GenerateWordWithMeta(context.Background(), wordReq)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			assertRedacted(t, "error", err.Error())
+			if !strings.Contains(err.Error(), `"name":"Al`) {
+				t.Errorf("error %q dropped the unmatched part of the body", err)
+			}
+		})
+	}
+}
+
+// TestRedactWarningHandler 简化渲染重试的 WarningDegradedOutput 以原错误为 Message，交给 WithWarningHandler 时已脱敏
+func TestRedactWarningHandler(t *testing.T) {
+	body := `{"code":"OVERLOADED","applicant":{"idCard":"` + secretIDCard + `","mobilePhone":"` + secretPhone + `"`
+	srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord, docgentest.Malformed(http.StatusServiceUnavailable, body)))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	var warnings []docgen.Warning
+	client := docgen.NewClient(srv.URL, docgen.WithDegradedMode(), docgen.WithRedactedKeys(redactedKeys...),
+		docgen.WithWarningHandler(func(op string, w docgen.Warning) { warnings = append(warnings, w) }))
+
+	result, err := client.GenerateWordWithMeta(context.Background(), wordReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Meta.Degraded || len(warnings) != 1 || warnings[0].Code != docgen.WarningDegradedOutput {
+		t.Fatalf("Degraded = %v, warnings %+v; want one %s warning", result.Meta.Degraded, warnings, docgen.WarningDegradedOutput)
+	}
+	assertRedacted(t, "warning", warnings[0].String())
+	for _, w := range result.Meta.Warnings {
+		assertRedacted(t, "Meta.Warnings", w.String())
+	}
+}
@@ -0,0 +1,162 @@
+package docgen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// ErrResultRedirected 服务端以 303 See Other 将结果指向其他位置（如对象存储的预签名 URL），而 SDK 未跟随
+//
+// 具体错误类型为 *ResultLocation，启用 WithNoRedirectFollow 时生成与下载方法返回该错误
+var ErrResultRedirected = errors.New("docgen: result redirected")
+
+// ResultLocation 结果所在的位置，由服务端的 303 See Other 响应给出
+//
+// errors.Is(err, ErrResultRedirected) 返回 true；URL 通常为带签名的临时地址，可直接交给浏览器或其他服务下载，
+// 下载时不需要（也不应携带）访问文档服务的凭证。错误信息中只包含主机名，不包含签名
+type ResultLocation struct {
+	// URL 结果地址（已按请求地址解析相对路径）
+	URL string
+	// SHA256 服务端在 303 响应中提供的内容摘要（见 responseSHA256），未提供时为空
+	SHA256 string
+	// FileName 服务端建议的文件名（Content-Disposition），未提供时为空
+	FileName string
+	// Warnings 服务端报告的非致命问题（X-Render-Warnings）
+	Warnings []Warning
+	// CorrelationID 请求的关联 ID
+	CorrelationID string
+}
+
+// Error 实现 error 接口
+func (l *ResultLocation) Error() string {
+	host := "another location"
+	if u, err := url.Parse(l.URL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return fmt.Sprintf("%v: result available at %s", ErrResultRedirected, host)
+}
+
+// Is 使 errors.Is(err, ErrResultRedirected) 成立
+func (l *ResultLocation) Is(target error) bool {
+	return target == ErrResultRedirected
+}
+
+// newResultLocation 从 303 响应构建 ResultLocation，缺少 Location 时返回 nil
+func newResultLocation(req *http.Request, resp *http.Response) *ResultLocation {
+	loc, err := resp.Location()
+	if err != nil {
+		return nil
+	}
+	l := &ResultLocation{
+		URL:           loc.String(),
+		SHA256:        responseSHA256(resp.Header),
+		Warnings:      headerWarnings(resp.Header),
+		CorrelationID: requestCorrelationID(req),
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		l.FileName = params["filename"]
+	}
+	return l
+}
+
+// errResultLocation 标记获取 303 指向的结果时的传输层错误，这类错误不影响服务的健康状态
+var errResultLocation = errors.New("fetch result location")
+
+// locationOnlyHeaders 303 响应中描述其自身而非结果的响应头，不合并到结果响应
+var locationOnlyHeaders = []string{
+	"Location", "Content-Length", "Content-Type", "Content-Encoding", "Transfer-Encoding",
+	"Connection", "Date", "Set-Cookie",
+}
+
+// redirectClient 返回在 303 See Other 处停止的 HTTPClient 副本，由 followResultLocation 处理 303；
+// 其他重定向仍按 HTTPClient.CheckRedirect（未设置时最多 10 次）处理
+func (c *Client) redirectClient() *http.Client {
+	client := *c.HTTPClient
+	next := client.CheckRedirect
+	client.CheckRedirect = func(r *http.Request, via []*http.Request) error {
+		if r.Response != nil && r.Response.StatusCode == http.StatusSeeOther {
+			return http.ErrUseLastResponse
+		}
+		if next != nil {
+			return next(r, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &client
+}
+
+// followResultLocation 跟随 303 See Other 获取结果，其他响应与启用 WithNoRedirectFollow 时原样返回
+//
+// 同源的地址沿用原请求的请求头；跨源的地址（如对象存储的预签名 URL）只发送 Accept，不携带 Authorization、
+// 租户、审计与关联 ID 等请求头。303 响应中的摘要、文件名与警告等响应头在结果响应未提供时合并过去，
+// 使摘要校验与文档元数据照常工作；结果响应的 Request 为跟随的请求，其 Response 为 303 响应（与 net/http 一致）
+func (c *Client) followResultLocation(client *http.Client, req *http.Request, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusSeeOther || c.noRedirectFollow {
+		return resp, nil
+	}
+	loc, err := resp.Location()
+	if err != nil {
+		// 缺少 Location 时交由调用方按非 2xx 响应处理
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	target, err := http.NewRequestWithContext(req.Context(), http.MethodGet, loc.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", errResultLocation, loc.Host, err)
+	}
+	if sameOrigin(req.URL, loc) {
+		target.Header = req.Header.Clone()
+		target.Header.Del("Content-Type")
+	} else if accept := req.Header.Get("Accept"); accept != "" {
+		target.Header.Set("Accept", accept)
+	}
+	target.Response = resp
+
+	final, err := client.Do(target)
+	if err != nil {
+		// *url.Error 的错误信息包含完整 URL（含签名），只保留底层错误
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("%w %s: %w", errResultLocation, loc.Host, err)
+	}
+	for name, values := range resp.Header {
+		if _, ok := final.Header[name]; ok || containsString(locationOnlyHeaders, name) {
+			continue
+		}
+		final.Header[name] = values
+	}
+	return final, nil
+}
+
+// sameOrigin 两个地址的协议与主机（含端口）是否相同
+func sameOrigin(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && a.Host == b.Host
+}
+
+// originalRequest 返回经过重定向的响应对应的原始请求
+func originalRequest(resp *http.Response) *http.Request {
+	req := resp.Request
+	for req != nil && req.Response != nil && req.Response.Request != nil {
+		req = req.Response.Request
+	}
+	return req
+}
+
+// resultURL 结果实际所在的地址，未经重定向时为空
+func resultURL(resp *http.Response) string {
+	if resp.Request == nil || resp.Request == originalRequest(resp) {
+		return ""
+	}
+	return resp.Request.URL.String()
+}
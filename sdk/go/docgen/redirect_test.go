@@ -0,0 +1,212 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// storageRequests 返回模拟服务器自身的对象存储收到的请求（同源重定向）
+func storageRequests(srv *docgentest.Server) []*docgentest.CapturedRequest {
+	var out []*docgentest.CapturedRequest
+	for _, r := range srv.RequestsTo(docgentest.AnyEndpoint) {
+		if strings.HasPrefix(r.Path, docgentest.EndpointStorage) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+var redirectReq = docgen.WordGenRequest{TemplateName: "t.docx", Data: map[string]any{"name": "张三"}, FileName: "report"}
+
+// TestResultRedirectFollowed 生成结果以 303 See Other 返回时，缓冲与流式生成都跟随 Location 下载文档；
+// 同源地址沿用原请求的凭证，跨源的预签名地址不携带 Authorization 与租户等请求头，
+// 303 响应中的摘要与文件名照常用于校验与元数据
+func TestResultRedirectFollowed(t *testing.T) {
+	calls := map[string]func(client *docgen.Client) ([]byte, *docgen.DocumentMeta, error){
+		"buffered": func(client *docgen.Client) ([]byte, *docgen.DocumentMeta, error) {
+			result, err := client.GenerateWordWithMeta(context.Background(), redirectReq)
+			if err != nil {
+				return nil, nil, err
+			}
+			return result.Data, &result.Meta, nil
+		},
+		"streaming": func(client *docgen.Client) ([]byte, *docgen.DocumentMeta, error) {
+			var buf bytes.Buffer
+			meta, err := client.GenerateWordTo(context.Background(), redirectReq, &buf)
+			return buf.Bytes(), meta, err
+		},
+	}
+	for _, origin := range []string{"same-origin", "cross-origin"} {
+		for name, call := range calls {
+			t.Run(origin+"/"+name, func(t *testing.T) {
+				var store *docgentest.ObjectStore
+				var srv *docgentest.Server
+				if origin == "same-origin" {
+					srv = docgentest.NewServer(docgentest.WithResultRedirects())
+				} else {
+					store = docgentest.NewObjectStore()
+					defer store.Close()
+					srv = docgentest.NewServer(docgentest.WithPresignedResults(store))
+				}
+				defer srv.Close()
+				srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+				client := docgen.NewClient(srv.URL, docgen.WithAPIKey("secret-key"), docgen.WithTenant("acme"))
+
+				doc, meta, err := call(client)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if text, err := docgentest.ExtractDocxText(doc); err != nil || !strings.Contains(text, "张三") {
+					t.Errorf("document text = %q, %v; want the rendered template", text, err)
+				}
+				sum := sha256.Sum256(doc)
+				if meta.SHA256 != hex.EncodeToString(sum[:]) || meta.FileName != "report.docx" {
+					t.Errorf("Meta SHA256=%s FileName=%q, want the 303 response's digest and file name", meta.SHA256, meta.FileName)
+				}
+
+				fetched := storageRequests(srv)
+				base := srv.URL
+				if store != nil {
+					fetched = store.Requests()
+					base = store.URL
+				}
+				if len(fetched) != 1 {
+					t.Fatalf("result fetched %d times, want 1", len(fetched))
+				}
+				if !strings.HasPrefix(meta.ResultURL, base+docgentest.EndpointStorage) {
+					t.Errorf("ResultURL = %q, want the location under %s", meta.ResultURL, base)
+				}
+				header := fetched[0].Header
+				if store != nil {
+					for _, h := range []string{"Authorization", docgen.TenantHeader, docgen.CorrelationIDHeader} {
+						if v := header.Get(h); v != "" {
+							t.Errorf("cross-origin fetch carries %s: %q", h, v)
+						}
+					}
+				} else if header.Get("Authorization") == "" || header.Get(docgen.TenantHeader) != "acme" {
+					t.Errorf("same-origin fetch dropped the credentials: %v", header)
+				}
+			})
+		}
+	}
+}
+
+// TestResultRedirectChecksum 跨源地址返回的内容与 303 响应中的摘要不一致时返回 *ChecksumMismatchError
+func TestResultRedirectChecksum(t *testing.T) {
+	doc := docgentest.MinimalDocx("hello")
+	sum := sha256.Sum256(doc)
+	tampered := append([]byte(nil), doc...)
+	tampered[len(tampered)/2] ^= 0xff
+
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(tampered)
+	}))
+	defer store.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-SHA256", hex.EncodeToString(sum[:]))
+		w.Header().Set("Location", store.URL+"/storage/result-1?X-Signature=abc")
+		w.WriteHeader(http.StatusSeeOther)
+	}))
+	defer srv.Close()
+	client := docgen.NewClient(srv.URL)
+
+	calls := map[string]func() error{
+		"buffered": func() error {
+			_, err := client.GenerateWordWithMeta(context.Background(), wordReq)
+			return err
+		},
+		"streaming": func() error {
+			_, err := client.GenerateWordTo(context.Background(), wordReq, &bytes.Buffer{})
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+			var mismatch *docgen.ChecksumMismatchError
+			if !errors.Is(err, docgen.ErrChecksumMismatch) || !errors.As(err, &mismatch) {
+				t.Fatalf("err = %v, want *ChecksumMismatchError", err)
+			}
+			if mismatch.Expected != hex.EncodeToString(sum[:]) {
+				t.Errorf("Expected = %s, want the 303 response's digest", mismatch.Expected)
+			}
+		})
+	}
+}
+
+// TestResultRedirectNotFollowed WithNoRedirectFollow 不下载结果，返回包含地址、摘要与文件名的 *ResultLocation
+func TestResultRedirectNotFollowed(t *testing.T) {
+	for _, origin := range []string{"same-origin", "cross-origin"} {
+		t.Run(origin, func(t *testing.T) {
+			var store *docgentest.ObjectStore
+			var srv *docgentest.Server
+			if origin == "same-origin" {
+				srv = docgentest.NewServer(docgentest.WithResultRedirects())
+			} else {
+				store = docgentest.NewObjectStore()
+				defer store.Close()
+				srv = docgentest.NewServer(docgentest.WithPresignedResults(store))
+			}
+			defer srv.Close()
+			srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+			client := docgen.NewClient(srv.URL, docgen.WithNoRedirectFollow())
+
+			var buf bytes.Buffer
+			_, err := client.GenerateWordTo(context.Background(), redirectReq, &buf)
+			var loc *docgen.ResultLocation
+			if !errors.Is(err, docgen.ErrResultRedirected) || !errors.As(err, &loc) {
+				t.Fatalf("err = %v, want *ResultLocation", err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("wrote %d bytes, want none", buf.Len())
+			}
+			if errors.Is(err, docgen.ErrUnreachable) || docgen.IsRetryable(err) {
+				t.Errorf("a result location is reported as a failure: %v", err)
+			}
+
+			base := srv.URL
+			fetched := len(storageRequests(srv))
+			if store != nil {
+				base = store.URL
+				fetched = len(store.Requests())
+			}
+			if fetched != 0 {
+				t.Errorf("result fetched %d times, want 0", fetched)
+			}
+			if !strings.HasPrefix(loc.URL, base+docgentest.EndpointStorage) || !strings.Contains(loc.URL, "X-Signature=") {
+				t.Errorf("URL = %q, want the absolute presigned location under %s", loc.URL, base)
+			}
+			if len(loc.SHA256) != 64 || loc.FileName != "report.docx" || loc.CorrelationID == "" {
+				t.Errorf("ResultLocation = %+v, want the digest, file name and correlation id", loc)
+			}
+			// 错误信息只包含主机名，不泄露签名
+			if msg := err.Error(); strings.Contains(msg, "X-Signature") {
+				t.Errorf("error %q leaks the signature", msg)
+			}
+
+			// 按地址下载的内容与摘要一致
+			resp, err := http.Get(loc.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var got bytes.Buffer
+			got.ReadFrom(resp.Body)
+			if sum := sha256.Sum256(got.Bytes()); fmt.Sprintf("%x", sum) != loc.SHA256 {
+				t.Errorf("downloaded %d bytes with digest %x, want %s", got.Len(), sum, loc.SHA256)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// newRequest 构建 HTTP 请求
+//
+// path: API 路径（已完成转义），如 "/api/v1/template/list"；前缀与版本按 WithAPIPrefix / WithAPIVersion 替换
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	if err := c.checkOpen(ctx); err != nil {
+		return nil, err
+	}
+	url, err := c.resolveURL(path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(c.withDebugRecord(ctx), method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if tenant := c.tenantFor(ctx); tenant != "" {
+		req.Header.Set(TenantHeader, tenant)
+	}
+	if locale := c.localeFor(ctx); locale != "" {
+		req.Header.Set("Accept-Language", locale)
+	}
+	if key := idempotencyKeyFor(ctx); key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if id := correlationIDFor(ctx); id != "" {
+		req.Header.Set(CorrelationIDHeader, id)
+	}
+	c.setAuditHeaders(req)
+	return req, nil
+}
+
+// roundTrip 发送请求并读取完整响应体（共享请求路径，不检查状态码）
+//
+// 超时错误统一包装为 *TimeoutError，其他传输层错误包装为 ErrUnreachable；启用 WithDebugDump 时写入调试文件
+func (c *Client) roundTrip(req *http.Request) (*http.Response, []byte, error) {
+	if err := c.throttle(req.Context()); err != nil {
+		return nil, nil, err
+	}
+	start := time.Now()
+	stats := c.stats.begin(req)
+	var resp *http.Response
+	var respBody []byte
+	var err error
+	var outcome hedgeOutcome
+	if c.hedgeable(req) {
+		var sent bufferedResponse
+		var cancel context.CancelFunc
+		sent, outcome, cancel, err = hedge(c, req, func(r *http.Request) (bufferedResponse, error) {
+			resp, body, err := c.send(r, start)
+			return bufferedResponse{resp, body}, err
+		}, func(bufferedResponse) {})
+		if cancel != nil {
+			cancel()
+		}
+		resp, respBody = sent.resp, sent.body
+	} else {
+		resp, respBody, err = c.send(req, start)
+	}
+	stats.end(req, resp, err, outcome)
+	c.emitMetrics(req, start, resp, err, outcome)
+	c.dumpExchange(req, start, resp, respBody, err)
+	if err != nil {
+		return nil, nil, requestError(req, err)
+	}
+	return resp, respBody, nil
+}
+
+// bufferedResponse 已读取完整响应体的响应
+type bufferedResponse struct {
+	resp *http.Response
+	body []byte
+}
+
+// send 发送请求并读取完整响应体
+//
+// 303 See Other 由 followResultLocation 跟随
+func (c *Client) send(req *http.Request, start time.Time) (*http.Response, []byte, error) {
+	client := c.redirectClient()
+	resp, err := client.Do(req)
+	if err == nil {
+		c.rateLimit.observe(resp.Header, time.Now())
+		resp, err = c.followResultLocation(client, req, resp)
+	}
+	if err != nil {
+		if canceled := canceledError(req); canceled != nil {
+			return nil, nil, canceled
+		}
+		if isTimeout(err) {
+			return nil, nil, newTimeoutError(req, start, err)
+		}
+		if !errors.Is(err, errResultLocation) {
+			c.healthCache.observeUnreachable()
+		}
+		return nil, nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if canceled := canceledError(req); canceled != nil {
+			return nil, nil, canceled
+		}
+		// 客户端超时可能在读取响应体的过程中触发
+		if isTimeout(err) {
+			return nil, nil, newTimeoutError(req, start, err)
+		}
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return resp, respBody, nil
+}
+
+// canceledError 调用方取消了请求时返回 ctx.Err()，原样返回而不包装为 ErrUnreachable，IsRetryable 为 false；
+// 超过 ctx 截止时间按超时处理（*TimeoutError，errors.Is(err, context.DeadlineExceeded) 仍成立），此时返回 nil
+func canceledError(req *http.Request) error {
+	if err := req.Context().Err(); errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// execute 发送请求并返回响应体，非 2xx 响应转换为错误
+func (c *Client) execute(req *http.Request) ([]byte, error) {
+	_, respBody, err := c.executeResponse(req)
+	return respBody, err
+}
+
+// executeResponse 发送请求并返回响应与响应体，非 2xx 响应转换为错误
+//
+// 服务端提供内容摘要（X-Content-SHA256 等，见 responseSHA256）时校验响应体，不一致时返回 *ChecksumMismatchError
+func (c *Client) executeResponse(req *http.Request) (*http.Response, []byte, error) {
+	resp, respBody, err := c.roundTrip(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, c.parseErrorResponse(req, resp, respBody)
+	}
+
+	if responseSHA256(resp.Header) != "" {
+		h := sha256.New()
+		h.Write(respBody)
+		if err := verifyChecksum(req, resp, h); err != nil {
+			return nil, nil, err
+		}
+	}
+	return resp, respBody, nil
+}
+
+// executeJSON 发送请求并将 JSON 响应解析到 result（保留整数精度）
+func (c *Client) executeJSON(req *http.Request, result any) error {
+	respBody, err := c.execute(req)
+	if err != nil {
+		return err
+	}
+
+	return decodeJSON(respBody, result)
+}
+
+// doJSON 发送 JSON 请求并将 JSON 响应解析到 result
+//
+// reqBody 为 nil 时不发送请求体；result 为 nil 时忽略响应体
+func (c *Client) doJSON(ctx context.Context, method, path string, reqBody, result any) error {
+	var body io.Reader
+	if reqBody != nil {
+		data, err := c.marshalRequest(ctx, path, reqBody)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if result == nil {
+		_, err = c.execute(req)
+		return err
+	}
+	return c.executeJSON(req, result)
+}
+
+// parseErrorResponse 解析错误响应，无法解析为 ErrorResponse 时返回包含原始响应体（已按 WithRedactedKeys 脱敏）的错误
+//
+// 携带租户头的请求返回 403 时转换为 *TenantForbiddenError，TEMPLATE_CHANGED 转换为 *TemplateChangedError；
+// 返回的错误包装为带有关联 ID（与调试文件路径）的 *OpError
+func (c *Client) parseErrorResponse(req *http.Request, resp *http.Response, respBody []byte) error {
+	err := c.errorResponse(req, resp, respBody)
+	c.stats.observeErrorResponse(req, err)
+	return requestError(req, err)
+}
+
+// errorResponse 将错误响应转换为错误，见 parseErrorResponse
+func (c *Client) errorResponse(req *http.Request, resp *http.Response, respBody []byte) error {
+	if resp.StatusCode == http.StatusSeeOther {
+		if loc := newResultLocation(req, resp); loc != nil {
+			return loc
+		}
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(respBody, &errResp); err != nil {
+		if tenant := req.Header.Get(TenantHeader); tenant != "" && resp.StatusCode == http.StatusForbidden {
+			return &TenantForbiddenError{Tenant: tenant, Err: &ErrorResponse{Status: resp.StatusCode, Code: CodeForbidden, Message: string(c.redactor.Redact(respBody)), Language: resp.Header.Get("Content-Language")}}
+		}
+		if req.Method == http.MethodHead && len(respBody) == 0 {
+			return headErrorResponse(req, resp)
+		}
+		return &statusError{status: resp.StatusCode, body: string(c.redactor.Redact(respBody))}
+	}
+	if errResp.Status == 0 {
+		errResp.Status = resp.StatusCode
+	}
+	errResp.Language = resp.Header.Get("Content-Language")
+	if tenant := req.Header.Get(TenantHeader); tenant != "" && errResp.Status == http.StatusForbidden {
+		return &TenantForbiddenError{Tenant: tenant, Err: &errResp}
+	}
+	if errResp.Code == CodeQuotaExceeded {
+		return &QuotaExceededError{Reset: quotaReset(resp.Header), Err: &errResp}
+	}
+	if errResp.Code == CodeResultExpired {
+		return &ResultExpiredError{ExpiredAt: resultExpiredAt(resp.Header), Err: &errResp}
+	}
+	if errResp.Code == CodeTemplateChanged {
+		return &TemplateChangedError{Current: strings.ToLower(resp.Header.Get(TemplateChecksumHeader)), Err: &errResp}
+	}
+	return &errResp
+}
+
+// headErrorResponse HEAD 请求的错误响应没有响应体，按状态码构造 *ErrorResponse：
+// 模板下载接口的 404 与 422 表示模板不存在（CodeTemplateNotFound），其他状态码没有错误码
+func headErrorResponse(req *http.Request, resp *http.Response) *ErrorResponse {
+	errResp := &ErrorResponse{Status: resp.StatusCode, Message: http.StatusText(resp.StatusCode), Language: resp.Header.Get("Content-Language")}
+	if (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity) &&
+		strings.Contains(req.URL.Path, "/api/v1/template/download") {
+		errResp.Code = CodeTemplateNotFound
+	}
+	return errResp
+}
+
+// statusError 响应体不是 JSON 错误（如代理返回的 HTML 错误页或空响应体）的错误响应
+type statusError struct {
+	status int
+	body   string
+}
+
+// Error 实现 error 接口
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.status, e.body)
+}
+
+// doStream 发送请求并返回未读取的响应（调用方负责关闭 Body），用于事件流与大文件下载
+//
+// 长连接不受 HTTPClient.Timeout 限制，由 ctx 控制生命周期；传输层错误的包装方式与 roundTrip 一致，
+// 303 See Other 由 followResultLocation 跟随
+func (c *Client) doStream(req *http.Request) (*http.Response, error) {
+	httpClient := c.redirectClient()
+	httpClient.Timeout = 0
+	do := func(r *http.Request) (*http.Response, error) {
+		resp, err := httpClient.Do(r)
+		if err != nil {
+			return nil, err
+		}
+		c.rateLimit.observe(resp.Header, time.Now())
+		return c.followResultLocation(httpClient, r, resp)
+	}
+
+	if err := c.throttle(req.Context()); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	stats := c.stats.begin(req)
+	var resp *http.Response
+	var err error
+	var outcome hedgeOutcome
+	if c.hedgeable(req) {
+		var cancel context.CancelFunc
+		resp, outcome, cancel, err = hedge(c, req, do, func(resp *http.Response) { resp.Body.Close() })
+		if err == nil {
+			// 调用方关闭响应体后才取消采用的请求
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		}
+	} else {
+		resp, err = do(req)
+	}
+	if err != nil {
+		if canceled := canceledError(req); canceled != nil {
+			err = canceled
+		} else if isTimeout(err) {
+			err = newTimeoutError(req, start, err)
+		} else {
+			err = fmt.Errorf("%w: %w", ErrUnreachable, err)
+		}
+		stats.end(req, nil, err, outcome)
+		c.emitMetrics(req, start, nil, err, outcome)
+		c.dumpExchange(req, start, nil, nil, err)
+		return nil, requestError(req, err)
+	}
+	stats.end(req, resp, nil, outcome)
+	c.emitMetrics(req, start, resp, nil, outcome)
+
+	if c.debugDump != nil && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		// 错误响应体较小，读出后记录并交还调用方
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(errBody))
+		c.dumpExchange(req, start, resp, errBody, nil)
+	} else {
+		c.dumpExchange(req, start, resp, nil, nil)
+	}
+	return resp, nil
+}
@@ -0,0 +1,508 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// ChecksumMismatchError 响应内容的 SHA-256 与服务端提供的摘要不一致
+//
+// errors.Is(err, ErrChecksumMismatch) 返回 true
+type ChecksumMismatchError struct {
+	// Endpoint 请求的接口路径
+	Endpoint string
+	// Expected 服务端提供的摘要（十六进制）
+	Expected string
+	// Actual 实际收到内容的摘要（十六进制）
+	Actual string
+}
+
+// Error 实现 error 接口
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("%v: %s: expected sha256 %s, got %s", ErrChecksumMismatch, e.Endpoint, e.Expected, e.Actual)
+}
+
+// Is 使 errors.Is(err, ErrChecksumMismatch) 成立
+func (e *ChecksumMismatchError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// DocumentMeta 生成或下载的文档元数据
+type DocumentMeta struct {
+	// FileName 服务端建议的文件名（Content-Disposition），未提供时为空；原样保留，用作本地文件名前应以 SanitizeFileName 清理
+	FileName string
+	// ContentType 响应的 Content-Type
+	ContentType string
+	// Size 文档大小（字节）
+	Size int64
+	// SHA256 收到内容的 SHA-256（十六进制小写），可与文档一同存档
+	SHA256 string
+	// Verified 服务端提供了摘要且与收到的内容一致
+	Verified bool
+	// Sheets Excel 生成（GenerateExcelWithMeta、GenerateExcelTo）的工作表布局，其他文档为空
+	Sheets []SheetLayout
+	// TemplateName 实际使用的模板（GenerateWordWithFallbackMeta、FillExcelTemplateWithFallbackMeta），其他文档为空
+	TemplateName string
+	// Warnings 服务端报告的非致命问题（如缺少数据的占位符、被截断的单元格、被替换的字体），
+	// 来自 multipart/mixed 响应的元数据部分或 X-Render-Warnings 响应头
+	Warnings []Warning
+	// Timings 服务端以 multipart/mixed 响应返回的各阶段耗时，如 "render"，其他响应为空
+	Timings map[string]time.Duration
+	// ImagesFetched 服务端成功下载并嵌入的 ImageURL 图片数
+	ImagesFetched int64
+	// ImagesFailed 服务端下载失败的 ImageURL 图片数（按 ImageFetchOptions.OnError 处理）
+	ImagesFailed int64
+	// CorrelationID 生成该文档的请求的关联 ID（X-Correlation-Id）
+	CorrelationID string
+	// ResultURL 服务端以 303 See Other 将结果指向其他位置（如对象存储的预签名 URL）时实际下载的地址，
+	// 未经重定向时为空；预签名 URL 含有签名，记录日志前注意脱敏
+	ResultURL string
+	// Degraded 服务端容量不足，文档由 WithDegradedMode 以简化渲染（SimpleRender）重新生成，缺少图片、图表与部分样式
+	Degraded bool
+}
+
+// DocumentResult 文档内容及其元数据
+type DocumentResult struct {
+	// Data 文档内容
+	Data []byte
+	// Meta 文档元数据
+	Meta DocumentMeta
+	// failures 批量生成（ContinueOnError）中渲染失败的条目
+	failures []ItemFailure
+}
+
+// GenerateWordWithMeta 生成 Word 文档，同时返回文件名与已校验的摘要等元数据
+func (c *Client) GenerateWordWithMeta(ctx context.Context, req WordGenRequest) (*DocumentResult, error) {
+	ctx = withCallCorrelationID(ctx)
+	if err := c.prepareWord(ctx, &req); err != nil {
+		return nil, err
+	}
+	return c.postDocument(ctx, "/api/v1/doc/word", req)
+}
+
+// BatchGenerateWordWithMeta 批量生成 Word 文档，同时返回元数据
+func (c *Client) BatchGenerateWordWithMeta(ctx context.Context, req WordBatchRequest) (*DocumentResult, error) {
+	ctx = withCallCorrelationID(ctx)
+	if len(req.Templates) > 0 {
+		return c.generateMixedBatch(ctx, req)
+	}
+	if err := c.prepareWordBatch(ctx, &req); err != nil {
+		return nil, err
+	}
+	return c.postDocument(ctx, "/api/v1/doc/word/batch", req)
+}
+
+// GenerateExcelWithMeta 生成 Excel 文档，同时返回元数据
+//
+// 数据行超过单个工作表上限时按 SplitRows 拆分，Meta.Sheets 返回各工作表的数据范围
+func (c *Client) GenerateExcelWithMeta(ctx context.Context, req ExcelGenRequest) (*DocumentResult, error) {
+	ctx = withCallCorrelationID(ctx)
+	sheets, err := c.prepareExcel(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.postDocument(ctx, "/api/v1/doc/excel", req)
+	if err != nil {
+		return nil, err
+	}
+	result.Meta.Sheets = sheets
+	return result, nil
+}
+
+// FillExcelTemplateWithMeta 填充 Excel 模板，同时返回元数据
+func (c *Client) FillExcelTemplateWithMeta(ctx context.Context, req ExcelFillRequest) (*DocumentResult, error) {
+	ctx = withCallCorrelationID(ctx)
+	if err := c.prepareFill(ctx, &req); err != nil {
+		return nil, err
+	}
+	return c.postDocument(ctx, "/api/v1/doc/excel/fill", req)
+}
+
+// GenerateWordTo 生成 Word 文档并直接写入 w，不在内存中缓存完整文档
+//
+// 写入的同时计算 SHA-256，服务端提供摘要时在写入完成后校验；返回 ErrChecksumMismatch 时
+// w 已收到全部内容，调用方应丢弃。流式写入不执行 WithOutputValidation 校验
+func (c *Client) GenerateWordTo(ctx context.Context, req WordGenRequest, w io.Writer) (*DocumentMeta, error) {
+	ctx = withCallCorrelationID(ctx)
+	if err := c.prepareWord(ctx, &req); err != nil {
+		return nil, err
+	}
+	return c.postDocumentTo(ctx, "/api/v1/doc/word", req, w)
+}
+
+// BatchGenerateWordTo 批量生成 Word 文档并直接写入 w，行为与 GenerateWordTo 一致
+func (c *Client) BatchGenerateWordTo(ctx context.Context, req WordBatchRequest, w io.Writer) (*DocumentMeta, error) {
+	ctx = withCallCorrelationID(ctx)
+	if len(req.Templates) > 0 {
+		return c.generateMixedBatchTo(ctx, req, w)
+	}
+	if err := c.prepareWordBatch(ctx, &req); err != nil {
+		return nil, err
+	}
+	return c.postDocumentTo(ctx, "/api/v1/doc/word/batch", req, w)
+}
+
+// DownloadJobResultTo 下载任务结果并直接写入 w，行为与 GenerateWordTo 一致
+func (c *Client) DownloadJobResultTo(ctx context.Context, jobID string, w io.Writer) (*DocumentMeta, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, jobPath(jobID, "/result"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	return c.streamDocument(req, w)
+}
+
+// documentData 返回 DocumentResult 中的文档内容
+func documentData(result *DocumentResult, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// prepareWord 补全租户模板名称并转换模板数据
+func (c *Client) prepareWord(ctx context.Context, req *WordGenRequest) error {
+	data, err := c.transformData("data", req.Data)
+	if err != nil {
+		return err
+	}
+	req.Data = data
+	if err := c.checkSections(ctx, "data", req.Data); err != nil {
+		return err
+	}
+	if err := c.checkContentControls(ctx, req.TemplateName, req.ContentControls); err != nil {
+		return err
+	}
+	if err := c.resolveOutputFormat(ctx, req.TemplateName, &req.OutputFormat); err != nil {
+		return err
+	}
+	if req.FontOptions, err = c.resolveFontOptions(ctx, req.FontOptions); err != nil {
+		return err
+	}
+	if err := c.precheckTemplate(ctx, req.TemplateName, req.ExpectedTemplateChecksum); err != nil {
+		return err
+	}
+	req.TemplateName = c.qualifyTemplate(ctx, req.TemplateName)
+	return nil
+}
+
+// prepareFill 转换并展开填充数据，补全租户模板名称
+func (c *Client) prepareFill(ctx context.Context, req *ExcelFillRequest) error {
+	if err := c.resolveOutputFormat(ctx, req.TemplateName, &req.OutputFormat); err != nil {
+		return err
+	}
+	if err := c.transformFill(req); err != nil {
+		return err
+	}
+	if err := c.flattenFill(req); err != nil {
+		return err
+	}
+	if err := c.precheckFill(ctx, *req); err != nil {
+		return err
+	}
+	fonts, err := c.resolveFontOptions(ctx, req.FontOptions)
+	if err != nil {
+		return err
+	}
+	req.FontOptions = fonts
+	if err := c.precheckTemplate(ctx, req.TemplateName, req.ExpectedTemplateChecksum); err != nil {
+		return err
+	}
+	req.TemplateName = c.qualifyTemplate(ctx, req.TemplateName)
+	return nil
+}
+
+// prepareWordBatch 补全批量请求的优先级与租户模板名称，并转换模板数据
+func (c *Client) prepareWordBatch(ctx context.Context, req *WordBatchRequest) error {
+	if req.Archive {
+		if err := c.rejectUnsupported(ctx, FeatureBatchArchive); err != nil {
+			return err
+		}
+	}
+	if err := c.resolveOutputFormat(ctx, req.TemplateName, &req.OutputFormat); err != nil {
+		return err
+	}
+	for _, name := range req.Templates {
+		if err := c.checkTemplateName(name); err != nil {
+			return err
+		}
+	}
+	priority, err := c.resolvePriority(req.Priority)
+	if err != nil {
+		return err
+	}
+	req.Priority = priority
+	if req.DataList, err = c.transformRows("dataList", req.DataList); err != nil {
+		return err
+	}
+	for i, data := range req.DataList {
+		if err := c.checkSections(ctx, fmt.Sprintf("dataList[%d]", i), data); err != nil {
+			return err
+		}
+	}
+	if req.FontOptions, err = c.resolveFontOptions(ctx, req.FontOptions); err != nil {
+		return err
+	}
+	if err := c.precheckTemplate(ctx, req.TemplateName, req.ExpectedTemplateChecksum); err != nil {
+		return err
+	}
+	req.TemplateName = c.qualifyTemplate(ctx, req.TemplateName)
+	if len(req.Templates) > 0 {
+		templates := make([]string, len(req.Templates))
+		for i, name := range req.Templates {
+			templates[i] = c.qualifyTemplate(ctx, name)
+		}
+		req.Templates = templates
+	}
+	return nil
+}
+
+// postDocument 发送生成请求并返回文档及元数据，启用 WithDegradedMode 时容量不足以简化渲染重试一次
+func (c *Client) postDocument(ctx context.Context, path string, reqBody any) (*DocumentResult, error) {
+	result, err := c.postDocumentOnce(ctx, path, reqBody)
+	if err == nil {
+		return result, nil
+	}
+	simple, ok := c.degradedRequest(ctx, reqBody, err)
+	if !ok {
+		return nil, err
+	}
+	result, retryErr := c.postDocumentOnce(degradedContext(ctx), path, simple)
+	if retryErr != nil {
+		return nil, degradedRetryError(err, retryErr)
+	}
+	c.markDegraded(path, &result.Meta, err)
+	return result, nil
+}
+
+// postDocumentOnce 发送一次生成请求并返回文档及元数据
+func (c *Client) postDocumentOnce(ctx context.Context, path string, reqBody any) (*DocumentResult, error) {
+	httpReq, err := c.newDocumentRequest(ctx, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	// 发送请求，错误响应与摘要校验由共享请求路径统一处理
+	resp, doc, err := c.executeResponse(httpReq)
+	if err != nil {
+		return nil, annotateTemplateChanged(err, reqBody)
+	}
+
+	// multipart/mixed 响应包含文档与渲染元数据
+	var part *documentPart
+	if boundary, ok := multipartBoundary(resp); ok {
+		var buf bytes.Buffer
+		if part, err = c.readMultipartDocument(httpReq, bytes.NewReader(doc), boundary, &buf, func() []byte { return doc }); err != nil {
+			return nil, requestError(httpReq, err)
+		}
+		doc = buf.Bytes()
+	}
+
+	if c.validateOutput {
+		if format := formatForPath(path); format != "" {
+			if err := ValidateDocument(doc, format); err != nil {
+				var contentErr *UnexpectedContentError
+				if errors.As(err, &contentErr) {
+					contentErr.Prefix = string(c.redactor.Redact([]byte(contentErr.Prefix)))
+				}
+				return nil, requestError(httpReq, err)
+			}
+		}
+	}
+
+	sum := sha256.Sum256(doc)
+	meta := newDocumentMeta(resp, int64(len(doc)), hex.EncodeToString(sum[:]))
+	result := &DocumentResult{Data: doc, Meta: meta}
+	if part != nil {
+		part.applyTo(&result.Meta)
+		if part.metadata != nil {
+			result.failures = part.metadata.Failures
+		}
+	}
+	if result.failures == nil {
+		if result.failures, err = c.fetchBatchReport(ctx, resp); err != nil {
+			return nil, requestError(httpReq, err)
+		}
+	}
+	if err := c.reportWarnings(httpReq, result.Meta.Warnings); err != nil {
+		return nil, requestError(httpReq, err)
+	}
+	if c.needsNormalize(ctx, reqBody) {
+		if err := normalizeResult(result); err != nil {
+			return nil, requestError(httpReq, err)
+		}
+	}
+	return result, nil
+}
+
+// postDocumentTo 发送生成请求并将文档流式写入 w
+func (c *Client) postDocumentTo(ctx context.Context, path string, reqBody any, w io.Writer) (*DocumentMeta, error) {
+	if c.needsNormalize(ctx, reqBody) {
+		// 规范化需要完整的文档，先在内存中生成再写入 w
+		result, err := c.postDocument(ctx, path, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(result.Data); err != nil {
+			return nil, fmt.Errorf("failed to write document: %w", err)
+		}
+		return &result.Meta, nil
+	}
+	meta, err := c.postDocumentStream(ctx, path, reqBody, w)
+	if err == nil {
+		return meta, nil
+	}
+	// 容量不足的错误响应在写入 w 之前返回，可以安全地重新生成
+	simple, ok := c.degradedRequest(ctx, reqBody, err)
+	if !ok {
+		return nil, err
+	}
+	meta, retryErr := c.postDocumentStream(degradedContext(ctx), path, simple, w)
+	if retryErr != nil {
+		return nil, degradedRetryError(err, retryErr)
+	}
+	c.markDegraded(path, meta, err)
+	return meta, nil
+}
+
+// postDocumentStream 发送一次生成请求并将文档流式写入 w
+func (c *Client) postDocumentStream(ctx context.Context, path string, reqBody any, w io.Writer) (*DocumentMeta, error) {
+	httpReq, err := c.newDocumentRequest(ctx, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := c.streamDocument(httpReq, w)
+	if err != nil {
+		return nil, annotateTemplateChanged(requestError(httpReq, err), reqBody)
+	}
+	return meta, nil
+}
+
+// newDocumentRequest 构建返回文档的 JSON POST 请求
+func (c *Client) newDocumentRequest(ctx context.Context, path string, reqBody any) (*http.Request, error) {
+	// 序列化请求体（设置了 MaxRequestBytes 时超限会提前中止）
+	body, err := c.marshalRequest(ctx, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := c.newRequest(c.withHedgeable(ctx), http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/octet-stream")
+	return httpReq, nil
+}
+
+// streamDocument 发送请求并将 2xx 响应体写入 w，写入时计算摘要并在结束后校验
+func (c *Client) streamDocument(req *http.Request, w io.Writer) (*DocumentMeta, error) {
+	start := time.Now()
+	resp, err := c.doStream(req)
+	if err != nil {
+		return nil, err
+	}
+	c.withBodyReadTimeout(req, resp, start)
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, c.parseErrorResponse(req, resp, respBody)
+	}
+
+	if boundary, ok := multipartBoundary(resp); ok {
+		meta, err := c.streamMultipartDocument(req, resp, boundary, w)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.reportWarnings(req, meta.Warnings); err != nil {
+			return nil, err
+		}
+		return meta, nil
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), resp.Body)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := verifyChecksum(req, resp, h); err != nil {
+		return nil, err
+	}
+	meta := newDocumentMeta(resp, n, hex.EncodeToString(h.Sum(nil)))
+	if err := c.reportWarnings(req, meta.Warnings); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// streamMultipartDocument 将 multipart/mixed 响应中的文档部分写入 w，响应级摘要按完整响应体校验
+func (c *Client) streamMultipartDocument(req *http.Request, resp *http.Response, boundary string, w io.Writer) (*DocumentMeta, error) {
+	h := sha256.New()
+	var prefix prefixRecorder
+	body := io.TeeReader(resp.Body, io.MultiWriter(h, &prefix))
+	part, err := c.readMultipartDocument(req, body, boundary, w, prefix.buf.Bytes)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	// 读完结束分隔符之后的内容，使摘要覆盖完整响应体
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := verifyChecksum(req, resp, h); err != nil {
+		return nil, err
+	}
+	meta := newDocumentMeta(resp, part.size, part.sha256)
+	part.applyTo(&meta)
+	return &meta, nil
+}
+
+// verifyChecksum 校验响应内容摘要，服务端未提供摘要、HEAD 请求与部分内容响应时跳过
+func verifyChecksum(req *http.Request, resp *http.Response, h hash.Hash) error {
+	if req.Method == http.MethodHead || resp.StatusCode == http.StatusPartialContent {
+		return nil
+	}
+	expected := responseSHA256(resp.Header)
+	if expected == "" {
+		return nil
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		return &ChecksumMismatchError{Endpoint: req.URL.Path, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// newDocumentMeta 从响应头构建文档元数据，Verified 在 responseSHA256 非空时为 true
+// （调用方已完成校验）
+func newDocumentMeta(resp *http.Response, size int64, digest string) DocumentMeta {
+	meta := DocumentMeta{
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        size,
+		SHA256:      digest,
+		Verified:    responseSHA256(resp.Header) != "",
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		meta.FileName = params["filename"]
+	}
+	meta.ImagesFetched, meta.ImagesFailed = imageCounts(resp.Header)
+	meta.Warnings = headerWarnings(resp.Header)
+	meta.CorrelationID = requestCorrelationID(originalRequest(resp))
+	meta.ResultURL = resultURL(resp)
+	return meta
+}
@@ -0,0 +1,210 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ResultExpiredAtHeader 结果已过期（CodeResultExpired）响应中的过期时间（RFC 3339）
+const ResultExpiredAtHeader = "X-Result-Expired-At"
+
+// ErrResultExpired 异步任务结果已超过保留期限被服务端删除，需重新提交任务
+//
+// 具体错误类型为 *ResultExpiredError，可通过 errors.As 获取过期时间
+var ErrResultExpired = errors.New("docgen: result expired")
+
+// ResultExpiredError 访问已过期的异步任务结果（410，错误码 CodeResultExpired）
+//
+// errors.Is(err, ErrResultExpired) 返回 true；DownloadJobResult、GetResultInfo 等访问结果的方法均可能返回
+type ResultExpiredError struct {
+	// ExpiredAt 结果的过期时间，服务端未提供时为零值
+	ExpiredAt time.Time
+	// Err 服务端错误响应
+	Err *ErrorResponse
+}
+
+// Error 实现 error 接口
+func (e *ResultExpiredError) Error() string {
+	if e.ExpiredAt.IsZero() {
+		return fmt.Sprintf("%v: %s", ErrResultExpired, e.Err.Message)
+	}
+	return fmt.Sprintf("%v: %s (expired at %s)", ErrResultExpired, e.Err.Message, e.ExpiredAt.Format(time.RFC3339))
+}
+
+// Unwrap 返回服务端错误响应
+func (e *ResultExpiredError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrResultExpired) 成立
+func (e *ResultExpiredError) Is(target error) bool {
+	return target == ErrResultExpired
+}
+
+// ResultInfo 服务端保存的异步任务结果
+type ResultInfo struct {
+	// ID 结果 ID，用于 GetResultInfo、ExtendResultRetention 与 DeleteResult
+	ID string `json:"id"`
+	// JobID 生成该结果的任务 ID，结果可通过 DownloadJobResult(JobID) 下载
+	JobID string `json:"jobId"`
+	// TemplateName 使用的模板文件名（动态生成 Excel 时为空）
+	TemplateName string `json:"templateName,omitempty"`
+	// ContentType 结果的内容类型
+	ContentType string `json:"contentType,omitempty"`
+	// Size 结果大小（字节）
+	Size int64 `json:"size"`
+	// CreatedAt 结果生成时间
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt 过期时间，到期后服务端删除结果，访问时返回 *ResultExpiredError
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ResultFilter 结果列表过滤条件，零值字段不参与过滤
+type ResultFilter struct {
+	// JobID 任务 ID
+	JobID string
+	// TemplateName 模板文件名
+	TemplateName string
+	// CreatedAfter 仅返回在该时间之后生成的结果
+	CreatedAfter time.Time
+	// CreatedBefore 仅返回在该时间之前生成的结果
+	CreatedBefore time.Time
+	// ExpiresBefore 仅返回在该时间之前过期的结果，用于查找即将删除的结果
+	ExpiresBefore time.Time
+	// Limit 最多返回的结果数，0 表示使用服务端默认值
+	Limit int
+}
+
+// ListResultsResponse 结果列表响应
+type ListResultsResponse struct {
+	Success bool         `json:"success"`
+	Count   int64        `json:"count"`
+	Results []ResultInfo `json:"results"`
+}
+
+// ListResults 按条件列出服务端保存的异步任务结果（不含已过期的结果），需服务端支持 FeatureResultStore
+//
+// filter: 过滤条件，零值表示列出全部结果
+func (c *Client) ListResults(filter ResultFilter) ([]ResultInfo, error) {
+	return c.ListResultsContext(context.Background(), filter)
+}
+
+// ListResultsContext 支持 context 的 ListResults
+func (c *Client) ListResultsContext(ctx context.Context, filter ResultFilter) ([]ResultInfo, error) {
+	if err := c.requireFeature(ctx, FeatureResultStore); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if filter.JobID != "" {
+		query.Set("job", filter.JobID)
+	}
+	if filter.TemplateName != "" {
+		query.Set("template", c.qualifyTemplate(ctx, filter.TemplateName))
+	}
+	for key, t := range map[string]time.Time{
+		"createdAfter":  filter.CreatedAfter,
+		"createdBefore": filter.CreatedBefore,
+		"expiresBefore": filter.ExpiresBefore,
+	} {
+		if !t.IsZero() {
+			query.Set(key, t.UTC().Format(time.RFC3339))
+		}
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	path := "/api/v1/results"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var result ListResultsResponse
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Results, nil
+}
+
+// GetResultInfo 查询结果信息，结果已过期时返回 *ResultExpiredError
+//
+// id: 结果 ID
+func (c *Client) GetResultInfo(id string) (*ResultInfo, error) {
+	return c.GetResultInfoContext(context.Background(), id)
+}
+
+// GetResultInfoContext 支持 context 的 GetResultInfo
+func (c *Client) GetResultInfoContext(ctx context.Context, id string) (*ResultInfo, error) {
+	if err := c.requireFeature(ctx, FeatureResultStore); err != nil {
+		return nil, err
+	}
+	var info ResultInfo
+	if err := c.doJSON(ctx, http.MethodGet, resultPath(id, ""), nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ExtendResultRetention 延长结果的保留期限：过期时间设为 ttl 之后，不会早于当前的过期时间
+//
+// 返回更新后的结果信息，ExpiresAt 为服务端实际采用的过期时间（服务端可能限制最长保留期限）；
+// ttl 按秒向上取整，结果已过期时返回 *ResultExpiredError
+func (c *Client) ExtendResultRetention(id string, ttl time.Duration) (*ResultInfo, error) {
+	return c.ExtendResultRetentionContext(context.Background(), id, ttl)
+}
+
+// ExtendResultRetentionContext 支持 context 的 ExtendResultRetention
+func (c *Client) ExtendResultRetentionContext(ctx context.Context, id string, ttl time.Duration) (*ResultInfo, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("docgen: result retention must be positive, got %s", ttl)
+	}
+	if err := c.requireFeature(ctx, FeatureResultStore); err != nil {
+		return nil, err
+	}
+	body := map[string]int64{"ttl": int64(math.Ceil(ttl.Seconds()))}
+	var info ResultInfo
+	if err := c.doJSON(ctx, http.MethodPost, resultPath(id, "/retention"), body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// DeleteResult 删除结果但保留任务记录，之后 JobStatus 仍可查询任务，DownloadJobResult 返回错误；
+// 与删除任务及结果的 DeleteJobResult 不同
+//
+// id: 结果 ID
+func (c *Client) DeleteResult(id string) error {
+	return c.DeleteResultContext(context.Background(), id)
+}
+
+// DeleteResultContext 支持 context 的 DeleteResult
+func (c *Client) DeleteResultContext(ctx context.Context, id string) error {
+	if err := c.requireFeature(ctx, FeatureResultStore); err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, http.MethodDelete, resultPath(id, ""), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.execute(req)
+	return err
+}
+
+// resultPath 构建结果接口路径，suffix 如 "/retention"
+func resultPath(id, suffix string) string {
+	return "/api/v1/results/" + url.PathEscape(id) + suffix
+}
+
+// resultExpiredAt 解析 ResultExpiredAtHeader，缺失或格式错误时返回零值
+func resultExpiredAt(h http.Header) time.Time {
+	t, err := time.Parse(time.RFC3339, h.Get(ResultExpiredAtHeader))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
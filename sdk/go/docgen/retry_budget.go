@@ -0,0 +1,152 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted 重试预算已用完，SDK 不再重试而是立即返回
+//
+// 具体错误类型为 *RetryBudgetExhaustedError
+var ErrRetryBudgetExhausted = errors.New("docgen: retry budget exhausted")
+
+// RetryBudgetExhaustedError 重试预算已用完，放弃本应进行的重试
+//
+// errors.Is(err, ErrRetryBudgetExhausted) 返回 true；errors.Unwrap 返回触发重试的最后一次错误
+type RetryBudgetExhaustedError struct {
+	// Op 放弃重试的操作，如 "download /api/v1/doc/jobs/xxx/result"
+	Op string
+	// State 放弃时的预算状态
+	State RetryBudgetState
+	// Err 触发重试的最后一次错误
+	Err error
+}
+
+// Error 实现 error 接口
+func (e *RetryBudgetExhaustedError) Error() string {
+	return fmt.Sprintf("%v: %s (%d retries per %s): %v", ErrRetryBudgetExhausted, e.Op, e.State.Capacity, e.State.Window, e.Err)
+}
+
+// Is 使 errors.Is(err, ErrRetryBudgetExhausted) 成立
+func (e *RetryBudgetExhaustedError) Is(target error) bool {
+	return target == ErrRetryBudgetExhausted
+}
+
+// Unwrap 返回触发重试的最后一次错误
+func (e *RetryBudgetExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// RetryBudgetState 重试预算的状态快照，见 RetryBudget.State 与 RequestMetrics.RetryBudget
+type RetryBudgetState struct {
+	// Available 当前可用的重试次数
+	Available int
+	// Capacity 预算容量（每个窗口内的重试次数）
+	Capacity int
+	// Window 预算完全恢复所需的时间
+	Window time.Duration
+	// Spent 累计消耗的重试次数
+	Spent int64
+	// Denied 累计因预算用完而放弃的重试次数
+	Denied int64
+}
+
+// RetryBudget 重试预算（令牌桶），由一个 Client 的所有调用或同一 context 下的调用共享
+//
+// 每次重试消耗一个令牌，令牌按 capacity/window 的速率匀速恢复，最多恢复到 capacity。
+// 服务端故障时，大量调用各自按上限重试会放大负载；共享预算使重试总量有界，预算用完后立即失败。
+// 可并发使用
+type RetryBudget struct {
+	capacity int
+	window   time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	spent  int64
+	denied int64
+}
+
+// NewRetryBudget 创建容量为 retries、每 window 完全恢复的重试预算，初始为满
+//
+// retries <= 0 时不允许任何重试；window <= 0 时令牌不恢复
+func NewRetryBudget(retries int, window time.Duration) *RetryBudget {
+	if retries < 0 {
+		retries = 0
+	}
+	return &RetryBudget{capacity: retries, window: window, tokens: float64(retries), last: time.Now()}
+}
+
+// TryAcquire 消耗一个令牌，预算用完时返回 false
+func (b *RetryBudget) TryAcquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		b.denied++
+		return false
+	}
+	b.tokens--
+	b.spent++
+	return true
+}
+
+// State 返回预算的当前状态
+func (b *RetryBudget) State() RetryBudgetState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	return RetryBudgetState{
+		Available: int(b.tokens),
+		Capacity:  b.capacity,
+		Window:    b.window,
+		Spent:     b.spent,
+		Denied:    b.denied,
+	}
+}
+
+// refill 按经过的时间恢复令牌，调用方持有 b.mu
+func (b *RetryBudget) refill(now time.Time) {
+	if b.window > 0 && now.After(b.last) {
+		b.tokens += float64(b.capacity) * float64(now.Sub(b.last)) / float64(b.window)
+		if b.tokens > float64(b.capacity) {
+			b.tokens = float64(b.capacity)
+		}
+	}
+	b.last = now
+}
+
+// retryBudgetContextKey 重试预算在 context 中的键
+type retryBudgetContextKey struct{}
+
+// WithRetryBudgetContext 返回使用预算 b 的 context，此 context 下的调用使用 b 而非 WithRetryBudget 设置的预算
+//
+// 用于为不同类型的调用分配独立的预算，如交互请求与批量任务互不挤占；b 为 nil 时此 context 下的重试不受预算限制
+func WithRetryBudgetContext(ctx context.Context, b *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetContextKey{}, retryBudgetOverride{b})
+}
+
+// retryBudgetOverride 包装 context 中的预算，以区分"未指定"与"指定为 nil"
+type retryBudgetOverride struct {
+	budget *RetryBudget
+}
+
+// retryBudgetFor 返回调用使用的预算：context 中指定的优先，其次为 WithRetryBudget 设置的，可能为 nil
+func (c *Client) retryBudgetFor(ctx context.Context) *RetryBudget {
+	if o, ok := ctx.Value(retryBudgetContextKey{}).(retryBudgetOverride); ok {
+		return o.budget
+	}
+	return c.retryBudget
+}
+
+// spendRetry 重试前消耗预算：没有预算或预算充足时返回 nil，预算用完时返回 *RetryBudgetExhaustedError
+func (c *Client) spendRetry(ctx context.Context, op string, lastErr error) error {
+	b := c.retryBudgetFor(ctx)
+	if b == nil || b.TryAcquire() {
+		return nil
+	}
+	return &RetryBudgetExhaustedError{Op: op, State: b.State(), Err: lastErr}
+}
@@ -0,0 +1,132 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// TestRetryBudgetDrainedByConcurrentCallers 多个调用方同时下载，服务端每次只发送 1 KiB 就断开（"正在宕机"），
+// 每个下载都会一直续传；共享预算用完后所有调用方立即失败，重试总数不超过预算容量
+func TestRetryBudgetDrainedByConcurrentCallers(t *testing.T) {
+	const callers, capacity = 10, 20
+	cuts := make([]int, callers+capacity+callers)
+	for i := range cuts {
+		cuts[i] = 1 << 10
+	}
+	srv := newRangeServer(t, 1<<20, cuts...)
+	batch := docgen.NewRetryBudget(capacity, time.Hour)
+	var metrics metricsRecorder
+	client := docgen.NewClient(srv.URL, docgen.WithRetryBudget(batch), docgen.WithMetricsHook(metrics.record))
+
+	dir := t.TempDir()
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = client.DownloadJobResultResumable(context.Background(), "j1", filepath.Join(dir, fmt.Sprintf("result-%d.docx", i)))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		var exhausted *docgen.RetryBudgetExhaustedError
+		if !errors.Is(err, docgen.ErrRetryBudgetExhausted) || !errors.As(err, &exhausted) {
+			t.Errorf("caller %d: err = %v, want *RetryBudgetExhaustedError", i, err)
+			continue
+		}
+		if exhausted.Err == nil {
+			t.Errorf("caller %d: exhausted error does not wrap the failure that triggered the retry", i)
+		}
+		if exhausted.State.Capacity != capacity || exhausted.State.Available != 0 {
+			t.Errorf("caller %d: state %+v, want an empty budget of %d", i, exhausted.State, capacity)
+		}
+	}
+
+	// 每个调用方的首次请求不消耗预算，之后所有调用方共享 capacity 次重试
+	if got := len(srv.ranges()); got != callers+capacity {
+		t.Errorf("server received %d requests, want %d", got, callers+capacity)
+	}
+	state := batch.State()
+	if state.Spent != capacity || state.Denied != callers {
+		t.Errorf("budget Spent=%d Denied=%d, want %d %d", state.Spent, state.Denied, capacity, callers)
+	}
+
+	// 指标中带有预算状态，最后一次请求时预算已用完
+	metrics.mu.Lock()
+	all := append([]docgen.RequestMetrics(nil), metrics.all...)
+	metrics.mu.Unlock()
+	if len(all) != callers+capacity {
+		t.Fatalf("metrics hook called %d times, want %d", len(all), callers+capacity)
+	}
+	for _, m := range all {
+		if m.RetryBudget == nil || m.RetryBudget.Capacity != capacity {
+			t.Fatalf("metrics RetryBudget = %+v, want the batch budget", m.RetryBudget)
+		}
+	}
+	if last := all[len(all)-1].RetryBudget; last.Available != 0 {
+		t.Errorf("last metrics Available = %d, want 0", last.Available)
+	}
+}
+
+// TestRetryBudgetContextOverride 批量任务用完客户端的预算后，使用独立预算的交互请求仍可重试
+func TestRetryBudgetContextOverride(t *testing.T) {
+	batch := docgen.NewRetryBudget(1, time.Hour)
+	if !batch.TryAcquire() {
+		t.Fatal("fresh budget denied a retry")
+	}
+	interactive := docgen.NewRetryBudget(5, time.Hour)
+
+	// 第一次请求断开，续传一次后完成
+	srv := newRangeServer(t, 64<<10, 1<<10)
+	client := docgen.NewClient(srv.URL, docgen.WithRetryBudget(batch))
+	dest := filepath.Join(t.TempDir(), "result.docx")
+
+	err := client.DownloadJobResultResumable(context.Background(), "j1", dest)
+	if !errors.Is(err, docgen.ErrRetryBudgetExhausted) {
+		t.Fatalf("batch budget: err = %v, want ErrRetryBudgetExhausted", err)
+	}
+
+	ctx := docgen.WithRetryBudgetContext(context.Background(), interactive)
+	if err := client.DownloadJobResultResumable(ctx, "j1", dest); err != nil {
+		t.Fatalf("interactive budget: %v", err)
+	}
+	assertDownloaded(t, dest, srv.content)
+	if got := interactive.State().Spent; got != 0 {
+		// 第二次调用从 .partial 续传，首次请求即完成
+		t.Errorf("interactive Spent = %d, want 0", got)
+	}
+	if got := batch.State(); got.Spent != 1 || got.Denied != 1 {
+		t.Errorf("batch budget %+v, want Spent 1 Denied 1", got)
+	}
+
+	// 交互预算用于需要重试的调用，不影响批量预算
+	srv2 := newRangeServer(t, 64<<10, 1<<10)
+	client2 := docgen.NewClient(srv2.URL, docgen.WithRetryBudget(batch))
+	dest2 := filepath.Join(t.TempDir(), "result.docx")
+	if err := client2.DownloadJobResultResumable(ctx, "j1", dest2); err != nil {
+		t.Fatalf("interactive budget with a retry: %v", err)
+	}
+	if got := interactive.State().Spent; got != 1 {
+		t.Errorf("interactive Spent = %d, want 1", got)
+	}
+	if got := batch.State(); got.Spent != 1 || got.Denied != 1 {
+		t.Errorf("batch budget changed to %+v by an interactive call", got)
+	}
+
+	// nil 预算表示不受限制
+	srv3 := newRangeServer(t, 64<<10, 1<<10, 1<<10)
+	dest3 := filepath.Join(t.TempDir(), "result.docx")
+	unlimited := docgen.WithRetryBudgetContext(context.Background(), nil)
+	if err := docgen.NewClient(srv3.URL, docgen.WithRetryBudget(batch)).DownloadJobResultResumable(unlimited, "j1", dest3); err != nil {
+		t.Fatalf("nil budget: %v", err)
+	}
+}
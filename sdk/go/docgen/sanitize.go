@@ -0,0 +1,113 @@
+package docgen
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultMaxFileNameLength SanitizeOptions.MaxLength 的默认值（字节），低于常见文件系统 255 字节的限制，为序号后缀留出余量
+const defaultMaxFileNameLength = 200
+
+// windowsReservedNames Windows 保留的设备名，不区分大小写，带扩展名时同样保留（如 NUL.docx）
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SanitizeOptions SanitizeFileName 的选项
+type SanitizeOptions struct {
+	// Replacement 替换非法字符的字符，默认 '_'；本身非法时使用默认值
+	Replacement rune
+	// MaxLength 文件名最大长度（字节），默认 200；超出时截断主文件名并保留扩展名
+	MaxLength int
+	// Exists 判断文件名是否已被占用（如检查目录或本次运行已使用的名称），
+	// 被占用时在扩展名前追加 "-1"、"-2"…… 直到未被占用；为 nil 时不检查
+	Exists func(name string) bool
+}
+
+// SanitizeFileName 将服务端或数据字段提供的名称转换为可在各平台安全使用的文件名（不含目录）
+//
+// 规则固定，相同输入总是得到相同结果：
+//   - 去掉首尾空白；路径分隔符（/ \）、Windows 非法字符（: * ? " < > |）与控制字符替换为 Replacement
+//   - 开头的 "." 替换为 Replacement，避免 ".."、隐藏文件；结尾的 "." 与空格去掉（Windows 不允许）
+//   - Windows 保留设备名（CON、NUL、COM1、LPT1 等，不区分大小写，包括带扩展名的形式）在设备名后插入 Replacement，如 CON_.docx
+//   - 超过 MaxLength 时截断主文件名，保留扩展名，不截断多字节字符
+//
+// 结果为空（输入为空或只有空白、点）时返回空字符串，调用方应使用默认名称
+func SanitizeFileName(name string, opts SanitizeOptions) string {
+	repl := opts.Replacement
+	if repl == 0 || !validFileNameRune(repl) || repl == '.' || repl == ' ' {
+		repl = '_'
+	}
+	maxLen := opts.MaxLength
+	if maxLen <= 0 {
+		maxLen = defaultMaxFileNameLength
+	}
+
+	name = strings.TrimSpace(name)
+	name = strings.Map(func(r rune) rune {
+		if !validFileNameRune(r) {
+			return repl
+		}
+		return r
+	}, name)
+	name = strings.TrimRight(name, ". ")
+	if strings.Trim(name, ".") == "" {
+		return ""
+	}
+	if trimmed := strings.TrimLeft(name, "."); len(trimmed) < len(name) {
+		name = strings.Repeat(string(repl), len(name)-len(trimmed)) + trimmed
+	}
+
+	ext := filepath.Ext(name)
+	if len(ext) > maxLen/2 {
+		ext = ""
+	}
+	base := strings.TrimSuffix(name, ext)
+	if first := strings.SplitN(base, ".", 2)[0]; windowsReservedNames[strings.ToUpper(strings.TrimRight(first, " "))] {
+		base = first + string(repl) + base[len(first):]
+	}
+	base = truncateUTF8(base, maxLen-len(ext))
+	name = base + ext
+
+	if opts.Exists == nil || !opts.Exists(name) {
+		return name
+	}
+	for n := 1; ; n++ {
+		suffix := fmt.Sprintf("-%d", n)
+		candidate := truncateUTF8(base, maxLen-len(ext)-len(suffix)) + suffix + ext
+		if !opts.Exists(candidate) {
+			return candidate
+		}
+	}
+}
+
+// validFileNameRune 字符能否出现在各平台的文件名中
+func validFileNameRune(r rune) bool {
+	return r >= 0x20 && r != 0x7f && r != utf8.RuneError && !strings.ContainsRune(`/\:*?"<>|`, r)
+}
+
+// truncateUTF8 截断到不超过 n 字节，不拆分多字节字符
+func truncateUTF8(s string, n int) string {
+	if n < 0 {
+		n = 0
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// derivedFileName 清理 SDK 自行决定的文件名（服务端建议的名称、数据字段的值），WithRawFileNames 时原样返回
+func (c *Client) derivedFileName(name string, exists func(string) bool) string {
+	if c.rawFileNames {
+		return name
+	}
+	return SanitizeFileName(name, SanitizeOptions{Exists: exists})
+}
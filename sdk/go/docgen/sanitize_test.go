@@ -0,0 +1,154 @@
+package docgen_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		opts docgen.SanitizeOptions
+		want string
+	}{
+		// 路径与盘符
+		{"dot dot", "..", docgen.SanitizeOptions{}, ""},
+		{"parent path", "../x", docgen.SanitizeOptions{}, "___x"},
+		{"windows parent path", `..\x.docx`, docgen.SanitizeOptions{}, "___x.docx"},
+		{"drive letter", `C:\x`, docgen.SanitizeOptions{}, "C__x"},
+		{"absolute path", "/etc/passwd", docgen.SanitizeOptions{}, "_etc_passwd"},
+		// Windows 保留设备名
+		{"device", "CON", docgen.SanitizeOptions{}, "CON_"},
+		{"device lower case with extension", "nul.docx", docgen.SanitizeOptions{}, "nul_.docx"},
+		{"device with trailing space", "COM1 .txt", docgen.SanitizeOptions{}, "COM1 _.txt"},
+		{"device with two extensions", "lpt9.tar.gz", docgen.SanitizeOptions{}, "lpt9_.tar.gz"},
+		{"device prefix is not a device", "CONSOLE.docx", docgen.SanitizeOptions{}, "CONSOLE.docx"},
+		{"COM0 is not a device", "COM0.docx", docgen.SanitizeOptions{}, "COM0.docx"},
+		// 开头与结尾的点
+		{"hidden file", ".hidden", docgen.SanitizeOptions{}, "_hidden"},
+		{"leading dots", "...report.docx", docgen.SanitizeOptions{}, "___report.docx"},
+		{"trailing dot and space", "report. ", docgen.SanitizeOptions{}, "report"},
+		{"only dots and spaces", " . . ", docgen.SanitizeOptions{}, ""},
+		// 非法字符与替换字符
+		{"illegal characters", `a:b*c?"d<e>f|g.docx`, docgen.SanitizeOptions{}, "a_b_c__d_e_f_g.docx"},
+		{"control characters", "a\x00b\tc.docx", docgen.SanitizeOptions{}, "a_b_c.docx"},
+		{"replacement", "a/b.docx", docgen.SanitizeOptions{Replacement: '-'}, "a-b.docx"},
+		{"illegal replacement falls back", "a/b.docx", docgen.SanitizeOptions{Replacement: '/'}, "a_b.docx"},
+		{"dot replacement falls back", ".x", docgen.SanitizeOptions{Replacement: '.'}, "_x"},
+		{"unicode kept", "合同 2024.docx", docgen.SanitizeOptions{}, "合同 2024.docx"},
+		// 截断：保留扩展名，不拆分多字节字符
+		{"truncate", "abcdefghij.docx", docgen.SanitizeOptions{MaxLength: 10}, "abcde.docx"},
+		{"truncate inside a character", "文档文档.docx", docgen.SanitizeOptions{MaxLength: 10}, "文.docx"},
+		{"truncate at a character boundary", "文档文档.docx", docgen.SanitizeOptions{MaxLength: 11}, "文档.docx"},
+		{"long extension is truncated with the name", "a.verylongextension", docgen.SanitizeOptions{MaxLength: 10}, "a.verylong"},
+		// Exists 冲突时追加序号
+		{"exists", "report.docx", docgen.SanitizeOptions{Exists: taken("report.docx", "report-1.docx")}, "report-2.docx"},
+		{"exists without extension", "report", docgen.SanitizeOptions{Exists: taken("report")}, "report-1"},
+		{"exists checks the sanitized name", "CON.docx", docgen.SanitizeOptions{Exists: taken("CON_.docx")}, "CON_-1.docx"},
+		{"suffix keeps the length limit", "abcdefg.docx", docgen.SanitizeOptions{MaxLength: 12, Exists: taken("abcdefg.docx")}, "abcde-1.docx"},
+		{"suffix does not split a character", "文档文.docx", docgen.SanitizeOptions{MaxLength: 14, Exists: taken("文档文.docx")}, "文档-1.docx"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := docgen.SanitizeFileName(tt.in, tt.opts)
+			if got != tt.want {
+				t.Errorf("SanitizeFileName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("SanitizeFileName(%q) = %q is not valid UTF-8", tt.in, got)
+			}
+			if limit := tt.opts.MaxLength; limit > 0 && len(got) > limit {
+				t.Errorf("SanitizeFileName(%q) = %q exceeds %d bytes", tt.in, got, limit)
+			}
+			// 结果再次清理不变
+			if got != "" && tt.opts.Exists == nil {
+				if again := docgen.SanitizeFileName(got, tt.opts); again != got {
+					t.Errorf("SanitizeFileName(%q) = %q, not stable", got, again)
+				}
+			}
+		})
+	}
+}
+
+// taken 返回判断名称是否在 names 中的 Exists 函数
+func taken(names ...string) func(string) bool {
+	return func(name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestArchiveEntryNames(t *testing.T) {
+	tests := []struct {
+		entry string
+		raw   bool
+		// want 交给回调的名称，为空表示条目被拒绝（ErrUnsafeEntryName）
+		want string
+	}{
+		{"report.docx", false, "report.docx"},
+		{"./report.docx", false, "report.docx"},
+		{"dept/report.docx", false, "dept/report.docx"},
+		{`dept\report.docx`, false, "dept/report.docx"},
+		{"a/../b.docx", false, "b.docx"},
+		{"..", false, ""},
+		{"../x", false, ""},
+		{`..\x`, false, ""},
+		{"a/../../x", false, ""},
+		{"/etc/passwd", false, ""},
+		{`\\server\share\x`, false, ""},
+		{`C:\x`, false, ""},
+		{"C:x", false, ""},
+		{"dept/CON.docx", false, "dept/CON_.docx"},
+		{"dept/nul", false, "dept/nul_"},
+		{".hidden/report.docx", false, "_hidden/report.docx"},
+		{"dept/a:b.docx", false, "dept/a_b.docx"},
+		// RawNames 只拒绝不安全的路径，不清理各级名称
+		{"dept/CON.docx", true, "dept/CON.docx"},
+		{".hidden/report.docx", true, ".hidden/report.docx"},
+		{"../x", true, ""},
+		{`C:\x`, true, ""},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: tt.entry, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, "doc")
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		result := &docgen.DocumentResult{Data: buf.Bytes()}
+		report, err := result.Documents(docgen.ArchiveOptions{SkipCorrupt: true, RawNames: tt.raw}, func(name string, r io.Reader, size int64) error {
+			got = append(got, name)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%q: %v", tt.entry, err)
+		}
+		if tt.want == "" {
+			if len(got) != 0 || len(report.Corrupt) != 1 || !errors.Is(report.Corrupt[0].Err, docgen.ErrUnsafeEntryName) {
+				t.Errorf("%q (raw %v): names %v, skipped %+v; want the entry rejected as unsafe", tt.entry, tt.raw, got, report.Corrupt)
+			}
+			continue
+		}
+		if strings.Join(got, ",") != tt.want {
+			t.Errorf("%q (raw %v): names %v, want %q", tt.entry, tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package docgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SectionMarker 重复区块序列化后的标记键，服务端据此按区块（{{?name}}...{{/name}}）而非表格行循环渲染
+const SectionMarker = "$section"
+
+// ErrSectionCollision 区块名称与标量键重名，模板中的同名标签无法确定取哪一个值
+//
+// 具体错误类型为 *SectionCollisionError
+var ErrSectionCollision = errors.New("docgen: section name collides with scalar key")
+
+// SectionCollisionError 区块名称与同一层或外层数据中的标量键重名
+//
+// 区块内的标签找不到时会向外层数据查找，因此外层的标量键与内层区块、区块条目中的标量键与外层区块同名都视为冲突。
+// errors.Is(err, ErrSectionCollision) 返回 true
+type SectionCollisionError struct {
+	// Path 发生冲突的数据位置，如 "data.parties[0]"
+	Path string
+	// Name 冲突的名称
+	Name string
+}
+
+// Error 实现 error 接口
+func (e *SectionCollisionError) Error() string {
+	return fmt.Sprintf("%v: %q at %s", ErrSectionCollision, e.Name, e.Path)
+}
+
+// Is 使 errors.Is(err, ErrSectionCollision) 成立
+func (e *SectionCollisionError) Is(target error) bool {
+	return target == ErrSectionCollision
+}
+
+// Data Word 模板渲染数据，键对应模板中的占位符
+//
+// 可直接赋值给 WordGenRequest.Data 等 map[string]any 字段，值可以是标量、ImageURL、Table 与 Section
+type Data map[string]any
+
+// With 返回加入重复区块后的数据副本，区块以其名称为键；d 不会被修改
+//
+// 名称与已有键重复时，请求在发送前返回 *SectionCollisionError
+func (d Data) With(sections ...SectionValue) Data {
+	out := make(Data, len(d)+len(sections))
+	for k, v := range d {
+		out[k] = v
+	}
+	for _, s := range sections {
+		if _, ok := out[s.Name]; ok {
+			out[s.Name] = sectionCollision{name: s.Name}
+			continue
+		}
+		out[s.Name] = s
+	}
+	return out
+}
+
+// SectionValue 重复区块，由 Section 创建
+type SectionValue struct {
+	// Name 区块名称，对应模板中的 {{?name}}...{{/name}}
+	Name string
+	// Items 每次重复使用的数据，条目中可以再包含区块（嵌套循环）、Table 与 ImageURL
+	Items []Data
+}
+
+// Section 创建重复区块：模板中 {{?name}} 与 {{/name}} 之间的内容按 items 逐项重复渲染
+//
+// 放入 Data.With，或以 name 为键放入数据中。嵌套循环示例（各方及其签署人）：
+//
+//	data := docgen.Data{"title": "采购合同"}.With(docgen.Section("parties",
+//	    docgen.Data{"name": "甲方"}.With(docgen.Section("signatories",
+//	        docgen.Data{"name": "张三"}, docgen.Data{"name": "李四"})),
+//	    docgen.Data{"name": "乙方"},
+//	))
+//
+// 需服务端支持 FeatureSections
+func Section(name string, items ...Data) SectionValue {
+	return SectionValue{Name: name, Items: items}
+}
+
+// MarshalJSON 实现 json.Marshaler：输出 {"$section": [...]}
+func (s SectionValue) MarshalJSON() ([]byte, error) {
+	items := s.Items
+	if items == nil {
+		items = []Data{}
+	}
+	return json.Marshal(map[string][]Data{SectionMarker: items})
+}
+
+// Table 创建表格行循环数据：模板表格中的行按 rows 逐行重复
+//
+// 与直接使用 []map[string]any 相同，便于在 Data 与区块条目中组合
+func Table(rows ...Data) []map[string]any {
+	out := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		out[i] = row
+	}
+	return out
+}
+
+// sectionCollision Data.With 遇到重名时留下的标记，校验与序列化时报告冲突
+type sectionCollision struct {
+	name string
+}
+
+// MarshalJSON 实现 json.Marshaler：总是返回 *SectionCollisionError
+func (c sectionCollision) MarshalJSON() ([]byte, error) {
+	return nil, &SectionCollisionError{Path: "data", Name: c.name}
+}
+
+// checkSections 校验数据中的区块：名称与键一致，且不与同一层或外层的标量键重名；
+// 数据包含区块时需服务端支持 FeatureSections
+func (c *Client) checkSections(ctx context.Context, path string, data map[string]any) error {
+	found, err := validateSections(path, data, nil, nil)
+	if err != nil {
+		return err
+	}
+	if found {
+		return c.rejectUnsupported(ctx, FeatureSections)
+	}
+	return nil
+}
+
+// validateSections 校验一层数据，outerScalars 与 outerSections 为外层的标量键与区块名称；返回是否包含区块
+func validateSections(path string, data map[string]any, outerScalars, outerSections map[string]bool) (bool, error) {
+	keys := make([]string, 0, len(data))
+	scalars := make(map[string]bool, len(data)+len(outerScalars))
+	for k := range outerScalars {
+		scalars[k] = true
+	}
+	for k, v := range data {
+		keys = append(keys, k)
+		if _, ok := v.(SectionValue); !ok {
+			scalars[k] = true
+		}
+	}
+	sort.Strings(keys)
+
+	found := false
+	for _, k := range keys {
+		switch v := data[k].(type) {
+		case sectionCollision:
+			return false, &SectionCollisionError{Path: path, Name: v.name}
+		case SectionValue:
+			found = true
+			if v.Name != k {
+				return false, fmt.Errorf("docgen: section %q stored under key %q at %s", v.Name, k, path)
+			}
+			if outerScalars[k] {
+				return false, &SectionCollisionError{Path: path, Name: k}
+			}
+			sections := make(map[string]bool, len(outerSections)+1)
+			for name := range outerSections {
+				sections[name] = true
+			}
+			sections[k] = true
+			for i, item := range v.Items {
+				if _, err := validateSections(path+"."+k+"["+strconv.Itoa(i)+"]", item, scalars, sections); err != nil {
+					return false, err
+				}
+			}
+		default:
+			if outerSections[k] {
+				return false, &SectionCollisionError{Path: path, Name: k}
+			}
+		}
+	}
+	return found, nil
+}
+
+// StructData 将结构体转换为 Word 模板数据
+//
+// 字段名遵循 json 标签（"-" 跳过、omitempty 省略零值），匿名嵌入的结构体字段提升到上一层。
+// 元素为结构体（或结构体指针）的切片转换为同名区块（Section），标签 docgen:"table" 使其转换为表格行循环（Table）；
+// 嵌套的结构体转换为 Data；实现了 json.Marshaler / encoding.TextMarshaler 的类型（如 time.Time、ImageValue）
+// 与其他值原样保留。标签 docgen:"sdt:TagName" 的字段是内容控件，不出现在返回的数据中（见 GenerateWordFromStruct）。
+// v 为 nil 或不是结构体（指针）时返回错误
+func StructData(v any) (Data, error) {
+	data, _, err := structData(v)
+	return data, err
+}
+
+// structData 将结构体转换为模板数据与内容控件（标签 docgen:"sdt:TagName" 的顶层字段）
+func structData(v any) (Data, map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("docgen: StructData needs a struct, got %T", v)
+	}
+	data := make(Data, rv.NumField())
+	controls := make(map[string]any)
+	structFields(data, controls, rv)
+	if len(controls) == 0 {
+		controls = nil
+	}
+	return data, controls, nil
+}
+
+// structFields 将结构体字段写入 data；controls 非 nil 时标签 docgen:"sdt:TagName" 的字段写入 controls，
+// 为 nil（嵌套的结构体）时该标签被忽略
+func structFields(data Data, controls map[string]any, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isFlattenLeaf(field.Type) {
+				if fv.Kind() == reflect.Pointer && fv.IsNil() {
+					continue
+				}
+				structFields(data, controls, reflect.Indirect(fv))
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && fv.IsZero() {
+			continue
+		}
+		docgenTag := field.Tag.Get("docgen")
+		if tag, ok := strings.CutPrefix(docgenTag, "sdt:"); ok && controls != nil {
+			controls[tag] = structControlValue(fv)
+			continue
+		}
+		data[name] = structValue(name, fv, docgenTag == "table")
+	}
+}
+
+// structControlValue 转换内容控件字段的值：bool 转换为 CheckboxValue，time.Time 转换为 DateControlValue，其他值原样保留
+func structControlValue(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch value := v.Interface().(type) {
+	case bool:
+		return CheckboxValue(value)
+	case time.Time:
+		return DateControlValue(value)
+	default:
+		return value
+	}
+}
+
+// structValue 转换一个字段的值，name 为字段在数据中的名称
+func structValue(name string, v reflect.Value, table bool) any {
+	if !v.IsValid() || (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil
+	}
+	if isFlattenLeaf(v.Type()) && v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return structValue(name, v.Elem(), table)
+	case reflect.Struct:
+		data := make(Data, v.NumField())
+		structFields(data, nil, v)
+		return data
+	case reflect.Slice, reflect.Array:
+		if !isStructElem(v.Type().Elem()) || v.Type().Implements(jsonMarshalerType) {
+			return v.Interface()
+		}
+		items := make([]Data, v.Len())
+		for i := range items {
+			items[i], _ = structValue(name, v.Index(i), false).(Data)
+		}
+		if table {
+			return Table(items...)
+		}
+		return Section(name, items...)
+	}
+	return v.Interface()
+}
+
+// isStructElem 切片元素是否为需转换的结构体（或结构体指针）
+func isStructElem(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isFlattenLeaf(t)
+}
+
+// GenerateWordFromStruct 以结构体为数据生成 Word 文档，转换规则见 StructData：
+// 元素为结构体的切片字段自动成为区块
+//
+// 标签 docgen:"sdt:TagName" 的顶层字段（含匿名嵌入的结构体字段）填充标记为 TagName 的内容控件（WordGenRequest.ContentControls）：
+// bool 字段转换为 CheckboxValue，time.Time 字段转换为 DateControlValue，下拉列表字段使用 ContentControlValue 类型（DropdownValue）
+func (c *Client) GenerateWordFromStruct(templateName string, v any, fileName string) ([]byte, error) {
+	return c.GenerateWordFromStructContext(context.Background(), templateName, v, fileName)
+}
+
+// GenerateWordFromStructContext 支持 context 的 GenerateWordFromStruct
+func (c *Client) GenerateWordFromStructContext(ctx context.Context, templateName string, v any, fileName string) ([]byte, error) {
+	data, controls, err := structData(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.GenerateWordContext(ctx, WordGenRequest{TemplateName: templateName, Data: data, ContentControls: controls, FileName: fileName})
+}
@@ -0,0 +1,114 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+type signatory struct {
+	Name      string            `json:"name"`
+	Title     string            `json:"title,omitempty"`
+	Signature docgen.ImageValue `json:"signature"`
+}
+
+type lineItem struct {
+	Product string  `json:"product"`
+	Amount  float64 `json:"amount"`
+}
+
+type party struct {
+	Role        string      `json:"role"`
+	Name        string      `json:"name"`
+	Signatories []signatory `json:"signatories"`
+	Items       []lineItem  `json:"items,omitempty" docgen:"table"`
+}
+
+type contract struct {
+	Title   string  `json:"title"`
+	Parties []party `json:"parties"`
+}
+
+func signatureImage(name string) docgen.ImageValue {
+	return docgen.ImageURL("https://img.example.com/sign/"+name+".png", docgen.ImageFetchOptions{OnError: docgen.ImageFailPlaceholder})
+}
+
+// TestSectionWireFormat 固定两层嵌套区块（各方 → 签署人）的线上格式，区块条目中包含表格与图片；
+// Section 构建的数据与 GenerateWordFromStruct 转换的结构体发送相同的请求体
+func TestSectionWireFormat(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("contract.docx", docgentest.MinimalDocx("{{title}}"))
+	client := docgen.NewClient(srv.URL)
+
+	data := docgen.Data{"title": "采购合同"}.With(docgen.Section("parties",
+		docgen.Data{
+			"role":  "甲方",
+			"name":  "北京某某科技有限公司",
+			"items": docgen.Table(docgen.Data{"product": "服务器", "amount": 12000.5}, docgen.Data{"product": "交换机", "amount": 3000.0}),
+		}.With(docgen.Section("signatories",
+			docgen.Data{"name": "张三", "title": "总经理", "signature": signatureImage("zhangsan")},
+			docgen.Data{"name": "李四", "signature": signatureImage("lisi")},
+		)),
+		docgen.Data{"role": "乙方", "name": "上海某某贸易有限公司"}.With(docgen.Section("signatories",
+			docgen.Data{"name": "王五", "title": "法人代表", "signature": signatureImage("wangwu")},
+		)),
+	))
+	if _, err := client.GenerateWordWithMeta(context.Background(), docgen.WordGenRequest{TemplateName: "contract.docx", Data: data, FileName: "contract"}); err != nil {
+		t.Fatal(err)
+	}
+	docgentest.AssertJSONEqualGolden(t, srv.LastRequest().Body, "testdata/wire/sections_nested.json", docgentest.GoldenOptions{})
+
+	v := contract{Title: "采购合同", Parties: []party{
+		{
+			Role: "甲方", Name: "北京某某科技有限公司",
+			Signatories: []signatory{
+				{Name: "张三", Title: "总经理", Signature: signatureImage("zhangsan")},
+				{Name: "李四", Signature: signatureImage("lisi")},
+			},
+			Items: []lineItem{{"服务器", 12000.5}, {"交换机", 3000}},
+		},
+		{
+			Role: "乙方", Name: "上海某某贸易有限公司",
+			Signatories: []signatory{{Name: "王五", Title: "法人代表", Signature: signatureImage("wangwu")}},
+		},
+	}}
+	if _, err := client.GenerateWordFromStruct("contract.docx", v, "contract"); err != nil {
+		t.Fatal(err)
+	}
+	docgentest.AssertJSONEqualGolden(t, srv.LastRequest().Body, "testdata/wire/sections_nested.json", docgentest.GoldenOptions{})
+}
+
+func TestSectionCollisionRejectedBeforeSending(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("contract.docx", docgentest.MinimalDocx("{{title}}"))
+	client := docgen.NewClient(srv.URL)
+
+	tests := []struct {
+		name string
+		data docgen.Data
+	}{
+		{"same level", docgen.Data{"parties": "x"}.With(docgen.Section("parties", docgen.Data{"name": "甲方"}))},
+		// 区块条目中找不到的标签向外层查找，内层区块与外层标量同名同样冲突
+		{"outer scalar", docgen.Data{"signatories": "x"}.With(docgen.Section("parties",
+			docgen.Data{"name": "甲方"}.With(docgen.Section("signatories", docgen.Data{"name": "张三"})),
+		))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(srv.RequestsTo(docgentest.EndpointWord))
+			_, err := client.GenerateWordWithMeta(context.Background(), docgen.WordGenRequest{TemplateName: "contract.docx", Data: tt.data})
+			var collision *docgen.SectionCollisionError
+			if !errors.Is(err, docgen.ErrSectionCollision) || !errors.As(err, &collision) {
+				t.Fatalf("err = %v, want *SectionCollisionError", err)
+			}
+			if n := len(srv.RequestsTo(docgentest.EndpointWord)) - before; n != 0 {
+				t.Errorf("%d requests sent despite the collision", n)
+			}
+		})
+	}
+}
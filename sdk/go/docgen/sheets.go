@@ -0,0 +1,98 @@
+package docgen
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	// ExcelMaxRows Excel 单个工作表的最大行数（含表头）
+	ExcelMaxRows = 1048576
+	// DefaultSplitRows 自动拆分时每个工作表的默认数据行数，加上表头恰好不超过 ExcelMaxRows
+	DefaultSplitRows = ExcelMaxRows - 1
+)
+
+// SheetLayout 生成的 Excel 中一个工作表的数据范围
+type SheetLayout struct {
+	// Name 工作表名称
+	Name string
+	// FirstRow 该工作表第一行数据在 ExcelGenRequest.Data 中的下标
+	FirstRow int
+	// Rows 数据行数（不含表头）
+	Rows int
+}
+
+// GenerateExcelTo 生成 Excel 文档并直接写入 w，行为与 GenerateWordTo 一致；返回的元数据包含工作表布局
+func (c *Client) GenerateExcelTo(ctx context.Context, req ExcelGenRequest, w io.Writer) (*DocumentMeta, error) {
+	ctx = withCallCorrelationID(ctx)
+	sheets, err := c.prepareExcel(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := c.postDocumentTo(ctx, "/api/v1/doc/excel", req, w)
+	if err != nil {
+		return nil, err
+	}
+	meta.Sheets = sheets
+	return meta, nil
+}
+
+// prepareExcel 补全 Excel 生成请求（优先级、字体选项、工作表拆分），返回预期的工作表布局
+func (c *Client) prepareExcel(ctx context.Context, req *ExcelGenRequest) ([]SheetLayout, error) {
+	priority, err := c.resolvePriority(req.Priority)
+	if err != nil {
+		return nil, err
+	}
+	req.Priority = priority
+	if req.FontOptions, err = c.resolveFontOptions(ctx, req.FontOptions); err != nil {
+		return nil, err
+	}
+
+	threshold := req.SplitRows
+	if threshold < 0 || threshold > DefaultSplitRows {
+		return nil, fmt.Errorf("docgen: splitRows %d out of range [1, %d]", threshold, DefaultSplitRows)
+	}
+	if threshold == 0 {
+		threshold = DefaultSplitRows
+	}
+	sheets := splitSheets(req.SheetName, len(req.Data), threshold)
+	if len(sheets) == 1 {
+		// 无需拆分时不发送 splitRows，兼容不支持拆分的服务端
+		req.SplitRows = 0
+		return sheets, nil
+	}
+
+	if err := c.rejectUnsupported(ctx, FeatureSheetSplit); err != nil {
+		return nil, err
+	}
+	req.SplitRows = threshold
+	return sheets, nil
+}
+
+// splitSheets 计算按 threshold 拆分后的工作表布局
+//
+// 只有一个工作表时使用 sheetName（默认 "Sheet1"）；拆分时命名为 "<sheetName>_1"、"<sheetName>_2"……，
+// sheetName 为空时使用 "Data"
+func splitSheets(sheetName string, rows, threshold int) []SheetLayout {
+	if rows <= threshold {
+		if sheetName == "" {
+			sheetName = "Sheet1"
+		}
+		return []SheetLayout{{Name: sheetName, Rows: rows}}
+	}
+
+	if sheetName == "" {
+		sheetName = "Data"
+	}
+	var sheets []SheetLayout
+	for first, i := 0, 1; first < rows; first, i = first+threshold, i+1 {
+		n := threshold
+		if rows-first < n {
+			n = rows - first
+		}
+		sheets = append(sheets, SheetLayout{Name: sheetName + "_" + strconv.Itoa(i), FirstRow: first, Rows: n})
+	}
+	return sheets
+}
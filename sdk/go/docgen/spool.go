@@ -0,0 +1,287 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// spoolFilePattern 暂存文件名模式
+const spoolFilePattern = "docgen-spool-*"
+
+// ErrResultClosed SpooledResult 已关闭或已通过 MoveTo 移走
+var ErrResultClosed = errors.New("docgen: result closed")
+
+// spoolConfig WithResultSpooling 的配置
+type spoolConfig struct {
+	dir       string
+	threshold int64
+}
+
+// SpooledResult 生成结果的句柄：小于 WithResultSpooling 阈值的文档保存在内存中，超过阈值的文档暂存在磁盘上
+//
+// 使用完毕后需调用 Close 删除暂存文件；通过 MoveTo 保存后无需再处理暂存文件，但调用 Close 仍然安全
+type SpooledResult struct {
+	// Meta 文档元数据
+	Meta DocumentMeta
+
+	mu     sync.Mutex
+	data   []byte
+	path   string
+	size   int64
+	closed bool
+}
+
+// Size 文档大小（字节）
+func (r *SpooledResult) Size() int64 {
+	return r.size
+}
+
+// Spooled 文档是否暂存在磁盘上
+func (r *SpooledResult) Spooled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.path != ""
+}
+
+// Open 打开文档内容，可多次调用，每次返回独立的读取位置；调用方负责关闭
+func (r *SpooledResult) Open() (io.ReadSeekCloser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, ErrResultClosed
+	}
+	if r.path != "" {
+		return os.Open(r.path)
+	}
+	return nopSeekCloser{bytes.NewReader(r.data)}, nil
+}
+
+// MoveTo 将文档保存到 path：暂存在磁盘上时重命名暂存文件（不复制内容，跨文件系统时退化为复制），
+// 否则写入新文件；两种方式得到的文件权限相同。成功后句柄关闭，Open 返回 ErrResultClosed
+func (r *SpooledResult) MoveTo(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrResultClosed
+	}
+	if r.path == "" {
+		if err := writeFile(path, r.data); err != nil {
+			return err
+		}
+	} else if err := moveFile(r.path, path); err != nil {
+		return err
+	}
+	r.closed, r.data, r.path = true, nil, ""
+	return nil
+}
+
+// moveFile 将暂存文件移动到 dst，权限与 writeFile 写入的文件相同（暂存文件为 0600）：
+// dst 已存在时保持其权限，否则为 0666 去掉 umask
+func moveFile(src, dst string) error {
+	var mode fs.FileMode
+	created := false
+	if info, err := os.Stat(dst); err == nil {
+		mode = info.Mode().Perm()
+	} else {
+		f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		f.Close()
+		if err != nil {
+			os.Remove(dst)
+			return err
+		}
+		mode, created = info.Mode().Perm(), true
+	}
+	if err := os.Rename(src, dst); err != nil {
+		// 跨文件系统时复制，写入已存在的 dst 保持其权限
+		if err := copyFile(src, dst); err != nil {
+			if created {
+				os.Remove(dst)
+			}
+			return err
+		}
+		os.Remove(src)
+		return nil
+	}
+	return os.Chmod(dst, mode)
+}
+
+// Close 删除暂存文件并释放内存，可重复调用
+func (r *SpooledResult) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed, r.data = true, nil
+	if r.path == "" {
+		return nil
+	}
+	path := r.path
+	r.path = ""
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// nopSeekCloser 为内存中的文档提供空操作的 Close
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+// Close 实现 io.Closer 接口
+func (nopSeekCloser) Close() error {
+	return nil
+}
+
+// copyFile 复制文件内容，失败时删除目标文件
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+	}
+	return err
+}
+
+// spoolWriter 在内存中缓存写入的内容，超过阈值后转存到暂存文件
+type spoolWriter struct {
+	cfg  *spoolConfig
+	buf  bytes.Buffer
+	file *os.File
+	size int64
+}
+
+// Write 实现 io.Writer 接口
+func (w *spoolWriter) Write(p []byte) (int, error) {
+	if w.file == nil && w.cfg != nil && int64(w.buf.Len()+len(p)) > w.cfg.threshold {
+		f, err := os.CreateTemp(w.cfg.dir, spoolFilePattern)
+		if err != nil {
+			return 0, fmt.Errorf("docgen: create spool file: %w", err)
+		}
+		w.file = f
+		if _, err := f.Write(w.buf.Bytes()); err != nil {
+			return 0, fmt.Errorf("docgen: write spool file: %w", err)
+		}
+		w.buf = bytes.Buffer{}
+	}
+	var n int
+	var err error
+	if w.file != nil {
+		if n, err = w.file.Write(p); err != nil {
+			err = fmt.Errorf("docgen: write spool file: %w", err)
+		}
+	} else {
+		n, err = w.buf.Write(p)
+	}
+	w.size += int64(n)
+	return n, err
+}
+
+// finish 关闭暂存文件
+func (w *spoolWriter) finish() error {
+	if w.file == nil {
+		return nil
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("docgen: close spool file: %w", err)
+	}
+	return nil
+}
+
+// discard 删除暂存文件（请求失败时调用）
+func (w *spoolWriter) discard() {
+	if w.file != nil {
+		w.file.Close()
+		os.Remove(w.file.Name())
+	}
+}
+
+// spoolResult 将 fn 写入的文档保存为 SpooledResult，失败时删除暂存文件
+func (c *Client) spoolResult(fn func(w io.Writer) (*DocumentMeta, error)) (*SpooledResult, error) {
+	return spoolTo(c.spool, fn)
+}
+
+// spoolTo 按 cfg 暂存 fn 写入的文档，cfg 为 nil 时保存在内存中
+func spoolTo(cfg *spoolConfig, fn func(w io.Writer) (*DocumentMeta, error)) (*SpooledResult, error) {
+	w := &spoolWriter{cfg: cfg}
+	meta, err := fn(w)
+	if err == nil {
+		err = w.finish()
+	}
+	if err != nil {
+		w.discard()
+		return nil, err
+	}
+	result := &SpooledResult{Meta: *meta, size: w.size}
+	if w.file != nil {
+		result.path = w.file.Name()
+	} else {
+		result.data = w.buf.Bytes()
+	}
+	return result, nil
+}
+
+// GenerateWordSpooled 生成 Word 文档，超过 WithResultSpooling 阈值时暂存到磁盘而不是保存在内存中
+//
+// 未启用 WithResultSpooling 时文档保存在内存中。与 GenerateWordTo 一样流式读取响应，不执行 WithOutputValidation 校验
+func (c *Client) GenerateWordSpooled(ctx context.Context, req WordGenRequest) (*SpooledResult, error) {
+	return c.spoolResult(func(w io.Writer) (*DocumentMeta, error) {
+		return c.GenerateWordTo(ctx, req, w)
+	})
+}
+
+// BatchGenerateWordSpooled 批量生成 Word 文档，行为与 GenerateWordSpooled 一致
+func (c *Client) BatchGenerateWordSpooled(ctx context.Context, req WordBatchRequest) (*SpooledResult, error) {
+	return c.spoolResult(func(w io.Writer) (*DocumentMeta, error) {
+		return c.BatchGenerateWordTo(ctx, req, w)
+	})
+}
+
+// GenerateExcelSpooled 生成 Excel 文档，行为与 GenerateWordSpooled 一致
+func (c *Client) GenerateExcelSpooled(ctx context.Context, req ExcelGenRequest) (*SpooledResult, error) {
+	return c.spoolResult(func(w io.Writer) (*DocumentMeta, error) {
+		return c.GenerateExcelTo(ctx, req, w)
+	})
+}
+
+// FillExcelTemplateSpooled 填充 Excel 模板，行为与 GenerateWordSpooled 一致
+func (c *Client) FillExcelTemplateSpooled(ctx context.Context, req ExcelFillRequest) (*SpooledResult, error) {
+	ctx = withCallCorrelationID(ctx)
+	return c.spoolResult(func(w io.Writer) (*DocumentMeta, error) {
+		if err := c.prepareFill(ctx, &req); err != nil {
+			return nil, err
+		}
+		return c.postDocumentTo(ctx, "/api/v1/doc/excel/fill", req, w)
+	})
+}
+
+// saveSpooled 将生成结果保存到 path，Save* 系列方法在启用 WithResultSpooling 时使用
+func saveSpooled(result *SpooledResult, err error, path string) error {
+	if err != nil {
+		return err
+	}
+	defer result.Close()
+	return result.MoveTo(path)
+}
@@ -0,0 +1,76 @@
+package docgen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+func TestSpooledSaveKeepsFileMode(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	spoolDir := t.TempDir()
+	out := t.TempDir()
+
+	plain := filepath.Join(out, "plain.docx")
+	if err := docgen.NewClient(srv.URL).SaveWord("t.docx", map[string]any{"a": 1}, plain); err != nil {
+		t.Fatal(err)
+	}
+	spooling := docgen.NewClient(srv.URL, docgen.WithResultSpooling(spoolDir, 0))
+	spooled := filepath.Join(out, "spooled.docx")
+	if err := spooling.SaveWord("t.docx", map[string]any{"a": 1}, spooled); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fileMode(t, spooled), fileMode(t, plain); got != want {
+		t.Errorf("spooled save mode %v, plain save mode %v", got, want)
+	}
+
+	existing := filepath.Join(out, "existing.docx")
+	if err := os.WriteFile(existing, nil, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(existing, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := spooling.SaveWord("t.docx", map[string]any{"a": 1}, existing); err != nil {
+		t.Fatal(err)
+	}
+	if got := fileMode(t, existing); got != 0640 {
+		t.Errorf("overwritten file mode %v, want 0640", got)
+	}
+
+	if left, _ := os.ReadDir(spoolDir); len(left) != 0 {
+		t.Errorf("spool files left behind: %v", left)
+	}
+}
+
+func TestSpooledSaveFailureLeavesNoFiles(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	spoolDir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "missing.docx")
+
+	client := docgen.NewClient(srv.URL, docgen.WithResultSpooling(spoolDir, 0))
+	if err := client.SaveWord("missing.docx", map[string]any{"a": 1}, out); err == nil {
+		t.Fatal("want error for a missing template")
+	}
+	if left, _ := os.ReadDir(spoolDir); len(left) != 0 {
+		t.Errorf("spool files left behind: %v", left)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("output created on failure: %v", err)
+	}
+}
+
+func fileMode(t *testing.T, path string) os.FileMode {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Mode().Perm()
+}
@@ -0,0 +1,240 @@
+package docgen
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JobEventStreamError 客户端产生的事件：进度流中断且重连失败，原因见 JobEvent.Err
+const JobEventStreamError JobEventType = "stream_error"
+
+// 进度流重连参数
+const (
+	// sseMaxReconnects 连续重连失败的上限
+	sseMaxReconnects = 5
+	// sseDefaultRetry 默认重连间隔，服务端可通过 retry 字段调整
+	sseDefaultRetry = time.Second
+)
+
+// StreamJobProgress 订阅任务进度事件流（Server-Sent Events，/api/v1/jobs/{id}/events）
+//
+// ctx: 取消后关闭事件流
+// jobID: 任务 ID
+//
+// 返回的通道依次输出进度、日志与结束事件；连接意外断开时携带 Last-Event-ID 自动重连，
+// 已收到的事件不会重复输出。任务进入终止状态（completed / failed / cancelled）
+// 或 ctx 取消、客户端 Close 后通道关闭；重连失败时先输出一个 JobEventStreamError 事件再关闭。
+// 首次连接失败（如任务不存在）直接返回错误
+func (c *Client) StreamJobProgress(ctx context.Context, jobID string) (<-chan JobEvent, error) {
+	// 客户端 Close 时停止事件流
+	ctx, done, err := c.track(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.openEventStream(ctx, jobID, "")
+	if err != nil {
+		done()
+		return nil, err
+	}
+
+	events := make(chan JobEvent)
+	go func() {
+		defer done()
+		c.streamJobEvents(ctx, jobID, body, events)
+	}()
+	return events, nil
+}
+
+// openEventStream 建立事件流连接
+func (c *Client) openEventStream(ctx context.Context, jobID, lastEventID string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, jobPath(jobID, "/events"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := c.doStream(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, c.parseErrorResponse(req, resp, respBody)
+	}
+	return resp.Body, nil
+}
+
+// streamJobEvents 读取事件流并在断线时重连，结束时关闭 events
+func (c *Client) streamJobEvents(ctx context.Context, jobID string, body io.ReadCloser, events chan<- JobEvent) {
+	defer close(events)
+
+	stream := &sseStream{jobID: jobID, retry: sseDefaultRetry}
+	failures := 0
+	for {
+		received, terminal, readErr := stream.read(ctx, body, events)
+		body.Close()
+		if terminal || ctx.Err() != nil {
+			return
+		}
+		if received > 0 {
+			failures = 0
+		}
+
+		// 连接意外断开（EOF 或读取错误），携带 Last-Event-ID 重连
+		lastErr := readErr
+		if lastErr == nil {
+			lastErr = io.ErrUnexpectedEOF
+		}
+		for {
+			failures++
+			if failures > sseMaxReconnects {
+				stream.emit(ctx, events, JobEvent{Type: JobEventStreamError, JobID: jobID, Time: time.Now(),
+					Err: fmt.Errorf("event stream for job %s lost after %d reconnects: %w", jobID, sseMaxReconnects, lastErr)})
+				return
+			}
+			if err := c.spendRetry(ctx, "event stream for job "+jobID, lastErr); err != nil {
+				stream.emit(ctx, events, JobEvent{Type: JobEventStreamError, JobID: jobID, Time: time.Now(), Err: err})
+				return
+			}
+
+			select {
+			case <-time.After(stream.retry):
+			case <-ctx.Done():
+				return
+			}
+
+			var err error
+			body, err = c.openEventStream(withRetryAttempt(ctx), jobID, stream.lastID)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			lastErr = err
+			if !IsRetryable(err) {
+				stream.emit(ctx, events, JobEvent{Type: JobEventStreamError, JobID: jobID, Time: time.Now(), Err: err})
+				return
+			}
+		}
+	}
+}
+
+// sseStream 事件流解析状态，跨重连保留
+type sseStream struct {
+	jobID  string
+	lastID string
+	retry  time.Duration
+}
+
+// read 读取单个连接上的事件，返回收到的事件数以及是否已收到终止事件
+func (s *sseStream) read(ctx context.Context, body io.Reader, events chan<- JobEvent) (int, bool, error) {
+	r := bufio.NewReader(body)
+	var eventName, eventID string
+	var data strings.Builder
+	hasData := false
+	received := 0
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			// 未以空行结束的事件不完整，按规范丢弃
+			if errors.Is(err, io.EOF) {
+				return received, false, nil
+			}
+			return received, false, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			// 空行：分发事件
+			if eventID != "" {
+				s.lastID = eventID
+			}
+			if hasData {
+				event := s.decode(eventName, eventID, data.String())
+				received++
+				if !s.emit(ctx, events, event) {
+					return received, false, ctx.Err()
+				}
+				if event.Type.Terminal() {
+					return received, true, nil
+				}
+			}
+			eventName, eventID = "", ""
+			data.Reset()
+			hasData = false
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// 注释（心跳）
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventName = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				eventID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// decode 将 SSE 事件转换为 JobEvent
+//
+// data 为 JobEvent JSON；log 事件的 data 也可以是纯文本
+func (s *sseStream) decode(name, id, data string) JobEvent {
+	var event JobEvent
+	if err := decodeJSON([]byte(data), &event); err != nil {
+		event = JobEvent{Message: data}
+		if name == "" {
+			name = string(JobEventLog)
+		}
+	}
+	if event.Type == "" {
+		event.Type = JobEventType(name)
+	}
+	if event.ID == "" {
+		event.ID = id
+	}
+	if event.JobID == "" {
+		event.JobID = s.jobID
+	}
+	return event
+}
+
+// emit 输出事件，ctx 取消时返回 false
+func (s *sseStream) emit(ctx context.Context, events chan<- JobEvent, event JobEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,143 @@
+package docgen_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// jobEventsEndpoint 任务 j1 的进度流路径
+const jobEventsEndpoint = docgentest.EndpointJobs + "/j1/events"
+
+// sseTotal 任务 j1 的事件总数：sseTotal-1 个进度事件与一个完成事件
+const sseTotal = 5
+
+// sseServer 提供任务 j1 进度流的 docgentest.Server，按 Last-Event-ID 续发事件
+type sseServer struct {
+	*docgentest.Server
+}
+
+// newSSEServer 第 n 次连接发送 connections[n] 后断开（如 InterruptedEventStream），之后的连接发送剩余的全部事件
+func newSSEServer(t *testing.T, connections ...docgentest.Response) *sseServer {
+	t.Helper()
+	s := &sseServer{docgentest.NewServer(docgentest.WithSequence(jobEventsEndpoint, connections...))}
+	t.Cleanup(s.Close)
+	s.AddJob(docgen.Job{ID: "j1", State: docgen.JobQueued}, nil)
+	for done := int64(1); done < sseTotal; done++ {
+		s.PublishJobEvent(docgen.JobEvent{Type: docgen.JobEventProgress, JobID: "j1", Progress: &docgen.JobProgress{Done: done, Total: sseTotal - 1}})
+	}
+	s.PublishJobEvent(docgen.JobEvent{Type: docgen.JobEventCompleted, JobID: "j1"})
+	return s
+}
+
+// interrupted 返回各次连接依次发送 batches[n] 个事件后中断的响应
+func interrupted(batches ...int) []docgentest.Response {
+	connections := make([]docgentest.Response, len(batches))
+	for i, n := range batches {
+		connections[i] = docgentest.InterruptedEventStream(n)
+	}
+	return connections
+}
+
+// headers 返回各次连接的 Last-Event-ID 请求头
+func (s *sseServer) headers() []string {
+	var ids []string
+	for _, r := range s.RequestsTo(jobEventsEndpoint) {
+		ids = append(ids, r.Header.Get("Last-Event-ID"))
+	}
+	return ids
+}
+
+func collect(t *testing.T, events <-chan docgen.JobEvent) []docgen.JobEvent {
+	t.Helper()
+	var got []docgen.JobEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return got
+			}
+			got = append(got, e)
+		case <-timeout:
+			t.Fatalf("event stream not closed, got %d events", len(got))
+		}
+	}
+}
+
+func TestStreamJobProgressReconnectsWithLastEventID(t *testing.T) {
+	srv := newSSEServer(t, interrupted(2, 1, 0)...)
+	client := docgen.NewClient(srv.URL)
+
+	events, err := client.StreamJobProgress(context.Background(), "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := collect(t, events)
+	if len(got) != sseTotal {
+		t.Fatalf("got %d events: %+v", len(got), got)
+	}
+	for i, e := range got {
+		if e.ID != strconv.Itoa(i+1) || e.JobID != "j1" {
+			t.Errorf("event %d = %+v", i, e)
+		}
+		if i < sseTotal-1 && (e.Type != docgen.JobEventProgress || e.Progress == nil || e.Progress.Done != int64(i+1)) {
+			t.Errorf("event %d = %+v, want progress %d", i, e, i+1)
+		}
+	}
+	if last := got[sseTotal-1]; last.Type != docgen.JobEventCompleted {
+		t.Errorf("last event = %+v, want completed", last)
+	}
+	// 空批次的连接没有新事件，同样以原来的 Last-Event-ID 重连
+	if headers := srv.headers(); fmt.Sprint(headers) != fmt.Sprint([]string{"", "2", "3", "3"}) {
+		t.Errorf("Last-Event-ID per connection = %q", headers)
+	}
+	if stats := client.Stats().Endpoints["GET /api/v1/jobs/{id}/events"]; stats.Requests != 4 || stats.Retries != 3 {
+		t.Errorf("events stats = %+v", stats)
+	}
+}
+
+func TestStreamJobProgressGivesUpOnNonRetryableError(t *testing.T) {
+	srv := newSSEServer(t, docgentest.InterruptedEventStream(1), docgentest.ErrorResponse(http.StatusForbidden, docgen.CodeForbidden, "token revoked"))
+	client := docgen.NewClient(srv.URL)
+	if _, err := client.StreamJobProgress(context.Background(), "missing"); err == nil {
+		t.Fatal("want an error for an unknown job")
+	}
+
+	events, err := client.StreamJobProgress(context.Background(), "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 断开后重连被拒绝时以 JobEventStreamError 结束
+	got := collect(t, events)
+	if len(got) != 2 || got[0].ID != "1" {
+		t.Fatalf("events = %+v, want one progress event and a stream error", got)
+	}
+	last := got[len(got)-1]
+	var errResp *docgen.ErrorResponse
+	if last.Type != docgen.JobEventStreamError || !errors.As(last.Err, &errResp) || errResp.Status != http.StatusForbidden {
+		t.Errorf("last event = %+v, want a stream error carrying the 403", last)
+	}
+}
+
+func TestStreamJobProgressStopsOnCancel(t *testing.T) {
+	srv := newSSEServer(t, interrupted(1, 0, 0, 0, 0, 0, 0, 0)...)
+	client := docgen.NewClient(srv.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.StreamJobProgress(ctx, "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := <-events; e.ID != "1" {
+		t.Fatalf("first event = %+v", e)
+	}
+	cancel()
+	collect(t, events)
+}
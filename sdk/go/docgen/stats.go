@@ -0,0 +1,319 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FailureKind 失败请求的分类，见 EndpointStats.Failures
+type FailureKind string
+
+const (
+	// FailureTimeout 请求超时（ErrTimeout）
+	FailureTimeout FailureKind = "timeout"
+	// FailureUnreachable 无法连接服务（ErrUnreachable）
+	FailureUnreachable FailureKind = "unreachable"
+	// FailureCanceled 调用方取消了请求
+	FailureCanceled FailureKind = "canceled"
+	// FailureRateLimited 服务端限流（429）
+	FailureRateLimited FailureKind = "rate-limited"
+	// FailureClientError 其他 4xx 响应（参数错误、模板不存在等）
+	FailureClientError FailureKind = "client-error"
+	// FailureServerError 5xx 响应
+	FailureServerError FailureKind = "server-error"
+	// FailureOther 其他错误（如读取响应体失败）
+	FailureOther FailureKind = "other"
+)
+
+// failureKinds 全部失败分类，下标与 endpointCounters.failures 对应
+var failureKinds = []FailureKind{
+	FailureTimeout, FailureUnreachable, FailureCanceled, FailureRateLimited,
+	FailureClientError, FailureServerError, FailureOther,
+}
+
+// Stats 客户端请求统计的快照，由 Client.Stats 返回，可直接序列化为 JSON 暴露在服务自身的调试接口上
+type Stats struct {
+	// Since 统计开始的时间：创建客户端或最近一次 ResetStats 的时间
+	Since time.Time `json:"since"`
+	// InFlight 已发出、尚未收到响应的请求数（不受 ResetStats 影响）
+	InFlight int64 `json:"inFlight"`
+	// Endpoints 按接口统计，键为方法与路径，如 "POST /api/v1/doc/word"；
+	// 路径中的任务 ID、模板名称等替换为 {id}、{name}、{page}，如 "GET /api/v1/jobs/{id}/result"
+	Endpoints map[string]EndpointStats `json:"endpoints"`
+	// RateLimit 最近一次响应报告的限流状态（见 RateLimitStatus），尚未收到时为 nil
+	RateLimit *RateLimitStatus `json:"rateLimit,omitempty"`
+	// Health WithHealthCache 缓存的健康探测结果（HealthState.String()），未启用或缓存已过期时为空
+	Health string `json:"health,omitempty"`
+}
+
+// EndpointStats 一个接口的请求统计
+type EndpointStats struct {
+	// Requests 请求数，每次重试计为一次请求，同一请求的对冲请求不单独计数（见 Hedges）
+	Requests int64 `json:"requests"`
+	// Successes 收到 2xx / 3xx 响应的请求数
+	Successes int64 `json:"successes"`
+	// Failures 按分类统计的失败请求数，只包含出现过的分类
+	Failures map[FailureKind]int64 `json:"failures,omitempty"`
+	// Retries SDK 重试发出的请求数（可续传下载、事件流重连、发件箱重试、WithDegradedMode 的简化渲染）
+	Retries int64 `json:"retries"`
+	// Hedges WithHedging 额外发出的对冲请求数
+	Hedges int64 `json:"hedges"`
+	// InFlight 已发出、尚未收到响应的请求数
+	InFlight int64 `json:"inFlight"`
+	// LastError 最近一次失败，没有失败时为 nil
+	LastError *ErrorSnapshot `json:"lastError,omitempty"`
+}
+
+// ErrorSnapshot 一次失败请求的摘要
+type ErrorSnapshot struct {
+	// Kind 失败分类
+	Kind FailureKind `json:"kind"`
+	// Status 响应状态码，未收到响应时为 0
+	Status int `json:"status,omitempty"`
+	// Code 服务端错误码（ErrorResponse.Code），未收到错误响应时为空
+	Code string `json:"code,omitempty"`
+	// Message 错误信息
+	Message string `json:"message"`
+	// At 失败的时间
+	At time.Time `json:"at"`
+	// CorrelationID 请求的关联 ID，用于在服务端日志中检索
+	CorrelationID string `json:"correlationId,omitempty"`
+}
+
+// Stats 返回请求统计的快照
+//
+// 计数使用原子操作维护，可在任意 goroutine 中频繁调用；各计数分别读取，并发请求进行时彼此之间可能相差正在完成的请求
+func (c *Client) Stats() Stats {
+	state := c.stats.current()
+	stats := Stats{
+		Since:     state.since,
+		InFlight:  c.stats.inFlight.Load(),
+		Endpoints: make(map[string]EndpointStats),
+	}
+	state.endpoints.Range(func(key, value any) bool {
+		stats.Endpoints[key.(string)] = value.(*endpointCounters).snapshot()
+		return true
+	})
+	if status, ok := c.RateLimitStatus(); ok {
+		stats.RateLimit = &status
+	}
+	if state, ok := c.healthCache.cached(); ok {
+		stats.Health = state.String()
+	}
+	return stats
+}
+
+// ResetStats 清零请求统计并将 Stats.Since 设为当前时间；重置前已发出的请求完成时不计入新的统计
+func (c *Client) ResetStats() {
+	c.stats.state.Store(newStatsState())
+}
+
+// clientStats 客户端请求统计，零值可用
+type clientStats struct {
+	// inFlight 全部接口的进行中请求数，重置统计时保留
+	inFlight atomic.Int64
+	state    atomic.Pointer[statsState]
+}
+
+// statsState 一个统计周期的计数，ResetStats 时整体替换
+type statsState struct {
+	since time.Time
+	// endpoints 接口键到 *endpointCounters
+	endpoints sync.Map
+}
+
+// newStatsState 创建从当前时间开始的统计周期
+func newStatsState() *statsState {
+	return &statsState{since: time.Now()}
+}
+
+// current 返回当前统计周期，首次调用时创建
+func (s *clientStats) current() *statsState {
+	if state := s.state.Load(); state != nil {
+		return state
+	}
+	s.state.CompareAndSwap(nil, newStatsState())
+	return s.state.Load()
+}
+
+// endpointCounters 一个接口的原子计数
+type endpointCounters struct {
+	requests  atomic.Int64
+	successes atomic.Int64
+	retries   atomic.Int64
+	hedges    atomic.Int64
+	inFlight  atomic.Int64
+	// failures 按 failureKinds 的顺序计数
+	failures  [7]atomic.Int64
+	lastError atomic.Pointer[ErrorSnapshot]
+}
+
+// snapshot 读取计数
+func (e *endpointCounters) snapshot() EndpointStats {
+	stats := EndpointStats{
+		Requests:  e.requests.Load(),
+		Successes: e.successes.Load(),
+		Retries:   e.retries.Load(),
+		Hedges:    e.hedges.Load(),
+		InFlight:  e.inFlight.Load(),
+		LastError: e.lastError.Load(),
+	}
+	for i, kind := range failureKinds {
+		if n := e.failures[i].Load(); n > 0 {
+			if stats.Failures == nil {
+				stats.Failures = make(map[FailureKind]int64)
+			}
+			stats.Failures[kind] = n
+		}
+	}
+	return stats
+}
+
+// recordFailure 计入一次失败并记录为最近一次失败
+func (e *endpointCounters) recordFailure(snapshot *ErrorSnapshot) {
+	for i, kind := range failureKinds {
+		if kind == snapshot.Kind {
+			e.failures[i].Add(1)
+		}
+	}
+	e.lastError.Store(snapshot)
+}
+
+// statsRequest 一个进行中的请求，由 clientStats.begin 创建
+type statsRequest struct {
+	stats    *clientStats
+	counters *endpointCounters
+}
+
+// begin 开始统计一个已发出的请求，返回的 statsRequest 需以 end 结束
+func (s *clientStats) begin(req *http.Request) statsRequest {
+	counters := s.endpoint(req, true)
+	counters.requests.Add(1)
+	if isRetryAttempt(req.Context()) {
+		counters.retries.Add(1)
+	}
+	counters.inFlight.Add(1)
+	s.inFlight.Add(1)
+	return statsRequest{stats: s, counters: counters}
+}
+
+// end 结束统计：resp 为最终采用的响应，err 为传输层错误
+func (r statsRequest) end(req *http.Request, resp *http.Response, err error, hedge hedgeOutcome) {
+	r.counters.inFlight.Add(-1)
+	r.stats.inFlight.Add(-1)
+	r.counters.hedges.Add(int64(hedge.hedges))
+	switch {
+	case err != nil:
+		r.counters.recordFailure(&ErrorSnapshot{
+			Kind: transportFailureKind(req, err), Message: err.Error(), At: time.Now(), CorrelationID: requestCorrelationID(req),
+		})
+	case resp.StatusCode < 400:
+		r.counters.successes.Add(1)
+	default:
+		r.counters.recordFailure(&ErrorSnapshot{
+			Kind: statusFailureKind(resp.StatusCode), Status: resp.StatusCode, Message: http.StatusText(resp.StatusCode),
+			At: time.Now(), CorrelationID: requestCorrelationID(req),
+		})
+	}
+}
+
+// observeErrorResponse 以解析出的服务端错误补充接口最近一次失败的错误码与信息
+func (s *clientStats) observeErrorResponse(req *http.Request, err error) {
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		return
+	}
+	counters := s.endpoint(req, false)
+	if counters == nil {
+		return
+	}
+	counters.lastError.Store(&ErrorSnapshot{
+		Kind: statusFailureKind(errResp.Status), Status: errResp.Status, Code: errResp.Code, Message: errResp.Message,
+		At: time.Now(), CorrelationID: requestCorrelationID(req),
+	})
+}
+
+// endpoint 返回请求所属接口的计数，create 为 false 且尚无计数时返回 nil
+func (s *clientStats) endpoint(req *http.Request, create bool) *endpointCounters {
+	state := s.current()
+	key := req.Method + " " + statsEndpoint(req.URL.Path)
+	if counters, ok := state.endpoints.Load(key); ok {
+		return counters.(*endpointCounters)
+	}
+	if !create {
+		return nil
+	}
+	counters, _ := state.endpoints.LoadOrStore(key, &endpointCounters{})
+	return counters.(*endpointCounters)
+}
+
+// transportFailureKind 传输层错误的分类
+func transportFailureKind(req *http.Request, err error) FailureKind {
+	switch {
+	case errors.Is(err, ErrTimeout):
+		return FailureTimeout
+	case req.Context().Err() != nil:
+		return FailureCanceled
+	case errors.Is(err, ErrUnreachable):
+		return FailureUnreachable
+	}
+	return FailureOther
+}
+
+// statusFailureKind 错误响应状态码的分类
+func statusFailureKind(status int) FailureKind {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return FailureRateLimited
+	case status >= 500:
+		return FailureServerError
+	case status >= 400:
+		return FailureClientError
+	}
+	return FailureOther
+}
+
+// statsEndpoint 将请求路径中的 ID 与名称替换为占位符，使统计的接口数有限
+func statsEndpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i > 0; i-- {
+		switch segments[i-1] {
+		case "jobs":
+			if segments[i] != "word" && segments[i] != "excel" {
+				segments[i] = "{id}"
+			}
+		case "results", "links", "uploads":
+			segments[i] = "{id}"
+		case "download", "variables", "dependencies":
+			segments[i] = "{name}"
+		case "pages":
+			segments[i] = "{page}"
+		case "template":
+			switch segments[i] {
+			case "list", "upload", "uploads", "info", "download", "variables", "dependencies":
+			default:
+				segments[i] = "{name}"
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// retryAttemptContextKey 标记 SDK 重试发出的请求
+type retryAttemptContextKey struct{}
+
+// withRetryAttempt 返回标记为重试的 context，其发出的请求计入 EndpointStats.Retries
+func withRetryAttempt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryAttemptContextKey{}, true)
+}
+
+// isRetryAttempt 请求是否由 SDK 重试发出
+func isRetryAttempt(ctx context.Context) bool {
+	retry, _ := ctx.Value(retryAttemptContextKey{}).(bool)
+	return retry
+}
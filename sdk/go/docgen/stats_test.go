@@ -0,0 +1,125 @@
+package docgen_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+const statsWordEndpoint = "POST /api/v1/doc/word"
+
+func overloaded() docgentest.Response {
+	return docgentest.ErrorResponse(http.StatusServiceUnavailable, docgen.CodeOverloaded, "server overloaded")
+}
+
+// TestStatsConcurrentReaders 在 -race 下运行：并发请求的同时读取、序列化并重置统计
+func TestStatsConcurrentReaders(t *testing.T) {
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	client := docgen.NewClient(srv.URL, docgen.WithHealthCache(time.Minute))
+
+	const workers, calls = 8, 25
+	run := func(reset bool) {
+		stop := make(chan struct{})
+		var readers sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			readers.Add(1)
+			go func(i int) {
+				defer readers.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					case <-time.After(100 * time.Microsecond):
+					}
+					stats := client.Stats()
+					if _, err := json.Marshal(stats); err != nil {
+						t.Errorf("marshal stats: %v", err)
+						return
+					}
+					if reset && i == 0 {
+						client.ResetStats()
+					}
+				}
+			}(i)
+		}
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < calls; i++ {
+					if _, err := client.GenerateWordContext(context.Background(), wordReq); err != nil {
+						t.Errorf("generate: %v", err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(stop)
+		readers.Wait()
+	}
+
+	run(true)
+	client.ResetStats()
+	run(false)
+
+	stats := client.Stats()
+	word := stats.Endpoints[statsWordEndpoint]
+	if word.Requests != workers*calls || word.Successes != workers*calls || word.InFlight != 0 || stats.InFlight != 0 {
+		t.Errorf("word stats = %+v, total in-flight %d; want %d requests and successes", word, stats.InFlight, workers*calls)
+	}
+}
+
+func TestStatsFailuresAndJSON(t *testing.T) {
+	srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointWord,
+		docgentest.ErrorResponse(http.StatusUnprocessableEntity, docgen.CodeRenderError, "bad data"),
+		overloaded(),
+	))
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("hello"))
+	client := docgen.NewClient(srv.URL)
+
+	ctx := docgen.WithCorrelationID(context.Background(), "corr-1")
+	for i := 0; i < 3; i++ {
+		_, _ = client.GenerateWordContext(ctx, wordReq)
+	}
+	stats := client.Stats()
+	word := stats.Endpoints[statsWordEndpoint]
+	if word.Requests != 3 || word.Successes != 1 ||
+		word.Failures[docgen.FailureClientError] != 1 || word.Failures[docgen.FailureServerError] != 1 {
+		t.Errorf("word stats = %+v", word)
+	}
+	if last := word.LastError; last == nil || last.Code != docgen.CodeOverloaded || last.Status != http.StatusServiceUnavailable || last.CorrelationID != "corr-1" {
+		t.Errorf("LastError = %+v", last)
+	}
+
+	raw, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	endpoint, _ := decoded["endpoints"].(map[string]any)[statsWordEndpoint].(map[string]any)
+	if endpoint["requests"] != 3.0 {
+		t.Errorf("endpoints JSON = %v", decoded["endpoints"])
+	}
+	if _, ok := decoded["health"]; ok {
+		t.Error("health reported without WithHealthCache")
+	}
+
+	client.ResetStats()
+	if after := client.Stats(); len(after.Endpoints) != 0 || !after.Since.After(stats.Since) {
+		t.Errorf("after ResetStats = %+v", after)
+	}
+}
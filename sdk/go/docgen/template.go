@@ -2,7 +2,8 @@ package docgen
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -10,6 +11,9 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // UploadResponse 上传模板响应
@@ -17,12 +21,14 @@ type UploadResponse struct {
 	Success  bool   `json:"success"`
 	Message  string `json:"message"`
 	FileName string `json:"fileName"`
+	// ExpiresAt 临时模板的过期时间（UploadTemporaryTemplate），永久模板为 nil
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 // ListTemplatesResponse 模板列表响应
 type ListTemplatesResponse struct {
 	Success   bool     `json:"success"`
-	Count     int      `json:"count"`
+	Count     int64    `json:"count"`
 	Templates []string `json:"templates"`
 }
 
@@ -30,7 +36,8 @@ type ListTemplatesResponse struct {
 //
 // filePath: 本地模板文件路径
 //
-// 返回上传结果，包含保存后的文件名
+// 返回上传结果，包含保存后的文件名。上传前按扩展名与文件头检查格式：旧版 .doc / .xls 返回 *LegacyFormatError，
+// 启用宏的 .docm / .xlsm 需 WithMacroTemplates(true)，否则返回 ErrMacroTemplatesDisabled
 func (c *Client) UploadTemplate(filePath string) (*UploadResponse, error) {
 	// 打开文件
 	file, err := os.Open(filePath)
@@ -38,6 +45,9 @@ func (c *Client) UploadTemplate(filePath string) (*UploadResponse, error) {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
+	if err := c.checkTemplateFile(filepath.Base(filePath), file); err != nil {
+		return nil, err
+	}
 
 	// 创建 multipart 表单
 	body := &bytes.Buffer{}
@@ -59,51 +69,54 @@ func (c *Client) UploadTemplate(filePath string) (*UploadResponse, error) {
 		return nil, fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	// 构建 HTTP 请求
-	url := fmt.Sprintf("%s/api/v1/template/upload", c.BaseURL)
-	req, err := http.NewRequest(http.MethodPost, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.doUpload(context.Background(), "/api/v1/template/upload", body, writer.FormDataContentType())
+}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+// UploadTemplateFromBytes 从字节数组上传模板文件
+//
+// data: 文件内容字节数组
+// filename: 文件名（需包含扩展名）
+//
+// 上传前的格式检查与 UploadTemplate 相同
+func (c *Client) UploadTemplateFromBytes(data []byte, filename string) (*UploadResponse, error) {
+	return c.uploadBytes(context.Background(), data, filename, UploadOptions{})
+}
 
-	// 发送请求
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
+// UploadOptions UploadTemplateFromBytesContext 的选项
+type UploadOptions struct {
+	// Overwrite 要求服务端覆盖同名模板
+	Overwrite bool
+	// TTL 大于 0 时上传为临时模板，服务端在 TTL 后自动删除（按秒向上取整发送），见 UploadTemporaryTemplate
+	TTL time.Duration
+}
 
-	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+// UploadTemplateFromBytesContext 从字节数组上传模板文件，UploadTemplateFromBytes 的 context 版本
+func (c *Client) UploadTemplateFromBytesContext(ctx context.Context, data []byte, filename string, opts UploadOptions) (*UploadResponse, error) {
+	return c.uploadBytes(ctx, data, filename, opts)
+}
 
-	// 处理错误响应
-	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, &errResp
+// UploadTemporaryTemplate 上传临时模板，服务端在 ttl 后自动删除，用于一次性生成
+//
+// 返回的 ExpiresAt 为服务端实际采用的过期时间（服务端可能缩短过长的 ttl）。需服务端支持 FeatureTemporaryTemplates；
+// ttl <= 0 时返回错误
+func (c *Client) UploadTemporaryTemplate(data []byte, filename string, ttl time.Duration) (*UploadResponse, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("docgen: temporary template %s: ttl must be positive", filename)
 	}
+	return c.uploadBytes(context.Background(), data, filename, UploadOptions{TTL: ttl})
+}
 
-	// 解析成功响应
-	var result UploadResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// uploadBytes 以 multipart 表单上传模板内容
+func (c *Client) uploadBytes(ctx context.Context, data []byte, filename string, opts UploadOptions) (*UploadResponse, error) {
+	if err := c.checkTemplateUpload(filename, data); err != nil {
+		return nil, err
+	}
+	if opts.TTL > 0 {
+		if err := c.rejectUnsupported(ctx, FeatureTemporaryTemplates); err != nil {
+			return nil, err
+		}
 	}
 
-	return &result, nil
-}
-
-// UploadTemplateFromBytes 从字节数组上传模板文件
-//
-// data: 文件内容字节数组
-// filename: 文件名（需包含扩展名）
-func (c *Client) UploadTemplateFromBytes(data []byte, filename string) (*UploadResponse, error) {
 	// 创建 multipart 表单
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -118,89 +131,41 @@ func (c *Client) UploadTemplateFromBytes(data []byte, filename string) (*UploadR
 	if err != nil {
 		return nil, fmt.Errorf("failed to write file content: %w", err)
 	}
+	if opts.TTL > 0 {
+		seconds := int64((opts.TTL + time.Second - 1) / time.Second)
+		if err := writer.WriteField("ttl", strconv.FormatInt(seconds, 10)); err != nil {
+			return nil, fmt.Errorf("failed to write ttl field: %w", err)
+		}
+	}
 
 	err = writer.Close()
 	if err != nil {
 		return nil, fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	// 构建 HTTP 请求
-	url := fmt.Sprintf("%s/api/v1/template/upload", c.BaseURL)
-	req, err := http.NewRequest(http.MethodPost, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	path := "/api/v1/template/upload"
+	if opts.Overwrite {
+		path += "?overwrite=true"
 	}
-
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	// 发送请求
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doUpload(ctx, path, body, writer.FormDataContentType())
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	if opts.TTL > 0 && resp.ExpiresAt == nil {
+		// 服务端未返回过期时间时按请求的 TTL 估算
+		expires := time.Now().Add(opts.TTL)
+		resp.ExpiresAt = &expires
 	}
-
-	// 处理错误响应
-	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, &errResp
-	}
-
-	// 解析成功响应
-	var result UploadResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	return &result, nil
+	return resp, nil
 }
 
 // ListTemplates 获取所有模板文件列表
 //
 // 返回模板文件名数组
 func (c *Client) ListTemplates() ([]string, error) {
-	// 构建 HTTP 请求
-	url := fmt.Sprintf("%s/api/v1/template/list", c.BaseURL)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	result, err := c.ListTemplatesWithDetails()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// 发送请求
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// 处理错误响应
-	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, &errResp
-	}
-
-	// 解析成功响应
-	var result ListTemplatesResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, err
 	}
 
 	return result.Templates, nil
@@ -210,42 +175,70 @@ func (c *Client) ListTemplates() ([]string, error) {
 //
 // 返回完整的响应结构，包含 success、count 和 templates
 func (c *Client) ListTemplatesWithDetails() (*ListTemplatesResponse, error) {
-	// 构建 HTTP 请求
-	url := fmt.Sprintf("%s/api/v1/template/list", c.BaseURL)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := c.newRequest(context.Background(), http.MethodGet, "/api/v1/template/list", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// 发送请求
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+	var result ListTemplatesResponse
+	if err := c.executeJSON(req, &result); err != nil {
+		return nil, err
+	}
+	if c.tenantNamespace {
+		result.Templates = c.unqualifyTemplates(req.Context(), result.Templates)
+		result.Count = int64(len(result.Templates))
 	}
-	defer resp.Body.Close()
 
-	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
+	return &result, nil
+}
+
+// TemplateInfo 模板文件详细信息
+type TemplateInfo struct {
+	// Name 模板文件名
+	Name string `json:"name"`
+	// Size 文件大小（字节）
+	Size int64 `json:"size"`
+	// LastModified 最后修改时间
+	LastModified time.Time `json:"lastModified"`
+	// ExpiresAt 临时模板的过期时间，永久模板为 nil
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ListTemplateInfosResponse 模板详细信息列表响应
+type ListTemplateInfosResponse struct {
+	Success   bool           `json:"success"`
+	Count     int64          `json:"count"`
+	Templates []TemplateInfo `json:"templates"`
+}
+
+// ListTemplateInfos 获取所有模板文件的详细信息（文件名、大小、修改时间）
+func (c *Client) ListTemplateInfos() ([]TemplateInfo, error) {
+	return c.ListTemplateInfosContext(context.Background())
+}
+
+// ListTemplateInfosContext ListTemplateInfos 的 context 版本
+func (c *Client) ListTemplateInfosContext(ctx context.Context) ([]TemplateInfo, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/template/info", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	// 处理错误响应
-	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, &errResp
+	var result ListTemplateInfosResponse
+	if err := c.executeJSON(req, &result); err != nil {
+		return nil, err
 	}
-
-	// 解析成功响应
-	var result ListTemplatesResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if c.tenantNamespace {
+		infos := result.Templates[:0]
+		for _, info := range result.Templates {
+			if local, ok := c.unqualifyTemplate(req.Context(), info.Name); ok {
+				info.Name = local
+				infos = append(infos, info)
+			}
+		}
+		result.Templates = infos
 	}
 
-	return &result, nil
+	return result.Templates, nil
 }
 
 // DeleteResponse 删除模板响应
@@ -261,80 +254,105 @@ type DeleteResponse struct {
 //
 // 返回删除结果
 func (c *Client) DeleteTemplate(templateName string) (*DeleteResponse, error) {
-	// 构建 HTTP 请求（对模板名称进行 URL 编码，支持中文和特殊字符）
-	apiURL := fmt.Sprintf("%s/api/v1/template/%s", c.BaseURL, url.PathEscape(templateName))
-	req, err := http.NewRequest(http.MethodDelete, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.deleteTemplateContext(context.Background(), templateName)
+}
 
-	// 发送请求
-	resp, err := c.HTTPClient.Do(req)
+// DownloadTemplate 下载模板文件
+//
+// templateName: 模板文件名
+//
+// 返回模板文件的字节数组
+func (c *Client) DownloadTemplate(templateName string) ([]byte, error) {
+	return c.DownloadTemplateContext(context.Background(), templateName)
+}
+
+// DownloadTemplateContext DownloadTemplate 的 context 版本
+func (c *Client) DownloadTemplateContext(ctx context.Context, templateName string) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.templatePath(ctx, "/api/v1/template/download", templateName), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
+	return c.execute(req)
+}
+
+// TemplateExists 检查模板文件是否存在
+//
+// templateName: 模板文件名
+//
+// 通过 HEAD 请求模板下载接口判断，不会传输模板内容；只有模板不存在时返回 false, nil，
+// 其他错误响应返回 *ErrorResponse
+func (c *Client) TemplateExists(templateName string) (bool, error) {
+	req, err := c.newRequest(context.Background(), http.MethodHead, c.templatePath(context.Background(), "/api/v1/template/download", templateName), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return false, err
 	}
 
-	// 处理错误响应
-	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+	if _, _, err := c.executeResponse(req); err != nil {
+		// 只有模板不存在（服务端返回 422 TEMPLATE_NOT_FOUND，HEAD 响应按状态码判断）返回 false, nil
+		if errors.Is(err, ErrTemplateNotFound) {
+			return false, nil
 		}
-		return nil, &errResp
+		return false, err
 	}
+	return true, nil
+}
 
-	// 解析成功响应
-	var result DeleteResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
+// TemplateSchema 模板占位符结构（模板内省结果）
+type TemplateSchema struct {
+	// TemplateName 模板文件名
+	TemplateName string `json:"templateName"`
+	// Variables 单值占位符（Word 的 {{name}}，Excel 的 {name}）
+	Variables []TemplateVariable `json:"variables"`
+	// Lists 列表区域（Word 的循环表格，Excel 的 {.field} 行循环）
+	Lists []TemplateList `json:"lists,omitempty"`
+	// ContentControls Word 模板中带标记的内容控件
+	ContentControls []TemplateContentControl `json:"contentControls,omitempty"`
+}
 
-	return &result, nil
+// TemplateVariable 模板占位符
+type TemplateVariable struct {
+	// Name 占位符名称
+	Name string `json:"name"`
+	// Type 值类型：text、number、integer、boolean、date、image，为空时按 text 处理
+	Type string `json:"type,omitempty"`
+	// Required 是否必填
+	Required bool `json:"required,omitempty"`
 }
 
-// DownloadTemplate 下载模板文件
+// TemplateList 模板列表区域
+type TemplateList struct {
+	// Name 列表数据的键名
+	Name string `json:"name"`
+	// Fields 每行数据包含的字段
+	Fields []TemplateVariable `json:"fields"`
+}
+
+// GetTemplateVariables 获取模板中的占位符结构
 //
 // templateName: 模板文件名
 //
-// 返回模板文件的字节数组
-func (c *Client) DownloadTemplate(templateName string) ([]byte, error) {
-	// 构建 HTTP 请求（对模板名称进行 URL 编码，支持中文和特殊字符）
-	apiURL := fmt.Sprintf("%s/api/v1/template/download/%s", c.BaseURL, url.PathEscape(templateName))
-	req, err := http.NewRequest(http.MethodGet, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// 返回单值占位符与列表区域定义
+func (c *Client) GetTemplateVariables(templateName string) (*TemplateSchema, error) {
+	return c.templateVariables(context.Background(), templateName)
+}
 
-	// 发送请求
-	resp, err := c.HTTPClient.Do(req)
+// templateVariables GetTemplateVariables 的 context 版本
+func (c *Client) templateVariables(ctx context.Context, templateName string) (*TemplateSchema, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.templatePath(ctx, "/api/v1/template/variables", templateName), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	var result TemplateSchema
+	if err := c.executeJSON(req, &result); err != nil {
+		return nil, err
 	}
-
-	// 处理错误响应
-	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, &errResp
+	if result.TemplateName == "" {
+		result.TemplateName = templateName
 	}
 
-	return respBody, nil
+	return &result, nil
 }
 
 // SaveTemplate 下载模板并保存到本地文件
@@ -349,3 +367,40 @@ func (c *Client) SaveTemplate(templateName, outputPath string) error {
 
 	return os.WriteFile(outputPath, content, 0644)
 }
+
+// doUpload 发送模板上传请求
+//
+// path: 上传接口路径（可带查询参数）
+// body: multipart 表单内容
+// contentType: multipart 表单的 Content-Type（包含 boundary）
+func (c *Client) doUpload(ctx context.Context, path string, body io.Reader, contentType string) (*UploadResponse, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	var result UploadResponse
+	if err := c.executeJSON(req, &result); err != nil {
+		return nil, err
+	}
+	c.invalidateSchema(result.FileName)
+	// 上传的模板由服务端按 X-Tenant-ID 存入租户命名空间
+	result.FileName, _ = c.unqualifyTemplate(req.Context(), result.FileName)
+
+	return &result, nil
+}
+
+// templatePath 构建以模板名称寻址的接口路径
+//
+// 启用 WithTenantNamespace 时先添加租户前缀。简单名称使用路径形式（base/{name}，对中文和特殊字符进行 URL 编码）；
+// 名称包含路径分隔符（如 "tenantA/invoice.docx"）或启用 WithQueryTemplateNames 时，
+// 使用查询参数形式（base?name=...），避免 %2F 被 servlet 容器解码为路径分隔符后返回 404
+func (c *Client) templatePath(ctx context.Context, base, templateName string) string {
+	templateName = c.qualifyTemplate(ctx, templateName)
+	if c.queryTemplateNames || strings.ContainsAny(templateName, "/\\") {
+		return base + "?" + url.Values{"name": {templateName}}.Encode()
+	}
+	return base + "/" + url.PathEscape(templateName)
+}
@@ -0,0 +1,81 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+var templateNames = []string{
+	"合同模板.docx",
+	"my template v2.docx",
+	"a+b=c.docx",
+	"100% done & 50%off?.docx",
+	"tenantA/invoice.docx",
+	"租户 A/发票+报价/模板 1.docx",
+	`windows\style.docx`,
+}
+
+func TestTemplateNameRoundTrip(t *testing.T) {
+	for _, query := range []bool{false, true} {
+		for _, name := range templateNames {
+			srv := docgentest.NewServer()
+			data := docgentest.MinimalDocx(name)
+			srv.AddTemplate(name, data)
+
+			var opts []docgen.Option
+			if query {
+				opts = append(opts, docgen.WithQueryTemplateNames())
+			}
+			client := docgen.NewClient(srv.URL, opts...)
+
+			if ok, err := client.TemplateExists(name); err != nil || !ok {
+				t.Errorf("query=%v %q: TemplateExists = %v, %v", query, name, ok, err)
+			}
+			wantQuery := query || strings.ContainsAny(name, `/\`)
+			if got := srv.LastRequest().Query.Get("name"); (got == name) != wantQuery {
+				t.Errorf("query=%v %q: ?name=%q, want query form %v", query, name, got, wantQuery)
+			}
+			if got, err := client.DownloadTemplate(name); err != nil || !bytes.Equal(got, data) {
+				t.Errorf("query=%v %q: DownloadTemplate = %d bytes, %v", query, name, len(got), err)
+			}
+			if _, err := client.DeleteTemplate(name); err != nil {
+				t.Errorf("query=%v %q: DeleteTemplate: %v", query, name, err)
+			}
+			if _, ok := srv.Template(name); ok {
+				t.Errorf("query=%v %q: template still on the server", query, name)
+			}
+			if ok, err := client.TemplateExists(name); err != nil || ok {
+				t.Errorf("query=%v %q: TemplateExists after delete = %v, %v", query, name, ok, err)
+			}
+			srv.Close()
+		}
+	}
+}
+
+// TestTemplateExistsErrors HEAD 错误响应没有响应体，只有模板不存在返回 false, nil，
+// 其他错误响应返回 *ErrorResponse
+func TestTemplateExistsErrors(t *testing.T) {
+	srv := docgentest.NewServer(docgentest.WithSequence(docgentest.EndpointTemplateDownload,
+		docgentest.ErrorResponse(http.StatusInternalServerError, docgen.CodeInternalError, "boom")))
+	defer srv.Close()
+	client := docgen.NewClient(srv.URL, docgen.WithQueryTemplateNames())
+
+	ok, err := client.TemplateExists("t.docx")
+	var errResp *docgen.ErrorResponse
+	if ok || !errors.As(err, &errResp) || errResp.Status != http.StatusInternalServerError || errors.Is(err, docgen.ErrTemplateNotFound) {
+		t.Errorf("TemplateExists on 500 = %v, %v; want *ErrorResponse with status 500", ok, err)
+	}
+	if ok, err := client.TemplateExists("missing.docx"); ok || err != nil {
+		t.Errorf("TemplateExists on a missing template = %v, %v; want false, nil", ok, err)
+	}
+	if _, err := client.GetTemplateChecksum(context.Background(), "missing.docx"); !errors.Is(err, docgen.ErrTemplateNotFound) {
+		t.Errorf("GetTemplateChecksum on a missing template: err = %v, want ErrTemplateNotFound", err)
+	}
+}
@@ -0,0 +1,107 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TenantHeader 租户 ID 请求头，服务端按租户隔离模板与配额
+const TenantHeader = "X-Tenant-ID"
+
+// ErrTenantForbidden 服务端拒绝跨租户访问（403），可配合 errors.Is 判断
+var ErrTenantForbidden = errors.New("docgen: tenant access forbidden")
+
+// TenantForbiddenError 跨租户访问被拒绝
+//
+// errors.Is(err, ErrTenantForbidden) 返回 true，可通过 errors.As 获取租户与服务端原始错误
+type TenantForbiddenError struct {
+	// Tenant 请求使用的租户 ID
+	Tenant string
+	// Err 服务端返回的原始错误
+	Err *ErrorResponse
+}
+
+// Error 实现 error 接口
+func (e *TenantForbiddenError) Error() string {
+	return fmt.Sprintf("tenant %s: access forbidden: %s", e.Tenant, e.Err.Message)
+}
+
+// Unwrap 返回服务端原始错误
+func (e *TenantForbiddenError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrTenantForbidden) 成立
+func (e *TenantForbiddenError) Is(target error) bool {
+	return target == ErrTenantForbidden
+}
+
+// tenantContextKey 请求级租户在 context 中的键
+type tenantContextKey struct{}
+
+// WithRequestTenant 返回指定租户的 context，覆盖 WithTenant 设置的客户端租户
+//
+// 适用于一个客户端为多个租户服务的后台任务，如：
+//
+//	ctx := docgen.WithRequestTenant(ctx, "tenantB")
+//	doc, err := client.GenerateWordContext(ctx, req)
+func WithRequestTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantTemplateName 返回租户命名空间下的模板名称（"<tenant>/<name>"）
+//
+// tenant 为空或 name 已带有该租户前缀时原样返回
+func TenantTemplateName(tenant, name string) string {
+	if tenant == "" || strings.HasPrefix(name, tenant+"/") {
+		return name
+	}
+	return tenant + "/" + name
+}
+
+// tenantFor 返回请求使用的租户：context 中的租户优先，其次为客户端租户
+func (c *Client) tenantFor(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return c.tenant
+}
+
+// qualifyTemplate 启用 WithTenantNamespace 时为模板名称添加租户前缀
+func (c *Client) qualifyTemplate(ctx context.Context, name string) string {
+	if !c.tenantNamespace || name == "" {
+		return name
+	}
+	return TenantTemplateName(c.tenantFor(ctx), name)
+}
+
+// unqualifyTemplates 启用 WithTenantNamespace 时去掉租户前缀，并过滤其他租户的模板
+//
+// 不带前缀的名称视为服务端已按租户过滤的结果，原样保留
+func (c *Client) unqualifyTemplates(ctx context.Context, names []string) []string {
+	tenant := c.tenantFor(ctx)
+	if !c.tenantNamespace || tenant == "" {
+		return names
+	}
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if local, ok := c.unqualifyTemplate(ctx, name); ok {
+			out = append(out, local)
+		}
+	}
+	return out
+}
+
+// unqualifyTemplate 去掉当前租户前缀；属于其他租户的名称返回 false
+func (c *Client) unqualifyTemplate(ctx context.Context, name string) (string, bool) {
+	tenant := c.tenantFor(ctx)
+	if !c.tenantNamespace || tenant == "" {
+		return name, true
+	}
+	if local, ok := strings.CutPrefix(name, tenant+"/"); ok {
+		return local, true
+	}
+	return name, !strings.ContainsAny(name, "/\\")
+}
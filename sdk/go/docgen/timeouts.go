@@ -0,0 +1,169 @@
+package docgen
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeoutPhase 请求超时发生的阶段，见 TimeoutError.Phase
+type TimeoutPhase string
+
+const (
+	// TimeoutTotal 整体超时：HTTPClient.Timeout 或 context 截止时间
+	TimeoutTotal TimeoutPhase = "total"
+	// TimeoutDial 建立 TCP 连接超时（TimeoutPolicy.DialTimeout），通常说明服务不可达
+	TimeoutDial TimeoutPhase = "dial"
+	// TimeoutTLSHandshake TLS 握手超时（TimeoutPolicy.TLSHandshakeTimeout）
+	TimeoutTLSHandshake TimeoutPhase = "tls-handshake"
+	// TimeoutResponseHeader 请求发出后等待响应头超时（TimeoutPolicy.ResponseHeaderTimeout），通常说明服务无响应
+	TimeoutResponseHeader TimeoutPhase = "response-header"
+	// TimeoutBodyRead 读取响应体时超过 TimeoutPolicy.BodyReadTimeout 没有收到数据，通常说明传输停滞
+	TimeoutBodyRead TimeoutPhase = "body-read"
+)
+
+// 按阶段分类的超时错误，*TimeoutError 同时满足 errors.Is(err, ErrTimeout) 与对应阶段的错误
+var (
+	// ErrDialTimeout 建立连接超时（TimeoutDial）
+	ErrDialTimeout = errors.New("docgen: dial timeout")
+	// ErrTLSHandshakeTimeout TLS 握手超时（TimeoutTLSHandshake）
+	ErrTLSHandshakeTimeout = errors.New("docgen: tls handshake timeout")
+	// ErrResponseHeaderTimeout 等待响应头超时（TimeoutResponseHeader）
+	ErrResponseHeaderTimeout = errors.New("docgen: response header timeout")
+	// ErrBodyReadTimeout 响应体读取停滞超时（TimeoutBodyRead）
+	ErrBodyReadTimeout = errors.New("docgen: body read timeout")
+)
+
+// timeoutPhaseErrors 各阶段对应的分类错误
+var timeoutPhaseErrors = map[TimeoutPhase]error{
+	TimeoutDial:           ErrDialTimeout,
+	TimeoutTLSHandshake:   ErrTLSHandshakeTimeout,
+	TimeoutResponseHeader: ErrResponseHeaderTimeout,
+	TimeoutBodyRead:       ErrBodyReadTimeout,
+}
+
+// TimeoutPolicy 分阶段的超时设置，由 WithTimeoutPolicy 使用，为 0 的字段不限制
+//
+// HTTPClient.Timeout 限制整个调用（含读取响应体），无法同时表达"服务无响应时尽快失败"与"允许长时间下载大文件"；
+// 分阶段设置后，可续传下载与 *To / *Spooled 等流式读取只受各阶段超时与 ctx 限制
+type TimeoutPolicy struct {
+	// DialTimeout 建立 TCP 连接的超时
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout TLS 握手的超时
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout 请求发送完毕后等待响应头的超时
+	ResponseHeaderTimeout time.Duration
+	// BodyReadTimeout 流式读取响应体时一次读取等待数据的最长时间，每次读取重新计时（调用方处理数据的时间不计入）
+	BodyReadTimeout time.Duration
+}
+
+// applyTransport 将连接阶段的超时设置到 transport
+func (p TimeoutPolicy) applyTransport(t *http.Transport) {
+	if p.DialTimeout > 0 {
+		dialer := &net.Dialer{Timeout: p.DialTimeout, KeepAlive: 30 * time.Second}
+		t.DialContext = dialer.DialContext
+	}
+	if p.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = p.TLSHandshakeTimeout
+	}
+	if p.ResponseHeaderTimeout > 0 {
+		t.ResponseHeaderTimeout = p.ResponseHeaderTimeout
+	}
+}
+
+// timeoutPhase 根据传输层错误判断超时阶段
+//
+// 请求的 context 已超时（调用方设置的截止时间）时一律视为 TimeoutTotal；
+// 连接超时的 *net.OpError 同样满足 errors.Is(err, context.DeadlineExceeded)，需先于该判断识别
+func timeoutPhase(req *http.Request, err error) TimeoutPhase {
+	if req.Context().Err() != nil {
+		return TimeoutTotal
+	}
+	// net/http 未导出这两种错误的类型，只能按错误信息识别
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "TLS handshake timeout"):
+		return TimeoutTLSHandshake
+	case strings.Contains(msg, "timeout awaiting response headers"):
+		return TimeoutResponseHeader
+	case strings.Contains(msg, "Client.Timeout exceeded"):
+		return TimeoutTotal
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout() {
+		return TimeoutDial
+	}
+	return TimeoutTotal
+}
+
+// withBodyReadTimeout 启用 TimeoutPolicy.BodyReadTimeout 时包装流式响应体，停滞超时后关闭连接并返回 *TimeoutError
+func (c *Client) withBodyReadTimeout(req *http.Request, resp *http.Response, start time.Time) {
+	timeout := c.timeouts.BodyReadTimeout
+	if timeout <= 0 {
+		return
+	}
+	b := &idleTimeoutBody{ReadCloser: resp.Body, req: req, start: start, timeout: timeout}
+	b.timer = time.AfterFunc(timeout, b.expire)
+	b.timer.Stop()
+	resp.Body = b
+}
+
+// idleTimeoutBody 读取时计时的响应体：一次 Read 阻塞超过 timeout 时关闭底层连接，使读取以 *TimeoutError 结束
+//
+// 只在 Read 阻塞期间计时，调用方处理数据的时间不计入
+type idleTimeoutBody struct {
+	io.ReadCloser
+	req     *http.Request
+	start   time.Time
+	timeout time.Duration
+	timer   *time.Timer
+
+	mu      sync.Mutex
+	expired bool
+}
+
+// Read 读取数据，阻塞超过 timeout 时返回 *TimeoutError（Phase 为 TimeoutBodyRead）
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	if b.isExpired() {
+		return 0, b.timeoutError()
+	}
+	b.timer.Reset(b.timeout)
+	n, err := b.ReadCloser.Read(p)
+	b.timer.Stop()
+	if err != nil && err != io.EOF && b.isExpired() {
+		return n, b.timeoutError()
+	}
+	return n, err
+}
+
+// Close 停止计时并关闭响应体
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	return b.ReadCloser.Close()
+}
+
+// expire 计时结束：标记超时并关闭底层连接，使阻塞的 Read 返回
+func (b *idleTimeoutBody) expire() {
+	b.mu.Lock()
+	b.expired = true
+	b.mu.Unlock()
+	b.ReadCloser.Close()
+}
+
+// isExpired 是否已超时
+func (b *idleTimeoutBody) isExpired() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.expired
+}
+
+// timeoutError 读取停滞的超时错误
+func (b *idleTimeoutBody) timeoutError() error {
+	err := newTimeoutError(b.req, b.start, errors.New("no data received for "+b.timeout.String()))
+	err.Phase = TimeoutBodyRead
+	return requestError(b.req, err)
+}
@@ -0,0 +1,174 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// phaseErrors 各超时阶段对应的哨兵错误，TimeoutTotal 没有单独的哨兵
+var phaseErrors = map[docgen.TimeoutPhase]error{
+	docgen.TimeoutDial:           docgen.ErrDialTimeout,
+	docgen.TimeoutTLSHandshake:   docgen.ErrTLSHandshakeTimeout,
+	docgen.TimeoutResponseHeader: docgen.ErrResponseHeaderTimeout,
+	docgen.TimeoutBodyRead:       docgen.ErrBodyReadTimeout,
+}
+
+// assertTimeoutPhase 断言 err 是 want 阶段的 *TimeoutError，且只匹配该阶段的哨兵错误
+func assertTimeoutPhase(t *testing.T, err error, want docgen.TimeoutPhase) {
+	t.Helper()
+	var timeoutErr *docgen.TimeoutError
+	if !errors.Is(err, docgen.ErrTimeout) || !errors.As(err, &timeoutErr) {
+		t.Fatalf("err = %v, want *TimeoutError", err)
+	}
+	if timeoutErr.Phase != want {
+		t.Errorf("Phase = %q, want %q (err: %v)", timeoutErr.Phase, want, err)
+	}
+	for phase, sentinel := range phaseErrors {
+		if got := errors.Is(err, sentinel); got != (phase == want) {
+			t.Errorf("errors.Is(err, %v) = %v for a %s timeout", sentinel, got, want)
+		}
+	}
+	if errors.Is(err, docgen.ErrUnreachable) {
+		t.Errorf("timeout also reported as ErrUnreachable: %v", err)
+	}
+}
+
+// stallingListener 接受连接但从不发送数据，TLS 握手停在等待 ServerHello
+func stallingListener(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(io.Discard, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return ln
+}
+
+// stallingBodyServer 发送响应头与部分响应体后停止发送，直到客户端断开
+func stallingBodyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("PK partial"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTimeoutPhases(t *testing.T) {
+	const limit = 50 * time.Millisecond
+	tests := []struct {
+		name  string
+		phase docgen.TimeoutPhase
+		call  func(t *testing.T) error
+	}{
+		{"dial", docgen.TimeoutDial, func(t *testing.T) error {
+			client := docgen.NewClient("http://127.0.0.1:1", docgen.WithTimeoutPolicy(docgen.TimeoutPolicy{DialTimeout: limit}))
+			transport, ok := client.HTTPClient.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("Transport is %T, want *http.Transport", client.HTTPClient.Transport)
+			}
+			// 本地无法可靠地制造不响应的地址：连接在建立前阻塞到拨号超时，与丢弃 SYN 的网络表现相同
+			dialer := &net.Dialer{
+				Timeout: limit,
+				ControlContext: func(ctx context.Context, network, address string, c syscall.RawConn) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			}
+			transport.DialContext = dialer.DialContext
+			_, err := client.GenerateWordContext(context.Background(), wordReq)
+			return err
+		}},
+		{"TLS handshake", docgen.TimeoutTLSHandshake, func(t *testing.T) error {
+			ln := stallingListener(t)
+			client := docgen.NewClient("https://"+ln.Addr().String(), docgen.WithTimeoutPolicy(docgen.TimeoutPolicy{TLSHandshakeTimeout: limit}))
+			_, err := client.GenerateWordContext(context.Background(), wordReq)
+			return err
+		}},
+		{"response header", docgen.TimeoutResponseHeader, func(t *testing.T) error {
+			srv := sleepyServer(t, 5*time.Second)
+			client := docgen.NewClient(srv.URL, docgen.WithTimeoutPolicy(docgen.TimeoutPolicy{ResponseHeaderTimeout: limit}))
+			_, err := client.GenerateWordContext(context.Background(), wordReq)
+			return err
+		}},
+		{"body read", docgen.TimeoutBodyRead, func(t *testing.T) error {
+			srv := stallingBodyServer(t)
+			client := docgen.NewClient(srv.URL, docgen.WithTimeoutPolicy(docgen.TimeoutPolicy{BodyReadTimeout: limit}))
+			var buf bytes.Buffer
+			_, err := client.GenerateWordTo(context.Background(), wordReq, &buf)
+			if buf.String() != "PK partial" {
+				t.Errorf("received %q before the stall, want %q", buf.String(), "PK partial")
+			}
+			return err
+		}},
+		{"per-call deadline", docgen.TimeoutTotal, func(t *testing.T) error {
+			srv := sleepyServer(t, 5*time.Second)
+			client := docgen.NewClient(srv.URL, docgen.WithTimeoutPolicy(docgen.TimeoutPolicy{ResponseHeaderTimeout: 5 * time.Second}))
+			ctx, cancel := context.WithTimeout(context.Background(), limit)
+			defer cancel()
+			_, err := client.GenerateWordContext(ctx, wordReq)
+			return err
+		}},
+		{"client timeout", docgen.TimeoutTotal, func(t *testing.T) error {
+			srv := sleepyServer(t, 5*time.Second)
+			client := docgen.NewClientWithTimeout(srv.URL, limit)
+			_, err := client.GenerateWordContext(context.Background(), wordReq)
+			return err
+		}},
+		{"deadline during dial", docgen.TimeoutTotal, func(t *testing.T) error {
+			// 调用方的截止时间先于拨号超时到达，仍属于总超时
+			client := docgen.NewClient("http://127.0.0.1:1", docgen.WithTimeoutPolicy(docgen.TimeoutPolicy{DialTimeout: 5 * time.Second}))
+			dialer := &net.Dialer{
+				Timeout: 5 * time.Second,
+				ControlContext: func(ctx context.Context, network, address string, c syscall.RawConn) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			}
+			client.HTTPClient.Transport.(*http.Transport).DialContext = dialer.DialContext
+			ctx, cancel := context.WithTimeout(context.Background(), limit)
+			defer cancel()
+			_, err := client.GenerateWordContext(ctx, wordReq)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := tt.call(t)
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("took %v, the %s timeout did not fire", elapsed, tt.phase)
+			}
+			assertTimeoutPhase(t, err, tt.phase)
+		})
+	}
+}
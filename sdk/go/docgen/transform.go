@@ -0,0 +1,188 @@
+package docgen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DataTransformer 发送前转换模板数据中的值
+//
+// key: 值所在的键名（切片元素使用所属键名）；返回替换后的值，返回错误时请求中止
+type DataTransformer func(key string, value any) (any, error)
+
+// TrimStrings 去掉字符串值首尾的空白字符
+func TrimStrings(_ string, value any) (any, error) {
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s), nil
+	}
+	return value, nil
+}
+
+// ZeroTimeAsEmpty 将零值 time.Time（及 nil 或指向零值的 *time.Time）转换为空字符串，
+// 避免模板中出现 "0001-01-01T00:00:00Z"
+func ZeroTimeAsEmpty(_ string, value any) (any, error) {
+	switch t := value.(type) {
+	case time.Time:
+		if t.IsZero() {
+			return "", nil
+		}
+	case *time.Time:
+		if t == nil || t.IsZero() {
+			return "", nil
+		}
+	}
+	return value, nil
+}
+
+// EnumMapper 将与 mapping 中的键相同的字符串值替换为对应的显示文本，如内部状态码 "P" → "待处理"
+//
+// mapping 在创建时复制，之后修改不影响转换结果
+func EnumMapper(mapping map[string]string) DataTransformer {
+	m := make(map[string]string, len(mapping))
+	for k, v := range mapping {
+		m[k] = v
+	}
+	return func(_ string, value any) (any, error) {
+		if s, ok := value.(string); ok {
+			if display, ok := m[s]; ok {
+				return display, nil
+			}
+		}
+		return value, nil
+	}
+}
+
+// transformData 依次应用 WithDataTransformer 设置的转换函数，返回新的 map，不修改 data
+//
+// 深度优先：嵌套 map 与切片的元素先于其所属容器转换；同一个值按注册顺序依次经过各转换函数。
+// path 为错误信息中的键路径前缀，如 "data"
+func (c *Client) transformData(path string, data map[string]any) (map[string]any, error) {
+	if len(c.transformers) == 0 || data == nil {
+		return data, nil
+	}
+	return c.transformMap(path, data)
+}
+
+// transformMap 转换 map 中的每个值
+func (c *Client) transformMap(path string, m map[string]any) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	out := make(map[string]any, len(m))
+	for _, key := range sortedMapKeys(m) {
+		v, err := c.transformValue(path+"."+key, key, m[key])
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+// transformValue 先转换嵌套值，再对 value 本身应用转换函数
+func (c *Client) transformValue(path, key string, value any) (any, error) {
+	var err error
+	switch v := value.(type) {
+	case map[string]any:
+		if value, err = c.transformMap(path, v); err != nil {
+			return nil, err
+		}
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			if items[i], err = c.transformValue(path+"["+strconv.Itoa(i)+"]", key, item); err != nil {
+				return nil, err
+			}
+		}
+		value = items
+	case Data:
+		m, err := c.transformMap(path, v)
+		if err != nil {
+			return nil, err
+		}
+		value = Data(m)
+	case []map[string]any:
+		if value, err = c.transformRows(path, v); err != nil {
+			return nil, err
+		}
+	case SectionValue:
+		items := make([]Data, len(v.Items))
+		for i, item := range v.Items {
+			m, err := c.transformMap(path+"["+strconv.Itoa(i)+"]", item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = m
+		}
+		value = Section(v.Name, items...)
+	}
+
+	for _, t := range c.transformers {
+		if value, err = t(key, value); err != nil {
+			return nil, fmt.Errorf("transform %s: %w", path, err)
+		}
+	}
+	return value, nil
+}
+
+// transformRows 转换数据行列表（DataList、ListData 中的列表）
+func (c *Client) transformRows(path string, rows []map[string]any) ([]map[string]any, error) {
+	if len(c.transformers) == 0 || rows == nil {
+		return rows, nil
+	}
+	out := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		var err error
+		if out[i], err = c.transformMap(path+"["+strconv.Itoa(i)+"]", row); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// transformListData 转换 ExcelFillRequest.ListData
+func (c *Client) transformListData(listData map[string][]map[string]any) (map[string][]map[string]any, error) {
+	if len(c.transformers) == 0 || listData == nil {
+		return listData, nil
+	}
+	names := make([]string, 0, len(listData))
+	for name := range listData {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make(map[string][]map[string]any, len(listData))
+	for _, name := range names {
+		var err error
+		if out[name], err = c.transformRows("listData."+name, listData[name]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// transformFill 转换 Excel 模板填充请求的 Data 与 ListData
+func (c *Client) transformFill(req *ExcelFillRequest) error {
+	data, err := c.transformData("data", req.Data)
+	if err != nil {
+		return err
+	}
+	listData, err := c.transformListData(req.ListData)
+	if err != nil {
+		return err
+	}
+	req.Data, req.ListData = data, listData
+	return nil
+}
+
+// sortedMapKeys 返回排序后的键名，使转换顺序与错误报告稳定
+func sortedMapKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,117 @@
+package docgen_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// TestDataTransformerOrder 多个转换函数按注册顺序作用于同一个值，嵌套值先于所属容器、同层按键名排序；
+// 发送的请求体为依次追加标记后的结果，调用方的数据不变
+func TestDataTransformerOrder(t *testing.T) {
+	var calls []string
+	marker := func(mark string) docgen.DataTransformer {
+		return func(key string, value any) (any, error) {
+			calls = append(calls, fmt.Sprintf("%s %s %T", mark, key, value))
+			if s, ok := value.(string); ok {
+				return s + mark, nil
+			}
+			return value, nil
+		}
+	}
+
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.docx", docgentest.MinimalDocx("{{name}}"))
+	client := docgen.NewClient(srv.URL,
+		docgen.WithDataTransformer(marker("A")),
+		docgen.WithDataTransformer(marker("B")),
+		docgen.WithDataTransformer(marker("C")))
+
+	data := map[string]any{
+		"name":  "x",
+		"addr":  map[string]any{"city": "y"},
+		"lines": []any{"z"},
+	}
+	if _, err := client.GenerateWordWithMeta(context.Background(), docgen.WordGenRequest{TemplateName: "t.docx", Data: data}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		// addr：先转换 city，再转换 addr 本身
+		"A city string", "B city string", "C city string",
+		"A addr map[string]interface {}", "B addr map[string]interface {}", "C addr map[string]interface {}",
+		// lines：切片元素使用所属键名
+		"A lines string", "B lines string", "C lines string",
+		"A lines []interface {}", "B lines []interface {}", "C lines []interface {}",
+		"A name string", "B name string", "C name string",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+
+	var sent struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(srv.LastRequest().Body, &sent); err != nil {
+		t.Fatal(err)
+	}
+	wantData := map[string]any{"name": "xABC", "addr": map[string]any{"city": "yABC"}, "lines": []any{"zABC"}}
+	if !reflect.DeepEqual(sent.Data, wantData) {
+		t.Errorf("sent data = %v, want %v", sent.Data, wantData)
+	}
+	if !reflect.DeepEqual(data, map[string]any{"name": "x", "addr": map[string]any{"city": "y"}, "lines": []any{"z"}}) {
+		t.Errorf("caller's data modified: %v", data)
+	}
+
+	// 同一请求重复发送时顺序不变
+	first := append([]string(nil), calls...)
+	calls = nil
+	if _, err := client.GenerateWordWithMeta(context.Background(), docgen.WordGenRequest{TemplateName: "t.docx", Data: data}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, first) {
+		t.Errorf("second run order differs:\n%s", strings.Join(calls, "\n"))
+	}
+}
+
+// TestDataTransformerError 转换函数返回错误时请求中止，错误包含键路径，之后的转换函数不再调用
+func TestDataTransformerError(t *testing.T) {
+	errBad := errors.New("bad value")
+	var after int
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	srv.AddTemplate("t.xlsx", docgentest.MinimalXlsx())
+	client := docgen.NewClient(srv.URL,
+		docgen.WithDataTransformer(func(key string, value any) (any, error) {
+			if value == "bad" {
+				return nil, errBad
+			}
+			return value, nil
+		}),
+		docgen.WithDataTransformer(func(key string, value any) (any, error) {
+			after++
+			return value, nil
+		}))
+
+	req := docgen.ExcelFillRequest{TemplateName: "t.xlsx", ListData: map[string][]map[string]any{
+		"rows": {{"sku": "A1"}, {"sku": "bad"}},
+	}}
+	_, err := client.FillExcelTemplateWithMeta(context.Background(), req)
+	if !errors.Is(err, errBad) || !strings.Contains(err.Error(), "listData.rows[1].sku") {
+		t.Fatalf("err = %v, want the transformer error with the key path", err)
+	}
+	if after != 1 {
+		t.Errorf("second transformer called %d times, want 1 (only for the value before the failure)", after)
+	}
+	if n := len(srv.RequestsTo(docgentest.EndpointExcelFill)); n != 0 {
+		t.Errorf("%d requests sent after the transformer failed", n)
+	}
+}
@@ -0,0 +1,284 @@
+package docgen
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// defaultUploadChunkSize 分片上传的默认分片大小
+const defaultUploadChunkSize = 8 << 20
+
+// UploadSession 分片上传会话
+type UploadSession struct {
+	// SessionID 会话 ID
+	SessionID string `json:"sessionId"`
+	// FileName 模板文件名
+	FileName string `json:"fileName"`
+	// Size 文件总大小（字节）
+	Size int64 `json:"size"`
+	// SHA256 文件内容的 SHA-256（十六进制），提交时由服务端校验
+	SHA256 string `json:"sha256"`
+	// ChunkSize 服务端确定的分片大小
+	ChunkSize int64 `json:"chunkSize"`
+	// Offset 服务端已确认接收的字节数
+	Offset int64 `json:"offset"`
+}
+
+// uploadManifest 本地会话清单，进程中断后用于找回未完成的会话
+type uploadManifest struct {
+	BaseURL   string `json:"baseUrl"`
+	FilePath  string `json:"filePath"`
+	SessionID string `json:"sessionId"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+	Offset    int64  `json:"offset"`
+}
+
+// UploadTemplateLarge 以可续传的分片会话上传大模板文件
+//
+// ctx: 取消后停止上传，会话保留以便下次继续
+// filePath: 本地模板文件路径
+//
+// 上传流程为 创建会话 → 按偏移量 PUT 分片 → 提交。每个分片被确认后，会话进度写入
+// 临时目录下的清单文件；进程中断后再次上传同一文件（大小与 SHA-256 均一致）时，
+// 从服务端最后确认的偏移量继续，而不是从头开始。会话在服务端失效时自动新建。
+// 分片大小由 Client.UploadChunkSize 指定（默认 8 MiB），服务端可调整
+func (c *Client) UploadTemplateLarge(ctx context.Context, filePath string) (*UploadResponse, error) {
+	// 客户端 Close 时停止上传，会话按 WithUploadClosePolicy 保留或放弃
+	ctx, done, err := c.track(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	if err := c.requireFeature(ctx, FeatureChunkedUpload); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	if err := c.checkTemplateFile(filepath.Base(filePath), file); err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	checksum := hex.EncodeToString(h.Sum(nil))
+
+	manifestPath := c.uploadManifestPath(filePath)
+	session, err := c.resumeUploadSession(ctx, manifestPath, info.Size(), checksum)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		session, err = c.createUploadSession(ctx, filepath.Base(filePath), info.Size(), checksum)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := uploadManifest{
+		BaseURL:   c.BaseURL,
+		FilePath:  filePath,
+		SessionID: session.SessionID,
+		Size:      session.Size,
+		SHA256:    checksum,
+		Offset:    session.Offset,
+	}
+	if err := writeUploadManifest(manifestPath, manifest); err != nil {
+		return nil, err
+	}
+	c.registerUpload(manifestPath, manifest)
+	defer func() {
+		if !c.closing() {
+			c.unregisterUpload(manifestPath)
+		}
+	}()
+
+	chunkSize := session.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = c.uploadChunkSize()
+	}
+	buf := make([]byte, chunkSize)
+	for offset := session.Offset; offset < session.Size; {
+		n, err := file.ReadAt(buf, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		acked, err := c.putUploadChunk(ctx, session, offset, buf[:n])
+		if err != nil {
+			if c.closing() {
+				return nil, fmt.Errorf("%w: upload of %s interrupted at offset %d: %w", ErrClientClosed, filePath, offset, err)
+			}
+			return nil, err
+		}
+		offset = acked
+		manifest.Offset = acked
+		if err := writeUploadManifest(manifestPath, manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	var result UploadResponse
+	if err := c.doJSON(ctx, http.MethodPost, uploadSessionPath(session.SessionID, "/commit"), nil, &result); err != nil {
+		return nil, err
+	}
+	os.Remove(manifestPath)
+	c.invalidateSchema(result.FileName)
+	return &result, nil
+}
+
+// AbortUploadSession 放弃分片上传会话并删除服务端已接收的数据
+//
+// sessionID: 会话 ID
+func (c *Client) AbortUploadSession(sessionID string) error {
+	return c.doJSON(context.Background(), http.MethodDelete, uploadSessionPath(sessionID, ""), nil, nil)
+}
+
+// AbortUploadTemplateLarge 放弃指定文件未完成的分片上传，并删除本地会话清单
+//
+// filePath: 传给 UploadTemplateLarge 的本地文件路径；没有未完成的会话时直接返回 nil
+func (c *Client) AbortUploadTemplateLarge(filePath string) error {
+	manifestPath := c.uploadManifestPath(filePath)
+	manifest, ok := readUploadManifest(manifestPath)
+	if !ok {
+		return nil
+	}
+	err := c.AbortUploadSession(manifest.SessionID)
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && errResp.Status == http.StatusNotFound {
+		err = nil
+	}
+	if err == nil {
+		os.Remove(manifestPath)
+	}
+	return err
+}
+
+// resumeUploadSession 根据本地清单找回未完成的会话，文件已变化或服务端会话失效时返回 nil
+func (c *Client) resumeUploadSession(ctx context.Context, manifestPath string, size int64, checksum string) (*UploadSession, error) {
+	manifest, ok := readUploadManifest(manifestPath)
+	if !ok {
+		return nil, nil
+	}
+	if manifest.BaseURL != c.BaseURL || manifest.Size != size || manifest.SHA256 != checksum {
+		// 文件内容已变化，旧会话作废
+		_ = c.AbortUploadSession(manifest.SessionID)
+		os.Remove(manifestPath)
+		return nil, nil
+	}
+
+	var session UploadSession
+	err := c.doJSON(ctx, http.MethodGet, uploadSessionPath(manifest.SessionID, ""), nil, &session)
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && errResp.Status == http.StatusNotFound {
+		os.Remove(manifestPath)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.Size != size {
+		return nil, nil
+	}
+	return &session, nil
+}
+
+// createUploadSession 创建分片上传会话
+func (c *Client) createUploadSession(ctx context.Context, fileName string, size int64, checksum string) (*UploadSession, error) {
+	req := struct {
+		FileName  string `json:"fileName"`
+		Size      int64  `json:"size"`
+		SHA256    string `json:"sha256"`
+		ChunkSize int64  `json:"chunkSize"`
+	}{fileName, size, checksum, c.uploadChunkSize()}
+	var session UploadSession
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/template/uploads", req, &session); err != nil {
+		return nil, err
+	}
+	if session.Size == 0 {
+		session.Size = size
+	}
+	return &session, nil
+}
+
+// putUploadChunk 上传一个分片，返回服务端确认的偏移量
+func (c *Client) putUploadChunk(ctx context.Context, session *UploadSession, offset int64, chunk []byte) (int64, error) {
+	path := uploadSessionPath(session.SessionID, "") + "?" + url.Values{"offset": {fmt.Sprint(offset)}}.Encode()
+	req, err := c.newRequest(ctx, http.MethodPut, path, bytes.NewReader(chunk))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, session.Size))
+
+	var ack UploadSession
+	if err := c.executeJSON(req, &ack); err != nil {
+		return 0, err
+	}
+	if ack.Offset <= offset {
+		return 0, fmt.Errorf("upload session %s: server acknowledged offset %d, expected more than %d", session.SessionID, ack.Offset, offset)
+	}
+	return ack.Offset, nil
+}
+
+// uploadChunkSize 返回配置的分片大小
+func (c *Client) uploadChunkSize() int64 {
+	if c.UploadChunkSize > 0 {
+		return c.UploadChunkSize
+	}
+	return defaultUploadChunkSize
+}
+
+// uploadManifestPath 本地清单路径：按服务地址与文件绝对路径区分
+func (c *Client) uploadManifestPath(filePath string) string {
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		abs = filePath
+	}
+	sum := sha256.Sum256([]byte(c.BaseURL + "\n" + abs))
+	return filepath.Join(os.TempDir(), "docgen-uploads", hex.EncodeToString(sum[:16])+".json")
+}
+
+// uploadSessionPath 构建会话接口路径，suffix 如 "/commit"
+func uploadSessionPath(sessionID, suffix string) string {
+	return "/api/v1/template/uploads/" + url.PathEscape(sessionID) + suffix
+}
+
+// readUploadManifest 读取本地清单
+func readUploadManifest(path string) (uploadManifest, bool) {
+	var m uploadManifest
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &m) != nil || m.SessionID == "" {
+		return uploadManifest{}, false
+	}
+	return m, true
+}
+
+// writeUploadManifest 原子写入本地清单
+func writeUploadManifest(path string, m uploadManifest) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create upload manifest dir: %w", err)
+	}
+	data, _ := json.Marshal(m)
+	return writeFileAtomic(path, data)
+}
@@ -0,0 +1,247 @@
+package docgen_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// 子进程模式的环境变量，见 TestUploadTemplateLargeCrashChild
+const (
+	uploadCrashURLEnv  = "DOCGEN_UPLOAD_CRASH_URL"
+	uploadCrashFileEnv = "DOCGEN_UPLOAD_CRASH_FILE"
+)
+
+// uploadChunk 测试使用的分片大小
+const uploadChunk = 512
+
+// uploadGate 转发到模拟服务器的代理：记录每个分片的 offset，
+// crashAt 大于 0 时第 crashAt 个分片请求阻塞到连接断开，模拟进程在上传中途被杀死
+type uploadGate struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	crashAt int
+	puts    int
+	offsets []int64
+	reached chan struct{}
+	// released 关闭后阻塞的分片请求返回
+	released chan struct{}
+}
+
+func newUploadGate(t *testing.T, srv *docgentest.Server, crashAt int) *uploadGate {
+	t.Helper()
+	g := &uploadGate{crashAt: crashAt, reached: make(chan struct{}), released: make(chan struct{})}
+	upstream := srv.Config.Handler
+	g.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			offset, _ := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+			g.mu.Lock()
+			g.puts++
+			g.offsets = append(g.offsets, offset)
+			crash := g.puts == g.crashAt
+			g.mu.Unlock()
+			if crash {
+				// 读完请求体后服务端才能感知连接断开
+				io.Copy(io.Discard, r.Body)
+				close(g.reached)
+				select {
+				case <-r.Context().Done():
+				case <-g.released:
+				}
+				return
+			}
+		}
+		upstream.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		g.release()
+		g.Close()
+	})
+	return g
+}
+
+// release 取消崩溃点，返回此后记录的 offset 的起始下标
+func (g *uploadGate) release() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.crashAt != 0 {
+		g.crashAt = 0
+		close(g.released)
+	}
+	return len(g.offsets)
+}
+
+func (g *uploadGate) offsetsFrom(i int) []int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]int64(nil), g.offsets[i:]...)
+}
+
+// largeTemplate 写入大于 n 个分片的 docx 模板
+func largeTemplate(t *testing.T, n int) (string, []byte) {
+	t.Helper()
+	noise := make([]byte, n*uploadChunk)
+	if _, err := rand.Read(noise); err != nil {
+		t.Fatal(err)
+	}
+	data := docgentest.MinimalDocx(hex.EncodeToString(noise))
+	if len(data) <= n*uploadChunk {
+		t.Fatalf("template is %d bytes, want more than %d", len(data), n*uploadChunk)
+	}
+	path := filepath.Join(t.TempDir(), "large.docx")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, data
+}
+
+// uploadManifests 返回 TMPDIR 下的上传清单
+func uploadManifests(t *testing.T, tmp string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(tmp, "docgen-uploads", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// TestUploadTemplateLargeCrashChild 由 TestUploadTemplateLargeResumesAfterCrash 在子进程中运行，
+// 上传会在分片请求中阻塞，直到父进程杀死该进程
+func TestUploadTemplateLargeCrashChild(t *testing.T) {
+	baseURL := os.Getenv(uploadCrashURLEnv)
+	if baseURL == "" {
+		t.Skip("only runs as the child process of TestUploadTemplateLargeResumesAfterCrash")
+	}
+	client := docgen.NewClient(baseURL)
+	client.UploadChunkSize = uploadChunk
+	_, err := client.UploadTemplateLarge(context.Background(), os.Getenv(uploadCrashFileEnv))
+	t.Fatalf("upload returned before the process was killed: %v", err)
+}
+
+func TestUploadTemplateLargeResumesAfterCrash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts a child process")
+	}
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	path, data := largeTemplate(t, 8)
+
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	const crashAt = 4
+	gate := newUploadGate(t, srv, crashAt)
+
+	// 子进程确认 3 个分片后在第 4 个分片处被杀死
+	cmd := exec.Command(os.Args[0], "-test.run=^TestUploadTemplateLargeCrashChild$", "-test.count=1")
+	cmd.Env = append(os.Environ(), uploadCrashURLEnv+"="+gate.URL, uploadCrashFileEnv+"="+path, "TMPDIR="+tmp)
+	var output bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &output
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-gate.reached:
+	case <-time.After(30 * time.Second):
+		cmd.Process.Kill()
+		cmd.Wait()
+		t.Fatalf("child never reached chunk %d:\n%s", crashAt, output.String())
+	}
+	if err := cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	cmd.Wait()
+
+	acked := int64((crashAt - 1) * uploadChunk)
+	if got := uploadManifests(t, tmp); len(got) != 1 {
+		t.Fatalf("manifests after crash = %v, want 1", got)
+	}
+	if got := srv.UploadSessionCount(); got != 1 {
+		t.Fatalf("sessions after crash = %d, want 1", got)
+	}
+
+	// 新进程（新客户端）使用相同的服务地址与文件续传
+	resumedFrom := gate.release()
+	client := docgen.NewClient(gate.URL)
+	client.UploadChunkSize = uploadChunk
+	result, err := client.UploadTemplateLarge(context.Background(), path)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if result.FileName != "large.docx" {
+		t.Errorf("FileName = %q, want large.docx", result.FileName)
+	}
+
+	offsets := gate.offsetsFrom(resumedFrom)
+	if len(offsets) == 0 || offsets[0] != acked {
+		t.Fatalf("resumed chunk offsets = %v, want to start at %d", offsets, acked)
+	}
+	if creates := len(srv.RequestsTo(docgentest.EndpointTemplateUploads)); creates != 1 {
+		t.Errorf("sessions created = %d, want 1 (resume must reuse the session)", creates)
+	}
+	got, ok := srv.Template("large.docx")
+	if !ok || !bytes.Equal(got, data) {
+		t.Errorf("stored template differs from the file (%d of %d bytes)", len(got), len(data))
+	}
+	if got := uploadManifests(t, tmp); len(got) != 0 {
+		t.Errorf("manifests after commit = %v, want none", got)
+	}
+	if got := srv.UploadSessionCount(); got != 0 {
+		t.Errorf("sessions after commit = %d, want 0", got)
+	}
+}
+
+func TestAbortUploadTemplateLarge(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	path, data := largeTemplate(t, 4)
+
+	srv := docgentest.NewServer()
+	defer srv.Close()
+	client := docgen.NewClient(srv.URL)
+	client.UploadChunkSize = uploadChunk
+
+	srv.FailUploadChunksAfter(2)
+	if _, err := client.UploadTemplateLarge(context.Background(), path); err == nil {
+		t.Fatal("upload succeeded despite the interruption")
+	}
+	if got := uploadManifests(t, tmp); len(got) != 1 {
+		t.Fatalf("manifests after interruption = %v, want 1", got)
+	}
+
+	if err := client.AbortUploadTemplateLarge(path); err != nil {
+		t.Fatalf("AbortUploadTemplateLarge: %v", err)
+	}
+	if got := srv.UploadSessionCount(); got != 0 {
+		t.Errorf("sessions after abort = %d, want 0", got)
+	}
+	if got := uploadManifests(t, tmp); len(got) != 0 {
+		t.Errorf("manifests after abort = %v, want none", got)
+	}
+
+	// 放弃后重新上传从头开始
+	srv.FailUploadChunksAfter(-1)
+	if _, err := client.UploadTemplateLarge(context.Background(), path); err != nil {
+		t.Fatalf("upload after abort: %v", err)
+	}
+	if creates := len(srv.RequestsTo(docgentest.EndpointTemplateUploads)); creates != 2 {
+		t.Errorf("sessions created = %d, want 2", creates)
+	}
+	if got, _ := srv.Template("large.docx"); !bytes.Equal(got, data) {
+		t.Error("stored template differs from the file")
+	}
+}
@@ -0,0 +1,131 @@
+package docgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// QuotaResetHeader 配额耗尽（CodeQuotaExceeded）响应中的配额重置时间（RFC 3339）
+const QuotaResetHeader = "X-Quota-Reset"
+
+// 用量统计周期，用于 GetUsage
+const (
+	// UsagePeriodDay 当天
+	UsagePeriodDay = "day"
+	// UsagePeriodMonth 当月（服务端默认）
+	UsagePeriodMonth = "month"
+)
+
+// ErrQuotaExceeded 调用方应用的生成配额已耗尽，配额重置前重试没有意义
+//
+// 具体错误类型为 *QuotaExceededError，可通过 errors.As 获取重置时间
+var ErrQuotaExceeded = errors.New("docgen: quota exceeded")
+
+// QuotaExceededError 生成配额已耗尽（429，错误码 CodeQuotaExceeded）
+//
+// errors.Is(err, ErrQuotaExceeded) 返回 true；与限流不同，IsRetryable 返回 false
+type QuotaExceededError struct {
+	// Reset 配额重置时间，服务端未提供时为零值
+	Reset time.Time
+	// Err 服务端错误响应
+	Err *ErrorResponse
+}
+
+// Error 实现 error 接口
+func (e *QuotaExceededError) Error() string {
+	if e.Reset.IsZero() {
+		return fmt.Sprintf("%v: %s", ErrQuotaExceeded, e.Err.Message)
+	}
+	return fmt.Sprintf("%v: %s (resets at %s)", ErrQuotaExceeded, e.Err.Message, e.Reset.Format(time.RFC3339))
+}
+
+// Unwrap 返回服务端错误响应
+func (e *QuotaExceededError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrQuotaExceeded) 成立
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// UsageReport 统计周期内的用量
+type UsageReport struct {
+	// Period 统计周期，如 "month"
+	Period string `json:"period"`
+	// From 周期开始时间
+	From time.Time `json:"from"`
+	// To 周期结束时间
+	To time.Time `json:"to"`
+	// Documents 生成的文档数
+	Documents int64 `json:"documents"`
+	// Bytes 生成的文档总字节数
+	Bytes int64 `json:"bytes"`
+	// Endpoints 按接口分类的用量，键为接口路径，如 "/api/v1/doc/word"
+	Endpoints map[string]EndpointUsage `json:"endpoints,omitempty"`
+}
+
+// EndpointUsage 单个接口的用量
+type EndpointUsage struct {
+	// Requests 请求数（包括失败的请求）
+	Requests int64 `json:"requests"`
+	// Documents 生成的文档数
+	Documents int64 `json:"documents"`
+	// Bytes 生成的文档总字节数
+	Bytes int64 `json:"bytes"`
+}
+
+// Quota 调用方应用的生成配额
+type Quota struct {
+	// Limit 周期内允许生成的文档数，0 表示不限制
+	Limit int64 `json:"limit"`
+	// Used 周期内已生成的文档数
+	Used int64 `json:"used"`
+	// Remaining 剩余可生成的文档数
+	Remaining int64 `json:"remaining"`
+	// Reset 配额重置时间
+	Reset time.Time `json:"reset"`
+}
+
+// GetUsage 查询统计周期内的用量（文档数、字节数及按接口的分类）
+//
+// period 为统计周期，如 UsagePeriodDay、UsagePeriodMonth；为空时使用服务端默认周期
+func (c *Client) GetUsage(ctx context.Context, period string) (*UsageReport, error) {
+	if err := c.requireFeature(ctx, FeatureUsage); err != nil {
+		return nil, err
+	}
+	path := "/api/v1/usage"
+	if period != "" {
+		path += "?" + url.Values{"period": {period}}.Encode()
+	}
+	var result UsageReport
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetQuota 查询当前周期的生成配额
+func (c *Client) GetQuota(ctx context.Context) (*Quota, error) {
+	if err := c.requireFeature(ctx, FeatureUsage); err != nil {
+		return nil, err
+	}
+	var result Quota
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/usage/quota", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// quotaReset 解析 X-Quota-Reset 响应头
+func quotaReset(h http.Header) time.Time {
+	reset, err := time.Parse(time.RFC3339, h.Get(QuotaResetHeader))
+	if err != nil {
+		return time.Time{}
+	}
+	return reset
+}
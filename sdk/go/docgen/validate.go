@@ -0,0 +1,105 @@
+package docgen
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format 文档格式
+type Format string
+
+const (
+	// FormatDocx Word 文档（.docx）
+	FormatDocx Format = "docx"
+	// FormatXlsx Excel 文档（.xlsx）
+	FormatXlsx Format = "xlsx"
+	// FormatDocm 启用宏的 Word 文档（.docm）
+	FormatDocm Format = "docm"
+	// FormatXlsm 启用宏的 Excel 文档（.xlsm）
+	FormatXlsm Format = "xlsm"
+	// FormatPDF PDF 文档
+	FormatPDF Format = "pdf"
+)
+
+// contentPrefixLen 校验失败时错误中保留的响应体开头长度
+const contentPrefixLen = 64
+
+// ValidateDocument 校验数据是否为指定格式的文档
+//
+// docx / docm / xlsx / xlsm 检查 zip 文件头、[Content_Types].xml 以及主部件（word/document.xml 或 xl/workbook.xml），
+// 旧版二进制格式（OLE 文件头）单独说明；pdf 检查 %PDF 文件头。校验失败返回 *UnexpectedContentError（errors.Is(err, ErrUnexpectedContent) 为 true）
+func ValidateDocument(data []byte, expected Format) error {
+	switch expected {
+	case FormatDocx, FormatDocm:
+		return validateOOXML(data, expected, "word/document.xml")
+	case FormatXlsx, FormatXlsm:
+		return validateOOXML(data, expected, "xl/workbook.xml")
+	case FormatPDF:
+		if !bytes.HasPrefix(data, []byte("%PDF-")) {
+			return newUnexpectedContent(data, expected, "missing %PDF header")
+		}
+		return nil
+	default:
+		return fmt.Errorf("docgen: unsupported format %q", expected)
+	}
+}
+
+// validateOOXML 校验 OOXML 压缩包结构
+func validateOOXML(data []byte, format Format, mainPart string) error {
+	if bytes.HasPrefix(data, oleSignature) {
+		return newUnexpectedContent(data, format, "legacy binary Office format (OLE), not Office Open XML")
+	}
+	if !bytes.HasPrefix(data, zipSignature) {
+		return newUnexpectedContent(data, format, "missing zip signature")
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return newUnexpectedContent(data, format, "invalid zip archive: "+err.Error())
+	}
+
+	var hasContentTypes, hasMain bool
+	for _, f := range zr.File {
+		switch f.Name {
+		case "[Content_Types].xml":
+			hasContentTypes = true
+		case mainPart:
+			hasMain = true
+		}
+	}
+	if !hasContentTypes {
+		return newUnexpectedContent(data, format, "missing [Content_Types].xml")
+	}
+	if !hasMain {
+		return newUnexpectedContent(data, format, "missing "+mainPart)
+	}
+	return nil
+}
+
+// newUnexpectedContent 构造格式校验错误，保留响应体开头
+func newUnexpectedContent(data []byte, format Format, reason string) error {
+	prefix := data
+	if len(prefix) > contentPrefixLen {
+		prefix = prefix[:contentPrefixLen]
+	}
+	return &UnexpectedContentError{
+		Format: format,
+		Reason: reason,
+		Prefix: strings.ToValidUTF8(string(prefix), string(utf8.RuneError)),
+	}
+}
+
+// formatForPath 根据生成接口路径推断输出格式，未知接口返回空字符串
+func formatForPath(path string) Format {
+	switch {
+	case strings.HasPrefix(path, "/api/v1/doc/word"), path == "/api/v1/doc/assemble":
+		return FormatDocx
+	case strings.HasPrefix(path, "/api/v1/doc/excel"):
+		return FormatXlsx
+	case strings.HasPrefix(path, "/api/v1/doc/pdf"):
+		return FormatPDF
+	}
+	return ""
+}
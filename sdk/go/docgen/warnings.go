@@ -0,0 +1,168 @@
+package docgen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WarningsHeader 单部分响应携带渲染警告的响应头，取值为 Warning 的 JSON 数组
+const WarningsHeader = "X-Render-Warnings"
+
+// 服务端警告码（Warning.Code）
+const (
+	// WarningMissingPlaceholder 模板中的占位符没有对应的数据，Placeholder 为占位符名称
+	WarningMissingPlaceholder = "MISSING_PLACEHOLDER"
+	// WarningValueTruncated 单元格内容超过长度上限被截断，Location 为单元格位置
+	WarningValueTruncated = "VALUE_TRUNCATED"
+	// WarningFontSubstituted 模板字体不可用，已替换为其他字体
+	WarningFontSubstituted = "FONT_SUBSTITUTED"
+	// WarningImageFetchFailed 图片下载失败且 OnError 为 ImageFailWarn，Location 为所在行或位置
+	WarningImageFetchFailed = "IMAGE_FETCH_FAILED"
+)
+
+// 按警告码分类的错误，WithStrictWarnings 转换的 *WarningError 包含对应警告时 errors.Is 返回 true
+var (
+	// ErrStrictWarning 启用 WithStrictWarnings 时生成结果包含被视为错误的警告，具体错误类型为 *WarningError
+	ErrStrictWarning = errors.New("docgen: generation reported warnings")
+	// ErrMissingPlaceholder 占位符缺少数据（WarningMissingPlaceholder）
+	ErrMissingPlaceholder = errors.New("docgen: missing placeholder")
+	// ErrValueTruncated 单元格内容被截断（WarningValueTruncated）
+	ErrValueTruncated = errors.New("docgen: value truncated")
+	// ErrFontSubstituted 字体被替换（WarningFontSubstituted）
+	ErrFontSubstituted = errors.New("docgen: font substituted")
+)
+
+// Warning 服务端生成时发现的非致命问题，如模板中的占位符没有对应的数据
+type Warning struct {
+	// Code 警告码，如 WarningMissingPlaceholder
+	Code string `json:"code,omitempty"`
+	// Placeholder 相关的占位符名称
+	Placeholder string `json:"placeholder,omitempty"`
+	// Location 在模板中的位置，如 "word/document.xml:paragraph 12" 或 "Sheet1!B3"
+	Location string `json:"location,omitempty"`
+	// Message 说明
+	Message string `json:"message,omitempty"`
+}
+
+// String 返回警告的简短描述
+func (w Warning) String() string {
+	var b strings.Builder
+	b.WriteString(w.Code)
+	if w.Placeholder != "" {
+		fmt.Fprintf(&b, " %q", w.Placeholder)
+	}
+	if w.Location != "" {
+		b.WriteString(" at " + w.Location)
+	}
+	if w.Message != "" {
+		b.WriteString(": " + w.Message)
+	}
+	return b.String()
+}
+
+// warningCodes 警告码到错误分类的映射，由 RegisterWarningCode 扩展
+var warningCodes = struct {
+	mu    sync.RWMutex
+	kinds map[string]error
+}{kinds: map[string]error{
+	WarningMissingPlaceholder: ErrMissingPlaceholder,
+	WarningValueTruncated:     ErrValueTruncated,
+	WarningFontSubstituted:    ErrFontSubstituted,
+	WarningImageFetchFailed:   ErrImageFetchFailed,
+}}
+
+// RegisterWarningCode 将服务端警告码归入错误分类 kind，之后包含该警告的 *WarningError 满足 errors.Is(err, kind)
+//
+// 与 RegisterErrorCode 一致：已注册的警告码（包括内置警告码）会被覆盖，kind 为 nil 时取消分类，可并发使用
+func RegisterWarningCode(code string, kind error) {
+	warningCodes.mu.Lock()
+	defer warningCodes.mu.Unlock()
+	if kind == nil {
+		delete(warningCodes.kinds, code)
+		return
+	}
+	warningCodes.kinds[code] = kind
+}
+
+// warningKind 返回警告码对应的错误分类，未分类时返回 nil
+func warningKind(code string) error {
+	warningCodes.mu.RLock()
+	defer warningCodes.mu.RUnlock()
+	return warningCodes.kinds[code]
+}
+
+// WarningError 启用 WithStrictWarnings 时，生成结果包含被视为错误的警告
+//
+// errors.Is(err, ErrStrictWarning) 返回 true；Warnings 中任一警告的分类（见 RegisterWarningCode）同样满足 errors.Is，
+// 如 errors.Is(err, ErrMissingPlaceholder)
+type WarningError struct {
+	// Op 请求，如 "POST /api/v1/doc/word"
+	Op string
+	// Warnings 被视为错误的警告
+	Warnings []Warning
+}
+
+// Error 实现 error 接口
+func (e *WarningError) Error() string {
+	parts := make([]string, len(e.Warnings))
+	for i, w := range e.Warnings {
+		parts[i] = w.String()
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrStrictWarning, e.Op, strings.Join(parts, "; "))
+}
+
+// Is 使 errors.Is(err, ErrStrictWarning) 以及警告码对应的分类成立
+func (e *WarningError) Is(target error) bool {
+	if target == ErrStrictWarning {
+		return true
+	}
+	for _, w := range e.Warnings {
+		if kind := warningKind(w.Code); kind != nil && kind == target {
+			return true
+		}
+	}
+	return false
+}
+
+// headerWarnings 解析 X-Render-Warnings 响应头，格式不正确时忽略
+func headerWarnings(h http.Header) []Warning {
+	value := h.Get(WarningsHeader)
+	if value == "" {
+		return nil
+	}
+	var warnings []Warning
+	if err := json.Unmarshal([]byte(value), &warnings); err != nil {
+		return nil
+	}
+	return warnings
+}
+
+// reportWarnings 将生成结果的警告交给 WithWarningHandler，并按 WithStrictWarnings 转换为 *WarningError
+func (c *Client) reportWarnings(req *http.Request, warnings []Warning) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+	op := req.Method + " " + req.URL.Path
+	if c.warningHandler != nil {
+		for _, w := range warnings {
+			c.warningHandler(op, w)
+		}
+	}
+	if !c.strictAllWarnings && len(c.strictWarnings) == 0 {
+		return nil
+	}
+	var strict []Warning
+	for _, w := range warnings {
+		if c.strictAllWarnings || c.strictWarnings[w.Code] {
+			strict = append(strict, w)
+		}
+	}
+	if len(strict) == 0 {
+		return nil
+	}
+	return &WarningError{Op: op, Warnings: strict}
+}
@@ -0,0 +1,176 @@
+package docgen
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader 服务端回调请求中携带签名的请求头
+const WebhookSignatureHeader = "X-Docgen-Signature"
+
+// WebhookTolerance 回调签名时间戳允许的最大偏差，超出时视为重放请求
+var WebhookTolerance = 5 * time.Minute
+
+// 回调签名校验错误，可配合 errors.Is 判断
+var (
+	// ErrInvalidSignature 签名头格式错误或签名不匹配
+	ErrInvalidSignature = errors.New("docgen: invalid webhook signature")
+	// ErrSignatureExpired 签名时间戳超出 WebhookTolerance，可能是重放请求
+	ErrSignatureExpired = errors.New("docgen: webhook signature expired")
+)
+
+// Callback 任务结束时的回调配置
+type Callback struct {
+	// URL 回调地址，任务结束时服务端向该地址 POST JobEvent
+	URL string `json:"url"`
+	// Secret HMAC-SHA256 签名密钥，非空时回调请求携带 X-Docgen-Signature 头
+	Secret string `json:"secret,omitempty"`
+	// Headers 回调请求附加的请求头
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// JobEventType 任务事件类型
+type JobEventType string
+
+const (
+	// JobEventProgress 进度更新
+	JobEventProgress JobEventType = "progress"
+	// JobEventLog 日志输出
+	JobEventLog JobEventType = "log"
+	// JobEventCompleted 任务成功完成
+	JobEventCompleted JobEventType = "completed"
+	// JobEventFailed 任务失败
+	JobEventFailed JobEventType = "failed"
+	// JobEventCancelled 任务已取消
+	JobEventCancelled JobEventType = "cancelled"
+)
+
+// Terminal 是否为任务结束事件
+func (t JobEventType) Terminal() bool {
+	switch t {
+	case JobEventCompleted, JobEventFailed, JobEventCancelled:
+		return true
+	}
+	return false
+}
+
+// JobEvent 任务事件（回调请求体或进度流中的事件）
+type JobEvent struct {
+	// ID 事件 ID
+	ID string `json:"id,omitempty"`
+	// Type 事件类型
+	Type JobEventType `json:"type"`
+	// JobID 任务 ID
+	JobID string `json:"jobId"`
+	// Time 事件产生时间
+	Time time.Time `json:"time"`
+	// Job 事件产生时的任务快照（可能为空）
+	Job *Job `json:"job,omitempty"`
+	// Progress 进度，JobEventProgress 事件时非空
+	Progress *JobProgress `json:"progress,omitempty"`
+	// Message 日志内容，JobEventLog 事件时非空
+	Message string `json:"message,omitempty"`
+	// Error 失败原因，JobEventFailed 事件时非空
+	Error *ErrorResponse `json:"error,omitempty"`
+	// Err 进度流中断原因，仅 JobEventStreamError 事件时非空（客户端产生，不参与序列化）
+	Err error `json:"-"`
+}
+
+// VerifyWebhookSignature 校验回调请求签名
+//
+// secret: 提交任务时 Callback.Secret 的值
+// body: 原始请求体（需在解析 JSON 前读取）
+// header: X-Docgen-Signature 请求头的值
+//
+// 签名头格式为 "t=<unix 秒>,v1=<hex>"，签名为 HMAC-SHA256(secret, "<t>.<body>")；
+// 可以包含多个 v1 值（密钥轮换期间），任意一个匹配即通过。
+// 时间戳参与签名，因此不能被篡改；与当前时间相差超过 WebhookTolerance 时返回 ErrSignatureExpired，
+// 用于拒绝重放请求。需要更严格的防重放时，接收方可再按 JobEvent.ID 去重。
+//
+// 示例：secret "whsec_test"、body `{"type":"completed","jobId":"j1"}`、t=1700000000 时，
+// v1 为 21079f539f637fbc0cd235b2522c73554d6ce0e4501a032e6929534c76ae1255
+func VerifyWebhookSignature(secret string, body []byte, header string) error {
+	return verifyWebhookSignature(secret, body, header, time.Now())
+}
+
+// verifyWebhookSignature 以指定的当前时间校验签名
+func verifyWebhookSignature(secret string, body []byte, header string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed %s header", ErrInvalidSignature, WebhookSignatureHeader)
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", ErrInvalidSignature, timestamp)
+	}
+
+	expected := webhookMAC(secret, timestamp, body)
+	matched := false
+	for _, sig := range signatures {
+		decoded, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(decoded, expected) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+
+	diff := now.Sub(time.Unix(ts, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > WebhookTolerance {
+		return fmt.Errorf("%w: timestamp %d is %s away from now", ErrSignatureExpired, ts, diff.Round(time.Second))
+	}
+	return nil
+}
+
+// SignWebhook 生成回调签名头的值，供测试与自建回调转发使用
+func SignWebhook(secret string, body []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(webhookMAC(secret, timestamp, body))
+}
+
+// webhookMAC 计算 HMAC-SHA256(secret, "<timestamp>.<body>")
+func webhookMAC(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// ParseWebhookEvent 解析回调请求体
+//
+// 应在 VerifyWebhookSignature 校验通过后调用
+func ParseWebhookEvent(body []byte) (*JobEvent, error) {
+	var event JobEvent
+	if err := decodeJSON(body, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook event: %w", err)
+	}
+	if event.Type == "" || event.JobID == "" {
+		return nil, fmt.Errorf("failed to parse webhook event: missing type or jobId")
+	}
+	return &event, nil
+}
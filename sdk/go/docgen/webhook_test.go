@@ -0,0 +1,91 @@
+package docgen
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// webhookVectors 独立于 SDK 计算的签名（openssl dgst -sha256 -hmac <secret>，输入为 "<t>.<body>"）
+var webhookVectors = []struct {
+	secret string
+	t      int64
+	body   string
+	sig    string
+}{
+	{"whsec_test", 1700000000, `{"type":"completed","jobId":"j1"}`, "21079f539f637fbc0cd235b2522c73554d6ce0e4501a032e6929534c76ae1255"},
+	{"rotated secret ✓", 1700000300, `{"type":"failed","jobId":"任务-2","error":{"status":422,"code":"RENDER_ERROR","message":"bad"}}`, "81f17cc3faeb0b9ebd0e32af34055f01c70ef131021976832b15e1722fbb6c79"},
+	{"", 1700000000, "", "c1da1b6c6b8e9da7f4bbb90f7cab0820f271ad19ccbf80c88479c4e14f37d1c6"},
+}
+
+func TestWebhookSignatureVectors(t *testing.T) {
+	for _, v := range webhookVectors {
+		now := time.Unix(v.t, 0)
+		header := SignWebhook(v.secret, []byte(v.body), now)
+		if want := "t=" + itoa(v.t) + ",v1=" + v.sig; header != want {
+			t.Errorf("SignWebhook(%q) = %s, want %s", v.secret, header, want)
+		}
+		if err := verifyWebhookSignature(v.secret, []byte(v.body), "t="+itoa(v.t)+",v1="+v.sig, now.Add(time.Minute)); err != nil {
+			t.Errorf("vector %q: %v", v.secret, err)
+		}
+	}
+}
+
+func TestWebhookSignatureRejects(t *testing.T) {
+	v := webhookVectors[0]
+	now := time.Unix(v.t, 0)
+	body := []byte(v.body)
+	valid := "t=" + itoa(v.t) + ",v1=" + v.sig
+	cases := []struct {
+		name   string
+		secret string
+		body   []byte
+		header string
+		now    time.Time
+		want   error
+	}{
+		{"wrong secret", "whsec_other", body, valid, now, ErrInvalidSignature},
+		{"tampered body", v.secret, []byte(`{"type":"completed","jobId":"j2"}`), valid, now, ErrInvalidSignature},
+		{"tampered timestamp", v.secret, body, "t=" + itoa(v.t+1) + ",v1=" + v.sig, now, ErrInvalidSignature},
+		{"missing timestamp", v.secret, body, "v1=" + v.sig, now, ErrInvalidSignature},
+		{"missing signature", v.secret, body, "t=" + itoa(v.t), now, ErrInvalidSignature},
+		{"non-hex signature", v.secret, body, "t=" + itoa(v.t) + ",v1=zz", now, ErrInvalidSignature},
+		{"replayed", v.secret, body, valid, now.Add(WebhookTolerance + time.Second), ErrSignatureExpired},
+		{"from the future", v.secret, body, valid, now.Add(-WebhookTolerance - time.Second), ErrSignatureExpired},
+	}
+	for _, tc := range cases {
+		if err := verifyWebhookSignature(tc.secret, tc.body, tc.header, tc.now); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestWebhookSignatureRotation(t *testing.T) {
+	v := webhookVectors[0]
+	// 密钥轮换期间服务端以新旧密钥各签一次，任一匹配即通过
+	header := "t=" + itoa(v.t) + ", v1=" + webhookVectors[1].sig + ", v1=" + v.sig
+	if err := verifyWebhookSignature(v.secret, []byte(v.body), header, time.Unix(v.t, 0)); err != nil {
+		t.Errorf("rotated header: %v", err)
+	}
+	if err := VerifyWebhookSignature("s", []byte("{}"), SignWebhook("s", []byte("{}"), time.Now())); err != nil {
+		t.Errorf("fresh signature: %v", err)
+	}
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	event, err := ParseWebhookEvent([]byte(webhookVectors[1].body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != JobEventFailed || event.JobID != "任务-2" || event.Error == nil || event.Error.Code != CodeRenderError {
+		t.Errorf("event = %+v", event)
+	}
+	if _, err := ParseWebhookEvent([]byte(`{"jobId":"j1"}`)); err == nil {
+		t.Error("event without type accepted")
+	}
+}
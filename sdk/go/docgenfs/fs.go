@@ -0,0 +1,399 @@
+// Package docgenfs 将文档生成服务的模板存储以 io/fs 文件系统的形式提供
+//
+// FS 实现 fs.FS、fs.ReadDirFS、fs.ReadFileFS 与 fs.StatFS，可直接用于 fs.WalkDir、
+// testing/fstest 等基于 io/fs 的工具；WriteFile 与 Remove 分别上传与删除模板：
+//
+//	fsys := docgenfs.New(ctx, client, docgenfs.Options{})
+//	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+//	    ...
+//	})
+//	err = fsys.WriteFile("contract.docx", data, 0o644)
+//
+// 目录列表来自 ListTemplateInfosContext 并被缓存，文件内容在 Open 时通过 DownloadTemplateContext 完整下载到内存。
+// 模板名称中的 "/" 视为目录分隔符，目录由名称推导而来，本身不存储在服务端
+package docgenfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// Options New 的选项
+type Options struct {
+	// CacheTTL 目录列表的缓存时间；0 表示一直缓存，直到调用 Invalidate 或通过此 FS 写入、删除；
+	// 负数表示不缓存，每次操作都重新获取
+	CacheTTL time.Duration
+}
+
+// FS 基于模板存储的文件系统，可并发使用
+type FS struct {
+	ctx    context.Context
+	client *docgen.Client
+	opts   Options
+
+	// mu 保护缓存的目录列表
+	mu      sync.Mutex
+	files   map[string]docgen.TemplateInfo
+	fetched time.Time
+}
+
+var (
+	_ fs.FS         = (*FS)(nil)
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+)
+
+// New 创建基于 client 模板存储的文件系统，ctx 用于此后的所有请求，取消后各操作返回 ctx.Err()
+func New(ctx context.Context, client *docgen.Client, opts Options) *FS {
+	return &FS{ctx: ctx, client: client, opts: opts}
+}
+
+// Invalidate 丢弃缓存的目录列表，下次操作时重新获取；其他客户端修改模板后应调用
+func (f *FS) Invalidate() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files = nil
+}
+
+// Open 打开文件或目录，实现 fs.FS；文件内容在打开时完整下载
+func (f *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	files, err := f.listing()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if info, ok := files[name]; ok {
+		data, err := f.download(info.Name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &file{info: fileInfoOf(info), r: bytes.NewReader(data)}, nil
+	}
+	entries, ok := readDir(files, name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &dir{info: dirInfo(name), entries: entries}, nil
+}
+
+// ReadDir 返回目录中按名称排序的条目，实现 fs.ReadDirFS
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	files, err := f.listing()
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	if _, ok := files[name]; ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	entries, ok := readDir(files, name)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return entries, nil
+}
+
+// ReadFile 下载模板内容，实现 fs.ReadFileFS
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	files, err := f.listing()
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	if _, ok := files[name]; !ok {
+		if _, isDir := readDir(files, name); isDir {
+			return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+		}
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+	}
+	data, err := f.download(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// Stat 返回文件或目录信息，只使用目录列表而不下载内容，实现 fs.StatFS
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	files, err := f.listing()
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	if info, ok := files[name]; ok {
+		return fileInfoOf(info), nil
+	}
+	if _, ok := readDir(files, name); !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return dirInfo(name), nil
+}
+
+// WriteFile 上传模板，同名模板已存在时覆盖；perm 被忽略（服务端不保存权限）
+//
+// name 不能包含目录（服务端按文件名保存模板），上传前的格式检查与 UploadTemplateFromBytes 相同
+func (f *FS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." || strings.Contains(name, "/") {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	defer f.Invalidate()
+	if _, err := f.client.UploadTemplateFromBytesContext(f.ctx, data, name, docgen.UploadOptions{Overwrite: true}); err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: err}
+	}
+	return nil
+}
+
+// Remove 删除模板；模板不存在时返回的错误满足 errors.Is(err, fs.ErrNotExist)
+func (f *FS) Remove(name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	defer f.Invalidate()
+	resp, err := f.client.DeleteTemplateWithOptions(f.ctx, name, docgen.DeleteOptions{})
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: notExist(err)}
+	}
+	if !resp.Success {
+		// 服务端对不存在的模板返回 success=false
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	return nil
+}
+
+// listing 返回以模板名称为键的目录列表，按 CacheTTL 缓存
+func (f *FS) listing() (map[string]docgen.TemplateInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.files != nil && f.opts.CacheTTL >= 0 && (f.opts.CacheTTL == 0 || time.Since(f.fetched) < f.opts.CacheTTL) {
+		return f.files, nil
+	}
+	infos, err := f.client.ListTemplateInfosContext(f.ctx)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]docgen.TemplateInfo, len(infos))
+	for _, info := range infos {
+		// 不是合法路径的名称（如以 "/" 开头）无法通过 io/fs 访问
+		if fs.ValidPath(info.Name) && info.Name != "." {
+			files[info.Name] = info
+		}
+	}
+	f.files, f.fetched = files, time.Now()
+	return files, nil
+}
+
+// download 下载模板内容，模板已不存在时返回 fs.ErrNotExist
+func (f *FS) download(name string) ([]byte, error) {
+	data, err := f.client.DownloadTemplateContext(f.ctx, name)
+	return data, notExist(err)
+}
+
+// notExist 将模板不存在的错误转换为同时满足 fs.ErrNotExist 的错误
+func notExist(err error) error {
+	if errors.Is(err, docgen.ErrTemplateNotFound) {
+		return &notExistError{err}
+	}
+	return err
+}
+
+// notExistError 同时匹配 fs.ErrNotExist 与原始错误
+type notExistError struct {
+	err error
+}
+
+// Error 实现 error 接口
+func (e *notExistError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap 返回原始错误
+func (e *notExistError) Unwrap() error {
+	return e.err
+}
+
+// Is 使 errors.Is(err, fs.ErrNotExist) 成立
+func (e *notExistError) Is(target error) bool {
+	return target == fs.ErrNotExist
+}
+
+// readDir 返回目录 name 的直接子条目；name 不是目录时 ok 为 false（根目录 "." 总是存在）
+func readDir(files map[string]docgen.TemplateInfo, name string) (entries []fs.DirEntry, ok bool) {
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	seen := make(map[string]bool)
+	for full, info := range files {
+		rest, found := strings.CutPrefix(full, prefix)
+		if !found {
+			continue
+		}
+		ok = true
+		child, _, isDir := strings.Cut(rest, "/")
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+		if isDir {
+			entries = append(entries, dirInfo(prefix+child))
+		} else {
+			entries = append(entries, fileInfoOf(info))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, ok || name == "."
+}
+
+// fileInfo 文件或目录信息，同时实现 fs.FileInfo 与 fs.DirEntry
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+}
+
+// fileInfoOf 模板信息对应的文件信息，模板只读（写入通过 FS.WriteFile）
+func fileInfoOf(info docgen.TemplateInfo) *fileInfo {
+	return &fileInfo{name: baseName(info.Name), size: info.Size, modTime: info.LastModified, mode: 0o444}
+}
+
+// dirInfo 由模板名称推导出的目录信息
+func dirInfo(name string) *fileInfo {
+	return &fileInfo{name: baseName(name), mode: fs.ModeDir | 0o555}
+}
+
+// baseName 路径的最后一段
+func baseName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// Name 实现 fs.FileInfo 与 fs.DirEntry
+func (i *fileInfo) Name() string {
+	return i.name
+}
+
+// Size 实现 fs.FileInfo，目录为 0
+func (i *fileInfo) Size() int64 {
+	return i.size
+}
+
+// Mode 实现 fs.FileInfo
+func (i *fileInfo) Mode() fs.FileMode {
+	return i.mode
+}
+
+// ModTime 实现 fs.FileInfo，目录为零值
+func (i *fileInfo) ModTime() time.Time {
+	return i.modTime
+}
+
+// IsDir 实现 fs.FileInfo 与 fs.DirEntry
+func (i *fileInfo) IsDir() bool {
+	return i.mode.IsDir()
+}
+
+// Sys 实现 fs.FileInfo
+func (i *fileInfo) Sys() any {
+	return nil
+}
+
+// Type 实现 fs.DirEntry
+func (i *fileInfo) Type() fs.FileMode {
+	return i.mode.Type()
+}
+
+// Info 实现 fs.DirEntry
+func (i *fileInfo) Info() (fs.FileInfo, error) {
+	return i, nil
+}
+
+// file 已下载到内存的模板
+type file struct {
+	info *fileInfo
+	r    *bytes.Reader
+}
+
+// Stat 实现 fs.File
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// Read 实现 fs.File
+func (f *file) Read(p []byte) (int, error) {
+	return f.r.Read(p)
+}
+
+// Close 实现 fs.File
+func (f *file) Close() error {
+	return nil
+}
+
+// Seek 实现 io.Seeker
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	return f.r.Seek(offset, whence)
+}
+
+// ReadAt 实现 io.ReaderAt
+func (f *file) ReadAt(p []byte, off int64) (int, error) {
+	return f.r.ReadAt(p, off)
+}
+
+// dir 打开的目录，实现 fs.ReadDirFile
+type dir struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+// Stat 实现 fs.File
+func (d *dir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+// Close 实现 fs.File
+func (d *dir) Close() error {
+	return nil
+}
+
+// Read 目录不可读取内容
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir 实现 fs.ReadDirFile：n > 0 时最多返回 n 个条目，读完后返回 io.EOF；n <= 0 时返回剩余全部条目
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
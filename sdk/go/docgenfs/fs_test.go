@@ -0,0 +1,133 @@
+package docgenfs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgenfs"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// newFS 启动带有嵌套目录模板的模拟服务器，返回基于其模板存储的 FS
+func newFS(t *testing.T) (*docgentest.Server, *docgenfs.FS) {
+	t.Helper()
+	srv := docgentest.NewServer()
+	t.Cleanup(srv.Close)
+	for _, name := range []string{"invoice.docx", "contracts/sales.docx", "contracts/hr/offer.docx", "reports/monthly.xlsx"} {
+		srv.AddTemplate(name, docgentest.MinimalDocx(name))
+	}
+	return srv, docgenfs.New(context.Background(), docgen.NewClient(srv.URL), docgenfs.Options{})
+}
+
+func TestFSConformance(t *testing.T) {
+	_, fsys := newFS(t)
+	if err := fstest.TestFS(fsys, "invoice.docx", "contracts/sales.docx", "contracts/hr/offer.docx", "reports/monthly.xlsx"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSMissingPaths(t *testing.T) {
+	_, fsys := newFS(t)
+	ops := map[string]func(name string) error{
+		"Open": func(name string) error {
+			f, err := fsys.Open(name)
+			if err == nil {
+				f.Close()
+			}
+			return err
+		},
+		"Stat": func(name string) error {
+			_, err := fsys.Stat(name)
+			return err
+		},
+		"ReadFile": func(name string) error {
+			_, err := fsys.ReadFile(name)
+			return err
+		},
+		"ReadDir": func(name string) error {
+			_, err := fsys.ReadDir(name)
+			return err
+		},
+	}
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"missing.docx", fs.ErrNotExist},
+		{"contracts/missing.docx", fs.ErrNotExist},
+		{"missing/sales.docx", fs.ErrNotExist},
+		// 名称前缀相同的模板不构成目录
+		{"contract", fs.ErrNotExist},
+		{"../invoice.docx", fs.ErrInvalid},
+		{"/invoice.docx", fs.ErrInvalid},
+		{"contracts/", fs.ErrInvalid},
+	}
+	for op, call := range ops {
+		for _, tt := range tests {
+			err := call(tt.name)
+			var pathErr *fs.PathError
+			if !errors.Is(err, tt.want) || !errors.As(err, &pathErr) || pathErr.Path != tt.name {
+				t.Errorf("%s(%q) = %v, want *PathError matching %v", op, tt.name, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestFSReadDir(t *testing.T) {
+	_, fsys := newFS(t)
+	tests := []struct {
+		dir  string
+		want []string
+	}{
+		{".", []string{"contracts/", "invoice.docx", "reports/"}},
+		{"contracts", []string{"hr/", "sales.docx"}},
+		{"contracts/hr", []string{"offer.docx"}},
+	}
+	for _, tt := range tests {
+		entries, err := fsys.ReadDir(tt.dir)
+		if err != nil {
+			t.Fatalf("ReadDir(%q): %v", tt.dir, err)
+		}
+		var got []string
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() {
+				name += "/"
+			}
+			got = append(got, name)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("ReadDir(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+
+	// 文件不是目录，目录不能按文件读取
+	if _, err := fsys.ReadDir("invoice.docx"); err == nil {
+		t.Error("ReadDir on a file succeeded")
+	}
+	if _, err := fsys.ReadFile("contracts"); err == nil || errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile on a directory = %v, want an is-a-directory error", err)
+	}
+}
+
+func TestFSRemoveMissing(t *testing.T) {
+	srv, fsys := newFS(t)
+	if err := fsys.Remove("missing.docx"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove missing = %v, want fs.ErrNotExist", err)
+	}
+	if err := fsys.Remove("invoice.docx"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.Template("invoice.docx"); ok {
+		t.Error("template still stored after Remove")
+	}
+	// 写入与删除后目录列表重新获取
+	if _, err := fsys.Stat("invoice.docx"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat after Remove = %v, want fs.ErrNotExist", err)
+	}
+}
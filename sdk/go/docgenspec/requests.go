@@ -0,0 +1,143 @@
+package docgenspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// RequestType 受校验的请求结构及其接口
+type RequestType struct {
+	// Name 请求结构名称，如 "WordGenRequest"
+	Name string
+	// Method 请求方法
+	Method string
+	// Path 接口路径（OpenAPI 文档中的路径）
+	Path string
+	// Sample 请求结构的零值，校验时按反射填充所有字段
+	Sample any
+}
+
+// Requests SDK 发送 JSON 请求体的生成接口
+var Requests = []RequestType{
+	{Name: "WordGenRequest", Method: http.MethodPost, Path: "/api/v1/doc/word", Sample: docgen.WordGenRequest{}},
+	{Name: "WordBatchRequest", Method: http.MethodPost, Path: "/api/v1/doc/word/batch", Sample: docgen.WordBatchRequest{}},
+	{Name: "ExcelGenRequest", Method: http.MethodPost, Path: "/api/v1/doc/excel", Sample: docgen.ExcelGenRequest{}},
+	{Name: "ExcelFillRequest", Method: http.MethodPost, Path: "/api/v1/doc/excel/fill", Sample: docgen.ExcelFillRequest{}},
+}
+
+// sampleValues 需要合法取值的类型（如枚举），其他类型按反射填充
+var sampleValues = map[reflect.Type]any{
+	reflect.TypeOf(docgen.Priority("")): docgen.PriorityNormal,
+}
+
+// CheckRequests 校验 Requests 中各请求结构序列化后的 JSON 是否符合 OpenAPI 文档
+//
+// 每个结构的所有字段都填充为非零值后序列化，因此 omitempty 字段同样受检查。
+// ignore 为允许的偏差，格式为 "WordGenRequest.fontOptions"（忽略该字段及其子字段），
+// 用于 SDK 已支持而服务端尚未发布的字段
+func CheckRequests(spec *Spec, ignore ...string) ([]Mismatch, error) {
+	var result []Mismatch
+	for _, rt := range Requests {
+		schema, err := spec.RequestSchema(rt.Method, rt.Path)
+		if err != nil {
+			return nil, err
+		}
+		body, err := json.Marshal(populate(reflect.TypeOf(rt.Sample)).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("docgenspec: marshal %s: %w", rt.Name, err)
+		}
+		mismatches, err := spec.Validate(rt.Name, schema, body)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mismatches {
+			if !ignored(m, ignore) {
+				result = append(result, m)
+			}
+		}
+	}
+	return result, nil
+}
+
+// ignored 判断不一致是否属于允许的偏差
+func ignored(m Mismatch, ignore []string) bool {
+	full := m.Request + "." + m.Field
+	for _, prefix := range ignore {
+		if full == prefix || strings.HasPrefix(full, prefix+".") || strings.HasPrefix(full, prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// populate 创建 t 类型的值并将所有字段填充为非零值
+func populate(t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+	if sample, ok := sampleValues[t]; ok {
+		v.Set(reflect.ValueOf(sample))
+		return v
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString("sample")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Pointer:
+		v.Set(populate(t.Elem()).Addr())
+	case reflect.Slice:
+		v.Set(reflect.Append(reflect.MakeSlice(t, 0, 1), populate(t.Elem())))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(t))
+		v.SetMapIndex(populate(t.Key()), populate(t.Elem()))
+	case reflect.Interface:
+		// 模板数据中的任意值
+		if t.NumMethod() == 0 {
+			v.Set(reflect.ValueOf("sample"))
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() {
+				v.Field(i).Set(populate(f.Type))
+			}
+		}
+	}
+	return v
+}
+
+// TB AssertRequestsMatchSpec 使用的 testing.TB 子集
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+// AssertRequestsMatchSpec 在测试中校验请求结构与 specPath 处的 OpenAPI 文档（JSON）一致，每处不一致报告一个错误
+//
+// 下游项目可在 CI 中对照所部署版本的文档运行，ignore 的格式见 CheckRequests
+func AssertRequestsMatchSpec(t TB, specPath string, ignore ...string) {
+	t.Helper()
+	spec, err := LoadSpec(specPath)
+	if err != nil {
+		t.Fatalf("%v", err)
+		return
+	}
+	mismatches, err := CheckRequests(spec, ignore...)
+	if err != nil {
+		t.Fatalf("%v", err)
+		return
+	}
+	for _, m := range mismatches {
+		t.Errorf("request does not match spec: %s", m)
+	}
+}
@@ -0,0 +1,146 @@
+// Package docgenspec 按服务端发布的 OpenAPI 文档校验 SDK 请求结构，发现字段改名、缺失等偏差
+//
+// 服务端（springdoc）在 /v3/api-docs 发布 OpenAPI 3 文档。可在测试中对照导出的文档检查：
+//
+//	func TestRequestsMatchSpec(t *testing.T) {
+//	    docgenspec.AssertRequestsMatchSpec(t, "testdata/api-docs.json")
+//	}
+//
+// 也可在服务启动时（由调用方的配置开关控制）对照正在运行的服务检查：
+//
+//	spec, err := docgenspec.FetchSpec(ctx, "http://localhost:8081", nil)
+//	if err == nil {
+//	    mismatches, err = docgenspec.CheckRequests(spec)
+//	}
+package docgenspec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// APIDocsPath 服务端 OpenAPI 文档路径
+const APIDocsPath = "/v3/api-docs"
+
+// Spec OpenAPI 3 文档中用于请求校验的部分
+type Spec struct {
+	OpenAPI    string              `json:"openapi"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// PathItem 接口路径下的各方法
+type PathItem map[string]*Operation
+
+// Operation 接口定义
+type Operation struct {
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema *Schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+// Schema JSON Schema 中用于校验的关键字
+type Schema struct {
+	Ref        string             `json:"$ref,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	// AdditionalProperties 为 false、true 或 Schema，未设置时为 nil
+	AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+	Enum                 []any           `json:"enum,omitempty"`
+	Nullable             bool            `json:"nullable,omitempty"`
+	AllOf                []*Schema       `json:"allOf,omitempty"`
+	OneOf                []*Schema       `json:"oneOf,omitempty"`
+	AnyOf                []*Schema       `json:"anyOf,omitempty"`
+}
+
+// ParseSpec 解析 JSON 格式的 OpenAPI 文档
+func ParseSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("docgenspec: parse spec: %w", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, fmt.Errorf("docgenspec: unsupported openapi version %q (want 3.x)", spec.OpenAPI)
+	}
+	return &spec, nil
+}
+
+// LoadSpec 读取 JSON 格式的 OpenAPI 文档文件，如从 /v3/api-docs 导出的文件
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("docgenspec: %w", err)
+	}
+	return ParseSpec(data)
+}
+
+// FetchSpec 从服务端的 /v3/api-docs 获取 OpenAPI 文档，httpClient 为 nil 时使用 http.DefaultClient
+func FetchSpec(ctx context.Context, baseURL string, httpClient *http.Client) (*Spec, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+APIDocsPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("docgenspec: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("docgenspec: fetch spec: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("docgenspec: fetch spec: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("docgenspec: fetch spec: status %d", resp.StatusCode)
+	}
+	return ParseSpec(data)
+}
+
+// RequestSchema 返回接口 JSON 请求体的 Schema（已解析 $ref）
+func (s *Spec) RequestSchema(method, path string) (*Schema, error) {
+	item, ok := s.Paths[path]
+	if !ok {
+		return nil, fmt.Errorf("docgenspec: path %s not in spec", path)
+	}
+	op := item[strings.ToLower(method)]
+	if op == nil || op.RequestBody == nil {
+		return nil, fmt.Errorf("docgenspec: %s %s has no request body in spec", method, path)
+	}
+	for mediaType, content := range op.RequestBody.Content {
+		if strings.HasPrefix(mediaType, "application/json") && content.Schema != nil {
+			return s.resolve(content.Schema)
+		}
+	}
+	return nil, fmt.Errorf("docgenspec: %s %s has no application/json request body in spec", method, path)
+}
+
+// resolve 解析 "#/components/schemas/Name" 形式的 $ref
+func (s *Spec) resolve(schema *Schema) (*Schema, error) {
+	for depth := 0; schema.Ref != ""; depth++ {
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		if !ok || depth > 32 {
+			return nil, fmt.Errorf("docgenspec: unsupported $ref %q", schema.Ref)
+		}
+		target, ok := s.Components.Schemas[name]
+		if !ok {
+			return nil, fmt.Errorf("docgenspec: $ref %q not found", schema.Ref)
+		}
+		schema = target
+	}
+	return schema, nil
+}
@@ -0,0 +1,251 @@
+package docgenspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MismatchKind 请求与 Schema 不一致的类型
+type MismatchKind string
+
+const (
+	// UnknownField SDK 发送了 Schema 中不存在的字段（服务端会静默忽略）
+	UnknownField MismatchKind = "unknown-field"
+	// MissingRequired Schema 中的必填字段 SDK 没有发送
+	MissingRequired MismatchKind = "missing-required"
+	// WrongType 字段类型与 Schema 不一致
+	WrongType MismatchKind = "wrong-type"
+	// InvalidEnum 取值不在 Schema 的枚举中
+	InvalidEnum MismatchKind = "invalid-enum"
+)
+
+// Mismatch 请求 JSON 与 Schema 的一处不一致
+type Mismatch struct {
+	// Request 请求结构名称，如 "WordGenRequest"
+	Request string
+	// Field 字段路径，如 "fontOptions.embed"、"dataList[0]"
+	Field string
+	// Kind 不一致类型
+	Kind MismatchKind
+	// Expected Schema 要求的类型或取值
+	Expected string
+	// Actual 请求中的类型或取值
+	Actual string
+}
+
+// String 返回不一致的描述
+func (m Mismatch) String() string {
+	s := fmt.Sprintf("%s.%s: %s", m.Request, m.Field, m.Kind)
+	if m.Expected != "" || m.Actual != "" {
+		s += fmt.Sprintf(" (expected %s, got %s)", m.Expected, m.Actual)
+	}
+	return s
+}
+
+// Validate 校验 JSON 请求体是否符合 Schema，返回所有不一致之处
+//
+// request 为 Mismatch.Request 中的名称。与 springdoc 对 java.lang.Object 的映射一致，
+// 没有 properties 的 {"type": "object"} 接受任意值
+func (s *Spec) Validate(request string, schema *Schema, body []byte) ([]Mismatch, error) {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("docgenspec: %s: %w", request, err)
+	}
+	v := validator{spec: s, request: request}
+	if err := v.validate(value, schema, ""); err != nil {
+		return nil, err
+	}
+	return v.mismatches, nil
+}
+
+// validator 递归校验 JSON 值
+type validator struct {
+	spec       *Spec
+	request    string
+	mismatches []Mismatch
+}
+
+// report 记录一处不一致
+func (v *validator) report(field string, kind MismatchKind, expected, actual string) {
+	v.mismatches = append(v.mismatches, Mismatch{Request: v.request, Field: field, Kind: kind, Expected: expected, Actual: actual})
+}
+
+// validate 按 Schema 校验 value，field 为当前字段路径
+func (v *validator) validate(value any, schema *Schema, field string) error {
+	schema, err := v.spec.resolve(schema)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		// null 与缺省等价，省略字段由 omitempty 决定
+		return nil
+	}
+	for _, sub := range schema.AllOf {
+		if err := v.validate(value, sub, field); err != nil {
+			return err
+		}
+	}
+	if len(schema.OneOf)+len(schema.AnyOf) > 0 {
+		alternatives := append(append([]*Schema(nil), schema.OneOf...), schema.AnyOf...)
+		return v.validateAlternatives(value, alternatives, field)
+	}
+	if len(schema.Enum) > 0 && !inEnum(value, schema.Enum) {
+		v.report(field, InvalidEnum, fmt.Sprint(schema.Enum), fmt.Sprint(value))
+	}
+
+	switch schema.Type {
+	case "":
+		return nil
+	case "object":
+		if len(schema.Properties) == 0 && len(schema.AdditionalProperties) == 0 && len(schema.Required) == 0 {
+			// java.lang.Object 映射为不带 properties 的 object，接受任意值
+			return nil
+		}
+		obj, ok := value.(map[string]any)
+		if !ok {
+			v.report(field, WrongType, "object", jsonType(value))
+			return nil
+		}
+		return v.validateObject(obj, schema, field)
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			v.report(field, WrongType, "array", jsonType(value))
+			return nil
+		}
+		if schema.Items == nil {
+			return nil
+		}
+		for i, item := range arr {
+			if err := v.validate(item, schema.Items, fmt.Sprintf("%s[%d]", field, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			v.report(field, WrongType, "integer", jsonType(value))
+		}
+	case "number", "string", "boolean":
+		if actual := jsonType(value); actual != schema.Type && !(schema.Type == "number" && actual == "integer") {
+			v.report(field, WrongType, schema.Type, actual)
+		}
+	}
+	return nil
+}
+
+// validateObject 校验对象的字段：未知字段、缺失的必填字段与各字段的值
+func (v *validator) validateObject(obj map[string]any, schema *Schema, field string) error {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			v.report(joinField(field, name), MissingRequired, "", "")
+		}
+	}
+	if len(schema.Properties) == 0 && len(schema.AdditionalProperties) == 0 {
+		// 只声明了必填字段的自由格式对象
+		return nil
+	}
+	additional, allowAny, err := v.additional(schema)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sub, ok := schema.Properties[name]
+		switch {
+		case ok:
+		case additional != nil:
+			sub = additional
+		case allowAny:
+			continue
+		default:
+			v.report(joinField(field, name), UnknownField, "", "")
+			continue
+		}
+		if err := v.validate(obj[name], sub, joinField(field, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// additional 解析 additionalProperties：返回值的 Schema，或是否允许任意字段
+func (v *validator) additional(schema *Schema) (*Schema, bool, error) {
+	raw := strings.TrimSpace(string(schema.AdditionalProperties))
+	switch raw {
+	case "", "false":
+		return nil, false, nil
+	case "true", "{}":
+		return nil, true, nil
+	}
+	var sub Schema
+	if err := json.Unmarshal(schema.AdditionalProperties, &sub); err != nil {
+		return nil, false, fmt.Errorf("docgenspec: additionalProperties: %w", err)
+	}
+	return &sub, false, nil
+}
+
+// validateAlternatives 校验 oneOf / anyOf：任一分支没有不一致即通过，否则报告第一个分支的不一致
+func (v *validator) validateAlternatives(value any, alternatives []*Schema, field string) error {
+	var first []Mismatch
+	for i, alt := range alternatives {
+		sub := validator{spec: v.spec, request: v.request}
+		if err := sub.validate(value, alt, field); err != nil {
+			return err
+		}
+		if len(sub.mismatches) == 0 {
+			return nil
+		}
+		if i == 0 {
+			first = sub.mismatches
+		}
+	}
+	v.mismatches = append(v.mismatches, first...)
+	return nil
+}
+
+// inEnum 判断取值是否在枚举中
+func inEnum(value any, enum []any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType 返回 JSON 值的类型名称，整数返回 "integer"
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// joinField 拼接字段路径
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,47 @@
+package docgentest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// EndpointAssemble 片段组装接口路径
+const EndpointAssemble = "/api/v1/doc/assemble"
+
+// handleAssemble 组装文档：每个片段以共享数据与片段数据合并后的结果生成一组段落，各片段的警告合并返回
+func (s *Server) handleAssemble(w http.ResponseWriter, req *CapturedRequest) {
+	var body docgen.AssemblySpec
+	if err := decodeBody(req.Body, &body); err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "malformed request body: "+err.Error())
+		return
+	}
+	if len(body.Fragments) == 0 {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "fragments: must not be empty")
+		return
+	}
+	var paragraphs []string
+	var warnings []docgen.Warning
+	for i, f := range body.Fragments {
+		if _, ok := s.Template(f.TemplateName); !ok {
+			writeError(w, http.StatusUnprocessableEntity, docgen.CodeTemplateNotFound, "Template not found: "+f.TemplateName)
+			return
+		}
+		data := make(map[string]any, len(body.Data)+len(f.Data))
+		for k, v := range body.Data {
+			data[k] = v
+		}
+		for k, v := range f.Data {
+			data[k] = v
+		}
+		if key, ok := renderFailure(data); ok {
+			writeError(w, http.StatusUnprocessableEntity, docgen.CodeRenderError, fmt.Sprintf("Render error in fragments[%d].%s", i, key))
+			return
+		}
+		paragraphs = append(paragraphs, dataParagraphs(data)...)
+		warnings = append(warnings, s.missingPlaceholderWarnings(f.TemplateName, data)...)
+	}
+	setWarnings(w, warnings)
+	writeDocument(w, s.volatile(req, MinimalDocx(paragraphs...)), withDefault(body.FileName, "assembled")+".docx", docgen.FormatDocx.ContentType())
+}
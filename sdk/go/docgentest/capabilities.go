@@ -0,0 +1,98 @@
+package docgentest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// EndpointCapabilities 能力查询接口路径
+const EndpointCapabilities = "/api/v1/capabilities"
+
+// mockFeatures 模拟服务器实现的可选功能及其接口路径（path 为空表示功能不对应独立接口）
+var mockFeatures = []struct {
+	feature docgen.Feature
+	path    string
+}{
+	{docgen.FeatureAsyncJobs, EndpointJobs},
+	{docgen.FeatureChunkedUpload, EndpointTemplateUploads},
+	{docgen.FeatureSignedLinks, EndpointLinks},
+	{docgen.FeatureBatchTemplates, ""},
+	{docgen.FeatureFontEmbedding, ""},
+	{docgen.FeatureSheetSplit, ""},
+	{docgen.FeatureTemplateChecksum, ""},
+	{docgen.FeatureBatchPartialFailure, ""},
+	{docgen.FeatureImageFetch, ""},
+	{docgen.FeatureUsage, EndpointUsage},
+	{docgen.FeatureTemplateDependencies, EndpointTemplateDependencies},
+	{docgen.FeatureBatchArchive, ""},
+	{docgen.FeaturePagedPreview, ""},
+	{docgen.FeatureMacroTemplates, ""},
+	{docgen.FeatureSections, ""},
+	{docgen.FeatureTemporaryTemplates, ""},
+	{docgen.FeatureAssembly, EndpointAssemble},
+	{docgen.FeatureDeterministicOutput, ""},
+	{docgen.FeatureResultStore, EndpointResults},
+	{docgen.FeatureSimpleRender, ""},
+	{docgen.FeatureContentControls, ""},
+}
+
+// WithDisabledFeatures 模拟尚未提供指定功能的旧版服务：能力接口不列出这些功能，对应接口返回 404，
+// 禁用 docgen.FeatureBatchTemplates 时批量生成忽略 templates 字段
+func WithDisabledFeatures(features ...docgen.Feature) ServerOption {
+	return func(s *Server) {
+		for _, f := range features {
+			s.disabledFeatures[f] = true
+		}
+	}
+}
+
+// WithoutCapabilitiesEndpoint 模拟不提供能力接口的旧版服务，客户端需以 OPTIONS 请求逐个探测
+func WithoutCapabilitiesEndpoint() ServerOption {
+	return func(s *Server) {
+		s.noCapabilities = true
+	}
+}
+
+// handleCapabilities 返回未被禁用的功能列表
+func (s *Server) handleCapabilities(w http.ResponseWriter) {
+	caps := docgen.Capabilities{Version: "mock", Features: []docgen.Feature{}}
+	for _, f := range mockFeatures {
+		if !s.disabledFeatures[f.feature] {
+			caps.Features = append(caps.Features, f.feature)
+		}
+	}
+	writeJSON(w, http.StatusOK, caps)
+}
+
+// disabledEndpoint 判断路径是否属于被禁用的功能
+func (s *Server) disabledEndpoint(path string) bool {
+	for _, f := range mockFeatures {
+		if f.path != "" && s.disabledFeatures[f.feature] && (path == f.path || strings.HasPrefix(path, f.path+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleOptions 响应 OPTIONS 探测：已实现的接口返回 204，其他路径返回 404
+func (s *Server) handleOptions(w http.ResponseWriter, path string) {
+	known := []string{
+		EndpointWord, EndpointWordBatch, EndpointExcel, EndpointExcelFill,
+		EndpointTemplateUpload, EndpointTemplateList, EndpointTemplateInfo, EndpointTemplate,
+	}
+	for _, f := range mockFeatures {
+		if f.path != "" {
+			known = append(known, f.path)
+		}
+	}
+	for _, p := range known {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			w.Header().Set("Allow", "GET, HEAD, POST, PUT, DELETE, OPTIONS")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for OPTIONS "+path)
+}
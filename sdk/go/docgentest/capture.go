@@ -0,0 +1,171 @@
+package docgentest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// CapturedRequest 模拟服务器捕获的请求
+type CapturedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Audit 解析请求携带的审计信息请求头
+func (r *CapturedRequest) Audit() docgen.AuditInfo {
+	return docgen.ParseAuditHeaders(r.Header)
+}
+
+// JSON 将请求体解析为通用结构（数值保留为 json.Number）
+func (r *CapturedRequest) JSON() (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(r.Body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("request body is not JSON: %w", err)
+	}
+	return v, nil
+}
+
+// JSONPath 按路径读取请求体中的值
+//
+// 路径语法：以 "." 分隔对象字段，以 "[n]" 访问数组元素，
+// 如 "dataList[0].name"、"listData.items[2].price"
+func (r *CapturedRequest) JSONPath(path string) (any, error) {
+	v, err := r.JSON()
+	if err != nil {
+		return nil, err
+	}
+	return lookupPath(v, path)
+}
+
+// AssertJSONPath 断言请求体中指定路径的值等于 want
+//
+// want 会先序列化为 JSON 再比较，因此 1、int64(1) 与 json.Number("1") 视为相等
+func AssertJSONPath(t testing.TB, r *CapturedRequest, path string, want any) {
+	t.Helper()
+	if r == nil {
+		t.Fatalf("AssertJSONPath(%q): no request captured", path)
+		return
+	}
+	got, err := r.JSONPath(path)
+	if err != nil {
+		t.Fatalf("AssertJSONPath(%q): %v", path, err)
+		return
+	}
+	if !jsonEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("AssertJSONPath(%q) = %s, want %s", path, gotJSON, wantJSON)
+	}
+}
+
+// lookupPath 在通用 JSON 结构中按路径查找
+func lookupPath(v any, path string) (any, error) {
+	tokens, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	cur := v
+	walked := ""
+	for _, tok := range tokens {
+		if idx, ok := tok.(int); ok {
+			arr, isArr := cur.([]any)
+			if !isArr {
+				return nil, fmt.Errorf("%s: not an array", displayPath(walked))
+			}
+			if idx < 0 || idx >= len(arr) {
+				return nil, fmt.Errorf("%s: index %d out of range (len %d)", displayPath(walked), idx, len(arr))
+			}
+			cur = arr[idx]
+			walked += fmt.Sprintf("[%d]", idx)
+			continue
+		}
+		key := tok.(string)
+		obj, isObj := cur.(map[string]any)
+		if !isObj {
+			return nil, fmt.Errorf("%s: not an object", displayPath(walked))
+		}
+		next, exists := obj[key]
+		if !exists {
+			return nil, fmt.Errorf("%s: field %q not found", displayPath(walked), key)
+		}
+		cur = next
+		if walked != "" {
+			walked += "."
+		}
+		walked += key
+	}
+	return cur, nil
+}
+
+// splitPath 将路径拆分为字段名（string）与下标（int）
+func splitPath(path string) ([]any, error) {
+	var tokens []any
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			open := strings.IndexByte(part, '[')
+			if open < 0 {
+				tokens = append(tokens, part)
+				break
+			}
+			if open > 0 {
+				tokens = append(tokens, part[:open])
+			}
+			end := strings.IndexByte(part[open:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed '['", path)
+			}
+			idx, err := strconv.Atoi(part[open+1 : open+end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", path, err)
+			}
+			tokens = append(tokens, idx)
+			part = part[open+end+1:]
+		}
+	}
+	return tokens, nil
+}
+
+// displayPath 用于错误提示的路径显示
+func displayPath(p string) string {
+	if p == "" {
+		return "$"
+	}
+	return p
+}
+
+// jsonEqual 以 JSON 语义比较两个值
+func jsonEqual(a, b any) bool {
+	na, errA := normalizeJSON(a)
+	nb, errB := normalizeJSON(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+// normalizeJSON 序列化后重新解析，消除 Go 类型差异
+func normalizeJSON(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out any
+	err = dec.Decode(&out)
+	return out, err
+}
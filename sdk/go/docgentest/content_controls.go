@@ -0,0 +1,35 @@
+package docgentest
+
+import (
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// contentControlParagraphs 模拟填充内容控件：每个控件生成一段 "[tag] value"，按标记排序；
+// 下拉列表与日期输出其值，复选框输出 ☒ / ☐。禁用 docgen.FeatureContentControls 时模拟旧版服务忽略该字段
+func (s *Server) contentControlParagraphs(controls map[string]any) []string {
+	if s.disabledFeatures[docgen.FeatureContentControls] {
+		return nil
+	}
+	lines := make([]string, 0, len(controls))
+	for _, tag := range sortedKeys(controls) {
+		lines = append(lines, "["+tag+"] "+contentControlText(controls[tag]))
+	}
+	return lines
+}
+
+// contentControlText 控件值的显示文本
+func contentControlText(v any) string {
+	if m, ok := v.(map[string]any); ok && len(m) == 1 {
+		switch {
+		case m[docgen.DropdownMarker] != nil:
+			return formatValue(m[docgen.DropdownMarker])
+		case m[docgen.DateControlMarker] != nil:
+			return formatValue(m[docgen.DateControlMarker])
+		case m[docgen.CheckboxMarker] == true:
+			return "☒"
+		case m[docgen.CheckboxMarker] == false:
+			return "☐"
+		}
+	}
+	return formatValue(v)
+}
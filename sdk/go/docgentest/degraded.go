@@ -0,0 +1,47 @@
+package docgentest
+
+import (
+	"net/http"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// WithLoadShedding 模拟负载过高的服务端：端点的生成请求返回 503 OVERLOADED，
+// 设置 simpleRender 的请求（且未禁用 docgen.FeatureSimpleRender）仍正常生成，用于测试 docgen.WithDegradedMode
+//
+// endpoint: 接口路径（如 EndpointWord），AnyEndpoint 表示所有生成端点
+func WithLoadShedding(endpoint string) ServerOption {
+	return WithLoadSheddingResponse(endpoint, ErrorResponse(http.StatusServiceUnavailable, docgen.CodeOverloaded, "server overloaded, try again later or use simpleRender"))
+}
+
+// WithLoadSheddingResponse 与 WithLoadShedding 相同，但完整渲染的生成请求收到 resp，
+// 用于模拟其他拒绝方式（如没有错误码的 503、429 限流），验证只有容量不足才触发简化渲染
+func WithLoadSheddingResponse(endpoint string, resp Response) ServerOption {
+	return func(s *Server) {
+		if s.loadShedding == nil {
+			s.loadShedding = make(map[string]Response)
+		}
+		s.loadShedding[endpoint] = resp
+	}
+}
+
+// shedLoad 端点处于负载削减且请求不是简化渲染时写入负载削减响应并返回 true
+func (s *Server) shedLoad(w http.ResponseWriter, r *http.Request, req *CapturedRequest) bool {
+	resp, ok := s.loadShedding[r.URL.Path]
+	if !ok {
+		resp, ok = s.loadShedding[AnyEndpoint]
+	}
+	if !ok || s.simpleRender(req) {
+		return false
+	}
+	resp.write(w, r)
+	return true
+}
+
+// simpleRender 请求是否要求简化渲染（跳过图片），禁用 docgen.FeatureSimpleRender 时模拟旧版服务忽略该字段
+func (s *Server) simpleRender(req *CapturedRequest) bool {
+	var body struct {
+		SimpleRender bool `json:"simpleRender"`
+	}
+	return !s.disabledFeatures[docgen.FeatureSimpleRender] && decodeBody(req.Body, &body) == nil && body.SimpleRender
+}
@@ -0,0 +1,82 @@
+package docgentest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// EndpointTemplateDependencies 模板引用关系查询接口路径
+const EndpointTemplateDependencies = "/api/v1/template/dependencies"
+
+// WithDeleteProtection 模拟拒绝删除仍被引用模板的服务端：删除被其他模板 include 的模板时返回 409 TEMPLATE_IN_USE
+func WithDeleteProtection() ServerOption {
+	return func(s *Server) {
+		s.deleteProtection = true
+	}
+}
+
+// SetTemplateIncludes 设置模板 include 的子模板，供 GetTemplateDependencies 返回；不传 includes 时清除
+func (s *Server) SetTemplateIncludes(name string, includes ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.includes == nil {
+		s.includes = make(map[string][]string)
+	}
+	if len(includes) == 0 {
+		delete(s.includes, name)
+		return
+	}
+	s.includes[name] = append([]string(nil), includes...)
+}
+
+// dependentsLocked 返回 include 了 name 的模板（已排序），调用方需持有 s.mu
+func (s *Server) dependentsLocked(name string) []string {
+	dependents := []string{}
+	for parent, includes := range s.includes {
+		for _, include := range includes {
+			if include == name {
+				dependents = append(dependents, parent)
+				break
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// handleDependencies 返回模板的引用关系
+func (s *Server) handleDependencies(w http.ResponseWriter, r *http.Request) {
+	name := templateNameFromRequest(r, EndpointTemplateDependencies)
+	s.mu.Lock()
+	_, exists := s.templates[name]
+	graph := docgen.DependencyGraph{
+		TemplateName: name,
+		Includes:     append([]string{}, s.includes[name]...),
+		Dependents:   s.dependentsLocked(name),
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		writeError(w, http.StatusUnprocessableEntity, docgen.CodeTemplateNotFound, "Template not found: "+name)
+		return
+	}
+	writeJSON(w, http.StatusOK, graph)
+}
+
+// refuseDeleteInUse 启用 WithDeleteProtection 且模板仍被引用时写入 409 并返回 true
+func (s *Server) refuseDeleteInUse(w http.ResponseWriter, name string) bool {
+	if !s.deleteProtection {
+		return false
+	}
+	s.mu.Lock()
+	dependents := s.dependentsLocked(name)
+	s.mu.Unlock()
+	if len(dependents) == 0 {
+		return false
+	}
+	writeError(w, http.StatusConflict, docgen.CodeTemplateInUse, "Template is included by "+strings.Join(dependents, ", "))
+	return true
+}
@@ -0,0 +1,34 @@
+package docgentest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// WithFieldDecryption 模拟服务端解密加密字段：生成类请求在渲染前使用 keys 解密 EncryptedField
+//
+// 捕获的请求（Requests、LastRequest）保留原始密文，可用于断言明文未被发送；
+// 无法解密时返回 400 FIELD_DECRYPTION_FAILED。keys 通常为 Keyring.Key，
+// 在测试中轮换密钥后旧密钥加密的字段仍可解密
+func WithFieldDecryption(keys docgen.KeyFunc) ServerOption {
+	return func(s *Server) {
+		s.fieldKeys = keys
+	}
+}
+
+// decryptRequest 返回解密后的请求副本，失败时写入错误响应并返回 nil
+func (s *Server) decryptRequest(w http.ResponseWriter, r *http.Request, req *CapturedRequest) *CapturedRequest {
+	if s.fieldKeys == nil || len(req.Body) == 0 || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return req
+	}
+	plain, err := docgen.DecryptFields(req.Body, s.fieldKeys)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeFieldDecryptionFailed, err.Error())
+		return nil
+	}
+	decrypted := *req
+	decrypted.Body = plain
+	return &decrypted
+}
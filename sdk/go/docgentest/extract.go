@@ -0,0 +1,277 @@
+package docgentest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ExtractDocxText 提取 .docx 正文文本
+//
+// 每个段落占一行，w:tab 转换为制表符，w:br 转换为换行；
+// 不包含页眉、页脚与批注，结果与压缩包元数据及生成时间无关
+func ExtractDocxText(doc []byte) (string, error) {
+	zr, err := openZip(doc)
+	if err != nil {
+		return "", err
+	}
+	data, err := readZipFile(zr, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("parse word/document.xml: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteByte('\t')
+			case "br", "cr":
+				sb.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				sb.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// ExtractXlsxCells 提取 .xlsx 各工作表的单元格文本
+//
+// 返回以工作表名称为键的二维数组，行列位置与单元格引用（如 B3）一致，
+// 缺失的单元格为空字符串；共享字符串与内联字符串均会被解析，数值保持原始文本
+func ExtractXlsxCells(doc []byte) (map[string][][]string, error) {
+	zr, err := openZip(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := unmarshalZipFile(zr, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := unmarshalZipFile(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, r := range rels.Relationships {
+		targets[r.ID] = resolvePart("xl", r.Target)
+	}
+
+	var shared []string
+	if findZipFile(zr, "xl/sharedStrings.xml") != nil {
+		var sst struct {
+			Items []richText `xml:"si"`
+		}
+		if err := unmarshalZipFile(zr, "xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+		shared = make([]string, len(sst.Items))
+		for i, si := range sst.Items {
+			shared[i] = si.String()
+		}
+	}
+
+	result := make(map[string][][]string, len(workbook.Sheets))
+	for _, s := range workbook.Sheets {
+		target, ok := targets[s.RID]
+		if !ok {
+			return nil, fmt.Errorf("sheet %q: relationship %q not found", s.Name, s.RID)
+		}
+		rows, err := readSheet(zr, target, shared)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %q: %w", s.Name, err)
+		}
+		result[s.Name] = rows
+	}
+	return result, nil
+}
+
+// richText 共享字符串或内联字符串（可能由多个格式片段组成）
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String 拼接全部文本片段
+func (r richText) String() string {
+	if len(r.Runs) == 0 {
+		return r.T
+	}
+	var sb strings.Builder
+	sb.WriteString(r.T)
+	for _, run := range r.Runs {
+		sb.WriteString(run.T)
+	}
+	return sb.String()
+}
+
+// readSheet 读取单个工作表的单元格
+func readSheet(zr *zip.Reader, name string, shared []string) ([][]string, error) {
+	var ws struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline richText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := unmarshalZipFile(zr, name, &ws); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range ws.Rows {
+		r := len(rows)
+		if row.R > 0 {
+			r = row.R - 1
+		}
+		for len(rows) <= r {
+			rows = append(rows, nil)
+		}
+		for j, c := range row.Cells {
+			col := j
+			if c.Ref != "" {
+				parsed, err := columnIndex(c.Ref)
+				if err != nil {
+					return nil, err
+				}
+				col = parsed
+			}
+			text := c.Value
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(c.Value)
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("cell %s: invalid shared string index %q", c.Ref, c.Value)
+				}
+				text = shared[idx]
+			case "inlineStr":
+				text = c.Inline.String()
+			}
+			for len(rows[r]) <= col {
+				rows[r] = append(rows[r], "")
+			}
+			rows[r][col] = text
+		}
+	}
+	return rows, nil
+}
+
+// columnIndex 将单元格引用（如 "AB12"）转换为从 0 开始的列序号
+func columnIndex(ref string) (int, error) {
+	n := 0
+	i := 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		n = n*26 + int(ref[i]-'A'+1)
+	}
+	if i == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return n - 1, nil
+}
+
+// resolvePart 将关系中的 Target 解析为压缩包内的条目名
+func resolvePart(base, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(base, target)
+}
+
+// openZip 打开 OOXML 压缩包
+func openZip(doc []byte) (*zip.Reader, error) {
+	zr, err := zip.NewReader(bytes.NewReader(doc), int64(len(doc)))
+	if err != nil {
+		return nil, fmt.Errorf("not an OOXML package: %w", err)
+	}
+	return zr, nil
+}
+
+// findZipFile 按名称查找压缩包条目，不存在时返回 nil
+func findZipFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// readZipFile 读取压缩包条目内容
+func readZipFile(zr *zip.Reader, name string) ([]byte, error) {
+	f := findZipFile(zr, name)
+	if f == nil {
+		return nil, fmt.Errorf("missing part %s", name)
+	}
+	return readEntry(f)
+}
+
+// readEntry 读取单个压缩包条目
+func readEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", f.Name, err)
+	}
+	return data, nil
+}
+
+// unmarshalZipFile 读取并解析压缩包内的 XML 条目
+func unmarshalZipFile(zr *zip.Reader, name string, v any) error {
+	data, err := readZipFile(zr, name)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	return nil
+}
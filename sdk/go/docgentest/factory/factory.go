@@ -0,0 +1,211 @@
+// Package factory 为测试生成逼真且可复现的假数据
+//
+// 按模板结构（docgen.TemplateSchema）为每个占位符生成类型合适的值：中文姓名、手机号、地址、日期、金额、
+// 嵌套列表等。相同的种子总是生成相同的数据，便于与 golden 文件比较：
+//
+//	srv := docgentest.NewServer()
+//	srv.AddTemplate("contract.docx", docgentest.MinimalDocx("合同"))
+//	srv.SetTemplateSchema(schema)
+//	client := docgen.NewClient(srv.URL)
+//	factory.New(42).AssertGolden(t, client, "contract.docx", "testdata/contract.golden", docgentest.GoldenOptions{})
+//
+// 按字段名模式注册生成器即可扩展：
+//
+//	factory.Register("*合同编号*", func(r *rand.Rand, v docgen.TemplateVariable) any {
+//	    return fmt.Sprintf("HT-%06d", r.Intn(1000000))
+//	})
+package factory
+
+import (
+	"fmt"
+	"math/rand"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// DefaultSeed 包级函数 FakeData、FakeRows 使用的种子
+const DefaultSeed = 1
+
+// defaultListRows Factory.ListRows 的默认值
+const defaultListRows = 3
+
+// Generator 为一个字段生成值，v 为字段定义（Name 与 Type），r 为 Factory 的随机源
+type Generator func(r *rand.Rand, v docgen.TemplateVariable) any
+
+// rule 按字段名模式注册的生成器
+type rule struct {
+	pattern string
+	gen     Generator
+}
+
+var (
+	// registryMu 保护 registry
+	registryMu sync.RWMutex
+	// registry 通过 Register 注册的全局生成器
+	registry []rule
+)
+
+// Register 注册全局生成器，对之后调用的所有 Factory 生效
+//
+// pattern 为 path.Match 风格的通配符（如 "*phone*"、"合同编号"），匹配字段名时不区分大小写；
+// 后注册的优先，Factory.Register 注册的生成器优先于全局生成器。pattern 语法错误时 panic
+func Register(pattern string, gen Generator) {
+	mustValidPattern(pattern)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, rule{pattern: strings.ToLower(pattern), gen: gen})
+}
+
+// Factory 假数据生成器，不可并发使用
+type Factory struct {
+	// ListRows 每个列表区域生成的行数，默认 3
+	ListRows int
+
+	rng   *rand.Rand
+	rules []rule
+}
+
+// New 创建以 seed 为种子的 Factory，相同种子与相同调用顺序总是生成相同的数据
+func New(seed int64) *Factory {
+	return &Factory{ListRows: defaultListRows, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Register 为此 Factory 注册生成器，规则与包级 Register 相同，优先于全局生成器
+func (f *Factory) Register(pattern string, gen Generator) *Factory {
+	mustValidPattern(pattern)
+	f.rules = append(f.rules, rule{pattern: strings.ToLower(pattern), gen: gen})
+	return f
+}
+
+// FakeData 为模板结构中的每个占位符生成值：单值占位符按名称与类型生成，
+// 列表区域生成 ListRows 行 []map[string]any
+func (f *Factory) FakeData(schema *docgen.TemplateSchema) map[string]any {
+	data := make(map[string]any, len(schema.Variables)+len(schema.Lists))
+	for _, v := range schema.Variables {
+		data[v.Name] = f.Value(v)
+	}
+	for _, l := range schema.Lists {
+		data[l.Name] = f.FakeRecords(l.Fields, f.ListRows)
+	}
+	return data
+}
+
+// FakeRecords 按字段定义生成 n 行记录
+func (f *Factory) FakeRecords(fields []docgen.TemplateVariable, n int) []map[string]any {
+	rows := make([]map[string]any, n)
+	for i := range rows {
+		row := make(map[string]any, len(fields))
+		for _, field := range fields {
+			row[field.Name] = f.Value(field)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// FakeRows 生成 n 行表格数据（如 ExcelGenRequest.Data），每列的值按表头名称生成
+func (f *Factory) FakeRows(headers []string, n int) [][]any {
+	rows := make([][]any, n)
+	for i := range rows {
+		row := make([]any, len(headers))
+		for j, h := range headers {
+			row[j] = f.Value(docgen.TemplateVariable{Name: h})
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// Value 为单个字段生成值
+//
+// 依次使用：此 Factory 注册的生成器、全局注册的生成器、字段类型（number、integer、boolean、date、image）、
+// 内置的字段名规则（姓名、电话、邮箱、地址、公司、日期、金额、数量、编号等，中英文均可），最后生成一段中文文本
+func (f *Factory) Value(v docgen.TemplateVariable) any {
+	name := strings.ToLower(v.Name)
+	if gen := matchRule(f.rules, name); gen != nil {
+		return gen(f.rng, v)
+	}
+	registryMu.RLock()
+	gen := matchRule(registry, name)
+	registryMu.RUnlock()
+	if gen != nil {
+		return gen(f.rng, v)
+	}
+	if gen := typeGenerators[strings.ToLower(v.Type)]; gen != nil {
+		return gen(f.rng, v)
+	}
+	if gen := matchRule(builtinRules, name); gen != nil {
+		return gen(f.rng, v)
+	}
+	return fakeText(f.rng, v)
+}
+
+// Render 获取模板结构，以生成的数据渲染模板，返回文档与使用的数据；任何一步失败时 t.Fatal
+//
+// .xlsx / .xlsm 模板使用 FillExcelTemplate（列表区域作为 listData），其他模板使用 GenerateWord
+func (f *Factory) Render(t testing.TB, c *docgen.Client, templateName string) ([]byte, map[string]any) {
+	t.Helper()
+	schema, err := c.GetTemplateVariables(templateName)
+	if err != nil {
+		t.Fatalf("factory: get variables of %s: %v", templateName, err)
+	}
+	data := f.FakeData(schema)
+
+	var doc []byte
+	if ext := strings.ToLower(path.Ext(templateName)); ext == ".xlsx" || ext == ".xlsm" {
+		single := make(map[string]any, len(schema.Variables))
+		for _, v := range schema.Variables {
+			single[v.Name] = data[v.Name]
+		}
+		lists := make(map[string][]map[string]any, len(schema.Lists))
+		for _, l := range schema.Lists {
+			lists[l.Name] = data[l.Name].([]map[string]any)
+		}
+		doc, err = c.FillExcelTemplate(templateName, single, lists, "")
+	} else {
+		doc, err = c.GenerateWord(templateName, data, "")
+	}
+	if err != nil {
+		t.Fatalf("factory: render %s: %v", templateName, err)
+	}
+	return doc, data
+}
+
+// AssertGolden 以生成的数据渲染模板（见 Render），并断言结果与 golden 文件一致（见 docgentest.AssertDocEqualGolden）
+func (f *Factory) AssertGolden(t testing.TB, c *docgen.Client, templateName, goldenPath string, opts docgentest.GoldenOptions) {
+	t.Helper()
+	doc, _ := f.Render(t, c, templateName)
+	docgentest.AssertDocEqualGolden(t, doc, goldenPath, opts)
+}
+
+// FakeData 以 DefaultSeed 为模板结构生成数据，见 Factory.FakeData
+func FakeData(schema *docgen.TemplateSchema) map[string]any {
+	return New(DefaultSeed).FakeData(schema)
+}
+
+// FakeRows 以 DefaultSeed 生成 n 行表格数据，见 Factory.FakeRows
+func FakeRows(headers []string, n int) [][]any {
+	return New(DefaultSeed).FakeRows(headers, n)
+}
+
+// matchRule 返回最后注册的、模式匹配 name 的生成器
+func matchRule(rules []rule, name string) Generator {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if ok, _ := path.Match(rules[i].pattern, name); ok {
+			return rules[i].gen
+		}
+	}
+	return nil
+}
+
+// mustValidPattern 检查通配符语法
+func mustValidPattern(pattern string) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic(fmt.Sprintf("factory: invalid pattern %q: %v", pattern, err))
+	}
+}
@@ -0,0 +1,126 @@
+package factory
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// baseDate 生成日期的起点，日期在此后两年内，与运行时间无关
+var baseDate = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	surnames    = []string{"王", "李", "张", "刘", "陈", "杨", "黄", "赵", "吴", "周", "徐", "孙", "马", "朱", "胡", "郭", "何", "林", "罗", "高"}
+	givenChars  = []string{"伟", "芳", "娜", "敏", "静", "磊", "强", "洋", "艳", "勇", "军", "杰", "娟", "涛", "明", "超", "秀", "霞", "平", "刚", "桂", "英", "华", "玉", "婷"}
+	pinyinNames = []string{"zhangwei", "wangfang", "liuyang", "chenjing", "yangmin", "zhaolei", "huangtao", "zhoujie", "wuxia", "linhua"}
+	cities      = []string{"北京市朝阳区", "上海市浦东新区", "广州市天河区", "深圳市南山区", "杭州市西湖区", "成都市武侯区", "南京市鼓楼区", "武汉市洪山区"}
+	streets     = []string{"建国路", "世纪大道", "天河路", "科技园路", "文三路", "人民南路", "中山路", "珞喻路"}
+	companyWord = []string{"华信", "远景", "启明", "恒通", "嘉禾", "瑞丰", "博远", "同创"}
+	companyKind = []string{"科技", "贸易", "咨询", "信息技术", "实业"}
+	textWords   = []string{"项目", "服务", "季度", "采购", "合作", "交付", "说明", "方案", "年度", "计划", "验收", "技术支持"}
+)
+
+// typeGenerators 按 TemplateVariable.Type 生成值，text 与空类型不在其中，交由字段名规则处理
+var typeGenerators = map[string]Generator{
+	"number":  fakeAmount,
+	"integer": fakeQuantity,
+	"boolean": fakeBool,
+	"date":    fakeDate,
+	"image":   fakeImage,
+}
+
+// builtinRules 内置的字段名规则，后面的优先（如 companyName 按公司生成，phoneNumber 按手机号生成）
+var builtinRules = []rule{
+	{"*name*", fakeName}, {"*姓名*", fakeName}, {"*联系人*", fakeName},
+	{"*id", fakeCode}, {"*no", fakeCode}, {"*number*", fakeCode}, {"*code*", fakeCode}, {"*编号*", fakeCode}, {"*单号*", fakeCode},
+	{"*date*", fakeDate}, {"*time*", fakeDate}, {"*day*", fakeDate}, {"*日期*", fakeDate}, {"*时间*", fakeDate},
+	{"*amount*", fakeAmount}, {"*price*", fakeAmount}, {"*total*", fakeAmount}, {"*fee*", fakeAmount}, {"*cost*", fakeAmount},
+	{"*金额*", fakeAmount}, {"*价格*", fakeAmount}, {"*单价*", fakeAmount}, {"*合计*", fakeAmount}, {"*费用*", fakeAmount},
+	{"*qty*", fakeQuantity}, {"*quantity*", fakeQuantity}, {"*count*", fakeQuantity}, {"*数量*", fakeQuantity},
+	{"is*", fakeBool}, {"has*", fakeBool}, {"*enabled", fakeBool}, {"是否*", fakeBool},
+	{"*company*", fakeCompany}, {"*org*", fakeCompany}, {"*公司*", fakeCompany}, {"*单位*", fakeCompany},
+	{"*address*", fakeAddress}, {"*addr*", fakeAddress}, {"*地址*", fakeAddress},
+	{"*email*", fakeEmail}, {"*mail*", fakeEmail}, {"*邮箱*", fakeEmail},
+	{"*phone*", fakePhone}, {"*mobile*", fakePhone}, {"*tel*", fakePhone}, {"*电话*", fakePhone}, {"*手机*", fakePhone},
+	{"*image*", fakeImage}, {"*photo*", fakeImage}, {"*logo*", fakeImage}, {"*图片*", fakeImage},
+}
+
+// pick 从候选项中随机选取一个
+func pick(r *rand.Rand, items []string) string {
+	return items[r.Intn(len(items))]
+}
+
+// fakeName 中文姓名（两到三个字）
+func fakeName(r *rand.Rand, _ docgen.TemplateVariable) any {
+	name := pick(r, surnames) + pick(r, givenChars)
+	if r.Intn(2) == 0 {
+		name += pick(r, givenChars)
+	}
+	return name
+}
+
+// fakePhone 手机号
+func fakePhone(r *rand.Rand, _ docgen.TemplateVariable) any {
+	return fmt.Sprintf("1%d%09d", []int{3, 5, 7, 8, 9}[r.Intn(5)], r.Intn(1000000000))
+}
+
+// fakeEmail 邮箱地址，使用保留的 example.com 域名
+func fakeEmail(r *rand.Rand, _ docgen.TemplateVariable) any {
+	return fmt.Sprintf("%s%d@example.com", pick(r, pinyinNames), r.Intn(100))
+}
+
+// fakeAddress 详细地址
+func fakeAddress(r *rand.Rand, _ docgen.TemplateVariable) any {
+	return fmt.Sprintf("%s%s%d号", pick(r, cities), pick(r, streets), r.Intn(300)+1)
+}
+
+// fakeCompany 公司名称
+func fakeCompany(r *rand.Rand, _ docgen.TemplateVariable) any {
+	city := pick(r, cities)
+	return fmt.Sprintf("%s%s%s有限公司", city[:strings.Index(city, "市")], pick(r, companyWord), pick(r, companyKind))
+}
+
+// fakeDate 日期，格式 2006-01-02
+func fakeDate(r *rand.Rand, _ docgen.TemplateVariable) any {
+	return baseDate.AddDate(0, 0, r.Intn(730)).Format("2006-01-02")
+}
+
+// fakeAmount 金额，保留两位小数
+func fakeAmount(r *rand.Rand, _ docgen.TemplateVariable) any {
+	return math.Round(r.Float64()*10000000) / 100
+}
+
+// fakeQuantity 数量（1 到 100）
+func fakeQuantity(r *rand.Rand, _ docgen.TemplateVariable) any {
+	return r.Intn(100) + 1
+}
+
+// fakeBool 布尔值
+func fakeBool(r *rand.Rand, _ docgen.TemplateVariable) any {
+	return r.Intn(2) == 1
+}
+
+// fakeCode 业务编号，如 NO2024000123
+func fakeCode(r *rand.Rand, _ docgen.TemplateVariable) any {
+	return fmt.Sprintf("NO%d%06d", 2024+r.Intn(2), r.Intn(1000000))
+}
+
+// fakeImage 图片地址，使用保留的 example.com 域名，模拟服务器按获取成功处理
+func fakeImage(r *rand.Rand, v docgen.TemplateVariable) any {
+	return docgen.ImageURL(fmt.Sprintf("https://images.example.com/%s-%d.png", url.PathEscape(v.Name), r.Intn(1000)), docgen.ImageFetchOptions{})
+}
+
+// fakeText 一段中文文本
+func fakeText(r *rand.Rand, _ docgen.TemplateVariable) any {
+	n := r.Intn(3) + 2
+	words := make([]string, n)
+	for i := range words {
+		words[i] = pick(r, textWords)
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,199 @@
+package docgentest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// UpdateGoldenEnv 设置为 "1" 或 "true" 时 AssertDocEqualGolden 与 AssertJSONEqualGolden 重新生成 golden 文件：
+//
+//	DOCGEN_UPDATE_GOLDEN=1 go test ./...
+//
+// 不使用命令行参数，避免与下游测试自行定义的 -update 参数冲突；需要 -update 时在测试中将其值传给 GoldenOptions.Update
+const UpdateGoldenEnv = "DOCGEN_UPDATE_GOLDEN"
+
+// GoldenOptions golden 文件比较选项
+type GoldenOptions struct {
+	// Update 为 true 时用当前内容覆盖 golden 文件（等同于设置 UpdateGoldenEnv 环境变量）
+	Update bool
+	// IncludeParts 为 true 时额外比较全部 XML 部件（经 docgen.NormalizeDocument 规范化，并去除 core.xml / app.xml 中的时间戳）
+	IncludeParts bool
+	// IgnoreParts IncludeParts 模式下忽略的部件名称，如 "docProps/app.xml"
+	IgnoreParts []string
+}
+
+// volatileElements core.xml / app.xml 中随生成时间变化的元素
+var volatileElements = regexp.MustCompile(`(?s)<(dcterms:created|dcterms:modified|cp:lastPrinted|TotalTime)\b[^>]*>.*?</(dcterms:created|dcterms:modified|cp:lastPrinted|TotalTime)>`)
+
+// AssertDocEqualGolden 断言文档规范化内容与 golden 文件一致
+//
+// 比较的是提取后的文本（.docx 为正文段落，.xlsx 为各工作表单元格），
+// 与压缩包元数据、条目顺序及生成时间无关；golden 文件不存在、GoldenOptions.Update 为 true
+// 或设置了 UpdateGoldenEnv 环境变量时写入当前内容
+func AssertDocEqualGolden(t testing.TB, doc []byte, goldenPath string, opts GoldenOptions) {
+	t.Helper()
+	got, err := NormalizeDocument(doc, opts)
+	if err != nil {
+		t.Fatalf("AssertDocEqualGolden(%s): %v", goldenPath, err)
+		return
+	}
+	assertGolden(t, "AssertDocEqualGolden", got, goldenPath, opts)
+}
+
+// AssertJSONEqualGolden 断言 JSON（如 CapturedRequest.Body 请求体）与 golden 文件一致，用于固定请求的线上格式：
+//
+//	docgentest.AssertJSONEqualGolden(t, srv.LastRequest().Body, "testdata/wire/order.json", docgentest.GoldenOptions{})
+//
+// 比较前按两个空格缩进格式化，键的顺序保持不变；golden 文件的写入规则与 AssertDocEqualGolden 相同，
+// IncludeParts 与 IgnoreParts 不适用
+func AssertJSONEqualGolden(t testing.TB, body []byte, goldenPath string, opts GoldenOptions) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err != nil {
+		t.Fatalf("AssertJSONEqualGolden(%s): body is not JSON: %v\n%s", goldenPath, err, body)
+		return
+	}
+	buf.WriteByte('\n')
+	assertGolden(t, "AssertJSONEqualGolden", buf.String(), goldenPath, opts)
+}
+
+// assertGolden 比较 got 与 golden 文件，需要时写入 golden 文件；name 为调用方函数名，用于错误信息
+func assertGolden(t testing.TB, name, got, goldenPath string, opts GoldenOptions) {
+	t.Helper()
+	want, err := os.ReadFile(goldenPath)
+	if opts.Update || updateRequested() || os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("%s(%s): %v", name, goldenPath, err)
+			return
+		}
+		if err := os.WriteFile(goldenPath, []byte(got), 0o644); err != nil {
+			t.Fatalf("%s(%s): %v", name, goldenPath, err)
+			return
+		}
+		t.Logf("%s: wrote %s", name, goldenPath)
+		return
+	}
+	if err != nil {
+		t.Fatalf("%s(%s): %v", name, goldenPath, err)
+		return
+	}
+
+	if got != string(want) {
+		t.Errorf("%s(%s): content differs (set "+UpdateGoldenEnv+"=1 to accept)\n%s",
+			name, goldenPath, firstDiff(string(want), got))
+	}
+}
+
+// NormalizeDocument 将 .docx / .xlsx 转换为稳定的文本表示，AssertDocEqualGolden 使用该结果比较
+func NormalizeDocument(doc []byte, opts GoldenOptions) (string, error) {
+	zr, err := openZip(doc)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	switch {
+	case findZipFile(zr, "word/document.xml") != nil:
+		text, err := ExtractDocxText(doc)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("# word/document.xml\n")
+		sb.WriteString(text)
+		sb.WriteByte('\n')
+	case findZipFile(zr, "xl/workbook.xml") != nil:
+		sheets, err := ExtractXlsxCells(doc)
+		if err != nil {
+			return "", err
+		}
+		names := make([]string, 0, len(sheets))
+		for name := range sheets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&sb, "# sheet %s\n", name)
+			for _, row := range sheets[name] {
+				cells := make([]string, len(row))
+				for i, c := range row {
+					cells[i] = escapeCell(c)
+				}
+				sb.WriteString(strings.Join(cells, "\t"))
+				sb.WriteByte('\n')
+			}
+		}
+	default:
+		return "", fmt.Errorf("unsupported document: neither word/document.xml nor xl/workbook.xml found")
+	}
+
+	if !opts.IncludeParts {
+		return sb.String(), nil
+	}
+
+	// 与 docgen.NormalizeDocument 使用相同的规范化，rsid 等随保存变化的属性不影响比较
+	normalized, err := docgen.NormalizeDocument(doc)
+	if err != nil {
+		return "", err
+	}
+	if zr, err = openZip(normalized); err != nil {
+		return "", err
+	}
+	ignored := make(map[string]bool, len(opts.IgnoreParts))
+	for _, p := range opts.IgnoreParts {
+		ignored[p] = true
+	}
+	files := append(zr.File[:0:0], zr.File...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	for _, f := range files {
+		if ignored[f.Name] || !(strings.HasSuffix(f.Name, ".xml") || strings.HasSuffix(f.Name, ".rels")) {
+			continue
+		}
+		data, err := readEntry(f)
+		if err != nil {
+			return "", err
+		}
+		data = volatileElements.ReplaceAll(data, nil)
+		fmt.Fprintf(&sb, "# part %s\n%s\n", f.Name, bytes.TrimSpace(data))
+	}
+	return sb.String(), nil
+}
+
+// updateRequested 是否设置了 UpdateGoldenEnv 环境变量
+func updateRequested() bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv))
+	return update
+}
+
+// escapeCell 转义单元格中的制表符、换行与反斜杠，保证每行一条记录
+func escapeCell(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+// firstDiff 返回首个不同行的说明
+func firstDiff(want, got string) string {
+	wl := strings.Split(want, "\n")
+	gl := strings.Split(got, "\n")
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g || i >= len(wl) || i >= len(gl) {
+			return fmt.Sprintf("line %d:\n  want: %q\n  got:  %q", i+1, w, g)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package docgentest_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgentest"
+)
+
+// 下游测试常见的 -update 参数，docgentest 不能重复注册
+var update = flag.Bool("update", false, "update golden files")
+
+func TestAssertDocEqualGoldenUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.golden")
+	docgentest.AssertDocEqualGolden(t, docgentest.MinimalDocx("first"), path, docgentest.GoldenOptions{Update: *update})
+
+	t.Setenv(docgentest.UpdateGoldenEnv, "1")
+	docgentest.AssertDocEqualGolden(t, docgentest.MinimalDocx("second"), path, docgentest.GoldenOptions{})
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "second") {
+		t.Fatalf("golden not updated by %s:\n%s", docgentest.UpdateGoldenEnv, got)
+	}
+
+	t.Setenv(docgentest.UpdateGoldenEnv, "")
+	docgentest.AssertDocEqualGolden(t, docgentest.MinimalDocx("second"), path, docgentest.GoldenOptions{})
+}
+
+func TestAssertJSONEqualGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wire", "body.json")
+	docgentest.AssertJSONEqualGolden(t, []byte(`{"b":1,"a":[true]}`), path, docgentest.GoldenOptions{Update: *update})
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 格式化后保持键的顺序，以换行结尾
+	if want := "{\n  \"b\": 1,\n  \"a\": [\n    true\n  ]\n}\n"; string(got) != want {
+		t.Fatalf("golden = %q, want %q", got, want)
+	}
+	// 空白不同的相同 JSON 与 golden 一致
+	docgentest.AssertJSONEqualGolden(t, []byte("{\"b\": 1, \"a\": [ true ]}"), path, docgentest.GoldenOptions{})
+
+	docgentest.AssertJSONEqualGolden(t, []byte(`{"b":2}`), path, docgentest.GoldenOptions{Update: true})
+	if got, _ := os.ReadFile(path); !strings.Contains(string(got), `"b": 2`) {
+		t.Fatalf("golden not updated by GoldenOptions.Update:\n%s", got)
+	}
+}
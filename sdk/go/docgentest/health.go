@@ -0,0 +1,56 @@
+package docgentest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// 健康检查探针接口路径
+const (
+	EndpointHealthLiveness  = "/actuator/health/liveness"
+	EndpointHealthReadiness = "/actuator/health/readiness"
+)
+
+// WithoutHealthProbes 模拟不提供存活与就绪探针的旧版服务：探针接口返回 404，只有聚合健康检查可用
+func WithoutHealthProbes() ServerOption {
+	return func(s *Server) {
+		s.noHealthProbes = true
+	}
+}
+
+// SetReady 设置就绪状态，模拟服务启动后模板存储仍在预热：未就绪时就绪探针返回 503 OUT_OF_SERVICE，
+// 聚合健康检查与存活探针仍报告 UP（默认已就绪）
+func (s *Server) SetReady(ready bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notReady = !ready
+}
+
+// handleHealth 响应聚合健康检查与探针接口
+func (s *Server) handleHealth(w http.ResponseWriter, path string) {
+	if path == EndpointHealth {
+		writeJSON(w, http.StatusOK, map[string]any{"status": "UP"})
+		return
+	}
+	s.mu.Lock()
+	noProbes, notReady := s.noHealthProbes, s.notReady
+	s.mu.Unlock()
+	switch {
+	case noProbes || path != EndpointHealthLiveness && path != EndpointHealthReadiness:
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for GET "+path)
+	case path == EndpointHealthReadiness && notReady:
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{
+			"status":     "OUT_OF_SERVICE",
+			"components": map[string]any{"templateStore": map[string]any{"status": "OUT_OF_SERVICE"}},
+		})
+	default:
+		writeJSON(w, http.StatusOK, map[string]any{"status": "UP"})
+	}
+}
+
+// isHealthPath 判断路径是否属于健康检查接口
+func isHealthPath(path string) bool {
+	return path == EndpointHealth || strings.HasPrefix(path, EndpointHealth+"/")
+}
@@ -0,0 +1,360 @@
+package docgentest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// 异步任务接口路径
+const (
+	EndpointJobs     = "/api/v1/jobs"
+	EndpointJobWord  = "/api/v1/jobs/word"
+	EndpointJobExcel = "/api/v1/jobs/excel"
+)
+
+// mockJob 模拟服务器中的异步任务
+type mockJob struct {
+	job    docgen.Job
+	result []byte
+	events []docgen.JobEvent
+	// resultExpiresAt 由 SetResultExpiry 或延长保留期限设置的过期时间，零值表示按保留时间计算
+	resultExpiresAt time.Time
+	// resultDeleted 结果已通过 DeleteResult 删除，任务记录保留
+	resultDeleted bool
+}
+
+// WithManualJobs 提交的任务保持 QUEUED 状态，由测试通过 PublishJobEvent 推进
+//
+// 默认情况下任务在提交时立即完成
+func WithManualJobs() ServerOption {
+	return func(s *Server) {
+		s.manualJobs = true
+	}
+}
+
+// AddJob 添加任务，result 为任务成功后可下载的结果
+func (s *Server) AddJob(job docgen.Job, result []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now().UTC()
+	}
+	if _, exists := s.jobs[job.ID]; !exists {
+		s.jobOrder = append(s.jobOrder, job.ID)
+	}
+	s.jobs[job.ID] = &mockJob{job: job, result: result}
+	s.notifyJobsLocked()
+}
+
+// Job 获取任务当前状态
+func (s *Server) Job(id string) (docgen.Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return docgen.Job{}, false
+	}
+	return j.job, true
+}
+
+// PublishJobEvent 发布任务事件并相应更新任务状态，事件会推送给进度流订阅者
+//
+// 事件 ID 按序号分配（用于 Last-Event-ID 续传），未设置 Time 时使用当前时间；任务不存在时返回 false
+func (s *Server) PublishJobEvent(event docgen.JobEvent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.publishJobEventLocked(event)
+}
+
+// DropEventStreamAfter 每个进度流连接发送 n 个事件后主动断开，用于测试客户端重连（0 表示不断开）
+func (s *Server) DropEventStreamAfter(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropEventsAfter = n
+}
+
+// publishJobEventLocked 发布任务事件，调用方需持有锁
+func (s *Server) publishJobEventLocked(event docgen.JobEvent) bool {
+	j, ok := s.jobs[event.JobID]
+	if !ok {
+		return false
+	}
+	now := time.Now().UTC()
+	event.ID = strconv.Itoa(len(j.events) + 1)
+	if event.Time.IsZero() {
+		event.Time = now
+	}
+
+	switch event.Type {
+	case docgen.JobEventProgress:
+		if j.job.StartedAt == nil {
+			j.job.StartedAt = &now
+		}
+		j.job.State = docgen.JobRunning
+		if event.Progress != nil {
+			j.job.Progress = *event.Progress
+		}
+	case docgen.JobEventCompleted:
+		j.job.State = docgen.JobSucceeded
+		j.job.Progress.Done = j.job.Progress.Total
+		j.job.FinishedAt = &now
+	case docgen.JobEventFailed:
+		j.job.State = docgen.JobFailed
+		j.job.Error = event.Error
+		j.job.FinishedAt = &now
+	case docgen.JobEventCancelled:
+		j.job.State = docgen.JobCancelled
+		j.job.FinishedAt = &now
+	}
+	snapshot := j.job
+	event.Job = &snapshot
+
+	j.events = append(j.events, event)
+	s.notifyJobsLocked()
+	return true
+}
+
+// notifyJobsLocked 唤醒等待任务事件的进度流，调用方需持有锁
+func (s *Server) notifyJobsLocked() {
+	close(s.jobsChanged)
+	s.jobsChanged = make(chan struct{})
+}
+
+// handleJobs 异步任务接口
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request, req *CapturedRequest) {
+	path := r.URL.Path
+	switch {
+	case path == EndpointJobs && r.Method == http.MethodGet:
+		s.handleListJobs(w, r)
+		return
+	case path == EndpointJobWord && r.Method == http.MethodPost:
+		s.submitJob(w, req, docgen.JobTypeWordBatch, s.handleWordBatch)
+		return
+	case path == EndpointJobExcel && r.Method == http.MethodPost:
+		s.submitJob(w, req, docgen.JobTypeExcelFill, s.handleExcelFill)
+		return
+	}
+
+	id, action, _ := strings.Cut(strings.TrimPrefix(path, EndpointJobs+"/"), "/")
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	var job docgen.Job
+	if ok {
+		job = j.job
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, docgen.CodeJobNotFound, "Job not found: "+id)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, job)
+	case action == "" && r.Method == http.MethodDelete:
+		s.mu.Lock()
+		delete(s.jobs, id)
+		for i, jid := range s.jobOrder {
+			if jid == id {
+				s.jobOrder = append(s.jobOrder[:i], s.jobOrder[i+1:]...)
+				break
+			}
+		}
+		s.notifyJobsLocked()
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case action == "cancel" && r.Method == http.MethodPost:
+		if job.State.Terminal() {
+			writeError(w, http.StatusConflict, docgen.CodeJobAlreadyFinished, fmt.Sprintf("Job %s is already %s", id, job.State))
+			return
+		}
+		s.PublishJobEvent(docgen.JobEvent{Type: docgen.JobEventCancelled, JobID: id})
+		job, _ = s.Job(id)
+		writeJSON(w, http.StatusOK, job)
+	case action == "result" && r.Method == http.MethodGet:
+		if job.State != docgen.JobSucceeded {
+			writeError(w, http.StatusConflict, docgen.CodeJobNotFinished, fmt.Sprintf("Job %s is %s", id, job.State))
+			return
+		}
+		if !s.disabledFeatures[docgen.FeatureResultStore] {
+			if _, ok := s.checkResult(w, id); !ok {
+				return
+			}
+		}
+		s.mu.Lock()
+		result := j.result
+		s.mu.Unlock()
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id))
+		s.serveDownload(w, r, result, jobContentType(job))
+	case action == "events" && r.Method == http.MethodGet:
+		s.handleJobEvents(w, r, id)
+	case action == "pages" || strings.HasPrefix(action, "pages/"):
+		s.handlePages(w, r, id, job, action)
+	default:
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for "+r.Method+" "+path)
+	}
+}
+
+// submitJob 提交任务：使用同步生成逻辑渲染结果，请求无效时与同步接口一样直接返回错误
+func (s *Server) submitJob(w http.ResponseWriter, req *CapturedRequest, requestType string, render func(http.ResponseWriter, *CapturedRequest)) {
+	rec := httptest.NewRecorder()
+	render(rec, req)
+	if rec.Code != http.StatusOK {
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+		return
+	}
+
+	var body struct {
+		TemplateName string           `json:"templateName"`
+		DataList     []map[string]any `json:"dataList"`
+		Priority     docgen.Priority  `json:"priority"`
+	}
+	_ = decodeBody(req.Body, &body)
+	total := int64(len(body.DataList))
+	if total == 0 {
+		total = 1
+	}
+
+	s.mu.Lock()
+	s.jobSeq++
+	id := fmt.Sprintf("job-%d", s.jobSeq)
+	s.jobs[id] = &mockJob{
+		job: docgen.Job{
+			ID:           id,
+			State:        docgen.JobQueued,
+			RequestType:  requestType,
+			TemplateName: body.TemplateName,
+			Priority:     docgen.Priority(withDefault(string(body.Priority), string(docgen.PriorityNormal))),
+			CreatedAt:    time.Now().UTC(),
+			Progress:     docgen.JobProgress{Total: total},
+		},
+		result: rec.Body.Bytes(),
+	}
+	s.jobOrder = append(s.jobOrder, id)
+	if !s.manualJobs {
+		s.publishJobEventLocked(docgen.JobEvent{Type: docgen.JobEventProgress, JobID: id, Progress: &docgen.JobProgress{Done: total, Total: total}})
+		s.publishJobEventLocked(docgen.JobEvent{Type: docgen.JobEventCompleted, JobID: id})
+	}
+	job := s.jobs[id].job
+	s.notifyJobsLocked()
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleListJobs 任务列表，支持 state、template、createdAfter、createdBefore、limit 过滤
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	states := make(map[string]bool)
+	for _, st := range q["state"] {
+		states[st] = true
+	}
+	var after, before time.Time
+	for key, t := range map[string]*time.Time{"createdAfter": &after, "createdBefore": &before} {
+		if v := q.Get(key); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, key+": invalid time "+v)
+				return
+			}
+			*t = parsed
+		}
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	s.mu.Lock()
+	jobs := make([]docgen.Job, 0, len(s.jobOrder))
+	for _, id := range s.jobOrder {
+		job := s.jobs[id].job
+		switch {
+		case len(states) > 0 && !states[string(job.State)]:
+		case q.Get("template") != "" && job.TemplateName != q.Get("template"):
+		case !after.IsZero() && job.CreatedAt.Before(after):
+		case !before.IsZero() && job.CreatedAt.After(before):
+		default:
+			jobs = append(jobs, job)
+		}
+		if limit > 0 && len(jobs) == limit {
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "count": len(jobs), "jobs": jobs})
+}
+
+// eventLimitKey 进度流连接的事件数上限在请求 context 中的键，见 InterruptedEventStream
+type eventLimitKey struct{}
+
+// handleJobEvents 任务进度事件流（text/event-stream），支持 Last-Event-ID 续传
+func (s *Server) handleJobEvents(w http.ResponseWriter, r *http.Request, id string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, docgen.CodeInternalError, "streaming unsupported")
+		return
+	}
+	next, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 50\n\n")
+	flusher.Flush()
+
+	limit, interrupted := r.Context().Value(eventLimitKey{}).(int)
+	sent := 0
+	for {
+		s.mu.Lock()
+		j, exists := s.jobs[id]
+		var pending []docgen.JobEvent
+		if exists && next < len(j.events) {
+			pending = append(pending, j.events[next:]...)
+		}
+		changed := s.jobsChanged
+		dropAfter := s.dropEventsAfter
+		s.mu.Unlock()
+		if !exists {
+			return
+		}
+
+		for _, event := range pending {
+			if interrupted && sent >= limit {
+				// 半个事件：没有结尾的空行，客户端应丢弃
+				fmt.Fprintf(w, "id: %s\ndata: {\"type\":%q", event.ID, event.Type)
+				flusher.Flush()
+				return
+			}
+			data, _ := json.Marshal(event)
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			flusher.Flush()
+			next++
+			sent++
+			if event.Type.Terminal() {
+				return
+			}
+			if dropAfter > 0 && sent >= dropAfter {
+				return
+			}
+		}
+
+		if interrupted && sent >= limit {
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package docgentest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// EndpointLinks 签名下载链接接口路径
+const EndpointLinks = "/api/v1/links"
+
+// signedLink 模拟服务器签发的下载链接
+type signedLink struct {
+	id           string
+	resourceType string
+	resourceID   string
+	expiresAt    time.Time
+}
+
+// handleLinks 签发、撤销与通过签名链接下载
+func (s *Server) handleLinks(w http.ResponseWriter, r *http.Request, req *CapturedRequest) {
+	if r.URL.Path == EndpointLinks && r.Method == http.MethodPost {
+		s.createLink(w, req)
+		return
+	}
+
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, EndpointLinks+"/"), "/")
+	s.mu.Lock()
+	link, ok := s.links[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, docgen.CodeLinkNotFound, "Link not found: "+id)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodDelete:
+		s.mu.Lock()
+		delete(s.links, id)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case action == "download" && r.Method == http.MethodGet:
+		if time.Now().After(link.expiresAt) {
+			writeError(w, http.StatusGone, docgen.CodeLinkExpired, "Link expired: "+id)
+			return
+		}
+		s.serveLink(w, r, link)
+	default:
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// createLink 签发链接，资源不存在时返回与对应接口一致的错误
+func (s *Server) createLink(w http.ResponseWriter, req *CapturedRequest) {
+	var body struct {
+		ResourceType string `json:"resourceType"`
+		ResourceID   string `json:"resourceId"`
+		TTLSeconds   int64  `json:"ttlSeconds"`
+	}
+	if err := decodeBody(req.Body, &body); err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "malformed request body: "+err.Error())
+		return
+	}
+	if body.TTLSeconds <= 0 {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "ttlSeconds: must be positive")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch body.ResourceType {
+	case "job":
+		if _, ok := s.jobs[body.ResourceID]; !ok {
+			writeError(w, http.StatusNotFound, docgen.CodeJobNotFound, "Job not found: "+body.ResourceID)
+			return
+		}
+	case "template":
+		if _, ok := s.templates[body.ResourceID]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, docgen.CodeTemplateNotFound, "Template not found: "+body.ResourceID)
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, "unknown resourceType: "+body.ResourceType)
+		return
+	}
+
+	s.linkSeq++
+	link := &signedLink{
+		id:           fmt.Sprintf("link-%d", s.linkSeq),
+		resourceType: body.ResourceType,
+		resourceID:   body.ResourceID,
+		expiresAt:    time.Now().Add(time.Duration(body.TTLSeconds) * time.Second).UTC(),
+	}
+	s.links[link.id] = link
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":        link.id,
+		"url":       s.URL + EndpointLinks + "/" + link.id + "/download?sig=mock",
+		"expiresAt": link.expiresAt.Format(time.RFC3339Nano),
+	})
+}
+
+// serveLink 通过签名链接下载资源
+func (s *Server) serveLink(w http.ResponseWriter, r *http.Request, link *signedLink) {
+	s.mu.Lock()
+	var data []byte
+	found := false
+	contentType := contentTypeDocx
+	switch link.resourceType {
+	case "job":
+		if j, ok := s.jobs[link.resourceID]; ok && j.job.State == docgen.JobSucceeded {
+			data, found = j.result, true
+		}
+	case "template":
+		if t, ok := s.templates[link.resourceID]; ok {
+			data, found = t.data, true
+			contentType = contentTypeFor(link.resourceID)
+		}
+	}
+	s.mu.Unlock()
+
+	if !found {
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "Resource not available: "+link.resourceID)
+		return
+	}
+	s.serveDownload(w, r, data, contentType)
+}
@@ -0,0 +1,168 @@
+package docgentest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EndpointStorage 结果对象的路径前缀，WithResultRedirects 时由模拟服务器自身提供
+const EndpointStorage = "/storage/"
+
+// ObjectStore 模拟对象存储：按预签名 URL（查询参数 X-Signature）提供生成结果，用于测试跨源的 303 See Other
+//
+// 与常见对象存储一样，同时携带 Authorization 请求头与签名的请求返回 400，签名错误返回 403；
+// 对象响应不含内容摘要，摘要只在服务端的 303 响应中提供
+type ObjectStore struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	objects  map[string]storedObject
+	requests []*CapturedRequest
+	seq      int
+	// base Location 中的地址前缀，为空时使用相对路径（同源）
+	base string
+	// rejectCredentials 拒绝携带 Authorization 的请求
+	rejectCredentials bool
+}
+
+// storedObject 对象存储中的一个结果
+type storedObject struct {
+	data        []byte
+	contentType string
+	signature   string
+}
+
+// NewObjectStore 启动模拟对象存储，与 WithPresignedResults 一同使用，测试结束时需调用 Close
+func NewObjectStore() *ObjectStore {
+	o := newObjectStore()
+	o.rejectCredentials = true
+	o.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		o.serve(w, r, &CapturedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body})
+	}))
+	o.base = o.URL
+	return o
+}
+
+// newObjectStore 创建空的对象存储
+func newObjectStore() *ObjectStore {
+	return &ObjectStore{objects: make(map[string]storedObject)}
+}
+
+// Requests 返回对象存储收到的全部请求
+func (o *ObjectStore) Requests() []*CapturedRequest {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]*CapturedRequest(nil), o.requests...)
+}
+
+// put 保存结果并返回其预签名地址
+func (o *ObjectStore) put(data []byte, contentType string) string {
+	var sig [16]byte
+	_, _ = rand.Read(sig[:])
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.seq++
+	key := "result-" + strconv.Itoa(o.seq)
+	o.objects[key] = storedObject{data: data, contentType: contentType, signature: hex.EncodeToString(sig[:])}
+	return o.base + EndpointStorage + key + "?X-Signature=" + o.objects[key].signature
+}
+
+// serve 按预签名地址返回结果
+func (o *ObjectStore) serve(w http.ResponseWriter, r *http.Request, req *CapturedRequest) {
+	o.mu.Lock()
+	o.requests = append(o.requests, req)
+	obj, ok := o.objects[strings.TrimPrefix(r.URL.Path, EndpointStorage)]
+	o.mu.Unlock()
+
+	switch {
+	case r.Method != http.MethodGet:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	case o.rejectCredentials && r.Header.Get("Authorization") != "":
+		http.Error(w, "InvalidArgument: only one auth mechanism allowed", http.StatusBadRequest)
+	case !ok:
+		http.Error(w, "NoSuchKey", http.StatusNotFound)
+	case r.URL.Query().Get("X-Signature") != obj.signature:
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+	default:
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		_, _ = w.Write(obj.data)
+	}
+}
+
+// WithResultRedirects 生成端点将成功的结果保存在模拟服务器自身的 EndpointStorage 下，
+// 以 303 See Other（相对路径的 Location）代替直接返回文档，用于测试同源的重定向
+func WithResultRedirects() ServerOption {
+	return func(s *Server) {
+		s.resultStore = newObjectStore()
+	}
+}
+
+// WithPresignedResults 生成端点将成功的结果保存在 store 中，以 303 See Other 返回预签名地址，
+// 用于测试跨源的重定向：store.Requests() 可检查 SDK 是否去掉了 Authorization 等请求头
+//
+// 303 响应携带原响应的 X-Content-SHA256、Content-Disposition 与警告等响应头
+func WithPresignedResults(store *ObjectStore) ServerOption {
+	return func(s *Server) {
+		s.resultStore = store
+	}
+}
+
+// redirectRecorder 暂存生成端点的响应，成功时转存到对象存储
+type redirectRecorder struct {
+	w      http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header 返回暂存的响应头
+func (r *redirectRecorder) Header() http.Header {
+	return r.header
+}
+
+// WriteHeader 记录状态码
+func (r *redirectRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// Write 暂存响应体
+func (r *redirectRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// beginResultRedirect 启用 WithResultRedirects / WithPresignedResults 时返回暂存响应的 redirectRecorder，否则返回 nil
+func (s *Server) beginResultRedirect(w http.ResponseWriter) *redirectRecorder {
+	if s.resultStore == nil {
+		return nil
+	}
+	return &redirectRecorder{w: w, header: w.Header()}
+}
+
+// endResultRedirect 将 2xx 文档响应转存到对象存储并写入 303，其他响应原样写出
+func (s *Server) endResultRedirect(rec *redirectRecorder) {
+	if rec.status < 200 || rec.status > 299 {
+		if rec.status != 0 {
+			rec.w.WriteHeader(rec.status)
+		}
+		_, _ = rec.w.Write(rec.body.Bytes())
+		return
+	}
+	location := s.resultStore.put(rec.body.Bytes(), rec.header.Get("Content-Type"))
+	rec.header.Del("Content-Type")
+	rec.header.Set("Location", location)
+	rec.w.WriteHeader(http.StatusSeeOther)
+}
@@ -0,0 +1,73 @@
+package docgentest
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// SetJobPageCount 设置任务结果的页数，供 GetDocumentPageCount 与 GetDocumentPage 使用；未设置时为 1 页
+func (s *Server) SetJobPageCount(id string, pages int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pageCounts == nil {
+		s.pageCounts = make(map[string]int)
+	}
+	s.pageCounts[id] = pages
+}
+
+// handlePages 分页预览接口：GET /api/v1/jobs/{id}/pages 返回页数，GET /api/v1/jobs/{id}/pages/{n}?format=pdf|png 返回单页
+//
+// 单页内容为包含页码的最小 PDF 或 1×1 PNG；页码超出范围时返回 416 PAGE_OUT_OF_RANGE
+func (s *Server) handlePages(w http.ResponseWriter, r *http.Request, id string, job docgen.Job, action string) {
+	if s.disabledFeatures[docgen.FeaturePagedPreview] || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for "+r.Method+" "+r.URL.Path)
+		return
+	}
+	if job.State != docgen.JobSucceeded {
+		writeError(w, http.StatusConflict, docgen.CodeJobNotFinished, fmt.Sprintf("Job %s is %s", id, job.State))
+		return
+	}
+	s.mu.Lock()
+	count, ok := s.pageCounts[id]
+	s.mu.Unlock()
+	if !ok {
+		count = 1
+	}
+
+	pageParam, hasPage := strings.CutPrefix(action, "pages/")
+	if !hasPage {
+		writeJSON(w, http.StatusOK, map[string]int{"pageCount": count})
+		return
+	}
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, "invalid page: "+pageParam)
+		return
+	}
+	if page < 1 || page > count {
+		writeError(w, http.StatusRequestedRangeNotSatisfiable, docgen.CodePageOutOfRange, fmt.Sprintf("page %d out of range (document has %d pages)", page, count))
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", string(docgen.PreviewPDF):
+		pdf := fmt.Sprintf("%%PDF-1.4\n%% %s page %d of %d\n%%%%EOF\n", id, page, count)
+		writeDocument(w, []byte(pdf), fmt.Sprintf("%s-%d.pdf", id, page), "application/pdf")
+	case string(docgen.PreviewPNG):
+		img := image.NewGray(image.Rect(0, 0, 1, 1))
+		img.SetGray(0, 0, color.Gray{Y: uint8(page)})
+		var buf bytes.Buffer
+		_ = png.Encode(&buf, img)
+		writeDocument(w, buf.Bytes(), fmt.Sprintf("%s-%d.png", id, page), "image/png")
+	default:
+		writeError(w, http.StatusBadRequest, docgen.CodeUnsupportedFormat, "unsupported preview format: "+format)
+	}
+}
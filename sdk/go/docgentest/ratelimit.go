@@ -0,0 +1,49 @@
+package docgentest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// mockRateLimit 固定窗口限流状态
+type mockRateLimit struct {
+	limit       int
+	window      time.Duration
+	windowStart time.Time
+	used        int
+}
+
+// WithRateLimit 模拟服务端固定窗口限流：每个响应携带 X-RateLimit-Limit / X-RateLimit-Remaining / X-RateLimit-Reset
+// （距窗口重置的秒数，向上取整），窗口内超过 limit 的请求返回 429 RATE_LIMITED
+func WithRateLimit(limit int, window time.Duration) ServerOption {
+	return func(s *Server) {
+		s.rateLimit = &mockRateLimit{limit: limit, window: window}
+	}
+}
+
+// takeRateLimit 计入一次请求并写入限流响应头，超出配额时返回 false，调用方需持有锁
+func (s *Server) takeRateLimit(h http.Header, now time.Time) bool {
+	rl := s.rateLimit
+	if rl == nil {
+		return true
+	}
+	if rl.windowStart.IsZero() || now.Sub(rl.windowStart) >= rl.window {
+		rl.windowStart, rl.used = now, 0
+	}
+	allowed := rl.used < rl.limit
+	if allowed {
+		rl.used++
+	}
+	reset := rl.windowStart.Add(rl.window).Sub(now)
+	h.Set(docgen.RateLimitLimitHeader, strconv.Itoa(rl.limit))
+	h.Set(docgen.RateLimitRemainingHeader, strconv.Itoa(rl.limit-rl.used))
+	h.Set(docgen.RateLimitResetHeader, strconv.Itoa(int((reset+time.Second-1)/time.Second)))
+	if !allowed {
+		h.Set("Retry-After", fmt.Sprint(int((reset+time.Second-1)/time.Second)))
+	}
+	return allowed
+}
@@ -0,0 +1,188 @@
+package docgentest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// EndpointResults 异步任务结果接口路径
+const EndpointResults = "/api/v1/results"
+
+// DefaultResultRetention 模拟服务器中任务结果的默认保留时间
+const DefaultResultRetention = 24 * time.Hour
+
+// maxResultRetention ExtendResultRetention 可设置的最长保留时间，更长的 ttl 被缩短为此值
+const maxResultRetention = 30 * 24 * time.Hour
+
+// WithResultRetention 设置任务结果的保留时间（自任务结束起算，默认 DefaultResultRetention）
+func WithResultRetention(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.resultRetention = d
+	}
+}
+
+// SetResultExpiry 设置任务结果的过期时间，用于测试访问已过期的结果；任务不存在时返回 false
+func (s *Server) SetResultExpiry(jobID string, expiresAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[jobID]
+	if !ok {
+		return false
+	}
+	j.resultExpiresAt = expiresAt
+	return true
+}
+
+// resultExpiryLocked 返回任务结果的过期时间：未单独设置时为任务结束时间加保留时间，
+// 没有结束时间的任务（如 AddJob 直接添加的已成功任务）以当前时间计算，调用方需持有锁
+func (s *Server) resultExpiryLocked(j *mockJob) time.Time {
+	if !j.resultExpiresAt.IsZero() {
+		return j.resultExpiresAt
+	}
+	finished := time.Now().UTC()
+	if j.job.FinishedAt != nil {
+		finished = *j.job.FinishedAt
+	}
+	retention := s.resultRetention
+	if retention <= 0 {
+		retention = DefaultResultRetention
+	}
+	return finished.Add(retention)
+}
+
+// resultInfoLocked 返回任务结果信息，任务未成功或结果已删除时返回 false，调用方需持有锁
+func (s *Server) resultInfoLocked(j *mockJob) (docgen.ResultInfo, bool) {
+	if j.job.State != docgen.JobSucceeded || j.resultDeleted {
+		return docgen.ResultInfo{}, false
+	}
+	created := j.job.CreatedAt
+	if j.job.FinishedAt != nil {
+		created = *j.job.FinishedAt
+	}
+	return docgen.ResultInfo{
+		ID:           j.job.ID,
+		JobID:        j.job.ID,
+		TemplateName: j.job.TemplateName,
+		ContentType:  jobContentType(j.job),
+		Size:         int64(len(j.result)),
+		CreatedAt:    created,
+		ExpiresAt:    s.resultExpiryLocked(j),
+	}, true
+}
+
+// checkResult 校验任务结果可以访问，结果已删除时写入 404、已过期时写入 410 RESULT_EXPIRED 并返回 false
+func (s *Server) checkResult(w http.ResponseWriter, id string) (docgen.ResultInfo, bool) {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	var info docgen.ResultInfo
+	if ok {
+		info, ok = s.resultInfoLocked(j)
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "Result not found: "+id)
+		return info, false
+	}
+	if !info.ExpiresAt.After(time.Now()) {
+		w.Header().Set(docgen.ResultExpiredAtHeader, info.ExpiresAt.UTC().Format(time.RFC3339))
+		writeError(w, http.StatusGone, docgen.CodeResultExpired, "Result expired: "+id)
+		return info, false
+	}
+	return info, true
+}
+
+// handleResults 任务结果接口：列表、查询、延长保留期限与删除
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request, req *CapturedRequest) {
+	if r.URL.Path == EndpointResults && r.Method == http.MethodGet {
+		s.handleListResults(w, r)
+		return
+	}
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, EndpointResults+"/"), "/")
+	info, ok := s.checkResult(w, id)
+	if !ok {
+		return
+	}
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, info)
+	case action == "" && r.Method == http.MethodDelete:
+		s.mu.Lock()
+		s.jobs[id].resultDeleted = true
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case action == "retention" && r.Method == http.MethodPost:
+		var body struct {
+			TTL int64 `json:"ttl"`
+		}
+		if err := decodeBody(req.Body, &body); err != nil || body.TTL <= 0 {
+			writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "ttl: must be a positive number of seconds")
+			return
+		}
+		ttl := time.Duration(body.TTL) * time.Second
+		if ttl > maxResultRetention {
+			ttl = maxResultRetention
+		}
+		s.mu.Lock()
+		j := s.jobs[id]
+		if expiresAt := time.Now().UTC().Add(ttl); expiresAt.After(s.resultExpiryLocked(j)) {
+			j.resultExpiresAt = expiresAt
+		}
+		info, _ = s.resultInfoLocked(j)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, info)
+	default:
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// handleListResults 结果列表（不含已过期的结果），支持 job、template、createdAfter、createdBefore、expiresBefore、limit 过滤
+func (s *Server) handleListResults(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var after, before, expiresBefore time.Time
+	for key, t := range map[string]*time.Time{"createdAfter": &after, "createdBefore": &before, "expiresBefore": &expiresBefore} {
+		if v := q.Get(key); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, key+": invalid time "+v)
+				return
+			}
+			*t = parsed
+		}
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	now := time.Now()
+
+	s.mu.Lock()
+	results := make([]docgen.ResultInfo, 0, len(s.jobOrder))
+	for _, id := range s.jobOrder {
+		info, ok := s.resultInfoLocked(s.jobs[id])
+		switch {
+		case !ok || !info.ExpiresAt.After(now):
+		case q.Get("job") != "" && info.JobID != q.Get("job"):
+		case q.Get("template") != "" && info.TemplateName != q.Get("template"):
+		case !after.IsZero() && info.CreatedAt.Before(after):
+		case !before.IsZero() && info.CreatedAt.After(before):
+		case !expiresBefore.IsZero() && !info.ExpiresAt.Before(expiresBefore):
+		default:
+			results = append(results, info)
+		}
+		if limit > 0 && len(results) == limit {
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "count": len(results), "results": results})
+}
+
+// jobContentType 任务结果的内容类型
+func jobContentType(job docgen.Job) string {
+	if job.RequestType == docgen.JobTypeExcel || job.RequestType == docgen.JobTypeExcelFill {
+		return contentTypeXlsx
+	}
+	return contentTypeDocx
+}
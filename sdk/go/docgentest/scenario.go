@@ -0,0 +1,203 @@
+package docgentest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// Response 预设响应，用于 WithSequence
+type Response struct {
+	// Status HTTP 状态码，0 表示 200
+	Status int
+	// Header 额外响应头
+	Header http.Header
+	// Body 响应体
+	Body []byte
+	// Delay 写入响应前的额外延迟
+	Delay time.Duration
+	// Handler 自定义处理函数，非 nil 时忽略其他字段
+	Handler http.HandlerFunc
+
+	// passthrough 延迟后交给默认处理（Header 替换默认处理设置的同名响应头），见 Delayed、RateLimitHeaders
+	passthrough bool
+	// interruptAfter 默认处理输出的响应体超过该字节数时中断（0 表示不中断），stall 为 true 时中断前等待客户端取消请求，
+	// 见 InterruptedDownload、StalledDownload
+	interruptAfter int64
+	stall          bool
+	// eventLimit 非 nil 时进度流发送该数量的事件后中断，见 InterruptedEventStream
+	eventLimit *int
+}
+
+// wait 等待 Delay，请求被取消时返回 false
+func (resp Response) wait(r *http.Request) bool {
+	if resp.Delay <= 0 {
+		return true
+	}
+	select {
+	case <-time.After(resp.Delay):
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// write 写入预设响应，等待 Delay 期间请求被取消时不写入并返回 false
+func (resp Response) write(w http.ResponseWriter, r *http.Request) bool {
+	if !resp.wait(r) {
+		return false
+	}
+	if resp.Handler != nil {
+		resp.Handler(w, r)
+		return true
+	}
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(resp.Body)
+	return true
+}
+
+// OK 返回 200 响应，附带指定的响应体
+func OK(body []byte) Response {
+	return Response{Status: http.StatusOK, Body: body}
+}
+
+// JSON 返回指定状态码的 JSON 响应
+func JSON(status int, v any) Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("docgentest: marshal response: %v", err))
+	}
+	return Response{Status: status, Header: http.Header{"Content-Type": {"application/json"}}, Body: body}
+}
+
+// ErrorResponse 返回与真实服务格式一致的错误响应（{"status","code","message"}）
+func ErrorResponse(status int, code, message string) Response {
+	return JSON(status, map[string]any{"status": status, "code": code, "message": message})
+}
+
+// RateLimited 返回 429 限流响应，与真实服务一样附带 Retry-After 头，以及剩余 0 次、retryAfter 后重置的
+// X-RateLimit-Remaining / X-RateLimit-Reset 头
+func RateLimited(retryAfter time.Duration) Response {
+	resp := ErrorResponse(http.StatusTooManyRequests, docgen.CodeRateLimited, "too many requests")
+	seconds := fmt.Sprint(int(retryAfter.Round(time.Second) / time.Second))
+	resp.Header.Set("Retry-After", seconds)
+	resp.Header.Set(docgen.RateLimitRemainingHeader, "0")
+	resp.Header.Set(docgen.RateLimitResetHeader, seconds)
+	return resp
+}
+
+// RateLimitHeaders 按默认逻辑处理请求，响应携带指定的限流响应头原始取值（空字符串表示不设置），
+// 用于测试客户端对各种取值（包括畸形取值）的解析：
+//
+//	docgentest.WithSequence(docgentest.EndpointWord, docgentest.RateLimitHeaders("100", "abc", "30"))
+func RateLimitHeaders(limit, remaining, reset string) Response {
+	h := make(http.Header)
+	for name, value := range map[string]string{
+		docgen.RateLimitLimitHeader:     limit,
+		docgen.RateLimitRemainingHeader: remaining,
+		docgen.RateLimitResetHeader:     reset,
+	} {
+		if value != "" {
+			h.Set(name, value)
+		}
+	}
+	return Response{Header: h, passthrough: true}
+}
+
+// Malformed 返回指定状态码的畸形响应体（如截断的 JSON 或 HTML 错误页）
+func Malformed(status int, body string) Response {
+	return Response{Status: status, Header: http.Header{"Content-Type": {"application/json"}}, Body: []byte(body)}
+}
+
+// Slow 在写入响应前延迟 d
+func Slow(d time.Duration, resp Response) Response {
+	resp.Delay += d
+	return resp
+}
+
+// Delayed 延迟 d 后按默认逻辑处理请求，用于模拟负载均衡后的个别慢实例：
+//
+//	docgentest.WithSequence(docgentest.EndpointWord, docgentest.Delayed(2*time.Second))
+//
+// 第一个请求在 2 秒后才得到正常响应，之后的请求不受影响
+func Delayed(d time.Duration) Response {
+	return Response{Delay: d, passthrough: true}
+}
+
+// InterruptedDownload 按默认逻辑处理下载请求（任务结果、模板下载），输出 n 字节响应体后断开连接，
+// 与 WithSequence 配合为每次下载指定中断位置，用于测试续传：
+//
+//	docgentest.WithSequence(docgentest.EndpointJobs+"/j1/result", docgentest.InterruptedDownload(100<<10), docgentest.InterruptedDownload(50<<10))
+//
+// 第一次下载在 100 KiB 处断开，续传的第二次下载再输出 50 KiB 后断开，之后的下载完整输出。
+// 所有下载都在同一位置中断时使用 InterruptDownloadsAfter
+func InterruptedDownload(n int64) Response {
+	return Response{passthrough: true, interruptAfter: n}
+}
+
+// StalledDownload 与 InterruptedDownload 相同，但输出 n 字节后停止输出，直到客户端取消请求才断开连接，
+// 用于模拟下载中途被终止的进程
+func StalledDownload(n int64) Response {
+	return Response{passthrough: true, interruptAfter: n, stall: true}
+}
+
+// InterruptedEventStream 按默认逻辑输出任务进度流，发送 n 个事件后写入半个事件（没有结尾的空行）并断开连接，
+// 模拟传输中断；与 WithSequence 配合为每次连接指定中断位置，用于测试 Last-Event-ID 续传：
+//
+//	docgentest.WithSequence(docgentest.EndpointJobs+"/j1/events", docgentest.InterruptedEventStream(2), docgentest.InterruptedEventStream(0))
+//
+// 第一次连接发送 2 个事件后断开，第二次连接不发送事件即断开，之后的连接不中断。
+// 每次连接都在相同数量的事件后断开时使用 DropEventStreamAfter
+func InterruptedEventStream(n int) Response {
+	return Response{passthrough: true, eventLimit: &n}
+}
+
+// Document 返回文档响应
+func Document(data []byte, contentType string) Response {
+	return Response{Status: http.StatusOK, Header: http.Header{"Content-Type": {contentType}}, Body: data}
+}
+
+// MultipartDocument 返回 multipart/mixed 文档响应：文档部分之后是 application/json 元数据部分，
+// 如 {"warnings": [{"code": "MISSING_PLACEHOLDER", "placeholder": "name"}], "timingsMs": {"render": 12}}
+func MultipartDocument(data []byte, contentType string, metadata any) Response {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	_, _ = part.Write(data)
+	meta, err := json.Marshal(metadata)
+	if err != nil {
+		panic(fmt.Sprintf("docgentest: marshal metadata: %v", err))
+	}
+	part, _ = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+	_, _ = part.Write(meta)
+	_ = mw.Close()
+	return Response{Status: http.StatusOK, Header: http.Header{"Content-Type": {"multipart/mixed; boundary=" + mw.Boundary()}}, Body: buf.Bytes()}
+}
+
+// DropConnection 不写入任何响应直接断开连接，模拟网络中断
+func DropConnection() Response {
+	return Response{Handler: func(w http.ResponseWriter, r *http.Request) {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				_ = conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}}
+}
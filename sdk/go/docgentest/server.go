@@ -0,0 +1,1020 @@
+// Package docgentest 提供用于测试的文档生成服务模拟服务器
+//
+// 模拟服务器基于 httptest 在进程内运行，实现与真实服务一致的接口路径和错误格式，
+// 并支持通过场景选项模拟慢响应、随机失败、限流与畸形响应体：
+//
+//	srv := docgentest.NewServer(
+//	    docgentest.WithSeed(42),
+//	    docgentest.WithLatency(docgentest.EndpointWord, 200*time.Millisecond),
+//	    docgentest.WithSequence(docgentest.EndpointWord,
+//	        docgentest.ErrorResponse(500, docgen.CodeInternalError, "boom"),
+//	        docgentest.RateLimited(time.Second),
+//	    ),
+//	)
+//	defer srv.Close()
+//	srv.AddTemplate("template.docx", docgentest.MinimalDocx("hello"))
+//	client := docgen.NewClient(srv.URL)
+package docgentest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// 服务接口路径，用于场景选项中指定端点
+const (
+	EndpointHealth           = "/actuator/health"
+	EndpointWord             = "/api/v1/doc/word"
+	EndpointWordBatch        = "/api/v1/doc/word/batch"
+	EndpointExcel            = "/api/v1/doc/excel"
+	EndpointExcelFill        = "/api/v1/doc/excel/fill"
+	EndpointTemplateUpload   = "/api/v1/template/upload"
+	EndpointTemplateList     = "/api/v1/template/list"
+	EndpointTemplateInfo     = "/api/v1/template/info"
+	EndpointTemplate         = "/api/v1/template"
+	EndpointTemplateDownload = "/api/v1/template/download"
+	EndpointTemplateVars     = "/api/v1/template/variables"
+
+	// AnyEndpoint 匹配所有端点
+	AnyEndpoint = "*"
+)
+
+// Server 模拟文档生成服务
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	rng       *rand.Rand
+	templates map[string]storedTemplate
+	schemas   map[string]docgen.TemplateSchema
+	requests  []*CapturedRequest
+	canceled  []*CapturedRequest
+	latency   map[string]time.Duration
+	failRate  map[string]float64
+	sequences map[string][]Response
+
+	jobs            map[string]*mockJob
+	jobOrder        []string
+	jobSeq          int
+	jobsChanged     chan struct{}
+	manualJobs      bool
+	dropEventsAfter int
+	interruptAfter  int64
+	noRangeRequests bool
+
+	uploads           map[string]*uploadSession
+	uploadSeq         int
+	uploadChunkBudget int
+
+	links   map[string]*signedLink
+	linkSeq int
+
+	fieldKeys docgen.KeyFunc
+
+	disabledFeatures map[docgen.Feature]bool
+	noCapabilities   bool
+
+	noHealthProbes bool
+	notReady       bool
+
+	rateLimit *mockRateLimit
+
+	usage      docgen.UsageReport
+	quotaLimit int64
+	quotaReset time.Time
+
+	includes         map[string][]string
+	deleteProtection bool
+
+	pageCounts map[string]int
+
+	volatileOutput bool
+
+	resultRetention time.Duration
+
+	loadShedding map[string]Response
+
+	resultStore *ObjectStore
+}
+
+// storedTemplate 模板存储条目
+type storedTemplate struct {
+	data    []byte
+	modTime time.Time
+	// expiresAt 临时模板的过期时间，永久模板为零值
+	expiresAt time.Time
+}
+
+// ServerOption 模拟服务器配置选项
+type ServerOption func(*Server)
+
+// WithSeed 设置随机数种子，使 WithFailureRate 的失败序列可复现（默认种子为 1）
+func WithSeed(seed int64) ServerOption {
+	return func(s *Server) {
+		s.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithLatency 为端点增加固定响应延迟
+//
+// endpoint: 接口路径（如 EndpointWord），AnyEndpoint 表示所有端点
+func WithLatency(endpoint string, d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.latency[endpoint] = d
+	}
+}
+
+// WithFailureRate 使端点以概率 p（0~1）返回 500 INTERNAL_ERROR
+func WithFailureRate(endpoint string, p float64) ServerOption {
+	return func(s *Server) {
+		s.failRate[endpoint] = p
+	}
+}
+
+// WithSequence 为端点预设响应序列
+//
+// 每次请求按顺序消费一个响应，序列耗尽后恢复正常处理
+func WithSequence(endpoint string, responses ...Response) ServerOption {
+	return func(s *Server) {
+		s.sequences[endpoint] = append(s.sequences[endpoint], responses...)
+	}
+}
+
+// WithTemplate 预置模板文件
+func WithTemplate(name string, data []byte) ServerOption {
+	return func(s *Server) {
+		s.templates[name] = storedTemplate{data: data, modTime: time.Now()}
+	}
+}
+
+// NewServer 创建并启动模拟服务器，使用完毕后需调用 Close
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		rng:       rand.New(rand.NewSource(1)),
+		templates: make(map[string]storedTemplate),
+		schemas:   make(map[string]docgen.TemplateSchema),
+		latency:   make(map[string]time.Duration),
+		failRate:  make(map[string]float64),
+		sequences: make(map[string][]Response),
+
+		jobs:        make(map[string]*mockJob),
+		jobsChanged: make(chan struct{}),
+
+		uploads:           make(map[string]*uploadSession),
+		uploadChunkBudget: -1,
+
+		links: make(map[string]*signedLink),
+
+		disabledFeatures: make(map[docgen.Feature]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// AddTemplate 添加或覆盖模板文件
+func (s *Server) AddTemplate(name string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates[name] = storedTemplate{data: data, modTime: time.Now()}
+}
+
+// SetTemplateSchema 设置模板的占位符结构，供 GetTemplateVariables 返回
+//
+// 未设置结构的已存在模板返回空结构
+func (s *Server) SetTemplateSchema(schema docgen.TemplateSchema) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schemas[schema.TemplateName] = schema
+}
+
+// Template 获取已存储的模板内容
+func (s *Server) Template(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.templates[name]
+	return t.data, ok
+}
+
+// Requests 返回已捕获的全部请求（按到达顺序）
+func (s *Server) Requests() []*CapturedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*CapturedRequest(nil), s.requests...)
+}
+
+// RequestsTo 返回发往指定端点的请求
+func (s *Server) RequestsTo(endpoint string) []*CapturedRequest {
+	var result []*CapturedRequest
+	for _, r := range s.Requests() {
+		if endpoint == AnyEndpoint || r.Path == endpoint {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// LastRequest 返回最近一次请求，无请求时返回 nil
+func (s *Server) LastRequest() *CapturedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return nil
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// CanceledRequests 返回客户端在服务端响应前取消的请求（按取消顺序），如对冲请求中落后、等待 Delayed 或 WithLatency 延迟时被取消的请求
+func (s *Server) CanceledRequests() []*CapturedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*CapturedRequest(nil), s.canceled...)
+}
+
+// recordCanceled 记录客户端取消的请求
+func (s *Server) recordCanceled(req *CapturedRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.canceled = append(s.canceled, req)
+}
+
+// ResetRequests 清空已捕获的请求
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.canceled = nil
+}
+
+// serveHTTP 请求入口：捕获请求，依次应用延迟、预设序列、随机失败，最后交给默认处理
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	captured := &CapturedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, captured)
+	delay := s.lookupLatency(r.URL.Path)
+	resp, hasResp := s.nextResponse(r.URL.Path)
+	fail := !hasResp && s.shouldFail(r.URL.Path)
+	allowed := s.takeRateLimit(w.Header(), time.Now())
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			s.recordCanceled(captured)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Language", negotiateLanguage(r.Header.Get("Accept-Language")))
+	switch {
+	case !allowed:
+		writeError(w, http.StatusTooManyRequests, docgen.CodeRateLimited, "too many requests")
+	case hasResp && resp.passthrough:
+		if !resp.wait(r) {
+			s.recordCanceled(captured)
+			return
+		}
+		for k, vs := range resp.Header {
+			w.Header()[k] = vs
+		}
+		if resp.eventLimit != nil {
+			r = r.WithContext(context.WithValue(r.Context(), eventLimitKey{}, *resp.eventLimit))
+		}
+		if resp.interruptAfter > 0 {
+			iw := &interruptingWriter{ResponseWriter: w, remaining: resp.interruptAfter}
+			if resp.stall {
+				iw.stall = r.Context().Done()
+			}
+			w = iw
+		}
+		s.handle(w, r, captured)
+	case hasResp:
+		if !resp.write(w, r) {
+			s.recordCanceled(captured)
+		}
+	case fail:
+		writeError(w, http.StatusInternalServerError, docgen.CodeInternalError, "simulated failure")
+	default:
+		s.handle(w, r, captured)
+	}
+}
+
+// negotiateLanguage 按 Accept-Language 选择响应语言，与真实服务一样支持 zh-CN 与 en-US，默认 zh-CN
+func negotiateLanguage(accept string) string {
+	for _, item := range strings.Split(accept, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(item), ";")
+		switch lang, _, _ := strings.Cut(strings.ToLower(tag), "-"); lang {
+		case "zh":
+			return "zh-CN"
+		case "en":
+			return "en-US"
+		}
+	}
+	return "zh-CN"
+}
+
+// lookupLatency 查找端点延迟，调用方需持有锁
+func (s *Server) lookupLatency(path string) time.Duration {
+	if d, ok := s.latency[path]; ok {
+		return d
+	}
+	return s.latency[AnyEndpoint]
+}
+
+// nextResponse 消费端点的下一个预设响应，调用方需持有锁
+func (s *Server) nextResponse(path string) (Response, bool) {
+	for _, key := range []string{path, AnyEndpoint} {
+		if seq := s.sequences[key]; len(seq) > 0 {
+			s.sequences[key] = seq[1:]
+			return seq[0], true
+		}
+	}
+	return Response{}, false
+}
+
+// shouldFail 按失败概率决定是否模拟失败，调用方需持有锁
+func (s *Server) shouldFail(path string) bool {
+	p, ok := s.failRate[path]
+	if !ok {
+		p, ok = s.failRate[AnyEndpoint]
+	}
+	return ok && p > 0 && s.rng.Float64() < p
+}
+
+// handle 默认处理逻辑，模拟真实服务的正常行为
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, req *CapturedRequest) {
+	path := r.URL.Path
+	if req = s.decryptRequest(w, r, req); req == nil {
+		return
+	}
+	if isGeneration(r.Method, path) && !s.disabledEndpoint(path) {
+		if s.shedLoad(w, r, req) {
+			return
+		}
+		if redirect := s.beginResultRedirect(w); redirect != nil {
+			defer s.endResultRedirect(redirect)
+			w = redirect
+		}
+		rec := s.beginUsage(w)
+		if rec == nil {
+			return
+		}
+		defer s.endUsage(path, rec)
+		w = rec
+	}
+	switch {
+	case s.disabledEndpoint(path):
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for "+r.Method+" "+path)
+	case r.Method == http.MethodOptions:
+		s.handleOptions(w, path)
+	case path == EndpointCapabilities && r.Method == http.MethodGet && !s.noCapabilities:
+		s.handleCapabilities(w)
+	case isHealthPath(path):
+		s.handleHealth(w, path)
+	case path == EndpointWord && r.Method == http.MethodPost:
+		s.handleWord(w, req)
+	case path == EndpointWordBatch && r.Method == http.MethodPost:
+		s.handleWordBatch(w, req)
+	case path == EndpointAssemble && r.Method == http.MethodPost:
+		s.handleAssemble(w, req)
+	case path == EndpointExcel && r.Method == http.MethodPost:
+		s.handleExcel(w, req)
+	case path == EndpointExcelFill && r.Method == http.MethodPost:
+		s.handleExcelFill(w, req)
+	case path == EndpointTemplateUpload && r.Method == http.MethodPost:
+		s.handleUpload(w, r, req)
+	case path == EndpointTemplateUploads || strings.HasPrefix(path, EndpointTemplateUploads+"/"):
+		s.handleUploads(w, r, req)
+	case path == EndpointTemplateList:
+		s.handleList(w)
+	case path == EndpointTemplateInfo:
+		s.handleInfo(w)
+	case strings.HasPrefix(path, EndpointTemplateVars):
+		s.handleVariables(w, r)
+	case strings.HasPrefix(path, EndpointTemplateDownload):
+		s.handleDownload(w, r)
+	case strings.HasPrefix(path, EndpointTemplateDependencies) && r.Method == http.MethodGet:
+		s.handleDependencies(w, r)
+	case strings.HasPrefix(path, EndpointTemplate) && r.Method == http.MethodDelete:
+		s.handleDelete(w, r)
+	case path == EndpointLinks || strings.HasPrefix(path, EndpointLinks+"/"):
+		s.handleLinks(w, r, req)
+	case path == EndpointJobs || strings.HasPrefix(path, EndpointJobs+"/"):
+		s.handleJobs(w, r, req)
+	case path == EndpointResults || strings.HasPrefix(path, EndpointResults+"/"):
+		s.handleResults(w, r, req)
+	case (path == EndpointUsage || path == EndpointQuota) && r.Method == http.MethodGet:
+		s.handleUsage(w, r)
+	case strings.HasPrefix(path, EndpointStorage) && s.resultStore != nil && s.resultStore.Server == nil:
+		s.resultStore.serve(w, r, req)
+	default:
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for "+r.Method+" "+path)
+	}
+}
+
+// handleWord 生成 Word：将数据按键排序后逐行写为段落
+func (s *Server) handleWord(w http.ResponseWriter, req *CapturedRequest) {
+	var body struct {
+		TemplateName    string         `json:"templateName"`
+		Data            map[string]any `json:"data"`
+		ContentControls map[string]any `json:"contentControls"`
+		FileName        string         `json:"fileName"`
+		OutputFormat    docgen.Format  `json:"outputFormat"`
+	}
+	if !s.decodeGeneration(w, req, &body, &body.TemplateName) {
+		return
+	}
+	if key, ok := renderFailure(body.Data); ok {
+		writeError(w, http.StatusUnprocessableEntity, docgen.CodeRenderError, "Render error in data."+key)
+		return
+	}
+	if !s.simpleRender(req) && !fetchImages(w, body.Data) {
+		return
+	}
+	s.setMissingPlaceholderWarnings(w, body.TemplateName, body.Data)
+	format := outputFormat(body.OutputFormat, docgen.FormatDocx)
+	paragraphs := append(dataParagraphs(body.Data), s.contentControlParagraphs(body.ContentControls)...)
+	writeDocument(w, s.volatile(req, MinimalDocx(paragraphs...)), withDefault(body.FileName, "generated")+"."+string(format), format.ContentType())
+}
+
+// handleWordBatch 批量生成 Word：每条数据生成一组段落
+func (s *Server) handleWordBatch(w http.ResponseWriter, req *CapturedRequest) {
+	var body struct {
+		TemplateName    string           `json:"templateName"`
+		DataList        []map[string]any `json:"dataList"`
+		Templates       []string         `json:"templates"`
+		FileName        string           `json:"fileName"`
+		ContinueOnError bool             `json:"continueOnError"`
+		Archive         bool             `json:"archive"`
+		OutputFormat    docgen.Format    `json:"outputFormat"`
+	}
+	if !s.decodeGeneration(w, req, &body, &body.TemplateName) {
+		return
+	}
+	if len(body.DataList) == 0 {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "dataList: must not be empty")
+		return
+	}
+	if s.disabledFeatures[docgen.FeatureBatchTemplates] {
+		// 旧版服务不识别 templates 字段
+		body.Templates = nil
+	}
+	if len(body.Templates) > 0 && len(body.Templates) != len(body.DataList) {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "templates: size must match dataList")
+		return
+	}
+	if s.disabledFeatures[docgen.FeatureBatchPartialFailure] {
+		body.ContinueOnError = false
+	}
+	if s.disabledFeatures[docgen.FeatureBatchArchive] {
+		body.Archive = false
+	}
+	var paragraphs []string
+	base := withDefault(body.FileName, "batch_generated")
+	// 压缩包模式：每个条目一个文档，按 DataList 下标（从 1 开始）命名
+	var entries [][2]string
+	var failures []docgen.ItemFailure
+	for i, data := range body.DataList {
+		if len(body.Templates) > 0 && body.Templates[i] != "" {
+			if _, ok := s.Template(body.Templates[i]); !ok {
+				writeError(w, http.StatusUnprocessableEntity, docgen.CodeTemplateNotFound, "Template not found: "+body.Templates[i])
+				return
+			}
+		}
+		if key, ok := renderFailure(data); ok {
+			message := fmt.Sprintf("Render error in dataList[%d].%s", i, key)
+			if !body.ContinueOnError {
+				writeError(w, http.StatusUnprocessableEntity, docgen.CodeRenderError, message)
+				return
+			}
+			failures = append(failures, docgen.ItemFailure{Index: i, Error: docgen.ErrorResponse{Status: http.StatusUnprocessableEntity, Code: docgen.CodeRenderError, Message: message}})
+			continue
+		}
+		paragraphs = append(paragraphs, dataParagraphs(data)...)
+		if body.Archive {
+			entries = append(entries, [2]string{fmt.Sprintf("%s_%d.%s", base, i+1, outputFormat(body.OutputFormat, docgen.FormatDocx)), string(s.volatile(req, MinimalDocx(dataParagraphs(data)...)))})
+		}
+	}
+	if len(failures) == len(body.DataList) {
+		writeError(w, http.StatusUnprocessableEntity, docgen.CodeRenderError, "Render error in every dataList item")
+		return
+	}
+	format := outputFormat(body.OutputFormat, docgen.FormatDocx)
+	fileName, doc, contentType := base+"."+string(format), MinimalDocx(paragraphs...), format.ContentType()
+	if body.Archive {
+		fileName, doc, contentType = base+".zip", buildZip(entries), contentTypeZip
+	}
+	doc = s.volatile(req, doc)
+	if body.ContinueOnError {
+		// 部分失败模式：文档之后附带失败条目报告
+		resp := MultipartDocument(doc, contentType, map[string]any{"failures": failures})
+		resp.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		resp.write(w, nil)
+		return
+	}
+	writeDocument(w, doc, fileName, contentType)
+}
+
+// fetchImages 模拟下载数据中的 docgen.ImageURL 图片：主机名以 ".invalid" 结尾的地址下载失败，
+// 其余视为成功。统计写入 docgen.ImagesFetchedHeader / docgen.ImagesFailedHeader；
+// 失败图片的 onError 为 "error" 时写入 422 IMAGE_FETCH_FAILED 并返回 false
+func fetchImages(w http.ResponseWriter, values ...any) bool {
+	var fetched, failed int
+	var fatal string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if spec, ok := v[docgen.ImageURLMarker].(map[string]any); ok && len(v) == 1 {
+				rawURL, _ := spec["url"].(string)
+				u, err := url.Parse(rawURL)
+				if err != nil || strings.HasSuffix(u.Hostname(), ".invalid") {
+					failed++
+					if spec["onError"] == string(docgen.ImageFailError) && fatal == "" {
+						fatal = rawURL
+					}
+					return
+				}
+				fetched++
+				return
+			}
+			for _, item := range v {
+				walk(item)
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case [][]any:
+			for _, row := range v {
+				walk(row)
+			}
+		case []map[string]any:
+			for _, row := range v {
+				walk(row)
+			}
+		case map[string][]map[string]any:
+			for _, rows := range v {
+				walk(rows)
+			}
+		}
+	}
+	for _, v := range values {
+		walk(v)
+	}
+	if fatal != "" {
+		writeError(w, http.StatusUnprocessableEntity, docgen.CodeImageFetchFailed, "Failed to fetch image: "+fatal)
+		return false
+	}
+	if fetched+failed > 0 {
+		w.Header().Set(docgen.ImagesFetchedHeader, strconv.Itoa(fetched))
+		w.Header().Set(docgen.ImagesFailedHeader, strconv.Itoa(failed))
+	}
+	return true
+}
+
+// RenderErrorValue 模板数据中等于该值的字符串使模拟渲染失败（RENDER_ERROR），用于测试错误处理与批量部分失败
+const RenderErrorValue = "docgentest:render-error"
+
+// renderFailure 返回触发模拟渲染失败的键名
+func renderFailure(data map[string]any) (string, bool) {
+	for _, k := range sortedKeys(data) {
+		if v, ok := data[k].(string); ok && v == RenderErrorValue {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// handleExcel 动态生成 Excel：表头 + 数据行
+func (s *Server) handleExcel(w http.ResponseWriter, req *CapturedRequest) {
+	var body struct {
+		SheetName string   `json:"sheetName"`
+		Headers   []string `json:"headers"`
+		Data      [][]any  `json:"data"`
+		FileName  string   `json:"fileName"`
+		SplitRows int      `json:"splitRows"`
+	}
+	if err := decodeBody(req.Body, &body); err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "malformed request body: "+err.Error())
+		return
+	}
+	if len(body.Headers) == 0 {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "headers: must not be empty")
+		return
+	}
+	if s.disabledFeatures[docgen.FeatureSheetSplit] {
+		// 旧版服务不识别 splitRows 字段
+		body.SplitRows = 0
+	}
+	if !s.simpleRender(req) && !fetchImages(w, body.Data) {
+		return
+	}
+	rowsPerSheet := len(body.Data)
+	if body.SplitRows > 0 && len(body.Data) > body.SplitRows {
+		rowsPerSheet = body.SplitRows
+	}
+
+	var sheets []Sheet
+	for first := 0; first == 0 || first < len(body.Data); first += rowsPerSheet {
+		end := first + rowsPerSheet
+		if end > len(body.Data) {
+			end = len(body.Data)
+		}
+		rows := [][]string{body.Headers}
+		for _, row := range body.Data[first:end] {
+			cells := make([]string, len(row))
+			for i, v := range row {
+				cells[i] = formatValue(v)
+			}
+			rows = append(rows, cells)
+		}
+		sheets = append(sheets, Sheet{Rows: rows})
+		if rowsPerSheet == 0 {
+			break
+		}
+	}
+	if len(sheets) == 1 {
+		sheets[0].Name = withDefault(body.SheetName, "Sheet1")
+	} else {
+		for i := range sheets {
+			sheets[i].Name = fmt.Sprintf("%s_%d", withDefault(body.SheetName, "Data"), i+1)
+		}
+	}
+	writeDocument(w, s.volatile(req, MinimalXlsx(sheets...)), withDefault(body.FileName, "generated")+".xlsx", contentTypeXlsx)
+}
+
+// handleExcelFill 填充 Excel：单值数据写入首行，每个列表写入一个工作表
+func (s *Server) handleExcelFill(w http.ResponseWriter, req *CapturedRequest) {
+	var body struct {
+		TemplateName string                      `json:"templateName"`
+		Data         map[string]any              `json:"data"`
+		ListData     map[string][]map[string]any `json:"listData"`
+		FileName     string                      `json:"fileName"`
+		OutputFormat docgen.Format               `json:"outputFormat"`
+	}
+	if !s.decodeGeneration(w, req, &body, &body.TemplateName) {
+		return
+	}
+	if !s.simpleRender(req) && !fetchImages(w, body.Data, body.ListData) {
+		return
+	}
+	sheets := []Sheet{{Name: "Sheet1", Rows: [][]string{dataParagraphs(body.Data)}}}
+	for _, name := range sortedKeys(body.ListData) {
+		var rows [][]string
+		for _, item := range body.ListData[name] {
+			rows = append(rows, dataParagraphs(item))
+		}
+		sheets = append(sheets, Sheet{Name: name, Rows: rows})
+	}
+	format := outputFormat(body.OutputFormat, docgen.FormatXlsx)
+	writeDocument(w, s.volatile(req, MinimalXlsx(sheets...)), withDefault(body.FileName, "filled")+"."+string(format), format.ContentType())
+}
+
+// decodeGeneration 解析生成请求并校验模板是否存在，失败时写入错误响应并返回 false
+func (s *Server) decodeGeneration(w http.ResponseWriter, req *CapturedRequest, body any, templateName *string) bool {
+	if err := decodeBody(req.Body, body); err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "malformed request body: "+err.Error())
+		return false
+	}
+	if *templateName == "" {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "templateName: must not be blank")
+		return false
+	}
+	data, ok := s.Template(*templateName)
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, docgen.CodeTemplateNotFound, "Template not found: "+*templateName)
+		return false
+	}
+	return s.checkTemplateChecksum(w, req, data)
+}
+
+// checkTemplateChecksum 校验请求的 expectedTemplateChecksum，模板已被替换时返回 412 TEMPLATE_CHANGED
+// 并在 docgen.TemplateChecksumHeader 中返回当前校验和；禁用 docgen.FeatureTemplateChecksum 时忽略该字段
+func (s *Server) checkTemplateChecksum(w http.ResponseWriter, req *CapturedRequest, template []byte) bool {
+	var body struct {
+		ExpectedTemplateChecksum string `json:"expectedTemplateChecksum"`
+	}
+	if s.disabledFeatures[docgen.FeatureTemplateChecksum] || decodeBody(req.Body, &body) != nil || body.ExpectedTemplateChecksum == "" {
+		return true
+	}
+	current := fmt.Sprintf("%x", sha256.Sum256(template))
+	if strings.EqualFold(current, body.ExpectedTemplateChecksum) {
+		return true
+	}
+	w.Header().Set(docgen.TemplateChecksumHeader, current)
+	writeError(w, http.StatusPreconditionFailed, docgen.CodeTemplateChanged, "Template has changed")
+	return false
+}
+
+// decodeBody 解析 JSON 请求体，数值保留为 json.Number
+func decodeBody(body []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// handleUpload 上传模板（multipart 表单的 file 字段）
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request, req *CapturedRequest) {
+	r.Body = io.NopCloser(bytes.NewReader(req.Body))
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, "file is required")
+		return
+	}
+	defer file.Close()
+	data, _ := io.ReadAll(file)
+	if len(data) == 0 {
+		writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, "file is empty")
+		return
+	}
+	resp := map[string]any{"success": true, "message": "模板上传成功", "fileName": header.Filename}
+	stored := storedTemplate{data: data, modTime: time.Now()}
+	if ttl := r.FormValue("ttl"); ttl != "" && !s.disabledFeatures[docgen.FeatureTemporaryTemplates] {
+		seconds, err := strconv.Atoi(ttl)
+		if err != nil || seconds <= 0 {
+			writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, "ttl: must be a positive number of seconds")
+			return
+		}
+		if seconds > maxTemplateTTL {
+			seconds = maxTemplateTTL
+		}
+		stored.expiresAt = stored.modTime.Add(time.Duration(seconds) * time.Second)
+		resp["expiresAt"] = stored.expiresAt.UTC().Format(time.RFC3339Nano)
+	}
+	s.mu.Lock()
+	s.templates[header.Filename] = stored
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// maxTemplateTTL 临时模板的最长保留时间（秒），更长的 ttl 被缩短为此值
+const maxTemplateTTL = 24 * 60 * 60
+
+// ExpireTemplates 删除在 now 之前过期的临时模板并返回其名称，用于测试服务端的自动清理
+func (s *Server) ExpireTemplates(now time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var expired []string
+	for _, name := range sortedKeys(s.templates) {
+		if t := s.templates[name]; !t.expiresAt.IsZero() && !t.expiresAt.After(now) {
+			delete(s.templates, name)
+			expired = append(expired, name)
+		}
+	}
+	return expired
+}
+
+// handleList 模板列表
+func (s *Server) handleList(w http.ResponseWriter) {
+	s.mu.Lock()
+	names := sortedKeys(s.templates)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "count": len(names), "templates": names})
+}
+
+// handleInfo 模板详细信息列表
+func (s *Server) handleInfo(w http.ResponseWriter) {
+	s.mu.Lock()
+	infos := make([]map[string]any, 0, len(s.templates))
+	for _, name := range sortedKeys(s.templates) {
+		t := s.templates[name]
+		info := map[string]any{"name": name, "size": len(t.data), "lastModified": t.modTime.UTC().Format(time.RFC3339Nano)}
+		if !t.expiresAt.IsZero() {
+			info["expiresAt"] = t.expiresAt.UTC().Format(time.RFC3339Nano)
+		}
+		infos = append(infos, info)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "count": len(infos), "templates": infos})
+}
+
+// handleDownload 下载模板，支持路径与 ?name= 两种寻址方式，以及 HEAD 请求
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	name := templateNameFromRequest(r, EndpointTemplateDownload)
+	data, ok := s.Template(name)
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, docgen.CodeTemplateNotFound, "Template not found: "+name)
+		return
+	}
+	s.serveDownload(w, r, data, contentTypeFor(name))
+}
+
+// serveDownload 输出可下载内容：支持 Range / If-Range，携带 ETag 与 Digest 响应头，
+// 并按 InterruptDownloadsAfter 的设置模拟连接中断
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request, data []byte, contentType string) {
+	sum := sha256.Sum256(data)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sum[:8]))
+	w.Header().Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum[:]))
+
+	s.mu.Lock()
+	limit := s.interruptAfter
+	ignoreRange := s.noRangeRequests
+	s.mu.Unlock()
+	if limit > 0 && r.Method != http.MethodHead {
+		w = &interruptingWriter{ResponseWriter: w, remaining: limit}
+	}
+	if ignoreRange {
+		r = r.Clone(r.Context())
+		r.Header.Del("Range")
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+// InterruptDownloadsAfter 模板下载与任务结果下载在输出 n 字节后断开连接，用于测试续传（0 表示不中断）
+func (s *Server) InterruptDownloadsAfter(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interruptAfter = n
+}
+
+// WithoutRangeRequests 模板下载与任务结果下载忽略 Range 请求头，始终以 200 返回完整内容，
+// 模拟不支持续传的旧版服务或代理
+func WithoutRangeRequests() ServerOption {
+	return func(s *Server) {
+		s.noRangeRequests = true
+	}
+}
+
+// interruptingWriter 写满指定字节数后中止连接
+type interruptingWriter struct {
+	http.ResponseWriter
+	remaining int64
+	// stall 非 nil 时写满后等待该通道关闭再中止连接
+	stall <-chan struct{}
+}
+
+// Write 写入至多 remaining 字节，超出时以 http.ErrAbortHandler 中止响应
+func (w *interruptingWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) <= w.remaining {
+		w.remaining -= int64(len(p))
+		return w.ResponseWriter.Write(p)
+	}
+	_, _ = w.ResponseWriter.Write(p[:w.remaining])
+	w.remaining = 0
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	if w.stall != nil {
+		<-w.stall
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// handleVariables 返回模板占位符结构
+func (s *Server) handleVariables(w http.ResponseWriter, r *http.Request) {
+	name := templateNameFromRequest(r, EndpointTemplateVars)
+	s.mu.Lock()
+	_, exists := s.templates[name]
+	schema, hasSchema := s.schemas[name]
+	s.mu.Unlock()
+
+	if !exists && !hasSchema {
+		writeError(w, http.StatusUnprocessableEntity, docgen.CodeTemplateNotFound, "Template not found: "+name)
+		return
+	}
+	schema.TemplateName = name
+	if schema.Variables == nil {
+		schema.Variables = []docgen.TemplateVariable{}
+	}
+	writeJSON(w, http.StatusOK, schema)
+}
+
+// handleDelete 删除模板，支持路径与 ?name= 两种寻址方式
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	name := templateNameFromRequest(r, EndpointTemplate)
+	if s.refuseDeleteInUse(w, name) {
+		return
+	}
+	s.mu.Lock()
+	_, ok := s.templates[name]
+	delete(s.templates, name)
+	delete(s.includes, name)
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusOK, map[string]any{"success": false, "message": "模板文件不存在", "fileName": name})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "message": "模板删除成功", "fileName": name})
+}
+
+// templateNameFromRequest 从 ?name= 参数或路径中提取模板名称
+func templateNameFromRequest(r *http.Request, base string) string {
+	if name := r.URL.Query().Get("name"); name != "" {
+		return name
+	}
+	return strings.TrimPrefix(r.URL.Path, base+"/")
+}
+
+const (
+	contentTypeDocx = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	contentTypeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	contentTypeZip  = "application/zip"
+)
+
+// contentTypeFor 按模板扩展名返回 Content-Type（.docm、.xlsm 为启用宏的类型）
+func contentTypeFor(name string) string {
+	return docgen.Format(strings.TrimPrefix(strings.ToLower(path.Ext(name)), ".")).ContentType()
+}
+
+// outputFormat 请求指定的输出格式，未指定时为 fallback；模拟服务不生成宏，只反映在文件名与 Content-Type 上
+func outputFormat(requested, fallback docgen.Format) docgen.Format {
+	if requested == "" {
+		return fallback
+	}
+	return requested
+}
+
+// writeDocument 写入文档响应
+func writeDocument(w http.ResponseWriter, data []byte, fileName, contentType string) {
+	sum := sha256.Sum256(data)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-SHA256", fmt.Sprintf("%x", sum))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError 写入与真实服务一致的错误响应
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]any{"status": status, "code": code, "message": message})
+}
+
+// dataParagraphs 将数据按键排序转换为 "key: value" 文本
+func dataParagraphs(data map[string]any) []string {
+	lines := make([]string, 0, len(data))
+	for _, k := range sortedKeys(data) {
+		lines = append(lines, k+": "+formatValue(data[k]))
+	}
+	return lines
+}
+
+// formatValue 将任意值格式化为文本（复合值使用 JSON）
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool, json.Number:
+		return fmt.Sprint(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// withDefault 值为空时返回默认值
+func withDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// sortedKeys 返回排序后的 map 键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,175 @@
+package docgentest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// EndpointTemplateUploads 分片上传会话接口路径
+const EndpointTemplateUploads = "/api/v1/template/uploads"
+
+// uploadSession 模拟服务器中的分片上传会话
+type uploadSession struct {
+	id        string
+	fileName  string
+	size      int64
+	sha256    string
+	chunkSize int64
+	data      []byte
+}
+
+// FailUploadChunksAfter 再接受 n 个分片后，后续分片请求返回 503，用于模拟上传中途中断（负数表示取消）
+func (s *Server) FailUploadChunksAfter(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploadChunkBudget = n
+}
+
+// UploadSessionCount 返回未提交的分片上传会话数
+func (s *Server) UploadSessionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.uploads)
+}
+
+// handleUploads 分片上传会话接口：创建 → PUT 分片 → 提交，支持查询与放弃会话
+func (s *Server) handleUploads(w http.ResponseWriter, r *http.Request, req *CapturedRequest) {
+	if r.URL.Path == EndpointTemplateUploads && r.Method == http.MethodPost {
+		s.createUpload(w, req)
+		return
+	}
+
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, EndpointTemplateUploads+"/"), "/")
+	s.mu.Lock()
+	u, ok := s.uploads[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, docgen.CodeUploadNotFound, "Upload session not found: "+id)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		s.mu.Lock()
+		resp := u.json()
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, resp)
+	case action == "" && r.Method == http.MethodPut:
+		s.putUploadChunk(w, r, u, req.Body)
+	case action == "" && r.Method == http.MethodDelete:
+		s.mu.Lock()
+		delete(s.uploads, id)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case action == "commit" && r.Method == http.MethodPost:
+		s.commitUpload(w, u)
+	default:
+		writeError(w, http.StatusNotFound, docgen.CodeNotFound, "no handler for "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// createUpload 创建会话
+func (s *Server) createUpload(w http.ResponseWriter, req *CapturedRequest) {
+	var body struct {
+		FileName  string `json:"fileName"`
+		Size      int64  `json:"size"`
+		SHA256    string `json:"sha256"`
+		ChunkSize int64  `json:"chunkSize"`
+	}
+	if err := decodeBody(req.Body, &body); err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "malformed request body: "+err.Error())
+		return
+	}
+	if body.FileName == "" || body.Size < 0 {
+		writeError(w, http.StatusBadRequest, docgen.CodeValidationError, "fileName and size are required")
+		return
+	}
+	if body.ChunkSize <= 0 {
+		body.ChunkSize = 8 << 20
+	}
+
+	s.mu.Lock()
+	s.uploadSeq++
+	u := &uploadSession{
+		id:        fmt.Sprintf("upload-%d", s.uploadSeq),
+		fileName:  body.FileName,
+		size:      body.Size,
+		sha256:    body.SHA256,
+		chunkSize: body.ChunkSize,
+	}
+	s.uploads[u.id] = u
+	resp := u.json()
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// putUploadChunk 接收分片：offset 必须等于已接收的字节数
+func (s *Server) putUploadChunk(w http.ResponseWriter, r *http.Request, u *uploadSession, chunk []byte) {
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, "offset is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.uploadChunkBudget == 0 {
+		writeError(w, http.StatusServiceUnavailable, docgen.CodeServiceUnavailable, "simulated upload interruption")
+		return
+	}
+	if offset != int64(len(u.data)) {
+		writeError(w, http.StatusConflict, docgen.CodeOffsetMismatch, fmt.Sprintf("expected offset %d, got %d", len(u.data), offset))
+		return
+	}
+	if offset+int64(len(chunk)) > u.size {
+		writeError(w, http.StatusBadRequest, docgen.CodeInvalidArgument, "chunk exceeds declared size")
+		return
+	}
+	if s.uploadChunkBudget > 0 {
+		s.uploadChunkBudget--
+	}
+	u.data = append(u.data, chunk...)
+	writeJSON(w, http.StatusOK, u.json())
+}
+
+// commitUpload 提交会话：校验大小与 SHA-256 后保存为模板
+func (s *Server) commitUpload(w http.ResponseWriter, u *uploadSession) {
+	s.mu.Lock()
+	data := u.data
+	s.mu.Unlock()
+
+	if int64(len(data)) != u.size {
+		writeError(w, http.StatusConflict, docgen.CodeUploadIncomplete, fmt.Sprintf("received %d of %d bytes", len(data), u.size))
+		return
+	}
+	sum := sha256.Sum256(data)
+	if u.sha256 != "" && !strings.EqualFold(hex.EncodeToString(sum[:]), u.sha256) {
+		writeError(w, http.StatusUnprocessableEntity, docgen.CodeChecksumMismatch, "uploaded content does not match sha256")
+		return
+	}
+
+	s.AddTemplate(u.fileName, data)
+	s.mu.Lock()
+	delete(s.uploads, u.id)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "message": "模板上传成功", "fileName": u.fileName})
+}
+
+// json 会话状态响应，调用方需持有锁
+func (u *uploadSession) json() map[string]any {
+	return map[string]any{
+		"sessionId": u.id,
+		"fileName":  u.fileName,
+		"size":      u.size,
+		"sha256":    u.sha256,
+		"chunkSize": u.chunkSize,
+		"offset":    len(u.data),
+	}
+}
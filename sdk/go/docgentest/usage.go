@@ -0,0 +1,118 @@
+package docgentest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// 用量与配额接口路径
+const (
+	EndpointUsage = "/api/v1/usage"
+	EndpointQuota = "/api/v1/usage/quota"
+)
+
+// WithQuota 限制生成的文档数：成功生成 limit 个文档后，生成请求返回 429 QUOTA_EXCEEDED，
+// X-Quota-Reset 为 reset；limit <= 0 表示不限制
+func WithQuota(limit int64, reset time.Time) ServerOption {
+	return func(s *Server) {
+		s.quotaLimit, s.quotaReset = limit, reset
+	}
+}
+
+// isGeneration 判断是否为计入用量的生成请求
+func isGeneration(method, path string) bool {
+	if method != http.MethodPost {
+		return false
+	}
+	switch path {
+	case EndpointWord, EndpointWordBatch, EndpointExcel, EndpointExcelFill, EndpointAssemble:
+		return true
+	}
+	return false
+}
+
+// usageRecorder 记录生成请求的响应状态与大小
+type usageRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader 记录状态码
+func (u *usageRecorder) WriteHeader(status int) {
+	u.status = status
+	u.ResponseWriter.WriteHeader(status)
+}
+
+// Write 记录响应大小
+func (u *usageRecorder) Write(b []byte) (int, error) {
+	if u.status == 0 {
+		u.status = http.StatusOK
+	}
+	n, err := u.ResponseWriter.Write(b)
+	u.bytes += int64(n)
+	return n, err
+}
+
+// beginUsage 检查配额，配额耗尽时写入 429 并返回 nil
+func (s *Server) beginUsage(w http.ResponseWriter) *usageRecorder {
+	s.mu.Lock()
+	exceeded := s.quotaLimit > 0 && s.usage.Documents >= s.quotaLimit
+	reset := s.quotaReset
+	s.mu.Unlock()
+	if exceeded {
+		if !reset.IsZero() {
+			w.Header().Set(docgen.QuotaResetHeader, reset.UTC().Format(time.RFC3339))
+		}
+		writeError(w, http.StatusTooManyRequests, docgen.CodeQuotaExceeded, "document quota exceeded")
+		return nil
+	}
+	return &usageRecorder{ResponseWriter: w}
+}
+
+// endUsage 计入一次生成请求的用量，成功的请求计为一个文档
+func (s *Server) endUsage(path string, rec *usageRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.usage.Endpoints == nil {
+		s.usage.From = time.Now().UTC()
+		s.usage.Endpoints = make(map[string]docgen.EndpointUsage)
+	}
+	ep := s.usage.Endpoints[path]
+	ep.Requests++
+	if rec.status >= 200 && rec.status <= 299 {
+		ep.Documents++
+		ep.Bytes += rec.bytes
+		s.usage.Documents++
+		s.usage.Bytes += rec.bytes
+	}
+	s.usage.Endpoints[path] = ep
+}
+
+// handleUsage 返回累计用量（模拟服务器不区分统计周期）或配额
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.URL.Path == EndpointQuota {
+		quota := docgen.Quota{Limit: s.quotaLimit, Used: s.usage.Documents, Reset: s.quotaReset}
+		if s.quotaLimit > 0 && s.usage.Documents < s.quotaLimit {
+			quota.Remaining = s.quotaLimit - s.usage.Documents
+		}
+		writeJSON(w, http.StatusOK, quota)
+		return
+	}
+	report := s.usage
+	report.Period = r.URL.Query().Get("period")
+	if report.Period == "" {
+		report.Period = docgen.UsagePeriodMonth
+	}
+	report.To = time.Now().UTC()
+	endpoints := make(map[string]docgen.EndpointUsage, len(s.usage.Endpoints))
+	for k, v := range s.usage.Endpoints {
+		endpoints[k] = v
+	}
+	report.Endpoints = endpoints
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,68 @@
+package docgentest
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// WithVolatileOutput 模拟每次生成字节都不同的服务端：压缩包条目使用当前时间，Word 段落带随机 w:rsidR 属性。
+// 请求设置 deterministic 时（且未禁用 docgen.FeatureDeterministicOutput）仍生成固定的字节
+func WithVolatileOutput() ServerOption {
+	return func(s *Server) {
+		s.volatileOutput = true
+	}
+}
+
+// volatile 启用 WithVolatileOutput 且请求未要求可复现输出时，为生成的文档加入随生成变化的内容
+func (s *Server) volatile(req *CapturedRequest, doc []byte) []byte {
+	var body struct {
+		Deterministic bool `json:"deterministic"`
+	}
+	if !s.volatileOutput || decodeBody(req.Body, &body) == nil && body.Deterministic && !s.disabledFeatures[docgen.FeatureDeterministicOutput] {
+		return doc
+	}
+	zr, err := zip.NewReader(bytes.NewReader(doc), int64(len(doc)))
+	if err != nil {
+		return doc
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			panic(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			panic(err)
+		}
+		if f.Name == "word/document.xml" {
+			data = []byte(strings.ReplaceAll(string(data), "<w:p>", fmt.Sprintf(`<w:p w:rsidR="%08X">`, s.randomUint32())))
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			panic(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			panic(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// randomUint32 返回随机数
+func (s *Server) randomUint32() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Uint32()
+}
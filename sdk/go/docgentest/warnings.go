@@ -0,0 +1,45 @@
+package docgentest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Mars-Sea/doc-gen-service/sdk/go/docgen"
+)
+
+// setMissingPlaceholderWarnings 模板设置了结构（SetTemplateSchema）时，为数据中缺少的单值占位符
+// 写入 X-Render-Warnings 响应头（MISSING_PLACEHOLDER）
+func (s *Server) setMissingPlaceholderWarnings(w http.ResponseWriter, templateName string, data map[string]any) {
+	setWarnings(w, s.missingPlaceholderWarnings(templateName, data))
+}
+
+// missingPlaceholderWarnings 返回数据中缺少的单值占位符的警告，模板未设置结构时返回 nil
+func (s *Server) missingPlaceholderWarnings(templateName string, data map[string]any) []docgen.Warning {
+	s.mu.Lock()
+	schema, ok := s.schemas[templateName]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	var warnings []docgen.Warning
+	for _, v := range schema.Variables {
+		if _, ok := data[v.Name]; !ok {
+			warnings = append(warnings, docgen.Warning{
+				Code:        docgen.WarningMissingPlaceholder,
+				Placeholder: v.Name,
+				Location:    "word/document.xml",
+				Message:     "no data for placeholder {{" + v.Name + "}}",
+			})
+		}
+	}
+	return warnings
+}
+
+// setWarnings 将警告写入 X-Render-Warnings 响应头，没有警告时不写入
+func setWarnings(w http.ResponseWriter, warnings []docgen.Warning) {
+	if len(warnings) == 0 {
+		return
+	}
+	value, _ := json.Marshal(warnings)
+	w.Header().Set(docgen.WarningsHeader, string(value))
+}